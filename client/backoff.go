@@ -0,0 +1,58 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pd
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// backoffer computes the exponential backoff with jitter, so that the clients
+// do not retry at the same time when the leader fails.
+type backoffer struct {
+	base    time.Duration
+	max     time.Duration
+	attempt int
+}
+
+func newBackoffer(base, max time.Duration) *backoffer {
+	return &backoffer{base: base, max: max}
+}
+
+// next returns the duration to wait before the next retry, which is a random
+// duration in [d/2, d), where d doubles after each retry until the max.
+func (b *backoffer) next() time.Duration {
+	d := b.max
+	if b.attempt < 32 && b.base<<uint(b.attempt) < b.max {
+		d = b.base << uint(b.attempt)
+	}
+	b.attempt++
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isLeaderFailure checks if the error is caused by the failure or the change
+// of the leader, which can be recovered by retrying with the new leader.
+func isLeaderFailure(err error) bool {
+	err = errors.Cause(err)
+	if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
+		return true
+	}
+	return strings.Contains(err.Error(), "not leader")
+}
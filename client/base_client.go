@@ -49,6 +49,9 @@ type baseClient struct {
 
 	gRPCDialOptions []grpc.DialOption
 	timeout         time.Duration
+	// tsoBatchWaitInterval is the max time to wait for more TSO requests to
+	// be merged into one batch.
+	tsoBatchWaitInterval time.Duration
 }
 
 // SecurityOption records options about tls
@@ -75,6 +78,16 @@ func WithCustomTimeoutOption(timeout time.Duration) ClientOption {
 	}
 }
 
+// WithMaxTSOBatchWaitInterval configures the client to wait at most the
+// interval for more TSO requests to be merged into one batch. It reduces the
+// number of TSO requests under heavy workload at the cost of latency, and it
+// is disabled by default.
+func WithMaxTSOBatchWaitInterval(interval time.Duration) ClientOption {
+	return func(c *baseClient) {
+		c.tsoBatchWaitInterval = interval
+	}
+}
+
 // newBaseClient returns a new baseClient.
 func newBaseClient(ctx context.Context, urls []string, security SecurityOption, opts ...ClientOption) (*baseClient, error) {
 	ctx1, cancel := context.WithCancel(ctx)
@@ -136,10 +149,46 @@ func (c *baseClient) leaderLoop() {
 			return
 		}
 
-		if err := c.updateLeader(); err != nil {
+		// Retry with backoff until the leader is found, so the callers do not
+		// wait for the next check.
+		bo := newBackoffer(updateLeaderBackoffBase, updateLeaderBackoffMax)
+		for {
+			err := c.updateLeader()
+			if err == nil {
+				break
+			}
 			log.Error("[pd] failed updateLeader", errs.ZapError(err))
+			select {
+			case <-time.After(bo.next()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// retryOnLeaderFailure calls f and retries it with backoff if the leader
+// fails, in case the leader is changed. It should be only used by the
+// idempotent requests.
+func (c *baseClient) retryOnLeaderFailure(ctx context.Context, f func() error) error {
+	var err error
+	bo := newBackoffer(leaderRetryBackoffBase, leaderRetryBackoffMax)
+	for i := 0; i < maxLeaderRetries; i++ {
+		if err = f(); err == nil {
+			return nil
+		}
+		c.ScheduleCheckLeader()
+		if ctx.Err() != nil || !isLeaderFailure(err) || i == maxLeaderRetries-1 {
+			break
+		}
+		log.Warn("[pd] request failed, retry with the new leader", zap.Int("retry", i+1), errs.ZapError(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(bo.next()):
 		}
 	}
+	return err
 }
 
 // ScheduleCheckLeader is used to check leader.
@@ -64,6 +64,7 @@ type Client interface {
 	// Limit limits the maximum number of regions returned.
 	// If a region has no leader, corresponding leader will be placed by a peer
 	// with empty value (PeerID is 0).
+	// The request is retried if the leader fails.
 	ScanRegions(ctx context.Context, key, endKey []byte, limit int) ([]*Region, error)
 	// GetStore gets a store from PD by store id.
 	// The store may expire later. Caller is responsible for caching and taking care
@@ -71,7 +72,7 @@ type Client interface {
 	GetStore(ctx context.Context, storeID uint64) (*metapb.Store, error)
 	// GetAllStores gets all stores from pd.
 	// The store may expire later. Caller is responsible for caching and taking care
-	// of store change.
+	// of store change. The request is retried if the leader fails.
 	GetAllStores(ctx context.Context, opts ...GetStoreOption) ([]*metapb.Store, error)
 	// Update GC safe point. TiKV will check it and do GC themselves if necessary.
 	// If the given safePoint is less than the current one, it will not be updated.
@@ -135,6 +136,13 @@ const (
 	updateLeaderTimeout   = time.Second // Use a shorter timeout to recover faster from network isolation.
 	maxMergeTSORequests   = 10000       // should be higher if client is sending requests in burst
 	maxInitClusterRetries = 100
+	maxLeaderRetries      = 10 // the max retries of the read requests when the leader fails
+	// the backoff to wait for the leader to be updated before retry.
+	leaderRetryBackoffBase = 100 * time.Millisecond
+	leaderRetryBackoffMax  = time.Second
+	// the backoff to retry finding the leader.
+	updateLeaderBackoffBase = 100 * time.Millisecond
+	updateLeaderBackoffMax  = 3 * time.Second
 )
 
 var (
@@ -291,6 +299,9 @@ func (c *client) tsLoop() {
 			for i := 1; i < pendingPlus1; i++ {
 				requests[i] = <-c.tsoRequests
 			}
+			if c.tsoBatchWaitInterval > 0 {
+				pendingPlus1 = c.waitMoreTSORequests(loopCtx, requests, pendingPlus1)
+			}
 			done := make(chan struct{})
 			dl := deadline{
 				timer:  time.After(c.timeout),
@@ -326,6 +337,25 @@ func (c *client) tsLoop() {
 	}
 }
 
+// waitMoreTSORequests waits for more requests to be merged into the batch,
+// and returns the number of requests in the batch.
+func (c *client) waitMoreTSORequests(ctx context.Context, requests []*tsoRequest, n int) int {
+	timer := time.NewTimer(c.tsoBatchWaitInterval)
+	defer timer.Stop()
+	for n < len(requests) {
+		select {
+		case req := <-c.tsoRequests:
+			requests[n] = req
+			n++
+		case <-timer.C:
+			return n
+		case <-ctx.Done():
+			return n
+		}
+	}
+	return n
+}
+
 func extractSpanReference(requests []*tsoRequest, opts []opentracing.StartSpanOption) []opentracing.StartSpanOption {
 	for _, req := range requests {
 		if span := opentracing.SpanFromContext(req.ctx); span != nil {
@@ -446,7 +476,12 @@ func (c *client) GetTSAsync(ctx context.Context) TSFuture {
 	req := tsoReqPool.Get().(*tsoRequest)
 	req.ctx = ctx
 	req.start = time.Now()
-	c.tsoRequests <- req
+	select {
+	case c.tsoRequests <- req:
+	case <-ctx.Done():
+		// Do not block the caller if there are too many pending requests.
+		req.done <- ctx.Err()
+	}
 
 	return req
 }
@@ -510,15 +545,18 @@ func (c *client) GetRegion(ctx context.Context, key []byte) (*Region, error) {
 	defer func() { cmdDurationGetRegion.Observe(time.Since(start).Seconds()) }()
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	resp, err := c.leaderClient().GetRegion(ctx, &pdpb.GetRegionRequest{
-		Header:    c.requestHeader(),
-		RegionKey: key,
+	var resp *pdpb.GetRegionResponse
+	err := c.retryOnLeaderFailure(ctx, func() (err error) {
+		resp, err = c.leaderClient().GetRegion(ctx, &pdpb.GetRegionRequest{
+			Header:    c.requestHeader(),
+			RegionKey: key,
+		})
+		return err
 	})
 	cancel()
 
 	if err != nil {
 		cmdFailDurationGetRegion.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 	return c.parseRegionResponse(resp), nil
@@ -533,15 +571,18 @@ func (c *client) GetPrevRegion(ctx context.Context, key []byte) (*Region, error)
 	defer func() { cmdDurationGetPrevRegion.Observe(time.Since(start).Seconds()) }()
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	resp, err := c.leaderClient().GetPrevRegion(ctx, &pdpb.GetRegionRequest{
-		Header:    c.requestHeader(),
-		RegionKey: key,
+	var resp *pdpb.GetRegionResponse
+	err := c.retryOnLeaderFailure(ctx, func() (err error) {
+		resp, err = c.leaderClient().GetPrevRegion(ctx, &pdpb.GetRegionRequest{
+			Header:    c.requestHeader(),
+			RegionKey: key,
+		})
+		return err
 	})
 	cancel()
 
 	if err != nil {
 		cmdFailDurationGetPrevRegion.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 	return c.parseRegionResponse(resp), nil
@@ -556,15 +597,18 @@ func (c *client) GetRegionByID(ctx context.Context, regionID uint64) (*Region, e
 	defer func() { cmdDurationGetRegionByID.Observe(time.Since(start).Seconds()) }()
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	resp, err := c.leaderClient().GetRegionByID(ctx, &pdpb.GetRegionByIDRequest{
-		Header:   c.requestHeader(),
-		RegionId: regionID,
+	var resp *pdpb.GetRegionResponse
+	err := c.retryOnLeaderFailure(ctx, func() (err error) {
+		resp, err = c.leaderClient().GetRegionByID(ctx, &pdpb.GetRegionByIDRequest{
+			Header:   c.requestHeader(),
+			RegionId: regionID,
+		})
+		return err
 	})
 	cancel()
 
 	if err != nil {
 		cmdFailedDurationGetRegionByID.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 	return c.parseRegionResponse(resp), nil
@@ -576,7 +620,7 @@ func (c *client) ScanRegions(ctx context.Context, key, endKey []byte, limit int)
 		defer span.Finish()
 	}
 	start := time.Now()
-	defer cmdDurationScanRegions.Observe(time.Since(start).Seconds())
+	defer func() { cmdDurationScanRegions.Observe(time.Since(start).Seconds()) }()
 
 	var cancel context.CancelFunc
 	scanCtx := ctx
@@ -585,15 +629,18 @@ func (c *client) ScanRegions(ctx context.Context, key, endKey []byte, limit int)
 		defer cancel()
 	}
 
-	resp, err := c.leaderClient().ScanRegions(scanCtx, &pdpb.ScanRegionsRequest{
-		Header:   c.requestHeader(),
-		StartKey: key,
-		EndKey:   endKey,
-		Limit:    int32(limit),
+	var resp *pdpb.ScanRegionsResponse
+	err := c.retryOnLeaderFailure(scanCtx, func() (err error) {
+		resp, err = c.leaderClient().ScanRegions(scanCtx, &pdpb.ScanRegionsRequest{
+			Header:   c.requestHeader(),
+			StartKey: key,
+			EndKey:   endKey,
+			Limit:    int32(limit),
+		})
+		return err
 	})
 	if err != nil {
 		cmdFailedDurationScanRegions.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 
@@ -633,15 +680,18 @@ func (c *client) GetStore(ctx context.Context, storeID uint64) (*metapb.Store, e
 	defer func() { cmdDurationGetStore.Observe(time.Since(start).Seconds()) }()
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	resp, err := c.leaderClient().GetStore(ctx, &pdpb.GetStoreRequest{
-		Header:  c.requestHeader(),
-		StoreId: storeID,
+	var resp *pdpb.GetStoreResponse
+	err := c.retryOnLeaderFailure(ctx, func() (err error) {
+		resp, err = c.leaderClient().GetStore(ctx, &pdpb.GetStoreRequest{
+			Header:  c.requestHeader(),
+			StoreId: storeID,
+		})
+		return err
 	})
 	cancel()
 
 	if err != nil {
 		cmdFailedDurationGetStore.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 	store := resp.GetStore()
@@ -669,15 +719,18 @@ func (c *client) GetAllStores(ctx context.Context, opts ...GetStoreOption) ([]*m
 	defer func() { cmdDurationGetAllStores.Observe(time.Since(start).Seconds()) }()
 
 	ctx, cancel := context.WithTimeout(ctx, c.timeout)
-	resp, err := c.leaderClient().GetAllStores(ctx, &pdpb.GetAllStoresRequest{
-		Header:                 c.requestHeader(),
-		ExcludeTombstoneStores: options.excludeTombstone,
+	var resp *pdpb.GetAllStoresResponse
+	err := c.retryOnLeaderFailure(ctx, func() (err error) {
+		resp, err = c.leaderClient().GetAllStores(ctx, &pdpb.GetAllStoresRequest{
+			Header:                 c.requestHeader(),
+			ExcludeTombstoneStores: options.excludeTombstone,
+		})
+		return err
 	})
 	cancel()
 
 	if err != nil {
 		cmdFailedDurationGetAllStores.Observe(time.Since(start).Seconds())
-		c.ScheduleCheckLeader()
 		return nil, errors.WithStack(err)
 	}
 	stores := resp.GetStores()
@@ -19,10 +19,13 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/testutil"
 	"go.uber.org/goleak"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func Test(t *testing.T) {
@@ -67,6 +70,58 @@ func (s *testClientSuite) TestUpdateURLs(c *C) {
 	c.Assert(cli.urls, DeepEquals, getURLs([]*pdpb.Member{members[1], members[3], members[2], members[0]}))
 }
 
+func (s *testClientSuite) TestRetryOnLeaderFailure(c *C) {
+	cli := &baseClient{checkLeaderCh: make(chan struct{}, 1)}
+	ctx := context.Background()
+	notLeader := status.Errorf(codes.Unavailable, "not leader")
+
+	// Succeed after the leader is updated.
+	calls := 0
+	err := cli.retryOnLeaderFailure(ctx, func() error {
+		calls++
+		if calls < 3 {
+			return notLeader
+		}
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(calls, Equals, 3)
+	// The leader check is scheduled.
+	c.Assert(len(cli.checkLeaderCh), Equals, 1)
+
+	// Give up when the context is done.
+	calls = 0
+	timeoutCtx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	err = cli.retryOnLeaderFailure(timeoutCtx, func() error {
+		calls++
+		return notLeader
+	})
+	c.Assert(err, NotNil)
+	c.Assert(calls, Less, maxLeaderRetries)
+
+	// Do not retry the errors not caused by the leader.
+	calls = 0
+	err = cli.retryOnLeaderFailure(ctx, func() error {
+		calls++
+		return errors.New("invalid argument")
+	})
+	c.Assert(err, NotNil)
+	c.Assert(calls, Equals, 1)
+}
+
+func (s *testClientSuite) TestBackoffer(c *C) {
+	bo := newBackoffer(100*time.Millisecond, time.Second)
+	for _, d := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		next := bo.next()
+		c.Assert(next, GreaterEqual, d/2)
+		c.Assert(next, LessEqual, d)
+	}
+	c.Assert(isLeaderFailure(errors.WithStack(status.Errorf(codes.Unavailable, "not leader"))), IsTrue)
+	c.Assert(isLeaderFailure(errors.New("[PD:client:ErrClientGetLeader]not leader")), IsTrue)
+	c.Assert(isLeaderFailure(status.Errorf(codes.InvalidArgument, "invalid")), IsFalse)
+}
+
 var _ = Suite(&testClientCtxSuite{})
 
 type testClientCtxSuite struct{}
@@ -223,6 +223,28 @@ func (s *testRegionCacheSuite) TestFifoCache(c *C) {
 	c.Assert(cache.Len(), Equals, 0)
 }
 
+func (s *testRegionCacheSuite) TestPriorityQueue(c *C) {
+	queue := NewPriorityQueue(4)
+	c.Assert(queue.Put(1, 1), IsTrue)
+	c.Assert(queue.Put(2, 3), IsTrue)
+	c.Assert(queue.Put(3, 1), IsTrue)
+	c.Assert(queue.Put(4, 2), IsTrue)
+	c.Assert(queue.Put(5, 5), IsFalse)
+	c.Assert(queue.Len(), Equals, 4)
+
+	// Raise the priority, but never lower it.
+	c.Assert(queue.Put(3, 4), IsTrue)
+	c.Assert(queue.Put(2, 0), IsTrue)
+	c.Assert(queue.Len(), Equals, 4)
+
+	c.Assert(queue.Pop(2), DeepEquals, []uint64{3, 2})
+	queue.Remove(4)
+	c.Assert(queue.Put(5, 1), IsTrue)
+	// The keys with the same priority are popped in order.
+	c.Assert(queue.Pop(3), DeepEquals, []uint64{1, 5})
+	c.Assert(queue.Len(), Equals, 0)
+}
+
 func (s *testRegionCacheSuite) TestTwoQueueCache(c *C) {
 	cache := newTwoQueue(3)
 	cache.Put(1, "1")
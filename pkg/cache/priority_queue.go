@@ -0,0 +1,138 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// PriorityQueue is a queue of keys, the key with the highest priority is
+// popped first, and the keys with the same priority are popped in the order
+// they are put. A key is kept at most once in the queue.
+type PriorityQueue struct {
+	sync.Mutex
+
+	// maxCount is the maximum number of keys.
+	// 0 means no limit.
+	maxCount int
+
+	items map[uint64]*priorityItem
+	heap  priorityHeap
+	seq   uint64
+}
+
+type priorityItem struct {
+	key      uint64
+	priority int
+	seq      uint64
+	index    int
+}
+
+// NewPriorityQueue returns a new PriorityQueue.
+func NewPriorityQueue(maxCount int) *PriorityQueue {
+	return &PriorityQueue{
+		maxCount: maxCount,
+		items:    make(map[uint64]*priorityItem),
+	}
+}
+
+// Put puts a key into the queue. If the key is already in the queue, its
+// priority is raised if the new one is higher. It returns false if the queue
+// is full.
+func (q *PriorityQueue) Put(key uint64, priority int) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	if item, ok := q.items[key]; ok {
+		if priority > item.priority {
+			item.priority = priority
+			heap.Fix(&q.heap, item.index)
+		}
+		return true
+	}
+	if q.maxCount != 0 && len(q.items) >= q.maxCount {
+		return false
+	}
+	q.seq++
+	item := &priorityItem{key: key, priority: priority, seq: q.seq}
+	heap.Push(&q.heap, item)
+	q.items[key] = item
+	return true
+}
+
+// Pop takes out at most n keys with the highest priorities.
+func (q *PriorityQueue) Pop(n int) []uint64 {
+	q.Lock()
+	defer q.Unlock()
+
+	keys := make([]uint64, 0, n)
+	for len(keys) < n && q.heap.Len() > 0 {
+		item := heap.Pop(&q.heap).(*priorityItem)
+		delete(q.items, item.key)
+		keys = append(keys, item.key)
+	}
+	return keys
+}
+
+// Remove removes the key from the queue.
+func (q *PriorityQueue) Remove(key uint64) {
+	q.Lock()
+	defer q.Unlock()
+
+	if item, ok := q.items[key]; ok {
+		heap.Remove(&q.heap, item.index)
+		delete(q.items, key)
+	}
+}
+
+// Len returns the number of keys in the queue.
+func (q *PriorityQueue) Len() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.items)
+}
+
+// priorityHeap implements heap.Interface.
+type priorityHeap []*priorityItem
+
+func (h priorityHeap) Len() int { return len(h) }
+
+func (h priorityHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *priorityHeap) Push(x interface{}) {
+	item := x.(*priorityItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *priorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,128 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build without_dashboard
+
+package dashboard
+
+import (
+	"io"
+	"net/http"
+)
+
+// statusPageHandler serves a simple status page when TiDB Dashboard is not
+// built. The page only reads the PD API, which is forwarded to the PD leader
+// by any member, so the page works on the address of any member.
+func statusPageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = io.WriteString(w, statusPage)
+}
+
+const statusPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>PD Status</title>
+<style>
+body { font-family: sans-serif; margin: 20px; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #f0f0f0; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<h1>PD Status</h1>
+<p>TiDB Dashboard is not built, this page shows the basic status of the cluster. It refreshes every 10 seconds.</p>
+<h2>Stores</h2>
+<table id="stores"></table>
+<h2>Regions</h2>
+<table id="regions"></table>
+<h2>Operators</h2>
+<table id="operators"></table>
+<h2>Hot Regions</h2>
+<table id="hotspots"></table>
+<script>
+const api = "/pd/api/v1";
+
+function render(id, header, rows) {
+  const table = document.getElementById(id);
+  table.innerHTML = "";
+  const tr = table.insertRow();
+  header.forEach(h => {
+    const th = document.createElement("th");
+    th.textContent = h;
+    tr.appendChild(th);
+  });
+  rows.forEach(row => {
+    const tr = table.insertRow();
+    row.forEach(v => { tr.insertCell().textContent = v === undefined ? "" : v; });
+  });
+}
+
+function renderError(id, err) {
+  const table = document.getElementById(id);
+  table.innerHTML = "";
+  const td = table.insertRow().insertCell();
+  td.className = "error";
+  td.textContent = String(err);
+}
+
+async function load(id, path, fn) {
+  try {
+    const resp = await fetch(api + path);
+    if (!resp.ok) {
+      throw new Error(resp.status + " " + (await resp.text()));
+    }
+    fn(await resp.json());
+  } catch (err) {
+    renderError(id, err);
+  }
+}
+
+function hotRows(kind, stats) {
+  const rows = [];
+  Object.entries((stats && stats.as_leader) || {}).forEach(([store, s]) => {
+    rows.push([kind, store, s.regions_count, Math.round(s.total_flow_bytes)]);
+  });
+  return rows;
+}
+
+function refresh() {
+  load("stores", "/stores", data => render("stores",
+    ["ID", "Address", "State", "Version", "Capacity", "Available", "Leaders", "Regions", "Last Heartbeat"],
+    (data.stores || []).map(s => [s.store.id, s.store.address, s.store.state_name, s.store.version,
+      s.status.capacity, s.status.available, s.status.leader_count, s.status.region_count,
+      s.status.last_heartbeat_ts])));
+  load("regions", "/stats/region", data => render("regions",
+    ["Count", "Empty Count", "Storage Size (MiB)", "Storage Keys"],
+    [[data.count, data.empty_count, data.storage_size, data.storage_keys]]));
+  load("operators", "/operators", data => render("operators",
+    ["Operator"], (data || []).map(op => [op])));
+  Promise.all([fetch(api + "/hotspot/regions/write"), fetch(api + "/hotspot/regions/read")])
+    .then(resps => Promise.all(resps.map(r => r.json())))
+    .then(([write, read]) => render("hotspots", ["Type", "Store", "Hot Regions", "Flow Bytes"],
+      hotRows("write", write).concat(hotRows("read", read))))
+    .catch(err => renderError("hotspots", err));
+}
+
+refresh();
+setInterval(refresh, 10000);
+</script>
+</body>
+</html>
+`
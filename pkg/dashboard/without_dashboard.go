@@ -17,7 +17,6 @@ package dashboard
 
 import (
 	"context"
-	"io"
 	"net/http"
 	"time"
 
@@ -38,14 +37,11 @@ var (
 // SetCheckInterval does nothing
 func SetCheckInterval(time.Duration) {}
 
-// GetServiceBuilders returns a empty Dashboard Builder
+// GetServiceBuilders returns a Dashboard Builder which serves a simple status page
 func GetServiceBuilders() []server.HandlerBuilder {
 	return []server.HandlerBuilder{
 		func(context.Context, *server.Server) (http.Handler, server.ServiceGroup, error) {
-			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				_, _ = io.WriteString(w, "Dashboard is not built.\n")
-			})
-			return handler, serviceGroup, nil
+			return http.HandlerFunc(statusPageHandler), serviceGroup, nil
 		},
 	}
 }
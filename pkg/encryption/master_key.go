@@ -59,6 +59,11 @@ func NewMasterKey(config *encryptionpb.MasterKey) (*MasterKey, error) {
 			key:    key,
 		}, nil
 	}
+	if config.GetKms() != nil {
+		// Using KMS requires the SDK of the cloud vendor, which is not
+		// bundled yet. Use the file master key instead.
+		return nil, errs.ErrEncryptionKMS.GenWithStack("KMS master key is not supported yet")
+	}
 	return nil, errors.New("unrecognized master key type")
 }
 
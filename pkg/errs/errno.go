@@ -50,6 +50,11 @@ var (
 	ErrStoreNotFound       = errors.Normalize("store %v not found", errors.RFCCodeText("PD:core:ErrStoreNotFound"))
 	ErrPauseLeaderTransfer = errors.Normalize("store %v is paused for leader transfer", errors.RFCCodeText("PD:core:ErrPauseLeaderTransfer"))
 	ErrStoreTombstone      = errors.Normalize("store %v has been removed", errors.RFCCodeText("PD:core:ErrStoreTombstone"))
+	ErrStoreNotUp          = errors.Normalize("store %v is not up", errors.RFCCodeText("PD:core:ErrStoreNotUp"))
+	ErrStoreDestroyed      = errors.Normalize("store %v has been physically destroyed", errors.RFCCodeText("PD:core:ErrStoreDestroyed"))
+	ErrStoreNotDraining    = errors.Normalize("store %v is not being drained", errors.RFCCodeText("PD:core:ErrStoreNotDraining"))
+	ErrStoreNotDestroyed   = errors.Normalize("store %v is not physically destroyed", errors.RFCCodeText("PD:core:ErrStoreNotDestroyed"))
+	ErrStoreNotTombstone   = errors.Normalize("store %v is not tombstone yet", errors.RFCCodeText("PD:core:ErrStoreNotTombstone"))
 )
 
 // client errors
@@ -66,6 +71,7 @@ var (
 	ErrUnexpectedOperatorStatus = errors.Normalize("operator with unexpected status", errors.RFCCodeText("PD:schedule:ErrUnexpectedOperatorStatus"))
 	ErrUnknownOperatorStep      = errors.Normalize("unknown operator step found", errors.RFCCodeText("PD:schedule:ErrUnknownOperatorStep"))
 	ErrMergeOperator            = errors.Normalize("merge operator error, %s", errors.RFCCodeText("PD:schedule:ErrMergeOperator"))
+	ErrInvalidOperatorPlan      = errors.Normalize("invalid operator plan, %s", errors.RFCCodeText("PD:schedule:ErrInvalidOperatorPlan"))
 )
 
 // scheduler errors
@@ -90,13 +96,18 @@ var (
 
 // cluster errors
 var (
-	ErrNotBootstrapped = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
-	ErrStoreIsUp       = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
+	ErrNotBootstrapped    = errors.Normalize("TiKV cluster not bootstrapped, please start TiKV first", errors.RFCCodeText("PD:cluster:ErrNotBootstrapped"))
+	ErrStoreIsUp          = errors.Normalize("store is still up, please remove store gracefully", errors.RFCCodeText("PD:cluster:ErrStoreIsUp"))
+	ErrLoadStoreDrain     = errors.Normalize("load store drain failed", errors.RFCCodeText("PD:cluster:ErrLoadStoreDrain"))
+	ErrLoadDestroyedStore = errors.Normalize("load physically destroyed store failed", errors.RFCCodeText("PD:cluster:ErrLoadDestroyedStore"))
+	ErrLoadStoreCordon    = errors.Normalize("load store cordon failed", errors.RFCCodeText("PD:cluster:ErrLoadStoreCordon"))
+	ErrLoadStorePause     = errors.Normalize("load store pause failed", errors.RFCCodeText("PD:cluster:ErrLoadStorePause"))
 )
 
 // versioninfo errors
 var (
-	ErrFeatureNotExisted = errors.Normalize("feature not existed", errors.RFCCodeText("PD:versioninfo:ErrFeatureNotExisted"))
+	ErrFeatureNotExisted     = errors.Normalize("feature not existed", errors.RFCCodeText("PD:versioninfo:ErrFeatureNotExisted"))
+	ErrClusterVersionTooHigh = errors.Normalize("cluster version %s is higher than the minimum store version %s", errors.RFCCodeText("PD:versioninfo:ErrClusterVersionTooHigh"))
 )
 
 // autoscaling errors
@@ -196,6 +207,7 @@ var (
 // prometheus errors
 var (
 	ErrPrometheusPushMetrics  = errors.Normalize("push metrics to gateway failed", errors.RFCCodeText("PD:prometheus:ErrPrometheusPushMetrics"))
+	ErrPrometheusRemoteWrite  = errors.Normalize("remote write metrics failed", errors.RFCCodeText("PD:prometheus:ErrPrometheusRemoteWrite"))
 	ErrPrometheusCreateClient = errors.Normalize("create client error", errors.RFCCodeText("PD:prometheus:ErrPrometheusCreateClient"))
 	ErrPrometheusQuery        = errors.Normalize("query error", errors.RFCCodeText("PD:prometheus:ErrPrometheusQuery"))
 )
@@ -227,6 +239,17 @@ var (
 	ErrNetstatTCPSocks = errors.Normalize("TCP socks error", errors.RFCCodeText("PD:netstat:ErrNetstatTCPSocks"))
 )
 
+// region weight errors
+var (
+	ErrRegionWeightContent = errors.Normalize("invalid region weight content, %s", errors.RFCCodeText("PD:weight:ErrRegionWeightContent"))
+	ErrLoadRegionWeight    = errors.Normalize("load region weight failed", errors.RFCCodeText("PD:weight:ErrLoadRegionWeight"))
+)
+
+// store config errors
+var (
+	ErrLoadStoreConfig = errors.Normalize("load store config failed", errors.RFCCodeText("PD:storeconfig:ErrLoadStoreConfig"))
+)
+
 // hex error
 var (
 	ErrHexDecodingString = errors.Normalize("decode string %s error", errors.RFCCodeText("PD:hex:ErrHexDecodingString"))
@@ -279,4 +302,8 @@ var (
 	ErrEncryptionEncryptRegion = errors.Normalize("encrypt region fail", errors.RFCCodeText("PD:encryption:ErrEncryptionEncryptRegion"))
 	ErrEncryptionDecryptRegion = errors.Normalize("decrypt region fail", errors.RFCCodeText("PD:encryption:ErrEncryptionDecryptRegion"))
 	ErrEncryptionNewMasterKey  = errors.Normalize("fail to get master key", errors.RFCCodeText("PD:encryption:ErrEncryptionNewMasterKey"))
+	ErrEncryptionKMS           = errors.Normalize("KMS error", errors.RFCCodeText("PD:encryption:ErrEncryptionKMS"))
+	ErrEncryptionLoadKeys      = errors.Normalize("fail to load data keys", errors.RFCCodeText("PD:encryption:ErrEncryptionLoadKeys"))
+	ErrEncryptionSaveDataKeys  = errors.Normalize("fail to save data keys", errors.RFCCodeText("PD:encryption:ErrEncryptionSaveDataKeys"))
+	ErrEncryptionKeyNotFound   = errors.Normalize("data key not found, key id = %d", errors.RFCCodeText("PD:encryption:ErrEncryptionKeyNotFound"))
 )
@@ -0,0 +1,123 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/tikv/pd/pkg/errs"
+)
+
+// TLSReloader reloads the certificate, the key and the CA files, so that the
+// short-lived certificates can be rotated without restarting. The listeners of
+// the embedded etcd, which also serve the HTTP and gRPC requests, and the etcd
+// and gRPC clients load the certificate and the key on every handshake, but
+// their trusted CAs are fixed once they are created. The reloader only makes a
+// changed CA take effect for the HTTP requests to other members, changing the
+// CA for the others still requires restarting.
+type TLSReloader struct {
+	cfg TLSConfig
+
+	mu           sync.RWMutex
+	clientConfig *tls.Config
+	digest       []byte
+	notAfter     time.Time
+}
+
+// NewTLSReloader creates a TLSReloader and loads the files.
+func NewTLSReloader(cfg TLSConfig) (*TLSReloader, error) {
+	r := &TLSReloader{cfg: cfg}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the files if they are changed, and returns whether they are
+// changed. The previous config is kept if the new files are invalid.
+func (r *TLSReloader) Reload() (bool, error) {
+	h := sha256.New()
+	for _, path := range []string{r.cfg.CAPath, r.cfg.CertPath, r.cfg.KeyPath} {
+		if path == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, errs.ErrSecurityConfig.FastGenByArgs(err.Error())
+		}
+		h.Write(data)
+	}
+	digest := h.Sum(nil)
+	r.mu.RLock()
+	changed := !bytes.Equal(digest, r.digest)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	clientConfig, err := r.cfg.ToTLSConfig()
+	if err != nil {
+		return false, err
+	}
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertPath, r.cfg.KeyPath)
+	if err != nil {
+		return false, errs.ErrSecurityConfig.FastGenByArgs(err.Error())
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return false, errs.ErrSecurityConfig.FastGenByArgs(err.Error())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clientConfig = clientConfig
+	r.digest = digest
+	r.notAfter = leaf.NotAfter
+	return true, nil
+}
+
+// NotAfter returns the expiration time of the current certificate.
+func (r *TLSReloader) NotAfter() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.notAfter
+}
+
+// ClientConfig returns the current client TLS config.
+func (r *TLSReloader) ClientConfig() *tls.Config {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clientConfig.Clone()
+}
+
+// DialTLS dials the address with the current client TLS config. It is used
+// as the DialTLS of http.Transport.
+func (r *TLSReloader) DialTLS(network, addr string) (net.Conn, error) {
+	cfg := r.ClientConfig()
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ServerName = host
+	}
+	return tls.Dial(network, addr, cfg)
+}
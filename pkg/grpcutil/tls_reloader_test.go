@@ -0,0 +1,115 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+func TestGRPCUtil(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testTLSReloaderSuite{})
+
+type testTLSReloaderSuite struct {
+	dir string
+}
+
+func (s *testTLSReloaderSuite) SetUpTest(c *C) {
+	dir, err := ioutil.TempDir("", "tls_reloader_test")
+	c.Assert(err, IsNil)
+	s.dir = dir
+}
+
+func (s *testTLSReloaderSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.dir)
+}
+
+// writeCert writes a self-signed certificate and its key, the certificate is
+// also used as the CA.
+func (s *testTLSReloaderSuite) writeCert(c *C, notAfter time.Time) TLSConfig {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "pd"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	cfg := TLSConfig{
+		CAPath:   filepath.Join(s.dir, "ca.pem"),
+		CertPath: filepath.Join(s.dir, "pd.pem"),
+		KeyPath:  filepath.Join(s.dir, "pd-key.pem"),
+	}
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	c.Assert(ioutil.WriteFile(cfg.CAPath, certPem, 0600), IsNil)
+	c.Assert(ioutil.WriteFile(cfg.CertPath, certPem, 0600), IsNil)
+	c.Assert(ioutil.WriteFile(cfg.KeyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600), IsNil)
+	return cfg
+}
+
+func (s *testTLSReloaderSuite) TestReload(c *C) {
+	notAfter := time.Now().Add(time.Hour).Truncate(time.Second)
+	cfg := s.writeCert(c, notAfter)
+	r, err := NewTLSReloader(cfg)
+	c.Assert(err, IsNil)
+	c.Assert(r.NotAfter().Equal(notAfter), IsTrue)
+	c.Assert(r.ClientConfig(), NotNil)
+
+	// Nothing is changed.
+	changed, err := r.Reload()
+	c.Assert(err, IsNil)
+	c.Assert(changed, IsFalse)
+
+	// The certificate is rotated.
+	newNotAfter := notAfter.Add(time.Hour)
+	s.writeCert(c, newNotAfter)
+	changed, err = r.Reload()
+	c.Assert(err, IsNil)
+	c.Assert(changed, IsTrue)
+	c.Assert(r.NotAfter().Equal(newNotAfter), IsTrue)
+
+	// The previous certificate is kept if the new one is invalid.
+	c.Assert(ioutil.WriteFile(cfg.KeyPath, []byte("invalid"), 0600), IsNil)
+	_, err = r.Reload()
+	c.Assert(err, NotNil)
+	c.Assert(r.NotAfter().Equal(newNotAfter), IsTrue)
+
+	// Fail to create the reloader with invalid files.
+	_, err = NewTLSReloader(cfg)
+	c.Assert(err, NotNil)
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 	"unicode"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
@@ -27,11 +28,33 @@ import (
 
 const zeroDuration = time.Duration(0)
 
+// The push types of the metrics.
+const (
+	// PushTypePushgateway pushes the metrics to Prometheus Pushgateway.
+	PushTypePushgateway = "pushgateway"
+	// PushTypeRemoteWrite pushes the metrics with Prometheus remote write protocol.
+	PushTypeRemoteWrite = "remote-write"
+)
+
 // MetricConfig is the metric configuration.
 type MetricConfig struct {
 	PushJob      string            `toml:"job" json:"job"`
+	PushInstance string            `toml:"instance" json:"instance"`
 	PushAddress  string            `toml:"address" json:"address"`
 	PushInterval typeutil.Duration `toml:"interval" json:"interval"`
+	// PushType is the way to push the metrics, it can be pushgateway or
+	// remote-write, the default is pushgateway.
+	PushType string `toml:"type" json:"type"`
+}
+
+// Validate checks the metric configuration.
+func (cfg *MetricConfig) Validate() error {
+	switch cfg.PushType {
+	case "", PushTypePushgateway, PushTypeRemoteWrite:
+		return nil
+	default:
+		return errors.Errorf("unknown metric push type %q, it should be %s or %s", cfg.PushType, PushTypePushgateway, PushTypeRemoteWrite)
+	}
 }
 
 func runesHasLowerNeighborAt(runes []rune, idx int) bool {
@@ -60,10 +83,10 @@ func camelCaseToSnakeCase(str string) string {
 }
 
 // prometheusPushClient pushes metrics to Prometheus Pushgateway.
-func prometheusPushClient(job, addr string, interval time.Duration) {
+func prometheusPushClient(job, instance, addr string, interval time.Duration) {
 	pusher := push.New(addr, job).
 		Gatherer(prometheus.DefaultGatherer).
-		Grouping("instance", instanceName())
+		Grouping("instance", instance)
 
 	for {
 		err := pusher.Push()
@@ -82,10 +105,24 @@ func Push(cfg *MetricConfig) {
 		return
 	}
 
-	log.Info("start Prometheus push client")
+	if err := cfg.Validate(); err != nil {
+		log.Error("disable Prometheus push client", errs.ZapError(err))
+		return
+	}
 
+	instance := cfg.PushInstance
+	if len(instance) == 0 {
+		instance = instanceName()
+	}
 	interval := cfg.PushInterval.Duration
-	go prometheusPushClient(cfg.PushJob, cfg.PushAddress, interval)
+	if cfg.PushType == PushTypeRemoteWrite {
+		log.Info("start Prometheus remote write client")
+		go prometheusRemoteWriteClient(cfg.PushJob, instance, cfg.PushAddress, interval)
+		return
+	}
+
+	log.Info("start Prometheus push client")
+	go prometheusPushClient(cfg.PushJob, instance, cfg.PushAddress, interval)
 }
 
 func instanceName() string {
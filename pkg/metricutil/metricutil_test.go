@@ -14,10 +14,15 @@
 package metricutil
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
 	. "github.com/pingcap/check"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/typeutil"
 )
 
@@ -84,3 +89,54 @@ func (s *testMetricsSuite) TestCoverage(c *C) {
 		Push(cfg)
 	}
 }
+
+func (s *testMetricsSuite) TestValidate(c *C) {
+	for _, t := range []string{"", PushTypePushgateway, PushTypeRemoteWrite} {
+		cfg := &MetricConfig{PushType: t}
+		c.Assert(cfg.Validate(), IsNil)
+	}
+	cfg := &MetricConfig{PushType: "unknown"}
+	c.Assert(cfg.Validate(), NotNil)
+}
+
+func (s *testMetricsSuite) TestRemoteWrite(c *C) {
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "test_counter",
+	}, []string{"type"})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "test_histogram",
+		Buckets: []float64{1, 2},
+	})
+	registry.MustRegister(counter, histogram)
+	counter.WithLabelValues("a").Add(3)
+	histogram.Observe(1.5)
+
+	mfs, err := registry.Gather()
+	c.Assert(err, IsNil)
+	series := toTimeSeries(mfs, "pd", "pd-1")
+	// 1 counter, 2 + 1 buckets, sum and count.
+	c.Assert(series, HasLen, 6)
+	c.Assert(series[0].labels, DeepEquals, []label{
+		{"__name__", "test_counter"},
+		{"instance", "pd-1"},
+		{"job", "pd"},
+		{"type", "a"},
+	})
+	c.Assert(series[0].value, Equals, 3.0)
+	c.Assert(series[3].labels[3], DeepEquals, label{"le", "+Inf"})
+	c.Assert(series[3].value, Equals, 1.0)
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Assert(r.Header.Get("Content-Encoding"), Equals, "snappy")
+		data, err := ioutil.ReadAll(r.Body)
+		c.Assert(err, IsNil)
+		body, err = snappy.Decode(nil, data)
+		c.Assert(err, IsNil)
+	}))
+	defer server.Close()
+	err = remoteWrite(server.Client(), registry, "pd", "pd-1", server.URL, time.Unix(100, 0))
+	c.Assert(err, IsNil)
+	c.Assert(body, DeepEquals, encodeWriteRequest(series, 100000))
+}
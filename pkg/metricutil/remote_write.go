@@ -0,0 +1,193 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/tikv/pd/pkg/errs"
+)
+
+const (
+	// The wire types of protobuf.
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+type timeSeries struct {
+	labels []label
+	value  float64
+}
+
+// prometheusRemoteWriteClient pushes metrics with Prometheus remote write protocol.
+func prometheusRemoteWriteClient(job, instance, addr string, interval time.Duration) {
+	client := &http.Client{Timeout: interval}
+	for {
+		err := remoteWrite(client, prometheus.DefaultGatherer, job, instance, addr, time.Now())
+		if err != nil {
+			log.Error("could not push metrics with Prometheus remote write", errs.ZapError(errs.ErrPrometheusRemoteWrite, err))
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// remoteWrite gathers the metrics and sends them to the remote write endpoint
+// as a snappy compressed protobuf WriteRequest.
+func remoteWrite(client *http.Client, g prometheus.Gatherer, job, instance, addr string, now time.Time) error {
+	mfs, err := g.Gather()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data := encodeWriteRequest(toTimeSeries(mfs, job, instance), now.UnixNano()/int64(time.Millisecond))
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	req, err := http.NewRequest(http.MethodPost, addr, bytes.NewReader(snappy.Encode(nil, data)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("server returned HTTP status %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// toTimeSeries converts the metric families to the time series, the job and
+// instance labels are attached to each of them.
+func toTimeSeries(mfs []*dto.MetricFamily, job, instance string) []timeSeries {
+	var series []timeSeries
+	for _, mf := range mfs {
+		name := mf.GetName()
+		for _, m := range mf.GetMetric() {
+			add := func(name string, value float64, extra ...label) {
+				labels := make([]label, 0, len(m.GetLabel())+len(extra)+3)
+				labels = append(labels, label{"__name__", name}, label{"job", job}, label{"instance", instance})
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "job" || l.GetName() == "instance" {
+						continue
+					}
+					labels = append(labels, label{l.GetName(), l.GetValue()})
+				}
+				labels = append(labels, extra...)
+				sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+				series = append(series, timeSeries{labels: labels, value: value})
+			}
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				add(name, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add(name, m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add(name, m.GetUntyped().GetValue())
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add(name, q.GetValue(), label{"quantile", formatFloat(q.GetQuantile())})
+				}
+				add(name+"_sum", s.GetSampleSum())
+				add(name+"_count", float64(s.GetSampleCount()))
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					add(name+"_bucket", float64(b.GetCumulativeCount()), label{"le", formatFloat(b.GetUpperBound())})
+				}
+				add(name+"_bucket", float64(h.GetSampleCount()), label{"le", "+Inf"})
+				add(name+"_sum", h.GetSampleSum())
+				add(name+"_count", float64(h.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// encodeWriteRequest encodes the time series as the WriteRequest message of
+// the remote write protocol, each time series holds one sample.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(series []timeSeries, timestamp int64) []byte {
+	var req []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var lb []byte
+			lb = appendBytesField(lb, 1, []byte(l.name))
+			lb = appendBytesField(lb, 2, []byte(l.value))
+			ts = appendBytesField(ts, 1, lb)
+		}
+		var sample []byte
+		sample = appendTag(sample, 1, wireFixed64)
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(s.value))
+		sample = append(sample, buf[:]...)
+		sample = appendTag(sample, 2, wireVarint)
+		sample = appendVarint(sample, uint64(timestamp))
+		ts = appendBytesField(ts, 2, sample)
+		req = appendBytesField(req, 1, ts)
+	}
+	return req
+}
+
+func appendTag(b []byte, field int, wireType int) []byte {
+	return appendVarint(b, uint64(field<<3|wireType))
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
@@ -40,6 +40,18 @@ func (mc *Cluster) SetEnableOneWayMerge(v bool) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableOneWayMerge = v })
 }
 
+// SetEnableCrossTableMerge updates the EnableCrossTableMerge configuration.
+func (mc *Cluster) SetEnableCrossTableMerge(v bool) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.EnableCrossTableMerge = v })
+}
+
+// SetKeyType updates the KeyType configuration.
+func (mc *Cluster) SetKeyType(v string) {
+	cfg := mc.GetPDServerConfig().Clone()
+	cfg.KeyType = v
+	mc.SetPDServerConfig(cfg)
+}
+
 // SetMaxSnapshotCount updates the MaxSnapshotCount configuration.
 func (mc *Cluster) SetMaxSnapshotCount(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MaxSnapshotCount = uint64(v) })
@@ -75,6 +87,21 @@ func (mc *Cluster) SetLeaderSchedulePolicy(v string) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.LeaderSchedulePolicy = v })
 }
 
+// SetBalanceLabelGroup updates the BalanceLabelGroup configuration.
+func (mc *Cluster) SetBalanceLabelGroup(v string) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.BalanceLabelGroup = v })
+}
+
+// SetTableOperatorShare updates the TableOperatorShare configuration.
+func (mc *Cluster) SetTableOperatorShare(v float64) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.TableOperatorShare = v })
+}
+
+// SetReplicaRepairReservedSlots updates the ReplicaRepairReservedSlots configuration.
+func (mc *Cluster) SetReplicaRepairReservedSlots(v int) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.ReplicaRepairReservedSlots = uint64(v) })
+}
+
 // SetTolerantSizeRatio updates the TolerantSizeRatio configuration.
 func (mc *Cluster) SetTolerantSizeRatio(v float64) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.TolerantSizeRatio = v })
@@ -95,6 +122,11 @@ func (mc *Cluster) SetMergeScheduleLimit(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.MergeScheduleLimit = uint64(v) })
 }
 
+// SetHeartbeatStalenessBound updates the HeartbeatStalenessBound configuration.
+func (mc *Cluster) SetHeartbeatStalenessBound(v time.Duration) {
+	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.HeartbeatStalenessBound = typeutil.NewDuration(v) })
+}
+
 // SetHotRegionScheduleLimit updates the HotRegionScheduleLimit configuration.
 func (mc *Cluster) SetHotRegionScheduleLimit(v int) {
 	mc.updateScheduleConfig(func(s *config.ScheduleConfig) { s.HotRegionScheduleLimit = uint64(v) })
@@ -118,6 +150,11 @@ func (mc *Cluster) SetMaxReplicas(v int) {
 	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.MaxReplicas = uint64(v) })
 }
 
+// SetMaxObservers updates the maxObservers configuration.
+func (mc *Cluster) SetMaxObservers(v int) {
+	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.MaxObservers = uint64(v) })
+}
+
 // SetLocationLabels updates the LocationLabels configuration.
 func (mc *Cluster) SetLocationLabels(v []string) {
 	mc.updateReplicationConfig(func(r *config.ReplicationConfig) { r.LocationLabels = v })
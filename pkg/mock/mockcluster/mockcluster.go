@@ -27,6 +27,7 @@ import (
 	"github.com/tikv/pd/server/core/storelimit"
 	"github.com/tikv/pd/server/kv"
 	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/schedule/weight"
 	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/versioninfo"
 	"go.uber.org/zap"
@@ -42,6 +43,7 @@ type Cluster struct {
 	*config.PersistOptions
 	ID               uint64
 	suspectRegions   map[uint64]struct{}
+	weightManager    *weight.Manager
 	disabledFeatures map[versioninfo.Feature]struct{}
 }
 
@@ -50,10 +52,11 @@ func NewCluster(opts *config.PersistOptions) *Cluster {
 	return &Cluster{
 		BasicCluster:     core.NewBasicCluster(),
 		IDAllocator:      mockid.NewIDAllocator(),
-		HotCache:         statistics.NewHotCache(),
+		HotCache:         statistics.NewHotCache(opts),
 		StoresStats:      statistics.NewStoresStats(),
 		PersistOptions:   opts,
 		suspectRegions:   map[uint64]struct{}{},
+		weightManager:    weight.NewManager(core.NewStorage(kv.NewMemoryKV())),
 		disabledFeatures: make(map[versioninfo.Feature]struct{}),
 	}
 }
@@ -145,6 +148,16 @@ func (mc *Cluster) FitRegion(region *core.RegionInfo) *placement.RegionFit {
 	return mc.RuleManager.FitRegion(mc.BasicCluster, region)
 }
 
+// GetRegionWeightManager returns the region weight manager of the cluster.
+func (mc *Cluster) GetRegionWeightManager() *weight.Manager {
+	return mc.weightManager
+}
+
+// GetRegionWeight returns the scheduling weight of the region.
+func (mc *Cluster) GetRegionWeight(region *core.RegionInfo) float64 {
+	return mc.weightManager.GetRegionWeight(region)
+}
+
 // GetRuleManager returns the ruleManager of the cluster.
 func (mc *Cluster) GetRuleManager() *placement.RuleManager {
 	return mc.RuleManager
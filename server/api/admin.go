@@ -14,9 +14,12 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tikv/pd/pkg/apiutil"
@@ -56,6 +59,66 @@ func (h *adminHandler) HandleDropCacheRegion(w http.ResponseWriter, r *http.Requ
 	h.rd.JSON(w, http.StatusOK, "The region is removed from server cache.")
 }
 
+// @Tags admin
+// @Summary Ask the leader of a region to report the heartbeat of the region right away, so that the stale region in cache is refreshed. The leader is asked to transfer the leadership to itself, which changes nothing in the region.
+// @Param id path integer true "Region Id"
+// @Produce json
+// @Success 200 {string} string "The region is asked to report the heartbeat."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The region is not found, has no leader, or its leader can not be reached."
+// @Router /admin/region/{id}/heartbeat [post]
+func (h *adminHandler) HandleRegionHeartbeat(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	vars := mux.Vars(r)
+	regionIDStr := vars["id"]
+	regionID, err := strconv.ParseUint(regionIDStr, 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if missed := rc.WakeRegions([]uint64{regionID}); len(missed) > 0 {
+		h.rd.JSON(w, http.StatusNotFound, "The region is not found, has no leader, or its leader can not be reached.")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The region is asked to report the heartbeat.")
+}
+
+// @Tags admin
+// @Summary Drop the regions from cache, all regions are dropped if no region is specified. The dropped regions are repopulated by the following heartbeats, the scheduling pauses until most regions are back when all of them are dropped.
+// @Accept json
+// @Param body body object false "json params"
+// @Produce json
+// @Success 200 {string} string "The regions are removed from server cache."
+// @Failure 400 {string} string "The input is invalid."
+// @Router /admin/cache/regions [post]
+func (h *adminHandler) HandleDropCacheRegions(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	data, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var input struct {
+		RegionIDs []uint64 `json:"region_ids"`
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &input); err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if len(input.RegionIDs) == 0 {
+		rc.DropCacheAllRegion()
+		h.rd.JSON(w, http.StatusOK, "All regions are removed from server cache.")
+		return
+	}
+	for _, id := range input.RegionIDs {
+		rc.DropCacheRegion(id)
+	}
+	h.rd.JSON(w, http.StatusOK, "The regions are removed from server cache.")
+}
+
 // FIXME: details of input json body params
 // @Tags admin
 // @Summary Reset the ts.
@@ -141,3 +204,18 @@ func (h *adminHandler) UpdateWaitAsyncTime(w http.ResponseWriter, r *http.Reques
 	cluster.GetReplicationMode().UpdateMemberWaitAsyncTime(memberID)
 	h.rd.JSON(w, http.StatusOK, nil)
 }
+
+// @Tags admin
+// @Summary Reload the TLS certificate, key and CA files. The certificate and the key are used by the new connections even without the reload, while a changed CA only takes effect for the HTTP requests to other members, the listeners and the etcd and gRPC clients still need restarting for it. The request is handled by the leader, set the PD-Allow-follower-handle header to reload the files of a follower.
+// @Produce json
+// @Success 200 {object} map[string]time.Time "The expiration time of the certificate."
+// @Failure 500 {string} string "TLS is not enabled or the files are invalid."
+// @Router /admin/tls/reload [post]
+func (h *adminHandler) ReloadTLS(w http.ResponseWriter, r *http.Request) {
+	notAfter, err := h.svr.ReloadTLS()
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, map[string]time.Time{"cert_not_after": notAfter})
+}
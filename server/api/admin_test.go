@@ -83,6 +83,67 @@ func (s *testAdminSuite) TestDropRegion(c *C) {
 	c.Assert(region.GetRegionEpoch().Version, Equals, uint64(50))
 }
 
+func (s *testAdminSuite) TestDropRegions(c *C) {
+	cluster := s.svr.GetRaftCluster()
+	url := fmt.Sprintf("%s/admin/cache/regions", s.urlPrefix)
+
+	for _, input := range []string{`{"region_ids": [%d]}`, ``} {
+		region := cluster.GetRegionByKey([]byte("foo")).Clone(
+			core.SetRegionConfVer(200),
+			core.SetRegionVersion(200),
+		)
+		err := cluster.HandleRegionHeartbeat(region)
+		c.Assert(err, IsNil)
+		region = region.Clone(
+			core.SetRegionConfVer(150),
+			core.SetRegionVersion(150),
+		)
+		err = cluster.HandleRegionHeartbeat(region)
+		c.Assert(err, NotNil)
+
+		// After drop regions from cache, lower version is accepted.
+		if input != "" {
+			input = fmt.Sprintf(input, region.GetID())
+		}
+		err = postJSON(testDialClient, url, []byte(input))
+		c.Assert(err, IsNil)
+		c.Assert(cluster.GetRegion(region.GetID()), IsNil)
+		err = cluster.HandleRegionHeartbeat(region)
+		c.Assert(err, IsNil)
+		region = cluster.GetRegionByKey([]byte("foo"))
+		c.Assert(region.GetRegionEpoch().Version, Equals, uint64(150))
+	}
+
+	err := postJSON(testDialClient, url, []byte(`{"region_ids": "foo"}`))
+	c.Assert(err, NotNil)
+}
+
+func (s *testAdminSuite) TestRegionHeartbeat(c *C) {
+	region := s.svr.GetRaftCluster().GetRegionByKey([]byte("foo"))
+	for _, t := range []struct {
+		id     string
+		status int
+	}{
+		{"foo", http.StatusBadRequest},
+		{"10000", http.StatusNotFound},
+		// The store of the leader has not set up the heartbeat stream.
+		{fmt.Sprint(region.GetID()), http.StatusNotFound},
+	} {
+		url := fmt.Sprintf("%s/admin/region/%s/heartbeat", s.urlPrefix, t.id)
+		res, err := testDialClient.Post(url, "application/json", nil)
+		c.Assert(err, IsNil)
+		c.Assert(res.StatusCode, Equals, t.status)
+		res.Body.Close()
+	}
+}
+
+func (s *testAdminSuite) TestReloadTLS(c *C) {
+	// TLS is not enabled in the test server.
+	err := postJSON(testDialClient, s.urlPrefix+"/admin/tls/reload", nil)
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "TLS is not enabled"), IsTrue)
+}
+
 var _ = Suite(&testTSOSuite{})
 
 type testTSOSuite struct {
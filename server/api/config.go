@@ -22,10 +22,12 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/coreos/go-semver/semver"
 	"github.com/pingcap/errcode"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/log"
 	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
@@ -425,12 +427,36 @@ func (h *confHandler) GetClusterVersion(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, h.svr.GetClusterVersion())
 }
 
+// ClusterVersionStatus is the cluster version and the features it supports.
+type ClusterVersionStatus struct {
+	ClusterVersion  *semver.Version `json:"cluster-version"`
+	MinStoreVersion *semver.Version `json:"min-store-version,omitempty"`
+	Features        map[string]bool `json:"features,omitempty"`
+}
+
+// @Tags config
+// @Summary Get cluster version, the minimum version of stores and the supported features.
+// @Produce json
+// @Success 200 {object} ClusterVersionStatus
+// @Router /config/cluster-version/status [get]
+func (h *confHandler) GetClusterVersionStatus(w http.ResponseWriter, r *http.Request) {
+	version := h.svr.GetClusterVersion()
+	status := &ClusterVersionStatus{ClusterVersion: &version}
+	if rc := h.svr.GetRaftCluster(); rc != nil {
+		status.MinStoreVersion = rc.GetMinStoreVersion()
+		status.Features = rc.GetFeatureSupport()
+	}
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
 // @Tags config
 // @Summary Update cluster version.
 // @Accept json
 // @Param body body object string "json params"
+// @Param force query bool false "Whether to skip checking the versions of stores"
 // @Produce json
 // @Success 200 {string} string "The cluster version is updated."
+// @Failure 400 {string} string "The input is invalid."
 // @Failure 500 {string} string "PD server failed to proceed the request."
 // @Failure 503 {string} string "PD server has no leader."
 // @Router /config/cluster-version [post]
@@ -445,7 +471,16 @@ func (h *confHandler) SetClusterVersion(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := h.svr.SetClusterVersion(version)
+	var err error
+	if r.URL.Query().Get("force") == "true" {
+		err = h.svr.ForceSetClusterVersion(version)
+	} else {
+		err = h.svr.SetClusterVersion(version)
+	}
+	if errs.ErrClusterVersionTooHigh.Equal(err) {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		apiutil.ErrorResp(h.rd, w, errcode.NewInternalErr(err))
 		return
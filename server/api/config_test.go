@@ -14,8 +14,11 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"strings"
 	"time"
 
@@ -275,3 +278,57 @@ func (s *testConfigSuite) TestConfigDefault(c *C) {
 	c.Assert(defaultCfg.Schedule.RegionScheduleLimit, Equals, uint64(2048))
 	c.Assert(defaultCfg.PDServerCfg.MetricStorage, Equals, "")
 }
+
+var _ = Suite(&testClusterVersionSuite{})
+
+type testClusterVersionSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testClusterVersionSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+}
+
+func (s *testClusterVersionSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testClusterVersionSuite) TestSetClusterVersion(c *C) {
+	// The bootstrap store does not report its version, which is regarded as 1.0.0.
+	addr := fmt.Sprintf("%s/config/cluster-version", s.urlPrefix)
+	old := s.svr.GetClusterVersion()
+	postData, err := json.Marshal(map[string]string{"cluster-version": "2.0.0"})
+	c.Assert(err, IsNil)
+	resp, err := testDialClient.Post(addr, "application/json", bytes.NewBuffer(postData))
+	c.Assert(err, IsNil)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(strings.Contains(string(body), "higher than the minimum store version"), IsTrue)
+	c.Assert(s.svr.GetClusterVersion(), DeepEquals, old)
+
+	err = postJSON(testDialClient, addr+"?force=true", postData)
+	c.Assert(err, IsNil)
+	c.Assert(s.svr.GetClusterVersion().String(), Equals, "2.0.0")
+
+	status := &ClusterVersionStatus{}
+	err = readJSON(testDialClient, addr+"/status", status)
+	c.Assert(err, IsNil)
+	c.Assert(status.ClusterVersion.String(), Equals, "2.0.0")
+	c.Assert(status.MinStoreVersion.String(), Equals, "1.0.0")
+	c.Assert(status.Features, DeepEquals, map[string]bool{
+		"region-merge":    true,
+		"batch-split":     false,
+		"learner":         true,
+		"joint-consensus": false,
+	})
+}
@@ -17,10 +17,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/etcdutil"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
 )
@@ -35,6 +39,32 @@ type Recommendation struct {
 	Instruction string `json:"instruction"`
 }
 
+// CheckResult is the result of a check of the diagnosis. The status is pass if
+// nothing is found, otherwise it is decided by the most severe recommendation.
+// A check which can not be run fails with the error.
+type CheckResult struct {
+	Name            string            `json:"name"`
+	Status          string            `json:"status"`
+	Error           string            `json:"error,omitempty"`
+	Recommendations []*Recommendation `json:"recommendations"`
+}
+
+// DiagnoseReport is the report of the diagnosis, the status is the worst
+// status of the checks.
+type DiagnoseReport struct {
+	Status string         `json:"status"`
+	Checks []*CheckResult `json:"checks"`
+}
+
+// check statuses, from the best to the worst
+const (
+	statusPass = "pass"
+	statusWarn = "warn"
+	statusFail = "fail"
+)
+
+var statusRanks = map[string]int{statusPass: 0, statusWarn: 1, statusFail: 2}
+
 // lint:file-ignore U1000 document available levels and modules
 const (
 	// analyze levels
@@ -44,9 +74,11 @@ const (
 	levelCritical = "Critical"
 
 	// analyze modules
-	modMember  = "member"
-	modTiKV    = "TiKV"
-	modDefault = "Default"
+	modMember   = "member"
+	modTiKV     = "TiKV"
+	modConfig   = "config"
+	modSchedule = "schedule"
+	modDefault  = "Default"
 
 	memberOneInstance diagnoseType = iota
 	memberEvenInstance
@@ -58,20 +90,40 @@ const (
 	tikvCap90
 	tikvLostPeers
 	tikvLostPeersLongTime
+	memberClockDrift
+	memberEtcdSlow
+	configReplicasMoreThanStores
+	scheduleHeartbeatBacklog
+)
+
+const (
+	// maxClockDrift is the max tolerated clock drift between PD members, the
+	// drift is measured with the Date header which is accurate to the second.
+	maxClockDrift = 3 * time.Second
+	// clockProbeTimeout bounds the time waiting for a member to respond, so
+	// that an unresponsive member does not hang the diagnosis.
+	clockProbeTimeout = 3 * time.Second
+	// heartbeatBacklogThreshold is half of the capacity of the heartbeat stream.
+	heartbeatBacklogThreshold = 512
+	tikvLostLongTime          = time.Hour
 )
 
 var (
 	diagnoseMap = map[diagnoseType]Recommendation{
-		memberOneInstance:           {modMember, levelWarning, "only one PD instance is running.", "please add PD instance."},
-		memberEvenInstance:          {modMember, levelMinor, "PD instances is even number.", "the recommended number of PD's instances is odd."},
-		memberLostPeers:             {modMember, levelMajor, "some PD instances is down.", "please check host load and traffic."},
-		memberLostPeersMoreThanHalf: {modMember, levelCritical, "more than half PD instances is down.", "please check host load and traffic."},
-		memberLeaderChanged:         {modMember, levelMinor, "PD cluster leader is changed.", "please check host load and traffic."},
-		tikvCap70:                   {modTiKV, levelWarning, "some TiKV storage used more than 70%.", "please add TiKV node."},
-		tikvCap80:                   {modTiKV, levelMinor, "some TiKV storage used more than 80%.", "please add TiKV node."},
-		tikvCap90:                   {modTiKV, levelMajor, "some TiKV storage used more than 90%.", "please add TiKV node."},
-		tikvLostPeers:               {modTiKV, levelWarning, "some TiKV lost connect.", "please check network."},
-		tikvLostPeersLongTime:       {modTiKV, levelMajor, "some TiKV lost connect more than 1h.", "please check network."},
+		memberOneInstance:            {modMember, levelWarning, "only one PD instance is running.", "please add PD instance."},
+		memberEvenInstance:           {modMember, levelMinor, "PD instances is even number.", "the recommended number of PD's instances is odd."},
+		memberLostPeers:              {modMember, levelMajor, "some PD instances is down.", "please check host load and traffic."},
+		memberLostPeersMoreThanHalf:  {modMember, levelCritical, "more than half PD instances is down.", "please check host load and traffic."},
+		memberLeaderChanged:          {modMember, levelMinor, "PD cluster leader is changed.", "please check host load and traffic."},
+		tikvCap70:                    {modTiKV, levelWarning, "some TiKV storage used more than 70%.", "please add TiKV node."},
+		tikvCap80:                    {modTiKV, levelMinor, "some TiKV storage used more than 80%.", "please add TiKV node."},
+		tikvCap90:                    {modTiKV, levelMajor, "some TiKV storage used more than 90%.", "please add TiKV node."},
+		tikvLostPeers:                {modTiKV, levelWarning, "some TiKV lost connect.", "please check network."},
+		tikvLostPeersLongTime:        {modTiKV, levelMajor, "some TiKV lost connect more than 1h.", "please check network."},
+		memberClockDrift:             {modMember, levelMajor, "the clocks of some PD instances drift.", "please check the NTP service."},
+		memberEtcdSlow:               {modMember, levelMinor, "the etcd requests are slow.", "please check the disk latency and network."},
+		configReplicasMoreThanStores: {modConfig, levelMajor, "max-replicas is larger than the number of up TiKV.", "please add TiKV node or decrease max-replicas."},
+		scheduleHeartbeatBacklog:     {modSchedule, levelMinor, "too many heartbeat responses are waiting to be sent.", "please check the network between PD and TiKV."},
 	}
 )
 
@@ -156,17 +208,209 @@ func (d *diagnoseHandler) membersDiagnose(rdd *[]*Recommendation) error {
 	return nil
 }
 
+// clockDiagnose compares the clocks of the members with the local clock by the
+// Date header of the responses. The members are probed by the self health API,
+// which is served by each member rather than redirected to the leader.
+func (d *diagnoseHandler) clockDiagnose(rdd *[]*Recommendation) error {
+	req := &pdpb.GetMembersRequest{Header: &pdpb.RequestHeader{ClusterId: d.svr.ClusterID()}}
+	members, err := d.svr.GetMembers(context.Background(), req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var drifts []string
+	for _, m := range members.Members {
+		if len(m.ClientUrls) == 0 {
+			continue
+		}
+		drift, rtt, err := d.probeClock(m.ClientUrls[0])
+		if err != nil {
+			// The lost members are reported by membersDiagnose.
+			continue
+		}
+		if isClockDrifted(drift, rtt) {
+			drifts = append(drifts, fmt.Sprintf("%d(%s)", m.MemberId, drift.Truncate(time.Second)))
+		}
+	}
+	if len(drifts) > 0 {
+		*rdd = append(*rdd, diagnosePD(memberClockDrift, "members "+strings.Join(drifts, ", "), ""))
+	}
+	return nil
+}
+
+// probeClock returns the clock drift of the member and the round trip time.
+func (d *diagnoseHandler) probeClock(clientURL string) (time.Duration, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), clockProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, clientURL+apiPrefix+"/api/v1/self/health", nil)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	start := time.Now()
+	resp, err := d.svr.GetHTTPClient().Do(req)
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	resp.Body.Close()
+	rtt := time.Since(start)
+	drift, err := clockDrift(start, rtt, resp.Header.Get("Date"))
+	return drift, rtt, err
+}
+
+// clockDrift returns the absolute difference between the remote clock in the
+// Date header and the local clock at the middle of the round trip.
+func clockDrift(start time.Time, rtt time.Duration, date string) (time.Duration, error) {
+	remote, err := http.ParseTime(date)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	drift := remote.Sub(start.Add(rtt / 2))
+	if drift < 0 {
+		drift = -drift
+	}
+	return drift, nil
+}
+
+// isClockDrifted returns true if the drift can not be explained by the round
+// trip time and the precision of the Date header.
+func isClockDrifted(drift, rtt time.Duration) bool {
+	return drift > maxClockDrift+rtt/2
+}
+
+func (d *diagnoseHandler) etcdDiagnose(rdd *[]*Recommendation) error {
+	start := time.Now()
+	_, err := etcdutil.EtcdKVGet(d.svr.GetClient(), d.svr.GetMember().GetLeaderPath())
+	if cost := time.Since(start); err != nil || cost > etcdutil.DefaultSlowRequestTime {
+		*rdd = append(*rdd, diagnosePD(memberEtcdSlow, fmt.Sprintf("the request costs %s.", cost), ""))
+	}
+	return nil
+}
+
+func (d *diagnoseHandler) storesDiagnose(rdd *[]*Recommendation) error {
+	rc := d.svr.GetRaftCluster()
+	if rc == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	var cap70, cap80, cap90, lost, lostLongTime []string
+	for _, store := range rc.GetStores() {
+		if store.IsTombstone() {
+			continue
+		}
+		id := strconv.FormatUint(store.GetID(), 10)
+		if store.DownTime() > tikvLostLongTime {
+			lostLongTime = append(lostLongTime, id)
+		} else if store.IsDisconnected() {
+			lost = append(lost, id)
+		}
+		if store.GetCapacity() == 0 {
+			continue
+		}
+		switch used := 1 - store.AvailableRatio(); {
+		case used > 0.9:
+			cap90 = append(cap90, id)
+		case used > 0.8:
+			cap80 = append(cap80, id)
+		case used > 0.7:
+			cap70 = append(cap70, id)
+		}
+	}
+	for _, c := range []struct {
+		key diagnoseType
+		ids []string
+	}{
+		{tikvCap90, cap90},
+		{tikvCap80, cap80},
+		{tikvCap70, cap70},
+		{tikvLostPeersLongTime, lostLongTime},
+		{tikvLostPeers, lost},
+	} {
+		if len(c.ids) > 0 {
+			*rdd = append(*rdd, diagnosePD(c.key, "stores "+strings.Join(c.ids, ", "), ""))
+		}
+	}
+	return nil
+}
+
+func (d *diagnoseHandler) configDiagnose(rdd *[]*Recommendation) error {
+	rc := d.svr.GetRaftCluster()
+	if rc == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	var upCount int
+	for _, store := range rc.GetStores() {
+		if store.IsUp() && !store.IsDisconnected() {
+			upCount++
+		}
+	}
+	if maxReplicas := rc.GetOpts().GetMaxReplicas(); maxReplicas > upCount {
+		*rdd = append(*rdd, diagnosePD(configReplicasMoreThanStores, fmt.Sprintf("max-replicas %d, up stores %d", maxReplicas, upCount), ""))
+	}
+	return nil
+}
+
+func (d *diagnoseHandler) heartbeatDiagnose(rdd *[]*Recommendation) error {
+	rc := d.svr.GetRaftCluster()
+	if rc == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	if backlog := rc.GetHeartbeatStreams().MsgLength(); backlog > heartbeatBacklogThreshold {
+		*rdd = append(*rdd, diagnosePD(scheduleHeartbeatBacklog, fmt.Sprintf("%d responses are pending.", backlog), ""))
+	}
+	return nil
+}
+
+// levelStatus returns the check status of a recommendation level.
+func levelStatus(level string) string {
+	switch level {
+	case levelWarning, levelMinor:
+		return statusWarn
+	default:
+		return statusFail
+	}
+}
+
+// worseStatus returns the worse one of the two statuses.
+func worseStatus(a, b string) string {
+	if statusRanks[b] > statusRanks[a] {
+		return b
+	}
+	return a
+}
+
+// runCheck runs a check and decides its status.
+func runCheck(name string, check func(*[]*Recommendation) error) *CheckResult {
+	result := &CheckResult{Name: name, Status: statusPass, Recommendations: []*Recommendation{}}
+	if err := check(&result.Recommendations); err != nil {
+		result.Status = statusFail
+		result.Error = err.Error()
+		return result
+	}
+	for _, r := range result.Recommendations {
+		result.Status = worseStatus(result.Status, levelStatus(r.Level))
+	}
+	return result
+}
+
 // @Tags diagnose
-// @Summary Diagnostic information of the cluster.
+// @Summary Diagnose the cluster by the checks of the members, the clocks, the etcd latency, the stores, the config and the heartbeat backlog. Each check passes, warns or fails, and the report is as bad as the worst check.
 // @Produce json
-// @Success 200 {array} Recommendation
-// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Success 200 {object} DiagnoseReport
 // @Router /diagnose [get]
 func (d *diagnoseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	rdd := []*Recommendation{}
-	if err := d.membersDiagnose(&rdd); err != nil {
-		d.rd.JSON(w, http.StatusInternalServerError, err.Error())
-		return
+	report := &DiagnoseReport{Status: statusPass}
+	for _, c := range []struct {
+		name  string
+		check func(*[]*Recommendation) error
+	}{
+		{"members", d.membersDiagnose},
+		{"clock", d.clockDiagnose},
+		{"etcd", d.etcdDiagnose},
+		{"stores", d.storesDiagnose},
+		{"config", d.configDiagnose},
+		{"heartbeat", d.heartbeatDiagnose},
+	} {
+		result := runCheck(c.name, c.check)
+		report.Status = worseStatus(report.Status, result.Status)
+		report.Checks = append(report.Checks, result)
 	}
-	d.rd.JSON(w, http.StatusOK, rdd)
+	d.rd.JSON(w, http.StatusOK, report)
 }
@@ -15,7 +15,10 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"net/http"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/tikv/pd/server"
@@ -25,15 +28,24 @@ var _ = Suite(&testDiagnoseAPISuite{})
 
 type testDiagnoseAPISuite struct{}
 
-func checkDiagnoseResponse(c *C, body []byte) {
-	got := []Recommendation{}
-	c.Assert(json.Unmarshal(body, &got), IsNil)
-	for _, r := range got {
-		c.Assert(len(r.Module) != 0, IsTrue)
-		c.Assert(len(r.Level) != 0, IsTrue)
-		c.Assert(len(r.Description) != 0, IsTrue)
-		c.Assert(len(r.Instruction) != 0, IsTrue)
+func checkDiagnoseResponse(c *C, body []byte) *DiagnoseReport {
+	report := &DiagnoseReport{}
+	c.Assert(json.Unmarshal(body, report), IsNil)
+	c.Assert(report.Checks, HasLen, 6)
+	status := statusPass
+	for _, check := range report.Checks {
+		c.Assert(len(check.Name) != 0, IsTrue)
+		c.Assert(len(check.Status) != 0, IsTrue)
+		status = worseStatus(status, check.Status)
+		for _, r := range check.Recommendations {
+			c.Assert(len(r.Module) != 0, IsTrue)
+			c.Assert(len(r.Level) != 0, IsTrue)
+			c.Assert(len(r.Description) != 0, IsTrue)
+			c.Assert(len(r.Instruction) != 0, IsTrue)
+		}
 	}
+	c.Assert(report.Status, Equals, status)
+	return report
 }
 
 func (s *testDiagnoseAPISuite) TestDiagnoseSlice(c *C) {
@@ -57,3 +69,74 @@ func (s *testDiagnoseAPISuite) TestDiagnoseSlice(c *C) {
 	c.Assert(err, IsNil)
 	checkDiagnoseResponse(c, buf)
 }
+
+func (s *testDiagnoseAPISuite) TestDiagnoseCluster(c *C) {
+	svr, cleanup := mustNewServer(c)
+	defer cleanup()
+	mustWaitLeader(c, []*server.Server{svr})
+	mustBootstrapCluster(c, svr)
+
+	addr := svr.GetConfig().ClientUrls + apiPrefix + "/api/v1/diagnose"
+	resp, err := testDialClient.Get(addr)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	buf, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	report := checkDiagnoseResponse(c, buf)
+
+	checks := make(map[string]*CheckResult)
+	for _, check := range report.Checks {
+		checks[check.Name] = check
+	}
+	// The bootstrapped store never sends heartbeats, so the max replicas is
+	// larger than the number of up stores.
+	c.Assert(checks["config"].Status, Equals, statusFail)
+	c.Assert(checks["config"].Recommendations[0].Module, Equals, modConfig)
+	c.Assert(checks["stores"].Recommendations, Not(HasLen), 0)
+	c.Assert(report.Status, Equals, statusFail)
+}
+
+func (s *testDiagnoseAPISuite) TestRunCheck(c *C) {
+	for _, t := range []struct {
+		keys   []diagnoseType
+		err    error
+		status string
+	}{
+		{nil, nil, statusPass},
+		{[]diagnoseType{memberOneInstance, memberEvenInstance}, nil, statusWarn},
+		{[]diagnoseType{tikvCap70, tikvCap90}, nil, statusFail},
+		{nil, errors.New("failed"), statusFail},
+	} {
+		result := runCheck("test", func(rdd *[]*Recommendation) error {
+			for _, key := range t.keys {
+				*rdd = append(*rdd, diagnosePD(key, "", ""))
+			}
+			return t.err
+		})
+		c.Assert(result.Status, Equals, t.status)
+		c.Assert(result.Error != "", Equals, t.err != nil)
+	}
+}
+
+func (s *testDiagnoseAPISuite) TestClockDrift(c *C) {
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	rtt := 2 * time.Second
+	for _, t := range []struct {
+		remote  time.Time
+		drift   time.Duration
+		drifted bool
+	}{
+		// The remote clock is compared at the middle of the round trip.
+		{start.Add(time.Second), 0, false},
+		{start.Add(5 * time.Second), 4 * time.Second, false},
+		{start.Add(10 * time.Second), 9 * time.Second, true},
+		{start.Add(-10 * time.Second), 11 * time.Second, true},
+	} {
+		drift, err := clockDrift(start, rtt, t.remote.Format(http.TimeFormat))
+		c.Assert(err, IsNil)
+		c.Assert(drift, Equals, t.drift)
+		c.Assert(isClockDrifted(drift, rtt), Equals, t.drifted)
+	}
+	_, err := clockDrift(start, rtt, "")
+	c.Assert(err, NotNil)
+}
@@ -16,9 +16,11 @@ package api
 import (
 	"encoding/json"
 	"io/ioutil"
+	"net/http"
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
 )
@@ -69,3 +71,38 @@ func (s *testHealthAPISuite) TestHealthSlice(c *C) {
 	c.Assert(err, IsNil)
 	checkSliceResponse(c, buf, cfgs, follow.GetConfig().Name)
 }
+
+func (s *testHealthAPISuite) TestProbes(c *C) {
+	_, svrs, clean := mustNewCluster(c, 3)
+	defer clean()
+	var follow *server.Server
+	for _, svr := range svrs {
+		if !svr.GetMember().IsLeader() {
+			follow = svr
+			break
+		}
+	}
+	c.Assert(follow, NotNil)
+
+	// The probes are served by the follower itself.
+	for _, probe := range []string{"health", "ready"} {
+		addr := follow.GetConfig().ClientUrls + apiPrefix + "/api/v1/self/" + probe
+		status := ProbeStatus{}
+		testutil.WaitUntil(c, func(c *C) bool {
+			resp, err := testDialClient.Get(addr)
+			c.Assert(err, IsNil)
+			defer resp.Body.Close()
+			buf, err := ioutil.ReadAll(resp.Body)
+			c.Assert(err, IsNil)
+			c.Assert(json.Unmarshal(buf, &status), IsNil)
+			return resp.StatusCode == http.StatusOK
+		})
+		c.Assert(status.Name, Equals, follow.Name())
+		c.Assert(status.Serving, IsTrue)
+		if probe == "ready" {
+			c.Assert(status.LeaderKnown, IsTrue)
+			c.Assert(status.EtcdQuorum, IsTrue)
+			c.Assert(status.Ready, IsTrue)
+		}
+	}
+}
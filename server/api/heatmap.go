@@ -0,0 +1,98 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/heatmap"
+	"github.com/unrolled/render"
+)
+
+type heatmapHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newHeatmapHandler(svr *server.Server, rd *render.Render) *heatmapHandler {
+	return &heatmapHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags heatmap
+// @Summary Get the flow heatmap of a key range over time.
+// @Param tag query string false "One of written_bytes, read_bytes, written_keys and read_keys, default is written_bytes"
+// @Param start_key query string false "Start key"
+// @Param end_key query string false "End key"
+// @Param start_time query integer false "Start Unix timestamp, default is 0"
+// @Param end_time query integer false "End Unix timestamp, default is now"
+// @Param key_buckets query integer false "Max number of key ranges, default is 256"
+// @Param time_buckets query integer false "Max number of time ranges, default is unlimited"
+// @Produce json
+// @Success 200 {object} heatmap.Matrix
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 412 {string} string "The heatmap is disabled."
+// @Router /heatmap [get]
+func (h *heatmapHandler) Get(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	if !rc.GetOpts().IsHeatmapEnabled() {
+		h.rd.JSON(w, http.StatusPreconditionFailed, "heatmap is disabled")
+		return
+	}
+	query := r.URL.Query()
+	q := &heatmap.Query{
+		StartKey:  []byte(query.Get("start_key")),
+		EndKey:    []byte(query.Get("end_key")),
+		StartTime: time.Unix(0, 0),
+		EndTime:   time.Now(),
+	}
+	if tag := query.Get("tag"); tag != "" {
+		t, err := heatmap.ParseTag(tag)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		q.Tag = t
+	}
+	for name, t := range map[string]*time.Time{"start_time": &q.StartTime, "end_time": &q.EndTime} {
+		if str := query.Get(name); str != "" {
+			v, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				h.rd.JSON(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			*t = time.Unix(v, 0)
+		}
+	}
+	for name, n := range map[string]*int{"key_buckets": &q.KeyBuckets, "time_buckets": &q.TimeBuckets} {
+		if str := query.Get(name); str != "" {
+			v, err := strconv.Atoi(str)
+			if err != nil || v <= 0 {
+				h.rd.JSON(w, http.StatusBadRequest, "invalid "+name)
+				return
+			}
+			*n = v
+		}
+	}
+	if len(q.EndKey) > 0 && string(q.StartKey) >= string(q.EndKey) {
+		h.rd.JSON(w, http.StatusBadRequest, "start_key should be less than end_key")
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rc.GetHeatmap(q))
+}
@@ -15,6 +15,8 @@ package api
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
@@ -58,6 +60,45 @@ func (h *hotStatusHandler) GetHotReadRegions(w http.ResponseWriter, r *http.Requ
 	h.rd.JSON(w, http.StatusOK, h.Handler.GetHotReadRegions())
 }
 
+// @Tags hotspot
+// @Summary List the history of hot regions in a time range.
+// @Param start query integer false "Start Unix timestamp, default is 0"
+// @Param end query integer false "End Unix timestamp, default is now"
+// @Produce json
+// @Success 200 {array} core.HistoryHotRegion
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /hotspot/history [get]
+func (h *hotStatusHandler) GetHistoryHotRegions(w http.ResponseWriter, r *http.Request) {
+	start, end := time.Unix(0, 0), time.Now()
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		startInt, err := strconv.ParseInt(startStr, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		start = time.Unix(startInt, 0)
+	}
+	if endStr := r.URL.Query().Get("end"); endStr != "" {
+		endInt, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		end = time.Unix(endInt, 0)
+	}
+	if !start.Before(end) {
+		h.rd.JSON(w, http.StatusBadRequest, "start should be earlier than end")
+		return
+	}
+	regions, err := h.Handler.GetHistoryHotRegions(start, end)
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, regions)
+}
+
 // @Tags hotspot
 // @Summary List the hot stores.
 // @Produce json
@@ -15,9 +15,11 @@ package api
 
 import (
 	"fmt"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/core"
 	_ "github.com/tikv/pd/server/schedulers"
 )
 
@@ -48,3 +50,26 @@ func (s testHotStatusSuite) TestGetHotStore(c *C) {
 	err := readJSON(testDialClient, s.urlPrefix+"/stores", &stat)
 	c.Assert(err, IsNil)
 }
+
+func (s testHotStatusSuite) TestGetHistoryHotRegions(c *C) {
+	now := time.Now()
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	regions := []*core.HistoryHotRegion{
+		{UpdateTime: ms(now.Add(-2 * time.Hour)), RegionID: 1, StoreID: 1, HotRegionType: "read"},
+		{UpdateTime: ms(now.Add(-time.Hour)), RegionID: 2, StoreID: 1, HotRegionType: "write"},
+	}
+	c.Assert(s.svr.GetHotRegionStorage().SaveHotRegions(regions), IsNil)
+
+	var history []*core.HistoryHotRegion
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/history", &history), IsNil)
+	c.Assert(history, HasLen, 2)
+
+	url := fmt.Sprintf("%s/history?start=%d&end=%d", s.urlPrefix, now.Add(-90*time.Minute).Unix(), now.Unix())
+	c.Assert(readJSON(testDialClient, url, &history), IsNil)
+	c.Assert(history, HasLen, 1)
+	c.Assert(history[0].RegionID, Equals, uint64(2))
+
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/history?start=abc", &history), NotNil)
+	url = fmt.Sprintf("%s/history?start=%d&end=%d", s.urlPrefix, now.Unix(), now.Add(-time.Hour).Unix())
+	c.Assert(readJSON(testDialClient, url, &history), NotNil)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
 )
 
 var _ = Suite(&testLabelsStoreSuite{})
@@ -177,6 +178,51 @@ func (s *testLabelsStoreSuite) TestStoresLabelFilter(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testLabelsStoreSuite) TestTopology(c *C) {
+	url := fmt.Sprintf("%s/labels/topology?labels=disk,zone", s.urlPrefix)
+	topo := &Topology{}
+	err := readJSON(testDialClient, url, topo)
+	c.Assert(err, IsNil)
+	c.Assert(topo.LocationLabels, DeepEquals, []string{"disk", "zone"})
+	c.Assert(topo.Root.StoreCount, Equals, len(s.stores))
+	c.Assert(topo.Root.Health["Up"], Equals, len(s.stores))
+	c.Assert(topo.Root.Children, HasLen, 2)
+	hdd, ssd := topo.Root.Children[0], topo.Root.Children[1]
+	c.Assert(hdd.Label, Equals, "disk")
+	c.Assert(hdd.Value, Equals, "hdd")
+	c.Assert(hdd.StoreCount, Equals, 1)
+	c.Assert(ssd.Value, Equals, "ssd")
+	c.Assert(ssd.StoreCount, Equals, 3)
+	c.Assert(ssd.Children, HasLen, 3)
+	c.Assert(ssd.Children[0].Value, Equals, "beijing")
+	leaf := ssd.Children[0].Children[0]
+	c.Assert(leaf.Label, Equals, topologyStoreLabel)
+	c.Assert(leaf.Value, Equals, "6")
+	c.Assert(topo.Warnings, HasLen, 0)
+
+	// The stores missing the label are put under the empty value.
+	url = fmt.Sprintf("%s/labels/topology?labels=other", s.urlPrefix)
+	topo = &Topology{}
+	err = readJSON(testDialClient, url, topo)
+	c.Assert(err, IsNil)
+	c.Assert(topo.Root.Children, HasLen, 2)
+	c.Assert(topo.Root.Children[0].Value, Equals, "")
+	c.Assert(topo.Root.Children[0].StoreCount, Equals, 3)
+	c.Assert(topo.Warnings, HasLen, 3)
+
+	// All the stores reporting the same rack is suspicious.
+	var stores []*core.StoreInfo
+	for i := uint64(1); i <= 3; i++ {
+		stores = append(stores, core.NewStoreInfo(&metapb.Store{
+			Id:     i,
+			Labels: []*metapb.StoreLabel{{Key: "zone", Value: fmt.Sprintf("z%d", i)}, {Key: "rack", Value: "r1"}},
+		}))
+	}
+	topo = newTopology(s.svr.GetScheduleConfig(), []string{"zone", "rack"}, stores)
+	c.Assert(topo.Root.Children, HasLen, 3)
+	c.Assert(topo.Warnings, DeepEquals, []string{"all the stores have the same rack r1"})
+}
+
 type testStrictlyLabelsStoreSuite struct {
 	svr       *server.Server
 	cleanup   cleanUpFunc
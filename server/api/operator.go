@@ -16,9 +16,14 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/unrolled/render"
@@ -27,6 +32,9 @@ import (
 type operatorHandler struct {
 	*server.Handler
 	r *render.Render
+	// idempotencyMu serializes the requests with idempotency keys, so that
+	// the concurrent retries can not create duplicate operators.
+	idempotencyMu sync.Mutex
 }
 
 func newOperatorHandler(handler *server.Handler, r *render.Render) *operatorHandler {
@@ -39,6 +47,7 @@ func newOperatorHandler(handler *server.Handler, r *render.Render) *operatorHand
 // @Tags operator
 // @Summary Get a Region's pending operator.
 // @Param region_id path int true "A Region's Id"
+// @Param detail query bool false "Return the structured detail of the running operator, including the steps and the remaining influence."
 // @Produce json
 // @Success 200 {object} schedule.OperatorWithStatus
 // @Failure 400 {string} string "The input is invalid."
@@ -53,6 +62,16 @@ func (h *operatorHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.URL.Query().Get("detail") == "true" {
+		detail, err := h.GetOperatorDetail(regionID)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusOK, detail)
+		return
+	}
+
 	op, err := h.GetOperatorStatus(regionID)
 	if err != nil {
 		h.r.JSON(w, http.StatusInternalServerError, err.Error())
@@ -64,51 +83,68 @@ func (h *operatorHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 // @Tags operator
 // @Summary List pending operators.
-// @Param kind query string false "Specify the operator kind." Enums(admin, leader, region)
+// @Param kind query string false "Specify the operator kind, such as admin, leader, region or waiting. Multiple kinds can be joined by ',', such as admin,region."
 // @Produce json
 // @Success 200 {array} operator.Operator
+// @Failure 400 {string} string "The input is invalid."
 // @Failure 500 {string} string "PD server failed to proceed the request."
 // @Router /operators [get]
 func (h *operatorHandler) List(w http.ResponseWriter, r *http.Request) {
-	var (
-		results []*operator.Operator
-		ops     []*operator.Operator
-		err     error
-	)
-
 	kinds, ok := r.URL.Query()["kind"]
 	if !ok {
-		results, err = h.GetOperators()
+		results, err := h.GetOperators()
 		if err != nil {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
-	} else {
-		for _, kind := range kinds {
-			switch kind {
-			case "admin":
-				ops, err = h.GetAdminOperators()
-			case "leader":
-				ops, err = h.GetLeaderOperators()
-			case "region":
-				ops, err = h.GetRegionOperators()
-			case "waiting":
-				ops, err = h.GetWaitingOperators()
+		h.r.JSON(w, http.StatusOK, results)
+		return
+	}
+
+	// The kinds are merged into one mask, so an operator of several kinds is
+	// listed only once.
+	var (
+		mask    operator.OpKind
+		waiting bool
+	)
+	for _, kind := range kinds {
+		for _, name := range strings.Split(kind, ",") {
+			if strings.TrimSpace(name) == "waiting" {
+				waiting = true
+				continue
 			}
+			k, err := operator.ParseOperatorKind(name)
 			if err != nil {
-				h.r.JSON(w, http.StatusInternalServerError, err.Error())
+				h.r.JSON(w, http.StatusBadRequest, err.Error())
 				return
 			}
-			results = append(results, ops...)
+			mask |= k
 		}
 	}
 
+	var results []*operator.Operator
+	if mask != 0 {
+		ops, err := h.GetOperatorsOfKind(mask)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		results = append(results, ops...)
+	}
+	if waiting {
+		ops, err := h.GetWaitingOperators()
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		results = append(results, ops...)
+	}
 	h.r.JSON(w, http.StatusOK, results)
 }
 
 // FIXME: details of input json body params
 // @Tags operator
-// @Summary Create an operator.
+// @Summary Create an operator. If idempotency_key is set, the retried requests with the same key do not create operators again. The keys are kept in the memory of the PD leader for 10 minutes, like the operators, so a request retried after the leader changes creates the operator again. If wait is set, such as 30s, it returns the status after the operator ends or the wait timeout. Wait is not supported by merge-region and scatter-regions, which involve more than one region.
 // @Accept json
 // @Param body body object true "json params"
 // @Produce json
@@ -128,6 +164,41 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var wait time.Duration
+	if v, ok := input["wait"].(string); ok {
+		var err error
+		if wait, err = parseWaitTimeout(v); err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// Only the operators of a single region can be waited on, as the
+		// response is the status of one operator.
+		if name == "merge-region" || name == "scatter-regions" {
+			h.r.JSON(w, http.StatusBadRequest, "wait is not supported by "+name)
+			return
+		}
+	}
+
+	key, _ := input["idempotency_key"].(string)
+	unlock := func() {}
+	defer func() { unlock() }()
+	if key != "" {
+		h.idempotencyMu.Lock()
+		unlock = h.idempotencyMu.Unlock
+		used, err := h.IsOperatorIdempotencyKeyUsed(key)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if used {
+			h.r.JSON(w, http.StatusOK, "The operator is already created.")
+			return
+		}
+	}
+
+	// regionIDs records the regions of the created operators.
+	var regionIDs []uint64
+
 	switch name {
 	case "transfer-leader":
 		regionID, ok := input["region_id"].(float64)
@@ -141,9 +212,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddTransferLeaderOperator(uint64(regionID), uint64(storeID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "transfer-region":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -160,9 +232,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddTransferRegionOperator(uint64(regionID), storeIDs); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "transfer-peer":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -180,9 +253,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddTransferPeerOperator(uint64(regionID), uint64(fromID), uint64(toID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "add-peer":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -195,9 +269,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddAddPeerOperator(uint64(regionID), uint64(storeID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "add-learner":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -210,9 +285,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddAddLearnerOperator(uint64(regionID), uint64(storeID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "remove-peer":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -225,9 +301,10 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if err := h.AddRemovePeerOperator(uint64(regionID), uint64(storeID)); err != nil {
-			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			h.respondOperatorError(w, err)
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "merge-region":
 		regionID, ok := input["source_region_id"].(float64)
 		if !ok {
@@ -243,6 +320,7 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID), uint64(targetID))
 	case "split-region":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -269,6 +347,7 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "scatter-region":
 		regionID, ok := input["region_id"].(float64)
 		if !ok {
@@ -280,22 +359,29 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		regionIDs = append(regionIDs, uint64(regionID))
 	case "scatter-regions":
 		// support both receiving key ranges or regionIDs
 		startKey, _ := input["start_key"].(string)
 		endKey, _ := input["end_key"].(string)
-		regionIDs, _ := input["region_ids"].([]uint64)
+		ids, _ := input["region_ids"].([]uint64)
 		group, _ := input["group"].(string)
 		retryLimit, ok := input["retry_limit"].(int)
 		if !ok {
 			// retry 5 times if retryLimit not defined
 			retryLimit = 5
 		}
-		processedPercentage, err := h.AddScatterRegionsOperators(regionIDs, startKey, endKey, group, retryLimit)
+		processedPercentage, scattered, err := h.AddScatterRegionsOperators(ids, startKey, endKey, group, retryLimit)
 		errorMessage := ""
 		if err != nil {
 			errorMessage = err.Error()
 		}
+		if key != "" && len(scattered) > 0 {
+			if err := h.SetOperatorIdempotencyKey(key, scattered...); err != nil {
+				h.r.JSON(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+		}
 		s := struct {
 			ProcessedPercentage int    `json:"processed-percentage"`
 			Error               string `json:"error"`
@@ -309,9 +395,74 @@ func (h *operatorHandler) Post(w http.ResponseWriter, r *http.Request) {
 		h.r.JSON(w, http.StatusBadRequest, "unknown operator")
 		return
 	}
+	if key != "" {
+		if err := h.SetOperatorIdempotencyKey(key, regionIDs...); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if wait > 0 && len(regionIDs) == 1 {
+		// Do not block the other requests with idempotency keys while waiting.
+		unlock()
+		unlock = func() {}
+		op, err := h.WaitOperator(r.Context(), regionIDs[0], wait)
+		if err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		h.r.JSON(w, http.StatusOK, op)
+		return
+	}
 	h.r.JSON(w, http.StatusOK, "The operator is created.")
 }
 
+// @Tags operator
+// @Summary Wait until the operator of the region ends, and get its status.
+// @Param region_id path int true "A Region's Id"
+// @Param timeout query string false "The max time to wait, such as 30s." default(30s)
+// @Produce json
+// @Success 200 {object} schedule.OperatorWithStatus
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /operators/{region_id}/wait [get]
+func (h *operatorHandler) Wait(w http.ResponseWriter, r *http.Request) {
+	regionID, err := strconv.ParseUint(mux.Vars(r)["region_id"], 10, 64)
+	if err != nil {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	timeout := defaultWaitOperatorTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		if timeout, err = parseWaitTimeout(v); err != nil {
+			h.r.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	op, err := h.WaitOperator(r.Context(), regionID, timeout)
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, op)
+}
+
+const (
+	defaultWaitOperatorTimeout = 30 * time.Second
+	maxWaitOperatorTimeout     = 10 * time.Minute
+)
+
+func parseWaitTimeout(s string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 || timeout > maxWaitOperatorTimeout {
+		return 0, errors.Errorf("the wait timeout should be in (0, %s]", maxWaitOperatorTimeout)
+	}
+	return timeout, nil
+}
+
 // @Tags operator
 // @Summary Cancel a Region's pending operator.
 // @Param region_id path int true "A Region's Id"
@@ -352,3 +503,13 @@ func parseStoreIDs(v interface{}) (map[uint64]struct{}, bool) {
 	}
 	return ids, true
 }
+
+// respondOperatorError responds the error of creating an operator. The
+// operators rejected by the validation are bad requests.
+func (h *operatorHandler) respondOperatorError(w http.ResponseWriter, err error) {
+	if errs.ErrInvalidOperatorPlan.Equal(err) {
+		h.r.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusInternalServerError, err.Error())
+}
@@ -14,9 +14,11 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"strings"
 
 	. "github.com/pingcap/check"
@@ -79,6 +81,13 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 	operator = mustReadURL(c, regionURL)
 	c.Assert(strings.Contains(operator, "add learner peer 1 on store 3"), IsTrue)
 	c.Assert(strings.Contains(operator, "RUNNING"), IsTrue)
+	detail := make(map[string]interface{})
+	c.Assert(readJSON(testDialClient, regionURL+"?detail=true", &detail), IsNil)
+	c.Assert(detail["region-id"], Equals, 1.0)
+	c.Assert(detail["status"], Equals, "Started")
+	c.Assert(detail["current-step"], Equals, 0.0)
+	c.Assert(detail["steps"].([]interface{})[0].(map[string]interface{})["step"], Equals, "add learner peer 1 on store 3")
+	c.Assert(detail["influence"].(map[string]interface{}), HasKey, "3")
 
 	_, err = doDelete(testDialClient, regionURL)
 	c.Assert(err, IsNil)
@@ -98,6 +107,72 @@ func (s *testOperatorSuite) TestAddRemovePeer(c *C) {
 	operator = mustReadURL(c, regionURL)
 	c.Assert(strings.Contains(operator, "add learner peer 2 on store 4"), IsTrue)
 
+	_, err = doDelete(testDialClient, regionURL)
+	c.Assert(err, IsNil)
+
+	// The retried request with the same idempotency key does not create operator again.
+	input := []byte(`{"name":"transfer-leader", "region_id": 1, "to_store_id": 2, "idempotency_key": "k1"}`)
+	err = postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), input, func(res []byte, _ int) {
+		c.Assert(strings.Contains(string(res), "The operator is created."), IsTrue)
+	})
+	c.Assert(err, IsNil)
+	operator = mustReadURL(c, regionURL)
+	c.Assert(strings.Contains(operator, "idempotency-key:k1"), IsTrue)
+	operators := mustReadURL(c, fmt.Sprintf("%s/operators", s.urlPrefix))
+	c.Assert(strings.Contains(operators, "idempotency-key:k1"), IsTrue)
+	_, err = doDelete(testDialClient, regionURL)
+	c.Assert(err, IsNil)
+	err = postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), input, func(res []byte, _ int) {
+		c.Assert(strings.Contains(string(res), "The operator is already created."), IsTrue)
+	})
+	c.Assert(err, IsNil)
+	operator = mustReadURL(c, regionURL)
+	c.Assert(strings.Contains(operator, "CANCEL"), IsTrue)
+
+	// Wait for the operator to end.
+	input = []byte(`{"name":"transfer-leader", "region_id": 1, "to_store_id": 2, "wait": "200ms"}`)
+	err = postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), input, func(res []byte, _ int) {
+		c.Assert(strings.Contains(string(res), "status: RUNNING"), IsTrue)
+	})
+	c.Assert(err, IsNil)
+	// The operators of more than one region can not be waited on.
+	input = []byte(`{"name":"merge-region", "source_region_id": 1, "target_region_id": 2, "wait": "200ms"}`)
+	err = postJSON(testDialClient, fmt.Sprintf("%s/operators", s.urlPrefix), input)
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "wait is not supported by merge-region"), IsTrue)
+	operator = mustReadURL(c, regionURL+"/wait?timeout=100ms")
+	c.Assert(strings.Contains(operator, "status: RUNNING"), IsTrue)
+	c.Assert(strings.Contains(operator, "transfer leader from store 1 to store 2"), IsTrue)
+	_, err = doDelete(testDialClient, regionURL)
+	c.Assert(err, IsNil)
+	operator = mustReadURL(c, regionURL+"/wait")
+	c.Assert(strings.Contains(operator, "status: CANCEL"), IsTrue)
+	res, err := testDialClient.Get(regionURL + "/wait?timeout=1h")
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+
+	// List operators by composed kinds.
+	res, err = testDialClient.Get(fmt.Sprintf("%s/operators?kind=admin,leader", s.urlPrefix))
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	res, err = testDialClient.Get(fmt.Sprintf("%s/operators?kind=admin,unknown", s.urlPrefix))
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+
+	// The operator adding peer to the offline store is rejected by the validation.
+	c.Assert(s.svr.GetRaftCluster().RemoveStore(4, false), IsNil)
+	res, err = testDialClient.Post(fmt.Sprintf("%s/operators", s.urlPrefix), "application/json",
+		bytes.NewBufferString(`{"name":"add-peer", "region_id": 1, "store_id": 4}`))
+	c.Assert(err, IsNil)
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(strings.Contains(string(body), "store 4 is offline"), IsTrue)
+
 	// Fail to add peer to tombstone store.
 	err = s.svr.GetRaftCluster().BuryStore(3, true)
 	c.Assert(err, IsNil)
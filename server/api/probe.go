@@ -0,0 +1,105 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tikv/pd/server"
+	"github.com/unrolled/render"
+)
+
+// readyCheckTimeout is the timeout of the quorum read in the readiness probe,
+// which should be shorter than the timeout of the probes.
+const readyCheckTimeout = time.Second
+
+// The probes are served by the member itself and are never redirected to the
+// leader, so they can be used as the liveness and readiness probes of each PD
+// member, e.g. by Kubernetes and load balancers.
+type probeHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+// ProbeStatus is the result of the probes of a PD member.
+type ProbeStatus struct {
+	Name string `json:"name"`
+	// Serving means the member is running and not closed.
+	Serving bool `json:"serving"`
+	// LeaderKnown means the member knows which member is the PD leader.
+	LeaderKnown bool `json:"leader_known"`
+	// EtcdQuorum means a quorum read of the embedded etcd succeeds.
+	EtcdQuorum bool `json:"etcd_quorum"`
+	// Ready means the member can serve requests.
+	Ready bool `json:"ready"`
+}
+
+func newProbeHandler(svr *server.Server, rd *render.Render) *probeHandler {
+	return &probeHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags probe
+// @Summary The liveness of the PD member handling the request.
+// @Produce json
+// @Success 200 {object} ProbeStatus
+// @Failure 503 {object} ProbeStatus "The member is not serving."
+// @Router /self/health [get]
+func (h *probeHandler) Health(w http.ResponseWriter, r *http.Request) {
+	status := ProbeStatus{
+		Name:    h.svr.Name(),
+		Serving: !h.svr.IsClosed(),
+	}
+	if !status.Serving {
+		h.rd.JSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
+// @Tags probe
+// @Summary The readiness of the PD member handling the request. The member is ready when it is serving, the PD leader is known and the quorum of etcd is available.
+// @Produce json
+// @Success 200 {object} ProbeStatus
+// @Failure 503 {object} ProbeStatus "The member is not ready."
+// @Router /self/ready [get]
+func (h *probeHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	status := ProbeStatus{
+		Name:    h.svr.Name(),
+		Serving: !h.svr.IsClosed(),
+	}
+	if status.Serving {
+		status.LeaderKnown = h.svr.GetMember().GetLeader() != nil
+		status.EtcdQuorum = h.checkEtcdQuorum(r.Context())
+	}
+	status.Ready = status.Serving && status.LeaderKnown && status.EtcdQuorum
+	if !status.Ready {
+		h.rd.JSON(w, http.StatusServiceUnavailable, status)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, status)
+}
+
+// checkEtcdQuorum checks the quorum of etcd with a linearizable read, which
+// succeeds only when the etcd leader is elected and the quorum is reachable.
+func (h *probeHandler) checkEtcdQuorum(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+	defer cancel()
+	_, err := h.svr.GetClient().Get(ctx, h.svr.GetMember().GetLeaderPath())
+	return err == nil
+}
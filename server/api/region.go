@@ -52,6 +52,7 @@ type RegionInfo struct {
 	ApproximateKeys int64             `json:"approximate_keys"`
 
 	ReplicationStatus *ReplicationStatus `json:"replication_status,omitempty"`
+	Hibernated        bool               `json:"hibernated,omitempty"`
 }
 
 // ReplicationStatus represents the replication mode status of the region.
@@ -96,6 +97,7 @@ func InitRegion(r *core.RegionInfo, s *RegionInfo) *RegionInfo {
 	s.ApproximateSize = r.GetApproximateSize()
 	s.ApproximateKeys = r.GetApproximateKeys()
 	s.ReplicationStatus = fromPBReplicationStatus(r.GetReplicationStatus())
+	s.Hibernated = r.IsHibernated()
 
 	return s
 }
@@ -578,6 +580,50 @@ func (h *regionsHandler) GetTopSize(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// @Tags region
+// @Summary List all hibernated regions, whose leaders reduce the heartbeat frequency because they are idle.
+// @Produce json
+// @Success 200 {object} RegionsInfo
+// @Router /regions/hibernated [get]
+func (h *regionsHandler) GetHibernatedRegions(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	var regions []*core.RegionInfo
+	for _, region := range rc.GetRegions() {
+		if region.IsHibernated() {
+			regions = append(regions, region)
+		}
+	}
+	h.rd.JSON(w, http.StatusOK, convertToAPIRegions(regions))
+}
+
+// @Tags region
+// @Summary Wake up the hibernated regions, so that they report the fresh state by the following heartbeats.
+// @Accept json
+// @Param body body object true "json params, e.g. {\"region_ids\": [1, 2]}"
+// @Produce json
+// @Success 200 {string} string "The regions are woken up."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "Some regions are not woken up, because they are not found, have no leader, or their leaders can not be reached."
+// @Router /regions/wake [post]
+func (h *regionsHandler) WakeRegions(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	var input struct {
+		RegionIDs []uint64 `json:"region_ids"`
+	}
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+		return
+	}
+	if len(input.RegionIDs) == 0 {
+		h.rd.JSON(w, http.StatusBadRequest, "empty regions")
+		return
+	}
+	if missed := rc.WakeRegions(input.RegionIDs); len(missed) > 0 {
+		h.rd.JSON(w, http.StatusNotFound, fmt.Sprintf("regions %v are not woken up, they are not found, have no leader, or their leaders can not be reached", missed))
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The regions are woken up.")
+}
+
 // @Tags region
 // @Summary Accelerate regions scheduling a in given range, only receive hex format for keys
 // @Accept json
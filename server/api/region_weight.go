@@ -0,0 +1,129 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule/weight"
+	"github.com/unrolled/render"
+)
+
+type regionWeightHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newRegionWeightHandler(svr *server.Server, rd *render.Render) *regionWeightHandler {
+	return &regionWeightHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags region_weight
+// @Summary List all key range weights of the regions.
+// @Produce json
+// @Success 200 {array} weight.KeyRangeWeight
+// @Router /config/region-weights [get]
+func (h *regionWeightHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r.Context())
+	h.rd.JSON(w, http.StatusOK, cluster.GetRegionWeightManager().GetWeights())
+}
+
+// @Tags region_weight
+// @Summary Get the key range weight by ID.
+// @Param id path string true "Weight Id"
+// @Produce json
+// @Success 200 {object} weight.KeyRangeWeight
+// @Failure 404 {string} string "The weight does not exist."
+// @Router /config/region-weight/{id} [get]
+func (h *regionWeightHandler) Get(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r.Context())
+	rw := cluster.GetRegionWeightManager().GetWeight(mux.Vars(r)["id"])
+	if rw == nil {
+		h.rd.JSON(w, http.StatusNotFound, nil)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, rw)
+}
+
+// @Tags region_weight
+// @Summary Get the scheduling weight of a region.
+// @Param id path integer true "Region Id"
+// @Produce json
+// @Success 200 {number} float64
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The region does not exist."
+// @Router /config/region-weight/region/{id} [get]
+func (h *regionWeightHandler) GetByRegion(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r.Context())
+	regionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, "invalid region id")
+		return
+	}
+	region := cluster.GetRegion(regionID)
+	if region == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrRegionNotFound(regionID).Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, cluster.GetRegionWeight(region))
+}
+
+// @Tags region_weight
+// @Summary Update the key range weight.
+// @Accept json
+// @Param weight body weight.KeyRangeWeight true "Parameters of the key range weight"
+// @Produce json
+// @Success 200 {string} string "Update region weight successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/region-weight [post]
+func (h *regionWeightHandler) Set(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r.Context())
+	var rw weight.KeyRangeWeight
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &rw); err != nil {
+		return
+	}
+	if err := rw.Adjust(); err != nil {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := cluster.GetRegionWeightManager().SetWeight(&rw); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Update region weight successfully.")
+}
+
+// @Tags region_weight
+// @Summary Delete the key range weight.
+// @Param id path string true "Weight Id"
+// @Produce json
+// @Success 200 {string} string "Delete region weight successfully."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/region-weight/{id} [delete]
+func (h *regionWeightHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	cluster := getCluster(r.Context())
+	if err := cluster.GetRegionWeightManager().DeleteWeight(mux.Vars(r)["id"]); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Delete region weight successfully.")
+}
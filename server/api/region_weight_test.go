@@ -0,0 +1,80 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule/weight"
+)
+
+var _ = Suite(&testRegionWeightSuite{})
+
+type testRegionWeightSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testRegionWeightSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/config", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+}
+
+func (s *testRegionWeightSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testRegionWeightSuite) TestRegionWeight(c *C) {
+	rw := weight.KeyRangeWeight{ID: "t1", StartKeyHex: "61", EndKeyHex: "63", Weight: 2}
+	data, err := json.Marshal(rw)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/region-weight", data), IsNil)
+	// Invalid weight.
+	rw.Weight = -1
+	data, err = json.Marshal(rw)
+	c.Assert(err, IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/region-weight", data), NotNil)
+
+	var weights []*weight.KeyRangeWeight
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/region-weights", &weights), IsNil)
+	c.Assert(weights, HasLen, 1)
+	c.Assert(weights[0].Weight, Equals, 2.0)
+	var got weight.KeyRangeWeight
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/region-weight/t1", &got), IsNil)
+	c.Assert(got.StartKeyHex, Equals, "61")
+
+	mustRegionHeartbeat(c, s.svr, newTestRegionInfo(2, 1, []byte("a"), []byte("b")))
+	var regionWeight float64
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/region-weight/region/2", &regionWeight), IsNil)
+	c.Assert(regionWeight, Equals, 2.0)
+
+	res, err := doDelete(testDialClient, s.urlPrefix+"/region-weight/t1")
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/region-weight/region/2", &regionWeight), IsNil)
+	c.Assert(regionWeight, Equals, weight.DefaultWeight)
+	c.Assert(readJSON(testDialClient, s.urlPrefix+"/region-weight/t1", &got), NotNil)
+}
@@ -60,6 +60,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	apiRouter.HandleFunc("/operators", operatorHandler.List).Methods("GET")
 	apiRouter.HandleFunc("/operators", operatorHandler.Post).Methods("POST")
 	apiRouter.HandleFunc("/operators/{region_id}", operatorHandler.Get).Methods("GET")
+	apiRouter.HandleFunc("/operators/{region_id}/wait", operatorHandler.Wait).Methods("GET")
 	apiRouter.HandleFunc("/operators/{region_id}", operatorHandler.Delete).Methods("DELETE")
 
 	schedulerHandler := newSchedulerHandler(svr, rd)
@@ -67,6 +68,9 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	apiRouter.HandleFunc("/schedulers", schedulerHandler.Post).Methods("POST")
 	apiRouter.HandleFunc("/schedulers/{name}", schedulerHandler.Delete).Methods("DELETE")
 	apiRouter.HandleFunc("/schedulers/{name}", schedulerHandler.PauseOrResume).Methods("POST")
+	apiRouter.HandleFunc("/schedulers/{name}/namespace", schedulerHandler.SetNamespace).Methods("POST")
+	apiRouter.HandleFunc("/schedulers/{name}/stores", schedulerHandler.GetStoreList).Methods("GET")
+	apiRouter.HandleFunc("/schedulers/{name}/stores", schedulerHandler.SetStoreList).Methods("POST")
 
 	schedulerConfigHandler := newSchedulerConfigHandler(svr, rd)
 	apiRouter.PathPrefix("/scheduler-config").Handler(schedulerConfigHandler)
@@ -87,6 +91,7 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	apiRouter.HandleFunc("/config/label-property", confHandler.SetLabelProperty).Methods("POST")
 	apiRouter.HandleFunc("/config/cluster-version", confHandler.GetClusterVersion).Methods("GET")
 	apiRouter.HandleFunc("/config/cluster-version", confHandler.SetClusterVersion).Methods("POST")
+	apiRouter.HandleFunc("/config/cluster-version/status", confHandler.GetClusterVersionStatus).Methods("GET")
 	apiRouter.HandleFunc("/config/replication-mode", confHandler.GetReplicationMode).Methods("GET")
 	apiRouter.HandleFunc("/config/replication-mode", confHandler.SetReplicationMode).Methods("POST")
 
@@ -106,6 +111,21 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/config/rule_group/{id}", rulesHandler.DeleteGroupConfig).Methods("DELETE")
 	clusterRouter.HandleFunc("/config/rule_groups", rulesHandler.GetAllGroupConfigs).Methods("GET")
 
+	regionWeightHandler := newRegionWeightHandler(svr, rd)
+	clusterRouter.HandleFunc("/config/region-weights", regionWeightHandler.GetAll).Methods("GET")
+	clusterRouter.HandleFunc("/config/region-weight/region/{id}", regionWeightHandler.GetByRegion).Methods("GET")
+	clusterRouter.HandleFunc("/config/region-weight/{id}", regionWeightHandler.Get).Methods("GET")
+	clusterRouter.HandleFunc("/config/region-weight", regionWeightHandler.Set).Methods("POST")
+	clusterRouter.HandleFunc("/config/region-weight/{id}", regionWeightHandler.Delete).Methods("DELETE")
+
+	storeConfigHandler := newStoreConfigHandler(svr, rd)
+	clusterRouter.HandleFunc("/config/store-config", storeConfigHandler.GetGlobal).Methods("GET")
+	clusterRouter.HandleFunc("/config/store-config", storeConfigHandler.UpdateGlobal).Methods("POST")
+	clusterRouter.HandleFunc("/config/store-config/store/{id}", storeConfigHandler.GetStoreConfig).Methods("GET")
+	clusterRouter.HandleFunc("/config/store-config/store/{id}/override", storeConfigHandler.GetStoreOverride).Methods("GET")
+	clusterRouter.HandleFunc("/config/store-config/store/{id}", storeConfigHandler.UpdateStoreOverride).Methods("POST")
+	clusterRouter.HandleFunc("/config/store-config/store/{id}", storeConfigHandler.DeleteStoreOverride).Methods("DELETE")
+
 	clusterRouter.HandleFunc("/config/placement-rule", rulesHandler.GetAllGroupBundles).Methods("GET")
 	clusterRouter.HandleFunc("/config/placement-rule", rulesHandler.SetAllGroupBundles).Methods("POST")
 	// {group} can be a regular expression, we should enable path encode to
@@ -122,22 +142,37 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/store/{id}/label", storeHandler.SetLabels).Methods("POST")
 	clusterRouter.HandleFunc("/store/{id}/weight", storeHandler.SetWeight).Methods("POST")
 	clusterRouter.HandleFunc("/store/{id}/limit", storeHandler.SetLimit).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/restart", storeHandler.SetRestart).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/restart", storeHandler.CancelRestart).Methods("DELETE")
+	clusterRouter.HandleFunc("/store/{id}/drain", storeHandler.Drain).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/drain", storeHandler.CancelDrain).Methods("DELETE")
+	clusterRouter.HandleFunc("/store/{id}/pause", storeHandler.PauseScheduling).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/pause", storeHandler.ResumeScheduling).Methods("DELETE")
+	clusterRouter.HandleFunc("/store/{id}/cordon", storeHandler.Cordon).Methods("POST")
+	clusterRouter.HandleFunc("/store/{id}/cordon", storeHandler.Uncordon).Methods("DELETE")
 	storesHandler := newStoresHandler(handler, rd)
 	clusterRouter.Handle("/stores", storesHandler).Methods("GET")
 	clusterRouter.HandleFunc("/stores/remove-tombstone", storesHandler.RemoveTombStone).Methods("DELETE")
+	clusterRouter.HandleFunc("/stores/destroyed", storesHandler.GetDestroyed).Methods("GET")
+	clusterRouter.HandleFunc("/stores/destroyed/{id}", storesHandler.RemoveDestroyed).Methods("DELETE")
 	clusterRouter.HandleFunc("/stores/limit", storesHandler.GetAllLimit).Methods("GET")
 	clusterRouter.HandleFunc("/stores/limit", storesHandler.SetAllLimit).Methods("POST")
 	clusterRouter.HandleFunc("/stores/limit/scene", storesHandler.SetStoreLimitScene).Methods("POST")
 	clusterRouter.HandleFunc("/stores/limit/scene", storesHandler.GetStoreLimitScene).Methods("GET")
+	clusterRouter.HandleFunc("/stores/balance-progress", storesHandler.GetBalanceProgress).Methods("GET")
+	clusterRouter.HandleFunc("/stores/{id}/score", storesHandler.GetScore).Methods("GET")
+	clusterRouter.HandleFunc("/stores/drain", storesHandler.GetDrainProgress).Methods("GET")
 
 	labelsHandler := newLabelsHandler(svr, rd)
 	clusterRouter.HandleFunc("/labels", labelsHandler.Get).Methods("GET")
 	clusterRouter.HandleFunc("/labels/stores", labelsHandler.GetStores).Methods("GET")
+	clusterRouter.HandleFunc("/labels/topology", labelsHandler.GetTopology).Methods("GET")
 
 	hotStatusHandler := newHotStatusHandler(handler, rd)
 	apiRouter.HandleFunc("/hotspot/regions/write", hotStatusHandler.GetHotWriteRegions).Methods("GET")
 	apiRouter.HandleFunc("/hotspot/regions/read", hotStatusHandler.GetHotReadRegions).Methods("GET")
 	apiRouter.HandleFunc("/hotspot/stores", hotStatusHandler.GetHotStores).Methods("GET")
+	apiRouter.HandleFunc("/hotspot/history", hotStatusHandler.GetHistoryHotRegions).Methods("GET")
 
 	regionHandler := newRegionHandler(svr, rd)
 	clusterRouter.HandleFunc("/region/id/{id}", regionHandler.GetRegionByID).Methods("GET")
@@ -167,6 +202,8 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 	clusterRouter.HandleFunc("/regions/check/hist-size", regionsHandler.GetSizeHistogram).Methods("GET")
 	clusterRouter.HandleFunc("/regions/check/hist-keys", regionsHandler.GetKeysHistogram).Methods("GET")
 	clusterRouter.HandleFunc("/regions/sibling/{id}", regionsHandler.GetRegionSiblings).Methods("GET")
+	clusterRouter.HandleFunc("/regions/hibernated", regionsHandler.GetHibernatedRegions).Methods("GET")
+	clusterRouter.HandleFunc("/regions/wake", regionsHandler.WakeRegions).Methods("POST")
 	clusterRouter.HandleFunc("/regions/accelerate-schedule", regionsHandler.AccelerateRegionsScheduleInRange).Methods("POST")
 	clusterRouter.HandleFunc("/regions/scatter", regionsHandler.ScatterRegions).Methods("POST")
 
@@ -186,15 +223,22 @@ func createRouter(ctx context.Context, prefix string, svr *server.Server) *mux.R
 
 	statsHandler := newStatsHandler(svr, rd)
 	clusterRouter.HandleFunc("/stats/region", statsHandler.Region).Methods("GET")
+	clusterRouter.HandleFunc("/stats/region/group", statsHandler.RegionGroup).Methods("GET")
+
+	heatmapHandler := newHeatmapHandler(svr, rd)
+	clusterRouter.HandleFunc("/heatmap", heatmapHandler.Get).Methods("GET")
 
 	trendHandler := newTrendHandler(svr, rd)
 	apiRouter.HandleFunc("/trend", trendHandler.Handle).Methods("GET")
 
 	adminHandler := newAdminHandler(svr, rd)
 	clusterRouter.HandleFunc("/admin/cache/region/{id}", adminHandler.HandleDropCacheRegion).Methods("DELETE")
+	clusterRouter.HandleFunc("/admin/cache/regions", adminHandler.HandleDropCacheRegions).Methods("POST")
+	clusterRouter.HandleFunc("/admin/region/{id}/heartbeat", adminHandler.HandleRegionHeartbeat).Methods("POST")
 	clusterRouter.HandleFunc("/admin/reset-ts", adminHandler.ResetTS).Methods("POST")
 	apiRouter.HandleFunc("/admin/persist-file/{file_name}", adminHandler.persistFile).Methods("POST")
 	clusterRouter.HandleFunc("/admin/replication_mode/wait-async", adminHandler.UpdateWaitAsyncTime).Methods("POST")
+	apiRouter.HandleFunc("/admin/tls/reload", adminHandler.ReloadTLS).Methods("POST")
 
 	logHandler := newLogHandler(svr, rd)
 	apiRouter.HandleFunc("/admin/log", logHandler.Handle).Methods("POST")
@@ -24,6 +24,7 @@ import (
 	"github.com/tikv/pd/pkg/apiutil"
 	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedulers"
 	"github.com/unrolled/render"
 )
@@ -196,6 +197,11 @@ func (h *schedulerHandler) Post(w http.ResponseWriter, r *http.Request) {
 			h.r.JSON(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+	case schedulers.LoadSplitName:
+		if err := h.AddLoadSplitScheduler(); err != nil {
+			h.r.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 	case schedulers.ShuffleHotRegionName:
 		limit := uint64(1)
 		l, ok := input["limit"].(float64)
@@ -308,6 +314,90 @@ func (h *schedulerHandler) PauseOrResume(w http.ResponseWriter, r *http.Request)
 	h.r.JSON(w, http.StatusOK, "Pause or resume the scheduler successfully.")
 }
 
+// @Tags scheduler
+// @Summary Bind a scheduler to a namespace, an empty namespace unbinds it.
+// @Accept json
+// @Param name path string true "The name of the scheduler."
+// @Param body body object true "json params"
+// @Produce json
+// @Success 200 {string} string "Set the namespace of the scheduler successfully."
+// @Failure 400 {string} string "Bad format request."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/namespace [post]
+func (h *schedulerHandler) SetNamespace(w http.ResponseWriter, r *http.Request) {
+	var input map[string]string
+	if err := apiutil.ReadJSONRespondError(h.r, w, r.Body, &input); err != nil {
+		return
+	}
+
+	name := mux.Vars(r)["name"]
+	namespace, ok := input["namespace"]
+	if !ok {
+		h.r.JSON(w, http.StatusBadRequest, "missing namespace")
+		return
+	}
+	if err := h.SetSchedulerNamespace(name, namespace); err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Set the namespace of the scheduler successfully.")
+}
+
+// @Tags scheduler
+// @Summary Limit the stores used by a scheduler, an empty list removes the limit.
+// @Accept json
+// @Param name path string true "The name of the scheduler."
+// @Param body body schedule.StoreList true "The stores allowed and denied"
+// @Produce json
+// @Success 200 {string} string "Set the store list of the scheduler successfully."
+// @Failure 400 {string} string "Bad format request."
+// @Failure 404 {string} string "The scheduler is not found."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/stores [post]
+func (h *schedulerHandler) SetStoreList(w http.ResponseWriter, r *http.Request) {
+	var list schedule.StoreList
+	if err := apiutil.ReadJSONRespondError(h.r, w, r.Body, &list); err != nil {
+		return
+	}
+	stores, err := h.GetStores()
+	if err != nil {
+		h.r.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	exists := make(map[uint64]struct{}, len(stores))
+	for _, s := range stores {
+		exists[s.GetID()] = struct{}{}
+	}
+	for _, id := range append(list.Allow, list.Deny...) {
+		if _, ok := exists[id]; !ok {
+			h.r.JSON(w, http.StatusBadRequest, fmt.Sprintf("store %d not found", id))
+			return
+		}
+	}
+	if err := h.SetSchedulerStoreList(mux.Vars(r)["name"], &list); err != nil {
+		h.handleErr(w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, "Set the store list of the scheduler successfully.")
+}
+
+// @Tags scheduler
+// @Summary Get the stores allowed and denied for a scheduler.
+// @Param name path string true "The name of the scheduler."
+// @Produce json
+// @Success 200 {object} schedule.StoreList
+// @Failure 404 {string} string "The scheduler is not found."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /schedulers/{name}/stores [get]
+func (h *schedulerHandler) GetStoreList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.GetSchedulerStoreList(mux.Vars(r)["name"])
+	if err != nil {
+		h.handleErr(w, err)
+		return
+	}
+	h.r.JSON(w, http.StatusOK, list)
+}
+
 type schedulerConfigHandler struct {
 	svr *server.Server
 	rd  *render.Render
@@ -98,7 +98,34 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 		args          []arg
 		extraTestFunc func(name string, c *C)
 	}{
-		{name: "balance-leader-scheduler"},
+		{
+			name: "balance-leader-scheduler",
+			extraTestFunc: func(name string, c *C) {
+				resp := make(map[string]interface{})
+				listURL := fmt.Sprintf("%s%s%s/%s/list", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["batch"], Equals, 4.0)
+
+				updateURL := fmt.Sprintf("%s%s%s/%s/config", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				body, err := json.Marshal(map[string]interface{}{"batch": 8})
+				c.Assert(err, IsNil)
+				c.Assert(postJSON(testDialClient, updateURL, body), IsNil)
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["batch"], Equals, 8.0)
+
+				// The invalid value is rejected and the config is kept.
+				body, err = json.Marshal(map[string]interface{}{"batch": 11})
+				c.Assert(err, IsNil)
+				c.Assert(postJSON(testDialClient, updateURL, body), ErrorMatches, "(?s).*should be in.*")
+				body, err = json.Marshal(map[string]interface{}{"ranges": []interface{}{}})
+				c.Assert(err, IsNil)
+				c.Assert(postJSON(testDialClient, updateURL, body), ErrorMatches, "(?s).*not found.*")
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["batch"], Equals, 8.0)
+			},
+		},
 		{
 			name: "balance-hot-region-scheduler",
 			extraTestFunc: func(name string, c *C) {
@@ -110,6 +137,9 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 					"min-hot-key-rate":          10,
 					"max-zombie-rounds":         3,
 					"max-peer-number":           1000,
+					"min-hot-degree":            0,
+					"max-peer-operators":        0,
+					"max-leader-operators":      0,
 					"byte-rate-rank-step-ratio": 0.05,
 					"key-rate-rank-step-ratio":  0.05,
 					"count-rank-step-ratio":     0.01,
@@ -137,9 +167,41 @@ func (s *testScheduleSuite) TestAPI(c *C) {
 					c.Assert(code, Equals, 200)
 				})
 				c.Assert(err, IsNil)
+				// The invalid value is rejected and the config is kept.
+				body, err = json.Marshal(map[string]interface{}{"min-hot-degree": -1})
+				c.Assert(err, IsNil)
+				err = postJSON(testDialClient, updateURL, body)
+				c.Assert(err, ErrorMatches, "(?s).*should not be negative.*")
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["min-hot-degree"], Equals, 0.0)
+			},
+		},
+		{
+			name: "balance-region-scheduler",
+			extraTestFunc: func(name string, c *C) {
+				resp := make(map[string]interface{})
+				listURL := fmt.Sprintf("%s%s%s/%s/list", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["dimension"], Equals, "size")
+
+				updateURL := fmt.Sprintf("%s%s%s/%s/config", s.svr.GetAddr(), apiPrefix, server.SchedulerConfigHandlerPath, name)
+				body, err := json.Marshal(map[string]interface{}{"dimension": "flow"})
+				c.Assert(err, IsNil)
+				c.Assert(postJSON(testDialClient, updateURL, body), IsNil)
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["dimension"], Equals, "flow")
+
+				// The invalid value is rejected and the config is kept.
+				body, err = json.Marshal(map[string]interface{}{"dimension": "cpu"})
+				c.Assert(err, IsNil)
+				c.Assert(postJSON(testDialClient, updateURL, body), ErrorMatches, "(?s).*should be one of.*")
+				resp = make(map[string]interface{})
+				c.Assert(readJSON(testDialClient, listURL, &resp), IsNil)
+				c.Assert(resp["dimension"], Equals, "flow")
 			},
 		},
-		{name: "balance-region-scheduler"},
 		{name: "shuffle-leader-scheduler"},
 		{name: "shuffle-region-scheduler"},
 		{
@@ -32,6 +32,10 @@ func NewHandler(ctx context.Context, svr *server.Server) (http.Handler, server.S
 		IsCore: true,
 	}
 	router := mux.NewRouter()
+	// The probes are served by each member without the redirection to the leader.
+	probeHandler := newProbeHandler(svr, createIndentRender())
+	router.HandleFunc(apiPrefix+"/api/v1/self/health", probeHandler.Health).Methods("GET")
+	router.HandleFunc(apiPrefix+"/api/v1/self/ready", probeHandler.Ready).Methods("GET")
 	r := createRouter(ctx, apiPrefix, svr)
 	router.PathPrefix(apiPrefix).Handler(negroni.New(
 		serverapi.NewRuntimeServiceValidator(svr, group),
@@ -15,6 +15,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tikv/pd/server"
@@ -37,6 +38,9 @@ func newServiceGCSafepointHandler(svr *server.Server, rd *render.Render) *servic
 type listServiceGCSafepoint struct {
 	ServiceGCSafepoints []*core.ServiceSafePoint `json:"service_gc_safe_points"`
 	GCSafePoint         uint64                   `json:"gc_safe_point"`
+	// MinServiceGCSafePoint is the minimum safe point of the unexpired
+	// services, GC should not go beyond it.
+	MinServiceGCSafePoint uint64 `json:"min_service_gc_safe_point"`
 }
 
 // @Tags servicegcsafepoint
@@ -57,9 +61,15 @@ func (h *serviceGCSafepointHandler) List(w http.ResponseWriter, r *http.Request)
 		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	min, err := storage.LoadMinServiceGCSafePoint(time.Now())
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	list := listServiceGCSafepoint{
-		GCSafePoint:         gcSafepoint,
-		ServiceGCSafepoints: ssps,
+		GCSafePoint:           gcSafepoint,
+		ServiceGCSafepoints:   ssps,
+		MinServiceGCSafePoint: min.SafePoint,
 	}
 	h.rd.JSON(w, http.StatusOK, list)
 }
@@ -70,7 +70,8 @@ func (s *testServiceGCSafepointSuite) TestRegionStats(c *C) {
 				SafePoint: 3,
 			},
 		},
-		GCSafePoint: 1,
+		GCSafePoint:           1,
+		MinServiceGCSafePoint: 1,
 	}
 	for _, ssp := range list.ServiceGCSafepoints {
 		err := storage.SaveServiceGCSafePoint(ssp)
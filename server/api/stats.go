@@ -15,9 +15,12 @@ package api
 
 import (
 	"net/http"
+	"strconv"
 
+	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/server"
 	"github.com/unrolled/render"
+	"go.etcd.io/etcd/clientv3"
 )
 
 type statsHandler struct {
@@ -45,3 +48,41 @@ func (h *statsHandler) Region(w http.ResponseWriter, r *http.Request) {
 	stats := rc.GetRegionStats([]byte(startKey), []byte(endKey))
 	h.rd.JSON(w, http.StatusOK, stats)
 }
+
+// @Tags stats
+// @Summary Get region statistics of a table or a key prefix.
+// @Param table_id query integer false "Table ID"
+// @Param prefix query string false "Key prefix"
+// @Produce json
+// @Success 200 {object} statistics.RegionStats
+// @Failure 400 {string} string "The input is invalid."
+// @Router /stats/region/group [get]
+func (h *statsHandler) RegionGroup(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	tableIDStr, prefix := r.URL.Query().Get("table_id"), r.URL.Query().Get("prefix")
+	var startKey, endKey []byte
+	switch {
+	case tableIDStr != "" && prefix != "":
+		h.rd.JSON(w, http.StatusBadRequest, "table_id and prefix can not be specified at the same time")
+		return
+	case tableIDStr != "":
+		tableID, err := strconv.ParseInt(tableIDStr, 10, 64)
+		if err != nil {
+			h.rd.JSON(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		startKey = codec.EncodeBytes(codec.GenerateTableKey(tableID))
+		endKey = codec.EncodeBytes(codec.GenerateTableKey(tableID + 1))
+	case prefix != "":
+		startKey = []byte(prefix)
+		// The range end of a prefix consisting of 0xff is the end of the key space.
+		if end := clientv3.GetPrefixRangeEnd(prefix); end != "\x00" {
+			endKey = []byte(end)
+		}
+	default:
+		h.rd.JSON(w, http.StatusBadRequest, "table_id or prefix should be specified")
+		return
+	}
+	stats := rc.GetRegionStats(startKey, endKey)
+	h.rd.JSON(w, http.StatusOK, stats)
+}
@@ -113,6 +113,8 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 			&metapb.Peer{Id: 108, StoreId: 4},
 			core.SetApproximateSize(50),
 			core.SetApproximateKeys(20),
+			core.SetWrittenBytes(1024),
+			core.SetWrittenKeys(64),
 		),
 	}
 
@@ -138,6 +140,8 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 		StoreLeaderKeys:  map[uint64]int64{1: 50, 4: 170, 5: 1},
 		StorePeerSize:    map[uint64]int64{1: 301, 2: 100, 3: 100, 4: 250, 5: 201},
 		StorePeerKeys:    map[uint64]int64{1: 201, 2: 50, 3: 50, 4: 170, 5: 151},
+		WrittenBytes:     1024,
+		WrittenKeys:      64,
 	}
 	res, err := testDialClient.Get(statsURL)
 	c.Assert(err, IsNil)
@@ -174,4 +178,21 @@ func (s *testStatsSuite) TestRegionStats(c *C) {
 	err = apiutil.ReadJSON(res.Body, stats)
 	c.Assert(err, IsNil)
 	c.Assert(stats, DeepEquals, stats23)
+
+	// Group by key prefix.
+	groupURL := statsURL + "/group"
+	stats = &statistics.RegionStats{}
+	c.Assert(readJSON(testDialClient, groupURL+"?prefix=a", stats), IsNil)
+	c.Assert(stats.Count, Equals, 1)
+	c.Assert(stats.StoreLeaderCount, DeepEquals, map[uint64]int{4: 1})
+
+	// Group by table, table keys are in region 3.
+	stats = &statistics.RegionStats{}
+	c.Assert(readJSON(testDialClient, groupURL+"?table_id=1", stats), IsNil)
+	c.Assert(stats.Count, Equals, 1)
+	c.Assert(stats.StoreLeaderCount, DeepEquals, map[uint64]int{5: 1})
+
+	c.Assert(readJSON(testDialClient, groupURL, stats), NotNil)
+	c.Assert(readJSON(testDialClient, groupURL+"?prefix=a&table_id=1", stats), NotNil)
+	c.Assert(readJSON(testDialClient, groupURL+"?table_id=abc", stats), NotNil)
 }
@@ -41,24 +41,31 @@ type MetaStore struct {
 
 // StoreStatus contains status about a store.
 type StoreStatus struct {
-	Capacity           typeutil.ByteSize  `json:"capacity"`
-	Available          typeutil.ByteSize  `json:"available"`
-	UsedSize           typeutil.ByteSize  `json:"used_size"`
-	LeaderCount        int                `json:"leader_count"`
-	LeaderWeight       float64            `json:"leader_weight"`
-	LeaderScore        float64            `json:"leader_score"`
-	LeaderSize         int64              `json:"leader_size"`
-	RegionCount        int                `json:"region_count"`
-	RegionWeight       float64            `json:"region_weight"`
-	RegionScore        float64            `json:"region_score"`
-	RegionSize         int64              `json:"region_size"`
-	SendingSnapCount   uint32             `json:"sending_snap_count,omitempty"`
-	ReceivingSnapCount uint32             `json:"receiving_snap_count,omitempty"`
-	ApplyingSnapCount  uint32             `json:"applying_snap_count,omitempty"`
-	IsBusy             bool               `json:"is_busy,omitempty"`
-	StartTS            *time.Time         `json:"start_ts,omitempty"`
-	LastHeartbeatTS    *time.Time         `json:"last_heartbeat_ts,omitempty"`
-	Uptime             *typeutil.Duration `json:"uptime,omitempty"`
+	Capacity            typeutil.ByteSize  `json:"capacity"`
+	Available           typeutil.ByteSize  `json:"available"`
+	UsedSize            typeutil.ByteSize  `json:"used_size"`
+	LeaderCount         int                `json:"leader_count"`
+	LeaderWeight        float64            `json:"leader_weight"`
+	LeaderScore         float64            `json:"leader_score"`
+	LeaderSize          int64              `json:"leader_size"`
+	RegionCount         int                `json:"region_count"`
+	RegionWeight        float64            `json:"region_weight"`
+	RegionScore         float64            `json:"region_score"`
+	RegionSize          int64              `json:"region_size"`
+	SendingSnapCount    uint32             `json:"sending_snap_count,omitempty"`
+	ReceivingSnapCount  uint32             `json:"receiving_snap_count,omitempty"`
+	ApplyingSnapCount   uint32             `json:"applying_snap_count,omitempty"`
+	IsBusy              bool               `json:"is_busy,omitempty"`
+	StartTS             *time.Time         `json:"start_ts,omitempty"`
+	LastHeartbeatTS     *time.Time         `json:"last_heartbeat_ts,omitempty"`
+	LastRegionHeartbeat *time.Time         `json:"last_region_heartbeat_ts,omitempty"`
+	Uptime              *typeutil.Duration `json:"uptime,omitempty"`
+	RestartDeadline     *time.Time         `json:"restart_deadline,omitempty"`
+	PauseDeadline       *time.Time         `json:"pause_deadline,omitempty"`
+	Cordoned            bool               `json:"cordoned,omitempty"`
+	CordonDeadline      *time.Time         `json:"cordon_deadline,omitempty"`
+	BackoffDeadline     *time.Time         `json:"backoff_deadline,omitempty"`
+	PhysicallyDestroyed bool               `json:"physically_destroyed,omitempty"`
 }
 
 // StoreInfo contains information about a store.
@@ -68,8 +75,9 @@ type StoreInfo struct {
 }
 
 const (
-	disconnectedName = "Disconnected"
-	downStateName    = "Down"
+	disconnectedName  = "Disconnected"
+	downStateName     = "Down"
+	cordonedStateName = "Cordoned"
 )
 
 func newStoreInfo(opt *config.ScheduleConfig, store *core.StoreInfo) *StoreInfo {
@@ -88,7 +96,7 @@ func newStoreInfo(opt *config.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 			LeaderSize:         store.GetLeaderSize(),
 			RegionCount:        store.GetRegionCount(),
 			RegionWeight:       store.GetRegionWeight(),
-			RegionScore:        store.RegionScore(opt.HighSpaceRatio, opt.LowSpaceRatio, 0),
+			RegionScore:        store.RegionScore(opt.RegionScoreFormulaVersion, opt.GetRegionScoreWeights(), opt.HighSpaceRatio, opt.LowSpaceRatio, 0),
 			RegionSize:         store.GetRegionSize(),
 			SendingSnapCount:   store.GetSendingSnapCount(),
 			ReceivingSnapCount: store.GetReceivingSnapCount(),
@@ -104,16 +112,40 @@ func newStoreInfo(opt *config.ScheduleConfig, store *core.StoreInfo) *StoreInfo
 	if lastHeartbeat := store.GetLastHeartbeatTS(); !lastHeartbeat.IsZero() {
 		s.Status.LastHeartbeatTS = &lastHeartbeat
 	}
+	if lastRegionHeartbeat := store.GetLastRegionHeartbeatTS(); !lastRegionHeartbeat.IsZero() {
+		s.Status.LastRegionHeartbeat = &lastRegionHeartbeat
+	}
 	if upTime := store.GetUptime(); upTime > 0 {
 		duration := typeutil.NewDuration(upTime)
 		s.Status.Uptime = &duration
 	}
+	s.Status.PhysicallyDestroyed = store.IsPhysicallyDestroyed()
+	if store.IsRestarting() {
+		deadline := store.GetRestartDeadline()
+		s.Status.RestartDeadline = &deadline
+	}
+	if store.IsSchedulingPaused() {
+		deadline := store.GetPauseDeadline()
+		s.Status.PauseDeadline = &deadline
+	}
+	if store.IsBackingOff() {
+		deadline := store.GetBackoffDeadline()
+		s.Status.BackoffDeadline = &deadline
+	}
+	if store.IsCordoned() {
+		s.Status.Cordoned = true
+		if deadline := store.GetCordonDeadline(); !deadline.IsZero() {
+			s.Status.CordonDeadline = &deadline
+		}
+	}
 
 	if store.GetState() == metapb.StoreState_Up {
 		if store.DownTime() > opt.MaxStoreDownTime.Duration {
 			s.Store.StateName = downStateName
 		} else if store.IsDisconnected() {
 			s.Store.StateName = disconnectedName
+		} else if store.IsCordoned() {
+			s.Store.StateName = cordonedStateName
 		}
 	}
 	return s
@@ -168,6 +200,8 @@ func (h *storeHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Tags store
 // @Summary Take down a store from the cluster.
 // @Param id path integer true "Store Id"
+// @Param force query string false "Mark the store as Tombstone directly"
+// @Param physically_destroyed query string false "The store will never come back, its peers are replaced without waiting and its address can not be registered again"
 // @Produce json
 // @Success 200 {string} string "The store is set as Offline or Tombstone."
 // @Failure 400 {string} string "The input is invalid."
@@ -189,7 +223,8 @@ func (h *storeHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	if force {
 		err = rc.BuryStore(storeID, force)
 	} else {
-		err = rc.RemoveStore(storeID)
+		_, physicallyDestroyed := r.URL.Query()["physically_destroyed"]
+		err = rc.RemoveStore(storeID, physicallyDestroyed)
 	}
 
 	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
@@ -396,6 +431,330 @@ func (h *storeHandler) SetLimit(w http.ResponseWriter, r *http.Request) {
 	h.rd.JSON(w, http.StatusOK, "The store's label is updated.")
 }
 
+// defaultStoreRestartGracePeriod is the grace period of restarting a store if
+// it is not specified.
+const defaultStoreRestartGracePeriod = 10 * time.Minute
+
+// @Tags store
+// @Summary Mark the store is going to restart. Its leaders are evicted and its down peers are not replaced in the grace period.
+// @Param id path integer true "Store Id"
+// @Param body body object false "json params, the grace period is 10m if it is not set"
+// @Produce json
+// @Success 200 {string} string "The store is marked as restarting."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/restart [post]
+func (h *storeHandler) SetRestart(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	grace := defaultStoreRestartGracePeriod
+	var input map[string]string
+	if r.ContentLength > 0 {
+		if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+			return
+		}
+	}
+	if v, ok := input["grace-period"]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid grace period")
+			return
+		}
+		grace = d
+	}
+
+	if err := rc.SetStoreRestart(storeID, grace); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store is marked as restarting.")
+}
+
+// @Tags store
+// @Summary Cancel the restarting state of the store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The restarting state of the store is canceled."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/restart [delete]
+func (h *storeHandler) CancelRestart(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	if err := rc.CancelStoreRestart(storeID); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The restarting state of the store is canceled.")
+}
+
+// @Tags store
+// @Summary Drain the store. Its leaders are evicted first, then it is set offline so that its regions are moved out, and it becomes tombstone once it is empty.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The store is being drained."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 410 {string} string "The store has already been removed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/drain [post]
+func (h *storeHandler) Drain(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	err := rc.DrainStore(storeID)
+	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.ErrorEqual(err, errs.ErrStoreTombstone.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusGone, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store is being drained.")
+}
+
+// @Tags store
+// @Summary Cancel draining the store. The store is set up again if it has been set offline by the drain.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The drain of the store is canceled."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store is not being drained."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/drain [delete]
+func (h *storeHandler) CancelDrain(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	err := rc.CancelStoreDrain(storeID)
+	if errors.ErrorEqual(err, errs.ErrStoreNotDraining.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The drain of the store is canceled.")
+}
+
+// defaultStorePauseTTL is the ttl of pausing the scheduling of a store if it
+// is not specified.
+const defaultStorePauseTTL = 10 * time.Minute
+
+// @Tags store
+// @Summary Pause the scheduling of the store, it is not selected as source or target of any new operator until the ttl expires.
+// @Param id path integer true "Store Id"
+// @Param body body object false "json params, the ttl is 10m if it is not set"
+// @Produce json
+// @Success 200 {string} string "The scheduling of the store is paused."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 410 {string} string "The store has already been removed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/pause [post]
+func (h *storeHandler) PauseScheduling(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	ttl := defaultStorePauseTTL
+	var input map[string]string
+	if r.ContentLength > 0 {
+		if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+			return
+		}
+	}
+	if v, ok := input["ttl"]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid ttl")
+			return
+		}
+		ttl = d
+	}
+
+	err := rc.PauseStoreScheduling(storeID, ttl)
+	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.ErrorEqual(err, errs.ErrStoreTombstone.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusGone, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The scheduling of the store is paused.")
+}
+
+// @Tags store
+// @Summary Resume the scheduling of the paused store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The scheduling of the store is resumed."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/pause [delete]
+func (h *storeHandler) ResumeScheduling(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	err := rc.ResumeStoreScheduling(storeID)
+	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The scheduling of the store is resumed.")
+}
+
+// @Tags store
+// @Summary Cordon the store, it keeps serving its data but is not selected as target of any new operator.
+// @Param id path integer true "Store Id"
+// @Param body body object false "json params, the cordon never expires if the ttl is not set"
+// @Produce json
+// @Success 200 {string} string "The store is cordoned."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 410 {string} string "The store has already been removed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/cordon [post]
+func (h *storeHandler) Cordon(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+
+	var ttl time.Duration
+	var input map[string]string
+	if r.ContentLength > 0 {
+		if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &input); err != nil {
+			return
+		}
+	}
+	if v, ok := input["ttl"]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			h.rd.JSON(w, http.StatusBadRequest, "invalid ttl")
+			return
+		}
+		ttl = d
+	}
+
+	err := rc.CordonStore(storeID, ttl)
+	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.ErrorEqual(err, errs.ErrStoreTombstone.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusGone, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store is cordoned.")
+}
+
+// @Tags store
+// @Summary Uncordon the store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The store is uncordoned."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /store/{id}/cordon [delete]
+func (h *storeHandler) Uncordon(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	err := rc.UncordonStore(storeID)
+	if errors.ErrorEqual(err, errs.ErrStoreNotFound.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store is uncordoned.")
+}
+
+// ScoreComponents is the components of a scheduling score of a store. The
+// score is the sum of the terms divided by the weight, and the pending
+// influence of the running operators is counted in the terms.
+type ScoreComponents struct {
+	Formula          string  `json:"formula"`
+	SizeTerm         float64 `json:"size_term"`
+	CountTerm        float64 `json:"count_term"`
+	SpaceAdjustment  float64 `json:"space_adjustment"`
+	FlowTerm         float64 `json:"flow_term"`
+	Weight           float64 `json:"weight"`
+	PendingInfluence int64   `json:"pending_influence"`
+	Score            float64 `json:"score"`
+}
+
+func newScoreComponents(b core.ScoreBreakdown) *ScoreComponents {
+	return &ScoreComponents{
+		Formula:          b.Formula,
+		SizeTerm:         b.SizeTerm,
+		CountTerm:        b.CountTerm,
+		SpaceAdjustment:  b.SpaceTerm,
+		FlowTerm:         b.FlowTerm,
+		Weight:           b.Weight,
+		PendingInfluence: b.Influence,
+		Score:            b.Score,
+	}
+}
+
+// StoreScore contains the components of the leader and region scores of a
+// store, which are used by the schedulers to pick the stores.
+type StoreScore struct {
+	StoreID uint64           `json:"store_id"`
+	Leader  *ScoreComponents `json:"leader"`
+	Region  *ScoreComponents `json:"region"`
+}
+
 type storesHandler struct {
 	*server.Handler
 	rd *render.Render
@@ -408,6 +767,40 @@ func newStoresHandler(handler *server.Handler, rd *render.Render) *storesHandler
 	}
 }
 
+// @Tags store
+// @Summary Get the components of a store's scheduling scores, which explain why the store is picked by the schedulers.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} StoreScore
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /stores/{id}/score [get]
+func (h *storesHandler) GetScore(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, err := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if err != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+		return
+	}
+	store := rc.GetStore(storeID)
+	if store == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return
+	}
+
+	opts := rc.GetOpts()
+	influence := rc.GetOperatorController().GetOpInfluence(rc).GetStoreInfluence(storeID)
+	leaderPolicy := opts.GetLeaderSchedulePolicy()
+	leaderDelta := influence.ResourceProperty(core.NewScheduleKind(core.LeaderKind, leaderPolicy))
+	regionDelta := influence.ResourceProperty(core.NewScheduleKind(core.RegionKind, core.BySize))
+	h.rd.JSON(w, http.StatusOK, &StoreScore{
+		StoreID: storeID,
+		Leader:  newScoreComponents(store.LeaderScoreBreakdown(leaderPolicy, leaderDelta)),
+		Region: newScoreComponents(store.RegionScoreBreakdown(opts.GetRegionScoreFormulaVersion(), opts.GetRegionScoreWeights(),
+			opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), regionDelta)),
+	})
+}
+
 // @Tags store
 // @Summary Remove tombstone records in the cluster.
 // @Produce json
@@ -425,6 +818,48 @@ func (h *storesHandler) RemoveTombStone(w http.ResponseWriter, r *http.Request)
 	h.rd.JSON(w, http.StatusOK, "Remove tombstone successfully.")
 }
 
+// @Tags store
+// @Summary Get the addresses of the physically destroyed stores, whose IDs and addresses can not be registered again.
+// @Produce json
+// @Success 200 {object} map[uint64]string
+// @Router /stores/destroyed [get]
+func (h *storesHandler) GetDestroyed(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	h.rd.JSON(w, http.StatusOK, rc.GetDestroyedStores())
+}
+
+// @Tags store
+// @Summary Allow the ID and the address of a physically destroyed store to be registered again. The store must have been tombstone or removed.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "The store can be registered again."
+// @Failure 400 {string} string "The input is invalid or the store is not tombstone yet."
+// @Failure 404 {string} string "The store is not physically destroyed."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /stores/destroyed/{id} [delete]
+func (h *storesHandler) RemoveDestroyed(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, errParse := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if errParse != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(errParse))
+		return
+	}
+	err := rc.RemoveDestroyedStore(storeID)
+	if errors.ErrorEqual(err, errs.ErrStoreNotDestroyed.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.ErrorEqual(err, errs.ErrStoreNotTombstone.FastGenByArgs(storeID)) {
+		h.rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "The store can be registered again.")
+}
+
 // FIXME: details of input json body params
 // @Tags store
 // @Summary Set limit of all stores in the cluster.
@@ -565,6 +1000,26 @@ func (h *storesHandler) GetStoreLimitScene(w http.ResponseWriter, r *http.Reques
 	h.rd.JSON(w, http.StatusOK, scene)
 }
 
+// @Tags store
+// @Summary Estimate how far the stores are from balanced in each dimension and the time to converge.
+// @Produce json
+// @Success 200 {object} cluster.BalanceProgress
+// @Router /stores/balance-progress [get]
+func (h *storesHandler) GetBalanceProgress(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	h.rd.JSON(w, http.StatusOK, rc.GetBalanceProgress())
+}
+
+// @Tags store
+// @Summary Show the progress of draining the stores.
+// @Produce json
+// @Success 200 {array} cluster.StoreDrainProgress
+// @Router /stores/drain [get]
+func (h *storesHandler) GetDrainProgress(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	h.rd.JSON(w, http.StatusOK, rc.GetStoreDrainProgress())
+}
+
 // @Tags store
 // @Summary Get stores in the cluster.
 // @Param state query array true "Specify accepted store states."
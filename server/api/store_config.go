@@ -0,0 +1,187 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errcode"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/cluster"
+	"github.com/unrolled/render"
+)
+
+type storeConfigHandler struct {
+	svr *server.Server
+	rd  *render.Render
+}
+
+func newStoreConfigHandler(svr *server.Server, rd *render.Render) *storeConfigHandler {
+	return &storeConfigHandler{
+		svr: svr,
+		rd:  rd,
+	}
+}
+
+// @Tags store_config
+// @Summary Get the config items shared by all stores.
+// @Produce json
+// @Success 200 {object} storeconfig.Snippet
+// @Router /config/store-config [get]
+func (h *storeConfigHandler) GetGlobal(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	h.rd.JSON(w, http.StatusOK, rc.GetStoreConfigManager().GetGlobal())
+}
+
+// @Tags store_config
+// @Summary Update the config items shared by all stores. The items with empty values are removed. The stores apply the change at their next poll.
+// @Accept json
+// @Param body body object true "json params, e.g. {\"raftstore.region-split-check-diff\": \"16MB\"}"
+// @Produce json
+// @Success 200 {string} string "Update store config successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/store-config [post]
+func (h *storeConfigHandler) UpdateGlobal(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	var items map[string]string
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &items); err != nil {
+		return
+	}
+	if err := rc.GetStoreConfigManager().UpdateGlobal(items); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Update store config successfully.")
+}
+
+// @Tags store_config
+// @Summary Get the effective config of a store. If the versions are given and the config is not newer than them, 304 is returned, which lets the stores poll for the changes cheaply. A change reaches a store only at its next poll, so the latency is up to the poll interval of the store.
+// @Param id path integer true "Store Id"
+// @Param global_version query integer false "The global version of the config the store has"
+// @Param local_version query integer false "The local version of the config the store has"
+// @Produce json
+// @Success 200 {object} storeconfig.StoreConfig
+// @Success 304 {string} string "The config is not changed."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Router /config/store-config/store/{id} [get]
+func (h *storeConfigHandler) GetStoreConfig(w http.ResponseWriter, r *http.Request) {
+	rc, storeID, ok := h.getStore(w, r)
+	if !ok {
+		return
+	}
+	cfg := rc.GetStoreConfigManager().GetStoreConfig(storeID)
+	query := r.URL.Query()
+	if query.Get("global_version") != "" || query.Get("local_version") != "" {
+		globalVersion, err1 := strconv.ParseUint(query.Get("global_version"), 10, 64)
+		localVersion, err2 := strconv.ParseUint(query.Get("local_version"), 10, 64)
+		if err1 != nil || err2 != nil {
+			h.rd.JSON(w, http.StatusBadRequest, "both global_version and local_version should be unsigned integers")
+			return
+		}
+		if !cfg.IsNewerThan(globalVersion, localVersion) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	h.rd.JSON(w, http.StatusOK, cfg)
+}
+
+// @Tags store_config
+// @Summary Get the config items overriding the global ones for a store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {object} storeconfig.Snippet
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store or the config does not exist."
+// @Router /config/store-config/store/{id}/override [get]
+func (h *storeConfigHandler) GetStoreOverride(w http.ResponseWriter, r *http.Request) {
+	rc, storeID, ok := h.getStore(w, r)
+	if !ok {
+		return
+	}
+	s := rc.GetStoreConfigManager().GetStoreOverride(storeID)
+	if s == nil {
+		h.rd.JSON(w, http.StatusNotFound, nil)
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, s)
+}
+
+// @Tags store_config
+// @Summary Update the config items overriding the global ones for a store. The items with empty values are removed.
+// @Param id path integer true "Store Id"
+// @Accept json
+// @Param body body object true "json params, e.g. {\"raftstore.region-split-check-diff\": \"16MB\"}"
+// @Produce json
+// @Success 200 {string} string "Update store config successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 404 {string} string "The store does not exist."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/store-config/store/{id} [post]
+func (h *storeConfigHandler) UpdateStoreOverride(w http.ResponseWriter, r *http.Request) {
+	rc, storeID, ok := h.getStore(w, r)
+	if !ok {
+		return
+	}
+	var items map[string]string
+	if err := apiutil.ReadJSONRespondError(h.rd, w, r.Body, &items); err != nil {
+		return
+	}
+	if err := rc.GetStoreConfigManager().UpdateStoreOverride(storeID, items); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Update store config successfully.")
+}
+
+// @Tags store_config
+// @Summary Delete the config items overriding the global ones for a store.
+// @Param id path integer true "Store Id"
+// @Produce json
+// @Success 200 {string} string "Delete store config successfully."
+// @Failure 400 {string} string "The input is invalid."
+// @Failure 500 {string} string "PD server failed to proceed the request."
+// @Router /config/store-config/store/{id} [delete]
+func (h *storeConfigHandler) DeleteStoreOverride(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	storeID, err := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if err != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+		return
+	}
+	if err := rc.GetStoreConfigManager().DeleteStoreOverride(storeID); err != nil {
+		h.rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.rd.JSON(w, http.StatusOK, "Delete store config successfully.")
+}
+
+func (h *storeConfigHandler) getStore(w http.ResponseWriter, r *http.Request) (*cluster.RaftCluster, uint64, bool) {
+	rc := getCluster(r.Context())
+	storeID, err := apiutil.ParseUint64VarsField(mux.Vars(r), "id")
+	if err != nil {
+		apiutil.ErrorResp(h.rd, w, errcode.NewInvalidInputErr(err))
+		return nil, 0, false
+	}
+	if rc.GetStore(storeID) == nil {
+		h.rd.JSON(w, http.StatusNotFound, server.ErrStoreNotFound(storeID).Error())
+		return nil, 0, false
+	}
+	return rc, storeID, true
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/storeconfig"
+)
+
+var _ = Suite(&testStoreConfigSuite{})
+
+type testStoreConfigSuite struct {
+	svr       *server.Server
+	cleanup   cleanUpFunc
+	urlPrefix string
+}
+
+func (s *testStoreConfigSuite) SetUpSuite(c *C) {
+	s.svr, s.cleanup = mustNewServer(c)
+	mustWaitLeader(c, []*server.Server{s.svr})
+
+	addr := s.svr.GetAddr()
+	s.urlPrefix = fmt.Sprintf("%s%s/api/v1/config/store-config", addr, apiPrefix)
+
+	mustBootstrapCluster(c, s.svr)
+	mustPutStore(c, s.svr, 1, metapb.StoreState_Up, nil)
+}
+
+func (s *testStoreConfigSuite) TearDownSuite(c *C) {
+	s.cleanup()
+}
+
+func (s *testStoreConfigSuite) TestStoreConfig(c *C) {
+	c.Assert(postJSON(testDialClient, s.urlPrefix, []byte(`{"a": "1", "b": "2"}`)), IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix, []byte(`{"a": 1}`)), NotNil)
+	var global storeconfig.Snippet
+	c.Assert(readJSON(testDialClient, s.urlPrefix, &global), IsNil)
+	c.Assert(global.Version, Equals, uint64(1))
+
+	storeURL := s.urlPrefix + "/store/1"
+	c.Assert(postJSON(testDialClient, storeURL, []byte(`{"b": "3"}`)), IsNil)
+	c.Assert(postJSON(testDialClient, s.urlPrefix+"/store/100", []byte(`{"b": "3"}`)), NotNil)
+	var cfg storeconfig.StoreConfig
+	c.Assert(readJSON(testDialClient, storeURL, &cfg), IsNil)
+	c.Assert(cfg.Items, DeepEquals, map[string]string{"a": "1", "b": "3"})
+	c.Assert(cfg.LocalVersion, Equals, uint64(1))
+
+	// Poll with the versions.
+	res, err := testDialClient.Get(storeURL + "?global_version=1&local_version=1")
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusNotModified)
+	res, err = testDialClient.Get(storeURL + "?global_version=0&local_version=1")
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	res, err = testDialClient.Get(storeURL + "?global_version=x")
+	c.Assert(err, IsNil)
+	res.Body.Close()
+	c.Assert(res.StatusCode, Equals, http.StatusBadRequest)
+
+	var override storeconfig.Snippet
+	c.Assert(readJSON(testDialClient, storeURL+"/override", &override), IsNil)
+	c.Assert(override.Items, DeepEquals, map[string]string{"b": "3"})
+	res, err = doDelete(testDialClient, storeURL)
+	c.Assert(err, IsNil)
+	c.Assert(res.StatusCode, Equals, http.StatusOK)
+	c.Assert(readJSON(testDialClient, storeURL+"/override", &override), NotNil)
+	c.Assert(readJSON(testDialClient, storeURL, &cfg), IsNil)
+	c.Assert(cfg.Items, DeepEquals, map[string]string{"a": "1", "b": "2"})
+}
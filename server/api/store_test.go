@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"strings"
@@ -162,6 +163,22 @@ func (s *testStoreSuite) TestStoreGet(c *C) {
 	checkStoresInfo(c, []*StoreInfo{info}, s.stores[:1])
 }
 
+func (s *testStoreSuite) TestStoreScore(c *C) {
+	var score StoreScore
+	c.Assert(readJSON(testDialClient, fmt.Sprintf("%s/stores/1/score", s.urlPrefix), &score), IsNil)
+	store := s.svr.GetRaftCluster().GetStore(1)
+	c.Assert(score.StoreID, Equals, uint64(1))
+	c.Assert(score.Leader.Formula, Equals, "count")
+	c.Assert(score.Leader.CountTerm, Equals, float64(store.GetLeaderCount()))
+	c.Assert(score.Leader.Weight, Equals, store.GetLeaderWeight())
+	sum := score.Region.SizeTerm + score.Region.CountTerm + score.Region.SpaceAdjustment + score.Region.FlowTerm
+	c.Assert(math.Abs(score.Region.Score-sum/score.Region.Weight) < 1e-6, IsTrue)
+
+	err := readJSON(testDialClient, fmt.Sprintf("%s/stores/100/score", s.urlPrefix), &score)
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(err.Error(), "404"), IsTrue)
+}
+
 func (s *testStoreSuite) TestStoreLabel(c *C) {
 	url := fmt.Sprintf("%s/store/1", s.urlPrefix)
 	var info StoreInfo
@@ -285,6 +302,120 @@ func (s *testStoreSuite) TestStoreSetState(c *C) {
 	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
 }
 
+func (s *testStoreSuite) TestStoreRestart(c *C) {
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	err := postJSON(testDialClient, url+"/restart", []byte(`{"grace-period": "1h"}`))
+	c.Assert(err, IsNil)
+	info := StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.RestartDeadline, NotNil)
+
+	// The store comes back with a new start time.
+	_, err = s.svr.StoreHeartbeat(context.Background(), &pdpb.StoreHeartbeatRequest{
+		Header: &pdpb.RequestHeader{ClusterId: s.svr.ClusterID()},
+		Stats:  &pdpb.StoreStats{StoreId: 4, StartTime: uint32(time.Now().Unix())},
+	})
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.RestartDeadline, IsNil)
+
+	// Use the default grace period and cancel it.
+	c.Assert(postJSON(testDialClient, url+"/restart", nil), IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.RestartDeadline, NotNil)
+	_, err = doDelete(testDialClient, url+"/restart")
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.RestartDeadline, IsNil)
+
+	// Invalid grace period or store state.
+	c.Assert(postJSON(testDialClient, url+"/restart", []byte(`{"grace-period": "-1m"}`)), NotNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/store/7/restart", s.urlPrefix), nil), NotNil)
+}
+
+func (s *testStoreSuite) TestStorePause(c *C) {
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	err := postJSON(testDialClient, url+"/pause", []byte(`{"ttl": "1h"}`))
+	c.Assert(err, IsNil)
+	info := StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.PauseDeadline, NotNil)
+	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
+
+	// Resume the scheduling.
+	_, err = doDelete(testDialClient, url+"/pause")
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.PauseDeadline, IsNil)
+
+	// The pause expires automatically.
+	c.Assert(postJSON(testDialClient, url+"/pause", []byte(`{"ttl": "100ms"}`)), IsNil)
+	time.Sleep(200 * time.Millisecond)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.PauseDeadline, IsNil)
+
+	// Invalid ttl or store state.
+	c.Assert(postJSON(testDialClient, url+"/pause", []byte(`{"ttl": "-1m"}`)), NotNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/store/7/pause", s.urlPrefix), nil), NotNil)
+
+	// Unknown store.
+	resp, err := testDialClient.Post(fmt.Sprintf("%s/store/100/pause", s.urlPrefix), "application/json", nil)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+	resp, err = doDelete(testDialClient, fmt.Sprintf("%s/store/100/pause", s.urlPrefix))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+}
+
+func (s *testStoreSuite) TestStoreCordon(c *C) {
+	url := fmt.Sprintf("%s/store/4", s.urlPrefix)
+	c.Assert(postJSON(testDialClient, url+"/cordon", nil), IsNil)
+	info := StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.Cordoned, IsTrue)
+	c.Assert(info.Status.CordonDeadline, IsNil)
+	c.Assert(info.Store.State, Equals, metapb.StoreState_Up)
+	c.Assert(info.Store.StateName, Equals, cordonedStateName)
+
+	// Uncordon the store.
+	_, err := doDelete(testDialClient, url+"/cordon")
+	c.Assert(err, IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.Cordoned, IsFalse)
+	c.Assert(info.Store.StateName, Equals, metapb.StoreState_Up.String())
+
+	// The cordon with a ttl expires automatically.
+	c.Assert(postJSON(testDialClient, url+"/cordon", []byte(`{"ttl": "1h"}`)), IsNil)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.CordonDeadline, NotNil)
+	c.Assert(postJSON(testDialClient, url+"/cordon", []byte(`{"ttl": "100ms"}`)), IsNil)
+	time.Sleep(200 * time.Millisecond)
+	info = StoreInfo{}
+	c.Assert(readJSON(testDialClient, url, &info), IsNil)
+	c.Assert(info.Status.Cordoned, IsFalse)
+
+	// Invalid ttl or store state.
+	c.Assert(postJSON(testDialClient, url+"/cordon", []byte(`{"ttl": "-1m"}`)), NotNil)
+	c.Assert(postJSON(testDialClient, fmt.Sprintf("%s/store/7/cordon", s.urlPrefix), nil), NotNil)
+
+	// Unknown store.
+	resp, err := testDialClient.Post(fmt.Sprintf("%s/store/100/cordon", s.urlPrefix), "application/json", nil)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+	resp, err = doDelete(testDialClient, fmt.Sprintf("%s/store/100/cordon", s.urlPrefix))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusNotFound)
+}
+
 func (s *testStoreSuite) TestUrlStoreFilter(c *C) {
 	table := []struct {
 		u    string
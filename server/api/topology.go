@@ -0,0 +1,154 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tikv/pd/pkg/typeutil"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+)
+
+// topologyStoreLabel is the label of the leaf nodes of the topology tree.
+const topologyStoreLabel = "store"
+
+// TopologyNode is a node of the cluster topology tree. The inner nodes are
+// the values of the location labels and the leaves are the stores.
+type TopologyNode struct {
+	Label       string            `json:"label"`
+	Value       string            `json:"value"`
+	StoreCount  int               `json:"store_count"`
+	Capacity    typeutil.ByteSize `json:"capacity"`
+	Available   typeutil.ByteSize `json:"available"`
+	LeaderCount int               `json:"leader_count"`
+	RegionCount int               `json:"region_count"`
+	// Health records the count of the stores in each state under the node.
+	Health   map[string]int  `json:"health"`
+	Children []*TopologyNode `json:"children,omitempty"`
+}
+
+func newTopologyNode(label, value string) *TopologyNode {
+	return &TopologyNode{
+		Label:  label,
+		Value:  value,
+		Health: make(map[string]int),
+	}
+}
+
+func (n *TopologyNode) getOrCreateChild(label, value string) *TopologyNode {
+	for _, child := range n.Children {
+		if child.Value == value {
+			return child
+		}
+	}
+	child := newTopologyNode(label, value)
+	n.Children = append(n.Children, child)
+	return child
+}
+
+func (n *TopologyNode) addStore(info *StoreInfo) {
+	n.StoreCount++
+	n.Capacity += info.Status.Capacity
+	n.Available += info.Status.Available
+	n.LeaderCount += info.Status.LeaderCount
+	n.RegionCount += info.Status.RegionCount
+	n.Health[info.Store.StateName]++
+}
+
+func (n *TopologyNode) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		if n.Children[i].Label == topologyStoreLabel {
+			a, _ := strconv.ParseUint(n.Children[i].Value, 10, 64)
+			b, _ := strconv.ParseUint(n.Children[j].Value, 10, 64)
+			return a < b
+		}
+		return n.Children[i].Value < n.Children[j].Value
+	})
+	for _, child := range n.Children {
+		child.sort()
+	}
+}
+
+// Topology is the cluster topology tree built from the location labels.
+type Topology struct {
+	LocationLabels []string      `json:"location_labels"`
+	Root           *TopologyNode `json:"root"`
+	// Warnings records the suspicious label settings, such as stores missing
+	// a location label or all stores reporting the same label value.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func newTopology(opt *config.ScheduleConfig, locationLabels []string, stores []*core.StoreInfo) *Topology {
+	topo := &Topology{
+		LocationLabels: locationLabels,
+		Root:           newTopologyNode("", ""),
+	}
+	levelValues := make([]map[string]struct{}, len(locationLabels))
+	for i := range levelValues {
+		levelValues[i] = make(map[string]struct{})
+	}
+	for _, store := range stores {
+		if store.IsTombstone() {
+			continue
+		}
+		info := newStoreInfo(opt, store)
+		node := topo.Root
+		node.addStore(info)
+		for i, label := range locationLabels {
+			value := store.GetLabelValue(label)
+			if value == "" {
+				topo.Warnings = append(topo.Warnings, fmt.Sprintf("store %d does not have the location label %s", store.GetID(), label))
+			}
+			levelValues[i][value] = struct{}{}
+			node = node.getOrCreateChild(label, value)
+			node.addStore(info)
+		}
+		leaf := node.getOrCreateChild(topologyStoreLabel, strconv.FormatUint(store.GetID(), 10))
+		leaf.addStore(info)
+	}
+	if topo.Root.StoreCount > 1 {
+		for i, label := range locationLabels {
+			if len(levelValues[i]) != 1 {
+				continue
+			}
+			for value := range levelValues[i] {
+				if value != "" {
+					topo.Warnings = append(topo.Warnings, fmt.Sprintf("all the stores have the same %s %s", label, value))
+				}
+			}
+		}
+	}
+	topo.Root.sort()
+	return topo
+}
+
+// @Tags label
+// @Summary Get the cluster topology tree built from the location labels.
+// @Param labels query string false "comma-separated location labels, the replication location labels are used by default"
+// @Produce json
+// @Success 200 {object} Topology
+// @Router /labels/topology [get]
+func (h *labelsHandler) GetTopology(w http.ResponseWriter, r *http.Request) {
+	rc := getCluster(r.Context())
+	locationLabels := h.svr.GetReplicationConfig().LocationLabels
+	if labels := r.URL.Query().Get("labels"); labels != "" {
+		locationLabels = strings.Split(labels, ",")
+	}
+	h.rd.JSON(w, http.StatusOK, newTopology(h.svr.GetScheduleConfig(), locationLabels, rc.GetStores()))
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// balanceProgressWindow is the window of the operator histories used to
+// estimate the balance speed, it is the time that histories are kept.
+const balanceProgressWindow = 5 * time.Minute
+
+// The balance dimensions.
+const (
+	BalanceDimensionLeader = "leader"
+	BalanceDimensionRegion = "region"
+)
+
+// BalanceDimension is the balance status of the stores in one dimension. The
+// leader dimension is measured by the leader count and the region dimension
+// is measured by the region size in MB, both are divided by the store weights.
+type BalanceDimension struct {
+	Dimension string `json:"dimension"`
+	// LabelValue is the value of the balance label group, it is empty if the
+	// balance is not constrained to the label group.
+	LabelValue string  `json:"label_value,omitempty"`
+	StoreCount int     `json:"store_count"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Mean       float64 `json:"mean"`
+	// Imbalance is (max - min) / max, 0 means the stores are balanced.
+	Imbalance float64 `json:"imbalance"`
+	// Pending is the amount to be moved before all stores reach the mean.
+	Pending float64 `json:"pending"`
+	// Speed is the amount moved per second by the recent operators.
+	Speed float64 `json:"speed"`
+	// EstimatedSeconds is the projected time to converge, it is -1 if there
+	// is something pending but nothing is moved recently.
+	EstimatedSeconds float64 `json:"estimated_seconds"`
+}
+
+// BalanceProgress estimates how far the cluster is from balanced.
+type BalanceProgress struct {
+	LabelKey   string              `json:"label_key,omitempty"`
+	Dimensions []*BalanceDimension `json:"dimensions"`
+}
+
+// GetBalanceProgress returns the balance progress of the cluster.
+func (c *RaftCluster) GetBalanceProgress() *BalanceProgress {
+	now := time.Now()
+	histories := c.GetOperatorController().GetHistory(now.Add(-balanceProgressWindow))
+	return computeBalanceProgress(c.GetStores(), histories, balanceProgressWindow, c.GetOpts().GetBalanceLabelGroup())
+}
+
+func computeBalanceProgress(stores []*core.StoreInfo, histories []operator.OpHistory, window time.Duration, labelKey string) *BalanceProgress {
+	groups := make(map[string][]*core.StoreInfo)
+	var totalSize int64
+	var totalCount int
+	for _, s := range stores {
+		if !s.IsUp() {
+			continue
+		}
+		var value string
+		if labelKey != "" {
+			value = s.GetLabelValue(labelKey)
+		}
+		groups[value] = append(groups[value], s)
+		totalSize += s.GetRegionSize()
+		totalCount += s.GetRegionCount()
+	}
+	var avgRegionSize float64
+	if totalCount > 0 {
+		avgRegionSize = float64(totalSize) / float64(totalCount)
+	}
+
+	values := make([]string, 0, len(groups))
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	progress := &BalanceProgress{LabelKey: labelKey}
+	for _, value := range values {
+		group := groups[value]
+		inGroup := make(map[uint64]struct{}, len(group))
+		for _, s := range group {
+			inGroup[s.GetID()] = struct{}{}
+		}
+		var leaderMoves, regionMoves int
+		for _, h := range histories {
+			if _, ok := inGroup[h.To]; !ok {
+				continue
+			}
+			switch h.Kind {
+			case core.LeaderKind:
+				leaderMoves++
+			case core.RegionKind:
+				regionMoves++
+			}
+		}
+		leader := newBalanceDimension(BalanceDimensionLeader, group, func(s *core.StoreInfo) (float64, float64) {
+			return float64(s.GetLeaderCount()), s.GetLeaderWeight()
+		})
+		leader.setSpeed(float64(leaderMoves) / window.Seconds())
+		region := newBalanceDimension(BalanceDimensionRegion, group, func(s *core.StoreInfo) (float64, float64) {
+			return float64(s.GetRegionSize()), s.GetRegionWeight()
+		})
+		region.setSpeed(float64(regionMoves) * avgRegionSize / window.Seconds())
+		leader.LabelValue, region.LabelValue = value, value
+		progress.Dimensions = append(progress.Dimensions, leader, region)
+	}
+	return progress
+}
+
+func newBalanceDimension(dimension string, stores []*core.StoreInfo, measure func(*core.StoreInfo) (amount, weight float64)) *BalanceDimension {
+	d := &BalanceDimension{
+		Dimension:  dimension,
+		StoreCount: len(stores),
+		Min:        math.MaxFloat64,
+	}
+	amounts := make([]float64, len(stores))
+	weights := make([]float64, len(stores))
+	var totalAmount, totalWeight float64
+	for i, s := range stores {
+		amount, weight := measure(s)
+		if weight <= 0 {
+			weight = 1e-6
+		}
+		amounts[i], weights[i] = amount, weight
+		totalAmount += amount
+		totalWeight += weight
+		score := amount / weight
+		d.Min = math.Min(d.Min, score)
+		d.Max = math.Max(d.Max, score)
+	}
+	if len(stores) == 0 {
+		d.Min = 0
+		return d
+	}
+	// Mean is the score of each store when they are balanced.
+	d.Mean = totalAmount / totalWeight
+	if d.Max > 0 {
+		d.Imbalance = (d.Max - d.Min) / d.Max
+	}
+	for i := range stores {
+		if excess := amounts[i] - d.Mean*weights[i]; excess > 0 {
+			d.Pending += excess
+		}
+	}
+	return d
+}
+
+func (d *BalanceDimension) setSpeed(speed float64) {
+	d.Speed = speed
+	switch {
+	case d.Pending == 0:
+		d.EstimatedSeconds = 0
+	case speed == 0:
+		d.EstimatedSeconds = -1
+	default:
+		d.EstimatedSeconds = d.Pending / speed
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testBalanceProgressSuite{})
+
+type testBalanceProgressSuite struct{}
+
+func newProgressStore(id uint64, zone string, leaderCount, regionCount int, regionSize int64) *core.StoreInfo {
+	return core.NewStoreInfo(
+		&metapb.Store{
+			Id:     id,
+			State:  metapb.StoreState_Up,
+			Labels: []*metapb.StoreLabel{{Key: "zone", Value: zone}},
+		},
+		core.SetLeaderCount(leaderCount),
+		core.SetRegionCount(regionCount),
+		core.SetRegionSize(regionSize),
+	)
+}
+
+func (s *testBalanceProgressSuite) TestBalanceProgress(c *C) {
+	stores := []*core.StoreInfo{
+		newProgressStore(1, "z1", 30, 30, 300),
+		newProgressStore(2, "z1", 10, 10, 100),
+		newProgressStore(3, "z2", 20, 20, 200),
+		newProgressStore(4, "z2", 20, 20, 200),
+		newProgressStore(5, "z2", 0, 0, 0).Clone(core.SetStoreState(metapb.StoreState_Tombstone)),
+	}
+	window := time.Second
+	histories := []operator.OpHistory{
+		{From: 1, To: 2, Kind: core.LeaderKind},
+		{From: 1, To: 2, Kind: core.LeaderKind},
+		{From: 1, To: 2, Kind: core.RegionKind},
+	}
+
+	progress := computeBalanceProgress(stores, histories, window, "")
+	c.Assert(progress.Dimensions, HasLen, 2)
+	leader := progress.Dimensions[0]
+	c.Assert(leader.Dimension, Equals, BalanceDimensionLeader)
+	c.Assert(leader.StoreCount, Equals, 4)
+	c.Assert(leader.Min, Equals, 10.0)
+	c.Assert(leader.Max, Equals, 30.0)
+	c.Assert(leader.Mean, Equals, 20.0)
+	c.Assert(leader.Pending, Equals, 10.0)
+	c.Assert(leader.Speed, Equals, 2.0)
+	c.Assert(leader.EstimatedSeconds, Equals, 5.0)
+	region := progress.Dimensions[1]
+	c.Assert(region.Dimension, Equals, BalanceDimensionRegion)
+	c.Assert(region.Pending, Equals, 100.0)
+	// 1 region of 10MB is moved in a second.
+	c.Assert(region.Speed, Equals, 10.0)
+	c.Assert(region.EstimatedSeconds, Equals, 10.0)
+
+	// Constrain the balance to the stores in the same zone.
+	progress = computeBalanceProgress(stores, histories, window, "zone")
+	c.Assert(progress.Dimensions, HasLen, 4)
+	c.Assert(progress.Dimensions[0].LabelValue, Equals, "z1")
+	c.Assert(progress.Dimensions[0].Pending, Equals, 10.0)
+	c.Assert(progress.Dimensions[2].LabelValue, Equals, "z2")
+	c.Assert(progress.Dimensions[2].Imbalance, Equals, 0.0)
+	c.Assert(progress.Dimensions[2].Pending, Equals, 0.0)
+	c.Assert(progress.Dimensions[2].EstimatedSeconds, Equals, 0.0)
+
+	// Nothing is moved recently.
+	progress = computeBalanceProgress(stores, nil, window, "")
+	c.Assert(progress.Dimensions[0].EstimatedSeconds, Equals, -1.0)
+}
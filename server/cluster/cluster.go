@@ -16,7 +16,9 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -38,6 +40,7 @@ import (
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/heatmap"
 	"github.com/tikv/pd/server/id"
 	syncer "github.com/tikv/pd/server/region_syncer"
 	"github.com/tikv/pd/server/replication"
@@ -45,7 +48,9 @@ import (
 	"github.com/tikv/pd/server/schedule/checker"
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/schedule/weight"
 	"github.com/tikv/pd/server/statistics"
+	"github.com/tikv/pd/server/storeconfig"
 	"github.com/tikv/pd/server/versioninfo"
 	"go.etcd.io/etcd/clientv3"
 	"go.uber.org/zap"
@@ -56,6 +61,19 @@ var backgroundJobInterval = 10 * time.Second
 const (
 	clientTimeout              = 3 * time.Second
 	defaultChangedRegionsLimit = 10000
+	// maxPriorityRegions is the max number of the abnormal regions waiting for
+	// the prioritized inspection.
+	maxPriorityRegions = 10000
+	// newRegionPriority is the lowest priority of the inspection of the new
+	// regions, such as the ones created by splits.
+	newRegionPriority = 1
+	// regionKeysCompactBatch is the max number of the cached regions checked in a
+	// round of compacting the region keys.
+	regionKeysCompactBatch = 4096
+	// regionKeysCompactStopRatio is the ratio of the region cache memory limit,
+	// below which the region keys are not compacted any more. It keeps the
+	// compaction from flapping around the limit.
+	regionKeysCompactStopRatio = 0.8
 )
 
 // Server is the interface for cluster.
@@ -64,6 +82,7 @@ type Server interface {
 	GetConfig() *config.Config
 	GetPersistOptions() *config.PersistOptions
 	GetStorage() *core.Storage
+	GetHotRegionStorage() *core.HotRegionStorage
 	GetHBStreams() *hbstream.HeartbeatStreams
 	GetRaftCluster() *RaftCluster
 	GetBasicCluster() *core.BasicCluster
@@ -101,10 +120,14 @@ type RaftCluster struct {
 	regionStats     *statistics.RegionStatistics
 	storesStats     *statistics.StoresStats
 	hotSpotCache    *statistics.HotCache
+	// hotRegionStorage keeps the history of hot regions.
+	hotRegionStorage *core.HotRegionStorage
+	heatmap          *heatmap.Recorder
 
 	coordinator      *coordinator
-	suspectRegions   *cache.TTLUint64 // suspectRegions are regions that may need fix
-	suspectKeyRanges *cache.TTLString // suspect key-range regions that may need fix
+	suspectRegions   *cache.TTLUint64     // suspectRegions are regions that may need fix
+	suspectKeyRanges *cache.TTLString     // suspect key-range regions that may need fix
+	priorityRegions  *cache.PriorityQueue // abnormal regions that should be checked first
 
 	wg           sync.WaitGroup
 	quit         chan struct{}
@@ -114,11 +137,25 @@ type RaftCluster struct {
 	etcdClient  *clientv3.Client
 	httpClient  *http.Client
 
+	// weightManager holds the key range weights of the regions.
+	weightManager *weight.Manager
+	// storeConfigManager holds the config snippets served to the stores.
+	storeConfigManager *storeconfig.Manager
+
 	replicationMode *replication.ModeManager
 	traceRegionFlow bool
+	// regionKeysCompacted is true when the memory used by the cached regions
+	// exceeds the limit and the keys of them are compacted.
+	regionKeysCompacted bool
 
 	// It's used to manage components.
 	componentManager *component.Manager
+
+	storeDrainController *storeDrainController
+	throughputTuner      *throughputTuner
+	// destroyedStores are the addresses of the physically destroyed stores,
+	// which can not be registered again even after their records are removed.
+	destroyedStores map[uint64]string
 }
 
 // Status saves some state information.
@@ -204,10 +241,15 @@ func (c *RaftCluster) InitCluster(id id.Allocator, opt *config.PersistOptions, s
 	c.storesStats = statistics.NewStoresStats()
 	c.prepareChecker = newPrepareChecker()
 	c.changedRegions = make(chan *core.RegionInfo, defaultChangedRegionsLimit)
-	c.hotSpotCache = statistics.NewHotCache()
+	c.hotSpotCache = statistics.NewHotCache(opt)
+	c.heatmap = heatmap.NewRecorder(opt.GetHeatmapMaxSnapshots(), opt.GetHeatmapMaxSpans())
 	c.suspectRegions = cache.NewIDTTL(c.ctx, time.Minute, 3*time.Minute)
 	c.suspectKeyRanges = cache.NewStringTTL(c.ctx, time.Minute, 3*time.Minute)
+	c.priorityRegions = cache.NewPriorityQueue(maxPriorityRegions)
 	c.traceRegionFlow = opt.GetPDServerConfig().TraceRegionFlow
+	c.storeDrainController = newStoreDrainController(c)
+	c.throughputTuner = newThroughputTuner(c)
+	c.destroyedStores = make(map[uint64]string)
 }
 
 // Start starts a cluster.
@@ -237,6 +279,24 @@ func (c *RaftCluster) Start(s Server) error {
 		}
 	}
 
+	c.weightManager = weight.NewManager(c.storage)
+	if err = c.weightManager.Initialize(); err != nil {
+		return err
+	}
+
+	c.storeConfigManager = storeconfig.NewManager(c.storage)
+	if err = c.storeConfigManager.Initialize(); err != nil {
+		return err
+	}
+
+	if err = c.storeDrainController.load(); err != nil {
+		return err
+	}
+
+	if err = c.throughputTuner.load(); err != nil {
+		return err
+	}
+
 	c.componentManager = component.NewManager(c.storage)
 	_, err = c.storage.LoadComponent(&c.componentManager)
 	if err != nil {
@@ -249,11 +309,12 @@ func (c *RaftCluster) Start(s Server) error {
 	}
 
 	c.coordinator = newCoordinator(c.ctx, cluster, s.GetHBStreams())
-	c.regionStats = statistics.NewRegionStatistics(c.opt, c.ruleManager)
+	c.regionStats = statistics.NewRegionStatistics(c.opt, c.ruleManager, schedule.NamespaceLabelKey)
 	c.limiter = NewStoreLimiter(s.GetPersistOptions())
+	c.hotRegionStorage = s.GetHotRegionStorage()
 	c.quit = make(chan struct{})
 
-	c.wg.Add(4)
+	c.wg.Add(6)
 	go c.runCoordinator()
 	failpoint.Inject("highFrequencyClusterJobs", func() {
 		backgroundJobInterval = 100 * time.Microsecond
@@ -261,6 +322,8 @@ func (c *RaftCluster) Start(s Server) error {
 	go c.runBackgroundJobs(backgroundJobInterval)
 	go c.syncRegions()
 	go c.runReplicationMode()
+	go c.runHotRegionHistory()
+	go c.runHeatmap(heatmap.DefaultRecordInterval)
 	c.running = true
 
 	return nil
@@ -281,6 +344,15 @@ func (c *RaftCluster) LoadClusterInfo() (*RaftCluster, error) {
 	if err := c.storage.LoadStores(c.core.PutStore); err != nil {
 		return nil, err
 	}
+	if err := c.loadDestroyedStores(); err != nil {
+		return nil, err
+	}
+	if err := c.loadStoreCordons(); err != nil {
+		return nil, err
+	}
+	if err := c.loadStorePauses(); err != nil {
+		return nil, err
+	}
 	log.Info("load stores",
 		zap.Int("count", c.GetStoreCount()),
 		zap.Duration("cost", time.Since(start)),
@@ -318,7 +390,10 @@ func (c *RaftCluster) runBackgroundJobs(interval time.Duration) {
 			return
 		case <-ticker.C:
 			c.checkStores()
+			c.storeDrainController.checkStores()
+			c.throughputTuner.tune()
 			c.collectMetrics()
+			c.checkRegionCacheMemory()
 			c.coordinator.opController.PruneHistory()
 		}
 	}
@@ -348,6 +423,54 @@ func (c *RaftCluster) runReplicationMode() {
 	c.replicationMode.Run(c.quit)
 }
 
+func (c *RaftCluster) runHeatmap(interval time.Duration) {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.quit:
+			log.Info("heatmap job has been stopped")
+			return
+		case now := <-ticker.C:
+			if !c.opt.IsHeatmapEnabled() {
+				c.heatmap.Reset()
+				continue
+			}
+			c.heatmap.SetLimits(c.opt.GetHeatmapMaxSnapshots(), c.opt.GetHeatmapMaxSpans())
+			c.heatmap.Record(now, c.scanAllRegions())
+		}
+	}
+}
+
+// scanAllRegions returns all regions sorted by start key.
+func (c *RaftCluster) scanAllRegions() []*core.RegionInfo {
+	const batch = 1024
+	var (
+		startKey []byte
+		regions  []*core.RegionInfo
+	)
+	for {
+		rs := c.core.ScanRange(startKey, nil, batch)
+		if len(rs) == 0 {
+			return regions
+		}
+		regions = append(regions, rs...)
+		startKey = rs[len(rs)-1].GetEndKey()
+		if len(startKey) == 0 {
+			return regions
+		}
+	}
+}
+
+// GetHeatmap returns the heatmap of the key space over time.
+func (c *RaftCluster) GetHeatmap(q *heatmap.Query) *heatmap.Matrix {
+	return c.heatmap.Heatmap(q)
+}
+
 // Stop stops the cluster.
 func (c *RaftCluster) Stop() {
 	c.Lock()
@@ -455,6 +578,32 @@ func (c *RaftCluster) RemoveSuspectRegion(id uint64) {
 	c.suspectRegions.Remove(id)
 }
 
+// getRegionAbnormalPriority returns how urgent the region needs to be
+// checked, which is the number of the down peers and the missing replicas
+// scaled by the weight of the region. 0 means the region is healthy.
+func (c *RaftCluster) getRegionAbnormalPriority(region *core.RegionInfo) int {
+	priority := len(region.GetDownPeers())
+	if !c.opt.IsPlacementRulesEnabled() {
+		if missing := c.opt.GetMaxReplicas() - len(region.GetVoters()); missing > 0 {
+			priority += missing
+		}
+	}
+	if priority > 0 {
+		priority = int(math.Ceil(float64(priority) * c.GetRegionWeight(region)))
+	}
+	return priority
+}
+
+// AddPriorityRegion adds the region to be checked before the other regions.
+func (c *RaftCluster) AddPriorityRegion(regionID uint64, priority int) {
+	c.priorityRegions.Put(regionID, priority)
+}
+
+// PopPriorityRegions takes out at most n regions to be checked first.
+func (c *RaftCluster) PopPriorityRegions(n int) []uint64 {
+	return c.priorityRegions.Pop(n)
+}
+
 // AddSuspectKeyRange adds the key range with the its ruleID as the key
 // The instance of each keyRange is like following format:
 // [2][]byte: start key/end key
@@ -498,7 +647,12 @@ func (c *RaftCluster) HandleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	if store == nil {
 		return errors.Errorf("store %v not found", storeID)
 	}
-	newStore := store.Clone(core.SetStoreStats(stats), core.SetLastHeartbeatTS(time.Now()))
+	opts := []core.StoreCreateOption{core.SetStoreStats(stats), core.SetLastHeartbeatTS(time.Now())}
+	if isStoreRestarted(store, stats.GetStartTime()) {
+		log.Info("store has been restarted", zap.Uint64("store-id", storeID))
+		opts = append(opts, core.ResetRestartDeadline())
+	}
+	newStore := store.Clone(opts...)
 	if newStore.IsLowSpace(c.opt.GetLowSpaceRatio()) {
 		log.Warn("store does not have enough disk space",
 			zap.Uint64("store-id", newStore.GetID()),
@@ -525,12 +679,16 @@ func (c *RaftCluster) HandleStoreHeartbeat(stats *pdpb.StoreStats) error {
 	if c.limiter != nil && c.opt.GetStoreLimitMode() == "auto" {
 		c.limiter.Collect(newStore.GetStoreStats())
 	}
+	if c.throughputTuner != nil && c.opt.IsThroughputTuningEnabled() {
+		c.throughputTuner.collect(newStore.GetStoreStats())
+	}
 
 	return nil
 }
 
 // processRegionHeartbeat updates the region information.
 func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
+	region = c.checkHibernatedRegion(region)
 	c.RLock()
 	origin, err := c.core.PreCheckPutRegion(region)
 	if err != nil {
@@ -541,6 +699,19 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	readItems := c.CheckReadStatus(region)
 	c.RUnlock()
 
+	c.updateRegionHeartbeatTS(region)
+
+	// The abnormal regions are checked before the others, and so are the new
+	// regions, so that they are not left under-replicated or misplaced until
+	// the patrol reaches them.
+	priority := c.getRegionAbnormalPriority(region)
+	if origin == nil && priority < newRegionPriority {
+		priority = newRegionPriority
+	}
+	if priority > 0 {
+		c.priorityRegions.Put(region.GetID(), priority)
+	}
+
 	// Save to storage if meta is updated.
 	// Save to cache if meta or leader is updated, or contains any down/pending peer.
 	// Mark isNew if the region in cache does not have leader.
@@ -592,6 +763,9 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 		if len(region.GetPeers()) != len(origin.GetPeers()) {
 			saveKV, saveCache = true, true
 		}
+		if region.IsHibernated() != origin.IsHibernated() {
+			saveCache = true
+		}
 
 		if region.GetApproximateSize() != origin.GetApproximateSize() ||
 			region.GetApproximateKeys() != origin.GetApproximateKeys() {
@@ -646,6 +820,9 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 				c.regionStats.ClearDefunctRegion(item.GetID())
 			}
 			c.labelLevelStats.ClearDefunctRegion(item.GetID(), c.opt.GetLocationLabels())
+			if c.ruleManager != nil {
+				c.ruleManager.InvalidateFitCache(item.GetID())
+			}
 		}
 
 		// Update related stores.
@@ -703,6 +880,61 @@ func (c *RaftCluster) processRegionHeartbeat(region *core.RegionInfo) error {
 	return nil
 }
 
+// regionHeartbeatRecordInterval is the min interval to record the region heartbeat
+// time of a store, so the store is not cloned on every region heartbeat.
+const regionHeartbeatRecordInterval = 10 * time.Second
+
+func (c *RaftCluster) updateRegionHeartbeatTS(region *core.RegionInfo) {
+	storeID := region.GetLeader().GetStoreId()
+	store := c.GetStore(storeID)
+	if store == nil {
+		return
+	}
+	if region.IsHibernated() && !store.HibernatesRegions() {
+		log.Info("store hibernates the idle regions", zap.Uint64("store-id", storeID))
+		c.core.SetStoreHibernatesRegions(storeID)
+	}
+	now := time.Now()
+	if now.Sub(store.GetLastRegionHeartbeatTS()) < regionHeartbeatRecordInterval {
+		return
+	}
+	c.core.SetStoreRegionHeartbeatTS(storeID, now)
+}
+
+// hibernatedRegionReportInterval is the reported interval in seconds of the
+// region heartbeat beyond which the region is considered hibernated. The
+// leaders of the active regions report every RegionHeartBeatReportInterval.
+const hibernatedRegionReportInterval = 2 * statistics.RegionHeartBeatReportInterval
+
+// checkHibernatedRegion flags the region as hibernated if it is idle and its
+// leader reports the heartbeat after a long gap. Only the idle regions whose
+// peers have caught up can hibernate, so a busy region or one with pending
+// peers which reports late is not regarded as hibernated. A hibernated leader
+// does not hear from the followers, so the down peers on the stores which are
+// still up are ignored, otherwise they would be replaced by mistake.
+func (c *RaftCluster) checkHibernatedRegion(region *core.RegionInfo) *core.RegionInfo {
+	interval := region.GetInterval()
+	// The first heartbeat of a leader has no start of the interval.
+	if interval.GetStartTimestamp() == 0 ||
+		interval.GetEndTimestamp() < interval.GetStartTimestamp()+hibernatedRegionReportInterval {
+		return region
+	}
+	if len(region.GetPendingPeers()) > 0 ||
+		region.GetBytesWritten() > 0 || region.GetBytesRead() > 0 ||
+		region.GetKeysWritten() > 0 || region.GetKeysRead() > 0 {
+		return region
+	}
+	var downPeers []*pdpb.PeerStats
+	for _, stats := range region.GetDownPeers() {
+		store := c.GetStore(stats.GetPeer().GetStoreId())
+		if store != nil && store.IsUp() && !store.IsDisconnected() {
+			continue
+		}
+		downPeers = append(downPeers, stats)
+	}
+	return region.Clone(core.SetHibernated(true), core.WithDownPeers(downPeers))
+}
+
 func (c *RaftCluster) updateStoreStatusLocked(id uint64) {
 	leaderCount := c.core.GetStoreLeaderCount(id)
 	regionCount := c.core.GetStoreRegionCount(id)
@@ -855,6 +1087,49 @@ func (c *RaftCluster) DropCacheRegion(id uint64) {
 	}
 }
 
+// WakeRegions wakes up the hibernated regions so that they report the fresh
+// state. The leader is asked to transfer the leadership to itself, which
+// wakes up the region and changes nothing. It returns the regions which are
+// not woken up, because they are not found, have no leader, or the message
+// can not be sent to the store of the leader.
+func (c *RaftCluster) WakeRegions(regionIDs []uint64) []uint64 {
+	hbStreams := c.GetHeartbeatStreams()
+	var missed []uint64
+	for _, id := range regionIDs {
+		region := c.GetRegion(id)
+		if region == nil || region.GetLeader() == nil {
+			missed = append(missed, id)
+			continue
+		}
+		sent := hbStreams.SendMsgAndWait(region, &pdpb.RegionHeartbeatResponse{
+			TransferLeader: &pdpb.TransferLeader{Peer: region.GetLeader()},
+		})
+		if !sent {
+			missed = append(missed, id)
+			continue
+		}
+		regionEventCounter.WithLabelValues("wake").Inc()
+	}
+	return missed
+}
+
+// DropCacheAllRegion removes all regions from the cache. The cluster is
+// marked not prepared, so the scheduling pauses until the heartbeats have
+// repopulated the cache.
+func (c *RaftCluster) DropCacheAllRegion() {
+	c.Lock()
+	defer c.Unlock()
+	checker := newPrepareChecker()
+	// The regions counted before the drop are what the heartbeats have to
+	// bring back, the emptied cache can't tell it.
+	checker.expectedSum = c.core.GetRegionCount()
+	for _, store := range c.core.GetStores() {
+		checker.expectedRegions[store.GetID()] = c.core.GetStoreRegionCount(store.GetID())
+	}
+	c.core.ResetRegionCache()
+	c.prepareChecker = checker
+}
+
 // GetCacheCluster gets the cached cluster.
 func (c *RaftCluster) GetCacheCluster() *core.BasicCluster {
 	c.RLock()
@@ -922,6 +1197,14 @@ func (c *RaftCluster) PutStore(store *metapb.Store, force bool) error {
 		return errors.Errorf("version should compatible with version  %s, got %s", clusterVersion, v)
 	}
 
+	// A physically destroyed store never comes back, neither its ID nor its
+	// address can be registered again.
+	for id, address := range c.destroyedStores {
+		if id == store.GetId() || address == store.GetAddress() {
+			return errs.ErrStoreDestroyed.FastGenByArgs(id)
+		}
+	}
+
 	// Store address can not be the same as other stores.
 	for _, s := range c.GetStores() {
 		// It's OK to start a new store on the same address if the old store has been removed.
@@ -989,9 +1272,15 @@ func (c *RaftCluster) checkStoreLabels(s *core.StoreInfo) error {
 	return nil
 }
 
-// RemoveStore marks a store as offline in cluster.
+// RemoveStore marks a store as offline in cluster. If physicallyDestroyed is
+// true, the store will never come back, so its peers are replaced without
+// waiting and its ID and address can not be registered again until
+// RemoveDestroyedStore is called.
+// The regions which lost the quorum with the destroyed stores are not recovered
+// by PD, because the store heartbeat can neither push the recovery plans to the
+// stores nor collect their raft states. Recover them with tikv-ctl instead.
 // State transition: Up -> Offline.
-func (c *RaftCluster) RemoveStore(storeID uint64) error {
+func (c *RaftCluster) RemoveStore(storeID uint64, physicallyDestroyed bool) error {
 	c.Lock()
 	defer c.Unlock()
 
@@ -1000,8 +1289,9 @@ func (c *RaftCluster) RemoveStore(storeID uint64) error {
 		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
 	}
 
-	// Remove an offline store should be OK, nothing to do.
-	if store.IsOffline() {
+	// Remove an offline store should be OK, nothing to do unless it is
+	// marked as physically destroyed now.
+	if store.IsOffline() && (!physicallyDestroyed || store.IsPhysicallyDestroyed()) {
 		return nil
 	}
 
@@ -1009,10 +1299,21 @@ func (c *RaftCluster) RemoveStore(storeID uint64) error {
 		return errs.ErrStoreTombstone.FastGenByArgs(storeID)
 	}
 
-	newStore := store.Clone(core.SetStoreState(metapb.StoreState_Offline))
+	opts := []core.StoreCreateOption{core.SetStoreState(metapb.StoreState_Offline)}
+	if physicallyDestroyed {
+		if c.storage != nil {
+			if err := c.storage.SaveDestroyedStore(storeID, store.GetAddress()); err != nil {
+				return err
+			}
+		}
+		c.destroyedStores[storeID] = store.GetAddress()
+		opts = append(opts, core.SetPhysicallyDestroyed())
+	}
+	newStore := store.Clone(opts...)
 	log.Warn("store has been offline",
 		zap.Uint64("store-id", newStore.GetID()),
-		zap.String("store-address", newStore.GetAddress()))
+		zap.String("store-address", newStore.GetAddress()),
+		zap.Bool("physically-destroyed", newStore.IsPhysicallyDestroyed()))
 	err := c.putStoreLocked(newStore)
 	if err == nil {
 		c.SetStoreLimit(storeID, storelimit.RemovePeer, storelimit.Unlimited)
@@ -1020,6 +1321,55 @@ func (c *RaftCluster) RemoveStore(storeID uint64) error {
 	return err
 }
 
+// GetDestroyedStores returns the addresses of the physically destroyed stores.
+func (c *RaftCluster) GetDestroyedStores() map[uint64]string {
+	c.RLock()
+	defer c.RUnlock()
+	stores := make(map[uint64]string, len(c.destroyedStores))
+	for id, address := range c.destroyedStores {
+		stores[id] = address
+	}
+	return stores
+}
+
+// RemoveDestroyedStore allows the ID and the address of a physically destroyed
+// store to be registered again. The store must have been tombstone or removed.
+func (c *RaftCluster) RemoveDestroyedStore(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.destroyedStores[storeID]; !ok {
+		return errs.ErrStoreNotDestroyed.FastGenByArgs(storeID)
+	}
+	if store := c.GetStore(storeID); store != nil && !store.IsTombstone() {
+		return errs.ErrStoreNotTombstone.FastGenByArgs(storeID)
+	}
+	if c.storage != nil {
+		if err := c.storage.DeleteDestroyedStore(storeID); err != nil {
+			return err
+		}
+	}
+	delete(c.destroyedStores, storeID)
+	log.Info("the physically destroyed store is allowed to be registered again", zap.Uint64("store-id", storeID))
+	return nil
+}
+
+// loadDestroyedStores loads the physically destroyed stores and flags the ones
+// still in the cluster. It is called with the cluster locked.
+func (c *RaftCluster) loadDestroyedStores() error {
+	return c.storage.LoadDestroyedStores(func(k, v string) {
+		id, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			log.Error("invalid physically destroyed store key", zap.String("key", k), errs.ZapError(errs.ErrLoadDestroyedStore))
+			return
+		}
+		c.destroyedStores[id] = v
+		if store := c.GetStore(id); store != nil {
+			c.core.PutStore(store.Clone(core.SetPhysicallyDestroyed()))
+		}
+	})
+}
+
 // BuryStore marks a store as tombstone in cluster.
 // State transition:
 // Case 1: Up -> Tombstone (if force is true);
@@ -1068,6 +1418,11 @@ func (c *RaftCluster) ResumeLeaderTransfer(storeID uint64) {
 	c.core.ResumeLeaderTransfer(storeID)
 }
 
+// SetStoreBackoff backs off the scheduling to a specific store until the deadline.
+func (c *RaftCluster) SetStoreBackoff(storeID uint64, deadline time.Time) {
+	c.core.SetStoreBackoff(storeID, deadline)
+}
+
 // AttachAvailableFunc attaches an available function to a specific store.
 func (c *RaftCluster) AttachAvailableFunc(storeID uint64, limitType storelimit.Type, f func() bool) {
 	c.core.AttachAvailableFunc(storeID, limitType, f)
@@ -1215,6 +1570,32 @@ func (c *RaftCluster) collectMetrics() {
 	c.collectHealthStatus()
 }
 
+// checkRegionCacheMemory compacts the keys of the cached regions when the
+// memory used by them exceeds the limit. Since then, the regions put into the
+// cache share the boundary keys with their neighbors, and the key copies of the
+// regions cached before are dropped in batches, until the memory falls well
+// below the limit.
+func (c *RaftCluster) checkRegionCacheMemory() {
+	size := c.core.GetRegionMemorySize()
+	limit := c.opt.GetRegionCacheMemoryLimit()
+	switch {
+	case !c.regionKeysCompacted && limit > 0 && size > limit:
+		log.Warn("the memory used by the cached regions exceeds the limit, start to compact the region keys",
+			zap.Int64("memory-size", size), zap.Int64("limit", limit))
+		c.core.SetRegionKeysCompaction(true)
+		c.regionKeysCompacted = true
+	case c.regionKeysCompacted && (limit == 0 || float64(size) < float64(limit)*regionKeysCompactStopRatio):
+		log.Info("the memory used by the cached regions is back under the limit, stop compacting the region keys",
+			zap.Int64("memory-size", size), zap.Int64("limit", limit))
+		c.core.SetRegionKeysCompaction(false)
+		c.regionKeysCompacted = false
+	}
+	if c.regionKeysCompacted && c.core.CompactRegionKeys(regionKeysCompactBatch) > 0 {
+		size = c.core.GetRegionMemorySize()
+	}
+	regionCacheMemoryGauge.Set(float64(size))
+}
+
 func (c *RaftCluster) resetMetrics() {
 	statsMap := statistics.NewStoreStatisticsMap(c.opt)
 	statsMap.Reset()
@@ -1222,6 +1603,7 @@ func (c *RaftCluster) resetMetrics() {
 	c.coordinator.resetSchedulerMetrics()
 	c.coordinator.resetHotSpotMetrics()
 	c.resetClusterMetrics()
+	regionCacheMemoryGauge.Set(0)
 	c.resetHealthStatus()
 }
 
@@ -1305,18 +1687,7 @@ func (c *RaftCluster) AllocID() (uint64, error) {
 func (c *RaftCluster) OnStoreVersionChange() {
 	c.RLock()
 	defer c.RUnlock()
-	var minVersion *semver.Version
-	stores := c.GetStores()
-	for _, s := range stores {
-		if s.IsTombstone() {
-			continue
-		}
-		v := versioninfo.MustParseVersion(s.GetVersion())
-
-		if minVersion == nil || v.LessThan(*minVersion) {
-			minVersion = v
-		}
-	}
+	minVersion := c.getMinStoreVersion()
 	clusterVersion := c.opt.GetClusterVersion()
 	// If the cluster version of PD is less than the minimum version of all stores,
 	// it will update the cluster version.
@@ -1338,6 +1709,38 @@ func (c *RaftCluster) OnStoreVersionChange() {
 	}
 }
 
+// GetMinStoreVersion returns the minimum version of the stores which are not
+// tombstone. It returns nil if there is no such store.
+func (c *RaftCluster) GetMinStoreVersion() *semver.Version {
+	c.RLock()
+	defer c.RUnlock()
+	return c.getMinStoreVersion()
+}
+
+func (c *RaftCluster) getMinStoreVersion() *semver.Version {
+	var minVersion *semver.Version
+	for _, s := range c.GetStores() {
+		if s.IsTombstone() {
+			continue
+		}
+		v := versioninfo.MustParseVersion(s.GetVersion())
+		if minVersion == nil || v.LessThan(*minVersion) {
+			minVersion = v
+		}
+	}
+	return minVersion
+}
+
+// GetFeatureSupport returns whether the named features are supported by the
+// current cluster version.
+func (c *RaftCluster) GetFeatureSupport() map[string]bool {
+	features := make(map[string]bool)
+	for _, f := range versioninfo.NamedFeatures() {
+		features[f.String()] = c.IsFeatureSupported(f)
+	}
+	return features
+}
+
 func (c *RaftCluster) changedRegionNotifier() <-chan *core.RegionInfo {
 	return c.changedRegions
 }
@@ -1458,6 +1861,28 @@ func (c *RaftCluster) putRegion(region *core.RegionInfo) error {
 	return nil
 }
 
+// GetRegionWeightManager returns the region weight manager reference.
+func (c *RaftCluster) GetRegionWeightManager() *weight.Manager {
+	c.RLock()
+	defer c.RUnlock()
+	return c.weightManager
+}
+
+// GetRegionWeight returns the scheduling weight of the region.
+func (c *RaftCluster) GetRegionWeight(region *core.RegionInfo) float64 {
+	if c.weightManager == nil {
+		return weight.DefaultWeight
+	}
+	return c.weightManager.GetRegionWeight(region)
+}
+
+// GetStoreConfigManager returns the store config manager reference.
+func (c *RaftCluster) GetStoreConfigManager() *storeconfig.Manager {
+	c.RLock()
+	defer c.RUnlock()
+	return c.storeConfigManager
+}
+
 // GetRuleManager returns the rule manager reference.
 func (c *RaftCluster) GetRuleManager() *placement.RuleManager {
 	c.RLock()
@@ -1475,12 +1900,17 @@ type prepareChecker struct {
 	start           time.Time
 	sum             int
 	isPrepared      bool
+	// expectedRegions and expectedSum are the region counts known before
+	// the region cache was dropped, they are empty on a fresh start.
+	expectedRegions map[uint64]int
+	expectedSum     int
 }
 
 func newPrepareChecker() *prepareChecker {
 	return &prepareChecker{
 		start:           time.Now(),
 		reactiveRegions: make(map[uint64]int),
+		expectedRegions: make(map[uint64]int),
 	}
 }
 
@@ -1490,7 +1920,7 @@ func (checker *prepareChecker) check(c *RaftCluster) bool {
 		return true
 	}
 	// The number of active regions should be more than total region of all stores * collectFactor
-	if float64(c.core.GetRegionCount())*collectFactor > float64(checker.sum) {
+	if float64(maxInt(c.core.GetRegionCount(), checker.expectedSum))*collectFactor > float64(checker.sum) {
 		return false
 	}
 	for _, store := range c.GetStores() {
@@ -1499,7 +1929,7 @@ func (checker *prepareChecker) check(c *RaftCluster) bool {
 		}
 		storeID := store.GetID()
 		// For each store, the number of active regions should be more than total region of the store * collectFactor
-		if float64(c.core.GetStoreRegionCount(storeID))*collectFactor > float64(checker.reactiveRegions[storeID]) {
+		if float64(maxInt(c.core.GetStoreRegionCount(storeID), checker.expectedRegions[storeID]))*collectFactor > float64(checker.reactiveRegions[storeID]) {
 			return false
 		}
 	}
@@ -1514,6 +1944,13 @@ func (checker *prepareChecker) collect(region *core.RegionInfo) {
 	checker.sum++
 }
 
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // GetHotWriteRegions gets hot write regions' info.
 func (c *RaftCluster) GetHotWriteRegions() *statistics.StoreHotPeersInfos {
 	c.RLock()
@@ -1565,6 +2002,36 @@ func (c *RaftCluster) PauseOrResumeScheduler(name string, t int64) error {
 	return c.coordinator.pauseOrResumeScheduler(name, t)
 }
 
+// SetSchedulerNamespace binds a scheduler to a namespace, an empty namespace
+// unbinds it.
+func (c *RaftCluster) SetSchedulerNamespace(name, namespace string) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.setSchedulerNamespace(name, namespace)
+}
+
+// GetSchedulerNamespace returns the namespace which a scheduler is bound to.
+func (c *RaftCluster) GetSchedulerNamespace(name string) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.getSchedulerNamespace(name)
+}
+
+// SetSchedulerStoreList limits the stores used by a scheduler, an empty list
+// removes the limit.
+func (c *RaftCluster) SetSchedulerStoreList(name string, list *schedule.StoreList) error {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.setSchedulerStoreList(name, list)
+}
+
+// GetSchedulerStoreList returns the store list which a scheduler is limited to.
+func (c *RaftCluster) GetSchedulerStoreList(name string) (*schedule.StoreList, error) {
+	c.RLock()
+	defer c.RUnlock()
+	return c.coordinator.getSchedulerStoreList(name)
+}
+
 // IsSchedulerPaused checks if a scheduler is paused.
 func (c *RaftCluster) IsSchedulerPaused(name string) (bool, error) {
 	c.RLock()
@@ -1590,15 +2057,9 @@ func (c *RaftCluster) GetAllStoresLimit() map[uint64]config.StoreLimitConfig {
 // AddStoreLimit add a store limit for a given store ID.
 func (c *RaftCluster) AddStoreLimit(store *metapb.Store) {
 	cfg := c.opt.GetScheduleConfig().Clone()
-	sc := config.StoreLimitConfig{
-		AddPeer:    config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer),
-		RemovePeer: config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer),
-	}
+	sc := config.DefaultStoreLimit.NewStoreLimitConfig()
 	if core.IsTiFlashStore(store) {
-		sc = config.StoreLimitConfig{
-			AddPeer:    config.DefaultTiFlashStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer),
-			RemovePeer: config.DefaultTiFlashStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer),
-		}
+		sc = config.DefaultTiFlashStoreLimit.NewStoreLimitConfig()
 	}
 	storeID := store.GetId()
 	cfg.StoreLimit[storeID] = sc
@@ -27,11 +27,13 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/mock/mockid"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/id"
 	"github.com/tikv/pd/server/kv"
 	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/schedule/weight"
 	"github.com/tikv/pd/server/versioninfo"
 )
 
@@ -377,6 +379,155 @@ func (s *testClusterInfoSuite) TestRegionFlowChanged(c *C) {
 
 }
 
+func (s *testClusterInfoSuite) TestRegionHeartbeatTS(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+	c.Assert(cluster.putStoreLocked(core.NewStoreInfo(&metapb.Store{Id: 1})), IsNil)
+	peer := &metapb.Peer{Id: 1, StoreId: 1}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: []*metapb.Peer{peer}}, peer)
+	c.Assert(cluster.GetStore(1).GetLastRegionHeartbeatTS().IsZero(), IsTrue)
+
+	c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	ts := cluster.GetStore(1).GetLastRegionHeartbeatTS()
+	c.Assert(ts.IsZero(), IsFalse)
+	// The time is not recorded again within the interval.
+	c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	c.Assert(cluster.GetStore(1).GetLastRegionHeartbeatTS(), Equals, ts)
+}
+
+func (s *testClusterInfoSuite) TestRegionWeightPriority(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	storage := core.NewStorage(kv.NewMemoryKV())
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	peer := &metapb.Peer{Id: 1, StoreId: 1}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: []*metapb.Peer{peer}}, peer)
+	// The region misses 2 replicas.
+	c.Assert(cluster.getRegionAbnormalPriority(region), Equals, 2)
+
+	cluster.weightManager = weight.NewManager(storage)
+	c.Assert(cluster.weightManager.SetWeight(&weight.KeyRangeWeight{ID: "w", Weight: 2.5}), IsNil)
+	c.Assert(cluster.getRegionAbnormalPriority(region), Equals, 5)
+	// The healthy region is not prioritized.
+	region = region.Clone(core.SetPeers([]*metapb.Peer{peer, {Id: 2, StoreId: 2}, {Id: 3, StoreId: 3}}))
+	c.Assert(cluster.getRegionAbnormalPriority(region), Equals, 0)
+}
+
+func (s *testClusterInfoSuite) TestRegionCacheMemory(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+	for _, region := range newTestRegions(10, 3) {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	}
+	size := cluster.core.GetRegionMemorySize()
+	c.Assert(size > 0, IsTrue)
+
+	// No limit.
+	cluster.checkRegionCacheMemory()
+	c.Assert(cluster.regionKeysCompacted, IsFalse)
+
+	setLimit := func(limit int64) {
+		cfg := opt.GetPDServerConfig().Clone()
+		cfg.RegionCacheMemoryLimit = typeutil.ByteSize(limit)
+		opt.SetPDServerConfig(cfg)
+	}
+	// The cached regions share the 9 boundary keys with their neighbors.
+	setLimit(size - 1)
+	cluster.checkRegionCacheMemory()
+	c.Assert(cluster.regionKeysCompacted, IsTrue)
+	c.Assert(cluster.core.GetRegionMemorySize(), Equals, size-9)
+
+	// The compaction goes on until the memory falls well below the limit.
+	setLimit(size)
+	cluster.checkRegionCacheMemory()
+	c.Assert(cluster.regionKeysCompacted, IsTrue)
+	setLimit(0)
+	cluster.checkRegionCacheMemory()
+	c.Assert(cluster.regionKeysCompacted, IsFalse)
+}
+
+func (s *testClusterInfoSuite) TestDropCacheAllRegion(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+	regions := newTestRegions(10, 3)
+	for _, region := range regions {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	}
+	c.Assert(cluster.isPrepared(), IsTrue)
+
+	// The scheduling waits until most of the dropped regions are back.
+	cluster.DropCacheAllRegion()
+	c.Assert(cluster.core.GetRegionCount(), Equals, 0)
+	c.Assert(cluster.isPrepared(), IsFalse)
+	for _, region := range regions[:7] {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	}
+	c.Assert(cluster.isPrepared(), IsFalse)
+	for _, region := range regions[7:] {
+		c.Assert(cluster.processRegionHeartbeat(region), IsNil)
+	}
+	c.Assert(cluster.isPrepared(), IsTrue)
+}
+
+func (s *testClusterInfoSuite) TestHibernatedRegion(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+	for _, store := range newTestStores(3) {
+		// Store 3 stops sending heartbeats.
+		if store.GetID() != 3 {
+			store = store.Clone(core.SetLastHeartbeatTS(time.Now()))
+		}
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	meta := newTestRegionMeta(1)
+	for id := uint64(1); id <= 3; id++ {
+		meta.Peers = append(meta.Peers, &metapb.Peer{Id: id + 10, StoreId: id})
+	}
+	heartbeat := &pdpb.RegionHeartbeatRequest{
+		Region: meta,
+		Leader: meta.Peers[0],
+		DownPeers: []*pdpb.PeerStats{
+			{Peer: meta.Peers[1], DownSeconds: 3600},
+			{Peer: meta.Peers[2], DownSeconds: 3600},
+		},
+		Interval: &pdpb.TimeInterval{StartTimestamp: 1000, EndTimestamp: 1060},
+	}
+
+	// The region reporting at the normal interval is not hibernated.
+	c.Assert(cluster.processRegionHeartbeat(core.RegionFromHeartbeat(heartbeat)), IsNil)
+	region := cluster.GetRegion(1)
+	c.Assert(region.IsHibernated(), IsFalse)
+	c.Assert(region.GetDownPeers(), HasLen, 2)
+	c.Assert(cluster.GetStore(1).HibernatesRegions(), IsFalse)
+
+	// The busy region reporting late is not hibernated.
+	heartbeat.Interval = &pdpb.TimeInterval{StartTimestamp: 1060, EndTimestamp: 1660}
+	heartbeat.BytesWritten = 1024
+	c.Assert(cluster.processRegionHeartbeat(core.RegionFromHeartbeat(heartbeat)), IsNil)
+	region = cluster.GetRegion(1)
+	c.Assert(region.IsHibernated(), IsFalse)
+	c.Assert(region.GetDownPeers(), HasLen, 2)
+
+	// Only the down peer on the store which stops sending heartbeats is kept.
+	heartbeat.Interval = &pdpb.TimeInterval{StartTimestamp: 1660, EndTimestamp: 2260}
+	heartbeat.BytesWritten = 0
+	c.Assert(cluster.processRegionHeartbeat(core.RegionFromHeartbeat(heartbeat)), IsNil)
+	region = cluster.GetRegion(1)
+	c.Assert(region.IsHibernated(), IsTrue)
+	c.Assert(region.GetDownPeers(), HasLen, 1)
+	c.Assert(region.GetDownPeers()[0].GetPeer().GetStoreId(), Equals, uint64(3))
+
+	// The gaps of the region heartbeats of the store are tolerated.
+	store := cluster.GetStore(1)
+	c.Assert(store.HibernatesRegions(), IsTrue)
+	store = store.Clone(core.SetLeaderCount(1), core.SetLastRegionHeartbeatTS(time.Now().Add(-time.Hour)))
+	c.Assert(store.IsHeartbeatStale(time.Minute), IsFalse)
+}
+
 func (s *testClusterInfoSuite) TestConcurrentRegionHeartbeat(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
@@ -510,6 +661,126 @@ func (s *testClusterInfoSuite) TestRegionSplitAndMerge(c *C) {
 	}
 }
 
+func (s *testClusterInfoSuite) TestRemovePhysicallyDestroyedStore(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	storage := core.NewStorage(kv.NewMemoryKV())
+	tc := newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	version := versioninfo.MinSupportedVersion(versioninfo.Version2_0).String()
+	newStore := func(id uint64, addr string) *metapb.Store {
+		return &metapb.Store{Id: id, Address: addr, Version: version}
+	}
+	c.Assert(tc.PutStore(newStore(1, "127.0.0.1:1"), false), IsNil)
+	c.Assert(tc.PutStore(newStore(2, "127.0.0.1:2"), false), IsNil)
+
+	c.Assert(tc.RemoveStore(1, true), IsNil)
+	c.Assert(tc.GetStore(1).IsOffline(), IsTrue)
+	c.Assert(tc.GetStore(1).IsPhysicallyDestroyed(), IsTrue)
+	// An offline store can be marked as physically destroyed later.
+	c.Assert(tc.RemoveStore(2, false), IsNil)
+	c.Assert(tc.GetStore(2).IsPhysicallyDestroyed(), IsFalse)
+	c.Assert(tc.RemoveStore(2, true), IsNil)
+	c.Assert(tc.GetStore(2).IsPhysicallyDestroyed(), IsTrue)
+
+	// Neither the ID nor the address can be registered again, even if the
+	// store is buried.
+	c.Assert(tc.PutStore(newStore(1, "127.0.0.1:3"), false), NotNil)
+	c.Assert(tc.PutStore(newStore(3, "127.0.0.1:1"), false), NotNil)
+	c.Assert(tc.BuryStore(1, false), IsNil)
+	c.Assert(tc.PutStore(newStore(3, "127.0.0.1:1"), false), NotNil)
+	c.Assert(tc.PutStore(newStore(3, "127.0.0.1:3"), false), IsNil)
+
+	// The store is still blocked after its record is removed.
+	c.Assert(tc.RemoveTombStoneRecords(), IsNil)
+	c.Assert(tc.GetStore(1), IsNil)
+	c.Assert(tc.PutStore(newStore(1, "127.0.0.1:4"), false), NotNil)
+	c.Assert(tc.PutStore(newStore(4, "127.0.0.1:1"), false), NotNil)
+
+	// The blocked stores are persisted apart from the store records.
+	tc = newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	c.Assert(storage.LoadStores(tc.core.PutStore), IsNil)
+	c.Assert(tc.loadDestroyedStores(), IsNil)
+	c.Assert(tc.GetDestroyedStores(), DeepEquals, map[uint64]string{1: "127.0.0.1:1", 2: "127.0.0.1:2"})
+	c.Assert(tc.GetStore(2).IsPhysicallyDestroyed(), IsTrue)
+	c.Assert(tc.GetStore(3).IsPhysicallyDestroyed(), IsFalse)
+	c.Assert(tc.PutStore(newStore(4, "127.0.0.1:1"), false), NotNil)
+
+	// The block is lifted explicitly after the store is buried or removed.
+	c.Assert(tc.RemoveDestroyedStore(3), NotNil)
+	c.Assert(tc.RemoveDestroyedStore(2), NotNil)
+	c.Assert(tc.RemoveDestroyedStore(1), IsNil)
+	c.Assert(tc.PutStore(newStore(4, "127.0.0.1:1"), false), IsNil)
+	var ids []string
+	c.Assert(storage.LoadDestroyedStores(func(k, v string) { ids = append(ids, k) }), IsNil)
+	c.Assert(ids, DeepEquals, []string{fmt.Sprintf("%020d", 2)})
+}
+
+func (s *testClusterInfoSuite) TestStoreCordonPersisted(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	storage := core.NewStorage(kv.NewMemoryKV())
+	tc := newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	version := versioninfo.MinSupportedVersion(versioninfo.Version2_0).String()
+	for i := uint64(1); i <= 4; i++ {
+		store := &metapb.Store{Id: i, Address: fmt.Sprintf("127.0.0.1:%d", i), Version: version}
+		c.Assert(tc.PutStore(store, false), IsNil)
+	}
+	c.Assert(tc.CordonStore(5, 0), NotNil)
+	c.Assert(tc.CordonStore(1, 0), IsNil)
+	c.Assert(tc.CordonStore(2, time.Hour), IsNil)
+	c.Assert(tc.CordonStore(3, time.Millisecond), IsNil)
+	c.Assert(tc.CordonStore(4, 0), IsNil)
+	c.Assert(tc.UncordonStore(4), IsNil)
+	time.Sleep(10 * time.Millisecond)
+
+	// The cordons survive the reload, and the expired ones are dropped.
+	tc = newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	c.Assert(storage.LoadStores(tc.core.PutStore), IsNil)
+	c.Assert(tc.loadStoreCordons(), IsNil)
+	c.Assert(tc.GetStore(1).IsCordoned(), IsTrue)
+	c.Assert(tc.GetStore(1).GetCordonDeadline().IsZero(), IsTrue)
+	c.Assert(tc.GetStore(2).IsCordoned(), IsTrue)
+	c.Assert(tc.GetStore(2).GetCordonDeadline().IsZero(), IsFalse)
+	c.Assert(tc.GetStore(3).IsCordoned(), IsFalse)
+	c.Assert(tc.GetStore(4).IsCordoned(), IsFalse)
+	var ids []string
+	c.Assert(storage.LoadStoreCordons(func(k, v string) { ids = append(ids, k) }), IsNil)
+	c.Assert(ids, DeepEquals, []string{fmt.Sprintf("%020d", 1), fmt.Sprintf("%020d", 2)})
+}
+
+func (s *testClusterInfoSuite) TestStorePausePersisted(c *C) {
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	storage := core.NewStorage(kv.NewMemoryKV())
+	tc := newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	version := versioninfo.MinSupportedVersion(versioninfo.Version2_0).String()
+	for i := uint64(1); i <= 4; i++ {
+		store := &metapb.Store{Id: i, Address: fmt.Sprintf("127.0.0.1:%d", i), Version: version}
+		c.Assert(tc.PutStore(store, false), IsNil)
+	}
+	c.Assert(tc.PauseStoreScheduling(5, 0), NotNil)
+	c.Assert(tc.PauseStoreScheduling(1, 0), IsNil)
+	c.Assert(tc.PauseStoreScheduling(2, time.Hour), IsNil)
+	c.Assert(tc.PauseStoreScheduling(3, time.Millisecond), IsNil)
+	c.Assert(tc.PauseStoreScheduling(4, 0), IsNil)
+	c.Assert(tc.ResumeStoreScheduling(4), IsNil)
+	time.Sleep(10 * time.Millisecond)
+
+	// The pauses survive the reload, and the expired ones are dropped.
+	tc = newTestRaftCluster(mockid.NewIDAllocator(), opt, storage, core.NewBasicCluster())
+	c.Assert(storage.LoadStores(tc.core.PutStore), IsNil)
+	c.Assert(tc.loadStorePauses(), IsNil)
+	c.Assert(tc.GetStore(1).IsSchedulingPaused(), IsTrue)
+	c.Assert(tc.GetStore(1).GetPauseDeadline().IsZero(), IsTrue)
+	c.Assert(tc.GetStore(2).IsSchedulingPaused(), IsTrue)
+	c.Assert(tc.GetStore(2).GetPauseDeadline().IsZero(), IsFalse)
+	c.Assert(tc.GetStore(3).IsSchedulingPaused(), IsFalse)
+	c.Assert(tc.GetStore(4).IsSchedulingPaused(), IsFalse)
+	var ids []string
+	c.Assert(storage.LoadStorePauses(func(k, v string) { ids = append(ids, k) }), IsNil)
+	c.Assert(ids, DeepEquals, []string{fmt.Sprintf("%020d", 1), fmt.Sprintf("%020d", 2)})
+}
+
 func (s *testClusterInfoSuite) TestUpdateStorePendingPeerCount(c *C) {
 	_, opt, err := newTestScheduleConfig()
 	c.Assert(err, IsNil)
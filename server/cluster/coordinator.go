@@ -32,6 +32,7 @@ import (
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
 	"github.com/tikv/pd/server/schedulers"
 	"github.com/tikv/pd/server/statistics"
 	"go.uber.org/zap"
@@ -43,8 +44,12 @@ const (
 	collectTimeout            = 5 * time.Minute
 	maxScheduleRetries        = 10
 	maxLoadConfigRetries      = 10
+	// patrolPriorityBudgetRatio is the max share of the time budget of a round of
+	// patrol that the priority regions can take. The rest is reserved for the
+	// suspect regions and the regular patrol, so a burst of abnormal regions does
+	// not stall them.
+	patrolPriorityBudgetRatio = 0.5
 
-	patrolScanRegionLimit = 128 // It takes about 14 minutes to iterate 1 million regions.
 	// PluginLoad means action for load plugin
 	PluginLoad = "PluginLoad"
 	// PluginUnload means action for unload plugin
@@ -105,8 +110,22 @@ func (c *coordinator) patrolRegions() {
 			return
 		}
 
-		// Check suspect regions first.
+		// The region cache is being repopulated after it was dropped.
+		if !c.cluster.isPrepared() {
+			continue
+		}
+
+		budget := newTimeBudget(c.cluster.GetOpts().GetScheduleTimeBudget())
+		// Check the abnormal regions first.
+		c.checkPriorityRegions(budget.share(patrolPriorityBudgetRatio))
+
+		// Check suspect regions.
 		for _, id := range c.cluster.GetSuspectRegions() {
+			if budget.exhausted() {
+				// The rest are still suspect and checked in the next round.
+				scheduleBudgetExceededCounter.WithLabelValues("patrol-suspect-regions").Inc()
+				break
+			}
 			region := c.cluster.GetRegion(id)
 			if region == nil {
 				// the region could be recent split, continue to wait.
@@ -129,7 +148,10 @@ func (c *coordinator) patrolRegions() {
 		// Check suspect key ranges
 		c.checkSuspectKeyRanges()
 
-		regions := c.cluster.ScanRegions(key, nil, patrolScanRegionLimit)
+		c.evictRestartingLeaders()
+		c.evictDrainingLeaders()
+
+		regions := c.cluster.ScanRegions(key, nil, c.cluster.GetOpts().GetPatrolRegionBatchSize())
 		if len(regions) == 0 {
 			// Resets the scan key.
 			key = nil
@@ -137,6 +159,11 @@ func (c *coordinator) patrolRegions() {
 		}
 
 		for _, region := range regions {
+			if budget.exhausted() {
+				// Resumes from the key in the next round.
+				scheduleBudgetExceededCounter.WithLabelValues("patrol-regions").Inc()
+				break
+			}
 			// Skips the region if there is already a pending operator.
 			if c.opController.GetOperator(region.GetID()) != nil {
 				continue
@@ -161,6 +188,102 @@ func (c *coordinator) patrolRegions() {
 	}
 }
 
+// checkPriorityRegions checks the regions reported abnormal by heartbeats,
+// such as the regions with down peers or missing replicas.
+func (c *coordinator) checkPriorityRegions(budget timeBudget) {
+	var exceeded bool
+	for _, id := range c.cluster.PopPriorityRegions(c.cluster.GetOpts().GetPatrolRegionBatchSize()) {
+		region := c.cluster.GetRegion(id)
+		if region == nil || c.opController.GetOperator(id) != nil {
+			continue
+		}
+		if exceeded || budget.exhausted() {
+			exceeded = true
+			c.cluster.AddPriorityRegion(id, c.cluster.getRegionAbnormalPriority(region))
+			continue
+		}
+		checkerIsBusy, ops := c.checkers.CheckRegion(region)
+		if checkerIsBusy {
+			// Check it again in the next round.
+			c.cluster.AddPriorityRegion(id, c.cluster.getRegionAbnormalPriority(region))
+			continue
+		}
+		if len(ops) > 0 {
+			c.opController.AddWaitingOperator(ops...)
+		}
+	}
+	if exceeded {
+		scheduleBudgetExceededCounter.WithLabelValues("patrol-priority-regions").Inc()
+	}
+}
+
+// timeBudget is the time budget of a round of patrol or scheduling. The zero
+// value means no budget.
+type timeBudget struct {
+	deadline time.Time
+}
+
+func newTimeBudget(budget time.Duration) timeBudget {
+	if budget <= 0 {
+		return timeBudget{}
+	}
+	return timeBudget{deadline: time.Now().Add(budget)}
+}
+
+func (b timeBudget) exhausted() bool {
+	return !b.deadline.IsZero() && !time.Now().Before(b.deadline)
+}
+
+// share returns a budget taking the ratio of the rest of the budget.
+func (b timeBudget) share(ratio float64) timeBudget {
+	if b.deadline.IsZero() {
+		return b
+	}
+	now := time.Now()
+	return timeBudget{deadline: now.Add(time.Duration(float64(b.deadline.Sub(now)) * ratio))}
+}
+
+// budgetCluster cuts off a scheduler whose Schedule call runs out of the
+// budget. The schedulers pick the regions to schedule randomly, once the
+// budget is exhausted no more region is picked, so the call returns soon
+// instead of overrunning the round.
+type budgetCluster struct {
+	opt.Cluster
+	budget timeBudget
+}
+
+// RandFollowerRegion returns a random region that has a follower on the store.
+func (c *budgetCluster) RandFollowerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if c.budget.exhausted() {
+		return nil
+	}
+	return c.Cluster.RandFollowerRegion(storeID, ranges, opts...)
+}
+
+// RandLeaderRegion returns a random region that has leader on the store.
+func (c *budgetCluster) RandLeaderRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if c.budget.exhausted() {
+		return nil
+	}
+	return c.Cluster.RandLeaderRegion(storeID, ranges, opts...)
+}
+
+// RandLearnerRegion returns a random region that has a learner on the store.
+func (c *budgetCluster) RandLearnerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if c.budget.exhausted() {
+		return nil
+	}
+	return c.Cluster.RandLearnerRegion(storeID, ranges, opts...)
+}
+
+// RandPendingRegion returns a random region that has a pending peer on the store.
+func (c *budgetCluster) RandPendingRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if c.budget.exhausted() {
+		return nil
+	}
+	return c.Cluster.RandPendingRegion(storeID, ranges, opts...)
+}
+
 // checkSuspectKeyRanges would pop one suspect key range group
 // The regions of new version key range and old version key range would be placed into
 // the suspect regions map
@@ -541,6 +664,16 @@ func (c *coordinator) addScheduler(scheduler schedule.Scheduler, args ...string)
 	if err := s.Prepare(c.cluster); err != nil {
 		return err
 	}
+	namespace, err := c.cluster.storage.LoadSchedulerNamespace(s.GetName())
+	if err != nil {
+		log.Error("can not load the namespace of scheduler", zap.String("scheduler-name", s.GetName()), errs.ZapError(err))
+	}
+	s.SetNamespace(namespace)
+	storeList := &schedule.StoreList{}
+	if _, err := c.cluster.storage.LoadSchedulerStoreList(s.GetName(), storeList); err != nil {
+		log.Error("can not load the store list of scheduler", zap.String("scheduler-name", s.GetName()), errs.ZapError(err))
+	}
+	s.SetStoreList(storeList)
 
 	c.wg.Add(1)
 	go c.runScheduler(s)
@@ -582,6 +715,16 @@ func (c *coordinator) removeScheduler(name string) error {
 		return err
 	}
 
+	if err = c.cluster.storage.RemoveSchedulerNamespace(name); err != nil {
+		log.Error("can not remove the scheduler namespace", errs.ZapError(err))
+		return err
+	}
+
+	if err = c.cluster.storage.RemoveSchedulerStoreList(name); err != nil {
+		log.Error("can not remove the scheduler store list", errs.ZapError(err))
+		return err
+	}
+
 	return nil
 }
 
@@ -637,6 +780,78 @@ func (c *coordinator) pauseOrResumeScheduler(name string, t int64) error {
 	return err
 }
 
+func (c *coordinator) setSchedulerNamespace(name, namespace string) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.cluster == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	var err error
+	if namespace == "" {
+		err = c.cluster.storage.RemoveSchedulerNamespace(name)
+	} else {
+		err = c.cluster.storage.SaveSchedulerNamespace(name, namespace)
+	}
+	if err != nil {
+		return err
+	}
+	s.SetNamespace(namespace)
+	return nil
+}
+
+func (c *coordinator) getSchedulerNamespace(name string) (string, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return "", errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return "", errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	return s.GetNamespace(), nil
+}
+
+func (c *coordinator) setSchedulerStoreList(name string, list *schedule.StoreList) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.cluster == nil {
+		return errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	var err error
+	if list.IsEmpty() {
+		err = c.cluster.storage.RemoveSchedulerStoreList(name)
+	} else {
+		err = c.cluster.storage.SaveSchedulerStoreList(name, list)
+	}
+	if err != nil {
+		return err
+	}
+	s.SetStoreList(list)
+	return nil
+}
+
+func (c *coordinator) getSchedulerStoreList(name string) (*schedule.StoreList, error) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.cluster == nil {
+		return nil, errs.ErrNotBootstrapped.FastGenByArgs()
+	}
+	s, ok := c.schedulers[name]
+	if !ok {
+		return nil, errs.ErrSchedulerNotFound.FastGenByArgs()
+	}
+	return s.GetStoreList(), nil
+}
+
 func (c *coordinator) isSchedulerPaused(name string) (bool, error) {
 	c.RLock()
 	defer c.RUnlock()
@@ -662,7 +877,7 @@ func (c *coordinator) runScheduler(s *scheduleController) {
 		select {
 		case <-timer.C:
 			timer.Reset(s.GetInterval())
-			if !s.AllowSchedule() {
+			if !c.cluster.isPrepared() || !s.AllowSchedule() {
 				continue
 			}
 			if op := s.Schedule(); op != nil {
@@ -688,6 +903,11 @@ type scheduleController struct {
 	ctx          context.Context
 	cancel       context.CancelFunc
 	delayUntil   int64
+	// namespace is the namespace which the scheduler is bound to, the
+	// scheduler works on the whole cluster if it is empty.
+	namespace atomic.Value
+	// storeList limits the stores used by the scheduler.
+	storeList atomic.Value
 }
 
 // newScheduleController creates a new scheduleController.
@@ -711,10 +931,54 @@ func (s *scheduleController) Stop() {
 	s.cancel()
 }
 
+// SetNamespace binds the scheduler to the namespace.
+func (s *scheduleController) SetNamespace(namespace string) {
+	s.namespace.Store(namespace)
+}
+
+// GetNamespace returns the namespace which the scheduler is bound to.
+func (s *scheduleController) GetNamespace() string {
+	namespace, _ := s.namespace.Load().(string)
+	return namespace
+}
+
+// SetStoreList limits the stores used by the scheduler.
+func (s *scheduleController) SetStoreList(list *schedule.StoreList) {
+	s.storeList.Store(list)
+}
+
+// GetStoreList returns the store list which the scheduler is limited to.
+func (s *scheduleController) GetStoreList() *schedule.StoreList {
+	list, _ := s.storeList.Load().(*schedule.StoreList)
+	if list == nil {
+		return &schedule.StoreList{}
+	}
+	return list
+}
+
+// clusterView returns the cluster visible to the scheduler.
+func (s *scheduleController) clusterView() opt.Cluster {
+	var cluster opt.Cluster = s.cluster
+	if namespace := s.GetNamespace(); namespace != "" {
+		cluster = schedule.GenNamespaceCluster(cluster, namespace)
+	}
+	if list := s.GetStoreList(); !list.IsEmpty() {
+		cluster = schedule.GenStoreListCluster(cluster, list)
+	}
+	return cluster
+}
+
 func (s *scheduleController) Schedule() []*operator.Operator {
+	budget := newTimeBudget(s.cluster.GetOpts().GetScheduleTimeBudget())
+	cluster := &budgetCluster{Cluster: s.clusterView(), budget: budget}
 	for i := 0; i < maxScheduleRetries; i++ {
+		if budget.exhausted() {
+			// Stops trying, the scheduler tries again in the next round.
+			scheduleBudgetExceededCounter.WithLabelValues(s.GetName()).Inc()
+			break
+		}
 		// If we have schedule, reset interval to the minimal interval.
-		if op := s.Scheduler.Schedule(s.cluster); op != nil {
+		if op := s.Scheduler.Schedule(cluster); op != nil {
 			s.nextInterval = s.Scheduler.GetMinInterval()
 			return op
 		}
@@ -730,7 +994,7 @@ func (s *scheduleController) GetInterval() time.Duration {
 
 // AllowSchedule returns if a scheduler is allowed to schedule.
 func (s *scheduleController) AllowSchedule() bool {
-	return s.Scheduler.IsScheduleAllowed(s.cluster) && !s.IsPaused()
+	return s.Scheduler.IsScheduleAllowed(s.clusterView()) && !s.IsPaused()
 }
 
 // isPaused returns if a scheduler is paused.
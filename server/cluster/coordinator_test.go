@@ -237,6 +237,31 @@ func (s *testCoordinatorSuite) TestDispatch(c *C) {
 	waitNoResponse(c, stream)
 }
 
+func (s *testCoordinatorSuite) TestWakeRegions(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+	tc.RaftCluster.coordinator = co
+
+	c.Assert(tc.addRegionStore(1, 1), IsNil)
+	c.Assert(tc.addRegionStore(2, 1), IsNil)
+	c.Assert(tc.addLeaderRegion(1, 1, 2), IsNil)
+
+	// The region is not woken up if the store of the leader has no stream.
+	c.Assert(tc.WakeRegions([]uint64{1, 2}), DeepEquals, []uint64{1, 2})
+
+	// The leader is asked to transfer the leadership to itself. Wake the
+	// region again until it is sent, as the stream may not be bound yet.
+	stream := mockhbstream.NewHeartbeatStream()
+	co.hbStreams.BindStream(1, stream)
+	testutil.WaitUntil(c, func(c *C) bool {
+		return len(tc.WakeRegions([]uint64{1, 2})) == 1
+	})
+	res := stream.Recv()
+	c.Assert(res, NotNil)
+	c.Assert(res.GetRegionId(), Equals, uint64(1))
+	c.Assert(res.GetTransferLeader().GetPeer().GetId(), Equals, tc.GetRegion(1).GetLeader().GetId())
+}
+
 func dispatchHeartbeat(co *coordinator, region *core.RegionInfo, stream opt.HeartbeatStream) error {
 	co.hbStreams.BindStream(region.GetLeader().GetStoreId(), stream)
 	if err := co.cluster.putRegion(region.Clone()); err != nil {
@@ -248,7 +273,7 @@ func dispatchHeartbeat(co *coordinator, region *core.RegionInfo, stream opt.Hear
 
 func (s *testCoordinatorSuite) TestCollectMetrics(c *C) {
 	tc, co, cleanup := prepare(nil, func(tc *testCluster) {
-		tc.regionStats = statistics.NewRegionStatistics(tc.GetOpts(), nil)
+		tc.regionStats = statistics.NewRegionStatistics(tc.GetOpts(), nil, "")
 	}, func(co *coordinator) { co.run() }, c)
 	defer cleanup()
 
@@ -724,6 +749,199 @@ func (s *testCoordinatorSuite) TestPersistScheduler(c *C) {
 	c.Assert(co.schedulers, HasLen, 3)
 }
 
+func (s *testCoordinatorSuite) TestSchedulerNamespace(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+	storage := tc.RaftCluster.storage
+
+	c.Assert(co.setSchedulerNamespace("not-exist", "analytics"), NotNil)
+	c.Assert(co.setSchedulerNamespace(schedulers.BalanceLeaderName, "analytics"), IsNil)
+	namespace, err := co.getSchedulerNamespace(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(namespace, Equals, "analytics")
+	namespace, err = storage.LoadSchedulerNamespace(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(namespace, Equals, "analytics")
+	_, ok := co.schedulers[schedulers.BalanceLeaderName].clusterView().(*schedule.NamespaceCluster)
+	c.Assert(ok, IsTrue)
+
+	// Unbind the namespace.
+	c.Assert(co.setSchedulerNamespace(schedulers.BalanceLeaderName, ""), IsNil)
+	namespace, err = storage.LoadSchedulerNamespace(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(namespace, Equals, "")
+	_, ok = co.schedulers[schedulers.BalanceLeaderName].clusterView().(*schedule.NamespaceCluster)
+	c.Assert(ok, IsFalse)
+
+	// The binding is removed with the scheduler.
+	c.Assert(co.setSchedulerNamespace(schedulers.BalanceLeaderName, "analytics"), IsNil)
+	c.Assert(co.removeScheduler(schedulers.BalanceLeaderName), IsNil)
+	namespace, err = storage.LoadSchedulerNamespace(schedulers.BalanceLeaderName)
+	c.Assert(err, IsNil)
+	c.Assert(namespace, Equals, "")
+}
+
+func (s *testCoordinatorSuite) TestSchedulerStoreList(c *C) {
+	tc, co, cleanup := prepare(nil, nil, func(co *coordinator) { co.run() }, c)
+	defer cleanup()
+	storage := tc.RaftCluster.storage
+
+	list := &schedule.StoreList{Deny: []uint64{4, 5}}
+	c.Assert(co.setSchedulerStoreList("not-exist", list), NotNil)
+	c.Assert(co.setSchedulerStoreList(schedulers.BalanceRegionName, list), IsNil)
+	got, err := co.getSchedulerStoreList(schedulers.BalanceRegionName)
+	c.Assert(err, IsNil)
+	c.Assert(got, DeepEquals, list)
+	loaded := &schedule.StoreList{}
+	ok, err := storage.LoadSchedulerStoreList(schedulers.BalanceRegionName, loaded)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+	c.Assert(loaded, DeepEquals, list)
+	_, ok = co.schedulers[schedulers.BalanceRegionName].clusterView().(*schedule.StoreListCluster)
+	c.Assert(ok, IsTrue)
+
+	// Remove the limit.
+	c.Assert(co.setSchedulerStoreList(schedulers.BalanceRegionName, &schedule.StoreList{}), IsNil)
+	ok, err = storage.LoadSchedulerStoreList(schedulers.BalanceRegionName, loaded)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+	_, ok = co.schedulers[schedulers.BalanceRegionName].clusterView().(*schedule.StoreListCluster)
+	c.Assert(ok, IsFalse)
+
+	// The list is removed with the scheduler.
+	c.Assert(co.setSchedulerStoreList(schedulers.BalanceRegionName, list), IsNil)
+	c.Assert(co.removeScheduler(schedulers.BalanceRegionName), IsNil)
+	ok, err = storage.LoadSchedulerStoreList(schedulers.BalanceRegionName, loaded)
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+}
+
+func (s *testCoordinatorSuite) TestEvictRestartingLeaders(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+	c.Assert(tc.addLeaderStore(2, 0), IsNil)
+	c.Assert(tc.addLeaderStore(3, 0), IsNil)
+	c.Assert(tc.addLeaderRegion(1, 1, 2, 3), IsNil)
+
+	// Nothing to do if no store is restarting.
+	co.evictRestartingLeaders()
+	c.Assert(co.opController.GetOperator(1), IsNil)
+
+	c.Assert(tc.SetStoreRestart(1, time.Hour), IsNil)
+	c.Assert(tc.GetStore(1).IsRestarting(), IsTrue)
+	co.evictRestartingLeaders()
+	op := co.opController.GetOperator(1)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, restartEvictLeaderDesc)
+	c.Assert(op.Step(0).(operator.TransferLeader).FromStore, Equals, uint64(1))
+
+	c.Assert(tc.CancelStoreRestart(1), IsNil)
+	c.Assert(tc.GetStore(1).IsRestarting(), IsFalse)
+	c.Assert(tc.SetStoreRestart(4, time.Hour), NotNil)
+}
+
+func (s *testCoordinatorSuite) TestDrainStore(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	c.Assert(tc.addLeaderStore(1, 1), IsNil)
+	c.Assert(tc.addLeaderStore(2, 0), IsNil)
+	c.Assert(tc.addLeaderStore(3, 0), IsNil)
+	c.Assert(tc.addLeaderRegion(1, 1, 2, 3), IsNil)
+	checkStage := func(stage storeDrainStage) {
+		progress := tc.GetStoreDrainProgress()
+		c.Assert(progress, HasLen, 1)
+		c.Assert(progress[0].StoreID, Equals, uint64(1))
+		c.Assert(progress[0].Stage, Equals, stage.String())
+	}
+
+	// The leaders are evicted first, and the store is not a target any more.
+	c.Assert(tc.DrainStore(1), IsNil)
+	checkStage(storeDrainEvictingLeader)
+	c.Assert(tc.GetStore(1).IsDraining(), IsTrue)
+	co.evictDrainingLeaders()
+	op := co.opController.GetOperator(1)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, drainEvictLeaderDesc)
+	c.Assert(op.Step(0).(operator.TransferLeader).FromStore, Equals, uint64(1))
+	tc.storeDrainController.checkStores()
+	checkStage(storeDrainEvictingLeader)
+	c.Assert(tc.GetStore(1).IsUp(), IsTrue)
+
+	// The store is set offline once its leaders are evicted.
+	c.Assert(tc.updateLeaderCount(1, 0), IsNil)
+	tc.storeDrainController.checkStores()
+	checkStage(storeDrainMigratingRegion)
+	c.Assert(tc.GetStore(1).IsOffline(), IsTrue)
+
+	// The drain is persisted.
+	d := newStoreDrainController(tc.RaftCluster)
+	c.Assert(d.load(), IsNil)
+	c.Assert(d.drains, HasLen, 1)
+	c.Assert(d.drains[1].Stage, Equals, storeDrainMigratingRegion)
+
+	// The store is set up again if the drain is canceled.
+	c.Assert(tc.CancelStoreDrain(1), IsNil)
+	c.Assert(tc.GetStore(1).IsUp(), IsTrue)
+	c.Assert(tc.GetStore(1).IsDraining(), IsFalse)
+	d = newStoreDrainController(tc.RaftCluster)
+	c.Assert(d.load(), IsNil)
+	c.Assert(d.drains, HasLen, 0)
+	c.Assert(tc.GetStoreDrainProgress(), HasLen, 0)
+	c.Assert(tc.CancelStoreDrain(1), NotNil)
+
+	// The drain is finished once the store becomes tombstone.
+	c.Assert(tc.DrainStore(1), IsNil)
+	tc.storeDrainController.checkStores()
+	checkStage(storeDrainMigratingRegion)
+	c.Assert(tc.BuryStore(1, false), IsNil)
+	tc.storeDrainController.checkStores()
+	checkStage(storeDrainFinished)
+	c.Assert(tc.GetStoreDrainProgress()[0].Progress, Equals, 1.0)
+	c.Assert(tc.GetStore(1).IsDraining(), IsFalse)
+
+	c.Assert(tc.DrainStore(1), NotNil)
+	c.Assert(tc.DrainStore(4), NotNil)
+}
+
+func (s *testCoordinatorSuite) TestCheckPriorityRegions(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	c.Assert(tc.addRegionStore(1, 1), IsNil)
+	c.Assert(tc.addRegionStore(2, 1), IsNil)
+	c.Assert(tc.addRegionStore(3, 0), IsNil)
+	c.Assert(tc.addLeaderRegion(1, 1, 2), IsNil)
+	c.Assert(tc.PopPriorityRegions(10), HasLen, 0)
+
+	// The region misses a replica.
+	c.Assert(tc.processRegionHeartbeat(tc.GetRegion(1)), IsNil)
+	// It is checked again in the next round if the budget is exhausted.
+	co.checkPriorityRegions(timeBudget{deadline: time.Now()})
+	c.Assert(co.opController.GetOperator(1), IsNil)
+	co.checkPriorityRegions(timeBudget{})
+	op := co.opController.GetOperator(1)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "make-up-replica")
+	c.Assert(tc.PopPriorityRegions(10), HasLen, 0)
+
+	// The region with more unhealthy peers is checked first.
+	tc.AddPriorityRegion(2, 1)
+	tc.AddPriorityRegion(3, 2)
+	c.Assert(tc.PopPriorityRegions(10), DeepEquals, []uint64{3, 2})
+
+	// The new region is checked first even if it is healthy.
+	meta := newTestRegionMeta(4)
+	meta.Peers = []*metapb.Peer{{Id: 41, StoreId: 1}, {Id: 42, StoreId: 2}, {Id: 43, StoreId: 3}}
+	region := core.NewRegionInfo(meta, meta.Peers[0])
+	c.Assert(tc.processRegionHeartbeat(region), IsNil)
+	c.Assert(tc.PopPriorityRegions(10), DeepEquals, []uint64{4})
+	c.Assert(tc.processRegionHeartbeat(region), IsNil)
+	c.Assert(tc.PopPriorityRegions(10), HasLen, 0)
+}
+
 func (s *testCoordinatorSuite) TestRemoveScheduler(c *C) {
 	tc, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
 		cfg.ReplicaScheduleLimit = 0
@@ -819,7 +1037,7 @@ func BenchmarkPatrolRegion(b *testing.B) {
 
 	tc, co, cleanup := prepare(func(cfg *config.ScheduleConfig) {
 		cfg.MergeScheduleLimit = mergeLimit
-	}, nil, nil, &C{})
+	}, func(tc *testCluster) { tc.prepareChecker.isPrepared = true }, nil, &C{})
 	defer cleanup()
 
 	tc.opt.SetSplitMergeInterval(time.Duration(0))
@@ -1007,6 +1225,93 @@ func (s *mockLimitScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 	return s.counter.OperatorCount(s.kind) < s.limit
 }
 
+// mockCountScheduler counts the scheduling calls and never creates operators.
+type mockCountScheduler struct {
+	schedule.Scheduler
+	count int
+}
+
+func (s *mockCountScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
+	s.count++
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+func (s *testScheduleControllerSuite) TestScheduleTimeBudget(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	cs := &mockCountScheduler{Scheduler: scheduler}
+	sc := newScheduleController(co, cs)
+
+	// Retries until the max retries without the budget.
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(cs.count, Equals, maxScheduleRetries)
+
+	// Does not even try once the budget is exhausted.
+	cfg := tc.GetOpts().GetScheduleConfig().Clone()
+	cfg.ScheduleTimeBudget = typeutil.NewDuration(time.Nanosecond)
+	tc.GetOpts().SetScheduleConfig(cfg)
+	cs.count = 0
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(cs.count, Equals, 0)
+
+	// Stops retrying once the budget is exhausted.
+	cfg.ScheduleTimeBudget = typeutil.NewDuration(5 * time.Millisecond)
+	tc.GetOpts().SetScheduleConfig(cfg)
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(cs.count, Greater, 0)
+	c.Assert(cs.count, Less, maxScheduleRetries)
+}
+
+// mockSlowScheduler overruns the budget before picking a region.
+type mockSlowScheduler struct {
+	schedule.Scheduler
+	picked *core.RegionInfo
+}
+
+func (s *mockSlowScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
+	time.Sleep(10 * time.Millisecond)
+	s.picked = cluster.RandLeaderRegion(1, []core.KeyRange{core.NewKeyRange("", "")})
+	return nil
+}
+
+func (s *testScheduleControllerSuite) TestScheduleTimeBudgetCutOff(c *C) {
+	tc, co, cleanup := prepare(nil, nil, nil, c)
+	defer cleanup()
+	c.Assert(tc.addLeaderRegion(1, 1), IsNil)
+
+	scheduler, err := schedule.CreateScheduler(schedulers.BalanceLeaderType, co.opController, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(schedulers.BalanceLeaderType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	ss := &mockSlowScheduler{Scheduler: scheduler}
+	sc := newScheduleController(co, ss)
+
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(ss.picked, NotNil)
+
+	// No region is picked once the call overruns the budget.
+	cfg := tc.GetOpts().GetScheduleConfig().Clone()
+	cfg.ScheduleTimeBudget = typeutil.NewDuration(5 * time.Millisecond)
+	tc.GetOpts().SetScheduleConfig(cfg)
+	c.Assert(sc.Schedule(), IsNil)
+	c.Assert(ss.picked, IsNil)
+}
+
+func (s *testCoordinatorSuite) TestTimeBudget(c *C) {
+	c.Assert(newTimeBudget(0).exhausted(), IsFalse)
+	c.Assert(newTimeBudget(0).share(patrolPriorityBudgetRatio).deadline.IsZero(), IsTrue)
+	c.Assert(newTimeBudget(time.Hour).exhausted(), IsFalse)
+
+	// The shared budget ends before the whole one.
+	budget := newTimeBudget(time.Hour)
+	shared := budget.share(patrolPriorityBudgetRatio)
+	c.Assert(shared.deadline.Before(budget.deadline), IsTrue)
+	c.Assert(shared.deadline.After(time.Now().Add(29*time.Minute)), IsTrue)
+	c.Assert(timeBudget{deadline: time.Now()}.exhausted(), IsTrue)
+}
+
 func (s *testScheduleControllerSuite) TestController(c *C) {
 	tc, co, cleanup := prepare(nil, nil, nil, c)
 	defer cleanup()
@@ -0,0 +1,92 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/pkg/logutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/statistics"
+)
+
+// runHotRegionHistory saves the snapshot of hot regions periodically and
+// removes the history out of the retention.
+func (c *RaftCluster) runHotRegionHistory() {
+	defer logutil.LogPanic()
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.quit:
+			log.Info("hot region history job has been stopped")
+			return
+		case <-time.After(c.opt.GetHotRegionsWriteInterval()):
+			reservedDays := c.opt.GetHotRegionsReservedDays()
+			if c.hotRegionStorage == nil || reservedDays == 0 {
+				continue
+			}
+			now := time.Now()
+			if err := c.hotRegionStorage.SaveHotRegions(c.packHotRegions(now)); err != nil {
+				log.Error("failed to save hot regions", errs.ZapError(err))
+			}
+			if err := c.hotRegionStorage.DeleteHotRegionsBefore(now.AddDate(0, 0, -int(reservedDays))); err != nil {
+				log.Error("failed to delete history hot regions", errs.ZapError(err))
+			}
+		}
+	}
+}
+
+// packHotRegions converts the hot peers in cache to history records.
+func (c *RaftCluster) packHotRegions(now time.Time) []*core.HistoryHotRegion {
+	updateTime := now.UnixNano() / int64(time.Millisecond)
+	hotDegree := c.opt.GetHotRegionCacheHitsThreshold()
+	var regions []*core.HistoryHotRegion
+	for _, kind := range []statistics.FlowKind{statistics.WriteFlow, statistics.ReadFlow} {
+		for _, peers := range c.hotSpotCache.RegionStats(kind) {
+			for _, peer := range peers {
+				if peer.HotDegree < hotDegree {
+					continue
+				}
+				region := c.GetRegion(peer.RegionID)
+				if region == nil {
+					continue
+				}
+				regions = append(regions, &core.HistoryHotRegion{
+					UpdateTime:    updateTime,
+					RegionID:      peer.RegionID,
+					StoreID:       peer.StoreID,
+					IsLeader:      peer.IsLeader(),
+					HotRegionType: kind.String(),
+					HotDegree:     peer.HotDegree,
+					FlowBytes:     peer.GetByteRate(),
+					KeyRate:       peer.GetKeyRate(),
+					StartKey:      core.HexRegionKeyStr(region.GetStartKey()),
+					EndKey:        core.HexRegionKeyStr(region.GetEndKey()),
+				})
+			}
+		}
+	}
+	return regions
+}
+
+// GetHistoryHotRegions returns the history of hot regions in [start, end).
+func (c *RaftCluster) GetHistoryHotRegions(start, end time.Time) ([]*core.HistoryHotRegion, error) {
+	if c.hotRegionStorage == nil {
+		return nil, nil
+	}
+	return c.hotRegionStorage.LoadHotRegions(start.UnixNano()/int64(time.Millisecond), end.UnixNano()/int64(time.Millisecond))
+}
@@ -57,6 +57,22 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 15),
 		})
 
+	scheduleBudgetExceededCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "budget_exceeded",
+			Help:      "Counter of the rounds cut off for exceeding the schedule time budget.",
+		}, []string{"type"})
+
+	regionCacheMemoryGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "cluster",
+			Name:      "region_cache_memory_bytes",
+			Help:      "Estimated memory used by the cached regions.",
+		})
+
 	clusterStateCPUGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "pd",
@@ -71,6 +87,14 @@ var (
 			Name:      "cluster_state_current",
 			Help:      "Current state of the cluster",
 		}, []string{"state"})
+
+	throughputTuningLimitGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "throughput_tuning_limit",
+			Help:      "The limits tuned by the throughput tuning.",
+		}, []string{"type"})
 )
 
 func init() {
@@ -79,6 +103,9 @@ func init() {
 	prometheus.MustRegister(schedulerStatusGauge)
 	prometheus.MustRegister(hotSpotStatusGauge)
 	prometheus.MustRegister(patrolCheckRegionsHistogram)
+	prometheus.MustRegister(scheduleBudgetExceededCounter)
 	prometheus.MustRegister(clusterStateCPUGauge)
 	prometheus.MustRegister(clusterStateCurrent)
+	prometheus.MustRegister(regionCacheMemoryGauge)
+	prometheus.MustRegister(throughputTuningLimitGauge)
 }
@@ -0,0 +1,94 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+// CordonStore prevents the store from being selected as target of any new
+// operator. Unlike offline, the store keeps serving and keeps its current
+// data, and unlike pausing, it can still be the source of operators. The
+// cordon expires after the ttl, or never expires if the ttl is zero.
+func (c *RaftCluster) CordonStore(storeID uint64, ttl time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsTombstone() {
+		return errs.ErrStoreTombstone.FastGenByArgs(storeID)
+	}
+	deadline := core.TTLDeadline(ttl)
+	if err := c.storage.SaveStoreCordon(storeID, deadline); err != nil {
+		return err
+	}
+	c.core.PutStore(store.Clone(core.SetCordon(deadline)))
+	log.Info("store is cordoned",
+		zap.Uint64("store-id", storeID),
+		zap.Duration("ttl", ttl))
+	return nil
+}
+
+// UncordonStore makes the cordoned store be selected as target again.
+func (c *RaftCluster) UncordonStore(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if err := c.storage.DeleteStoreCordon(storeID); err != nil {
+		return err
+	}
+	c.core.PutStore(store.Clone(core.ResetCordon()))
+	log.Info("store is uncordoned", zap.Uint64("store-id", storeID))
+	return nil
+}
+
+// loadStoreCordons loads the cordons of the stores, so they survive the restart
+// of PD and the change of the leader. The expired ones are dropped. It is
+// called with the cluster locked.
+func (c *RaftCluster) loadStoreCordons() error {
+	return c.storage.LoadStoreCordons(func(k, v string) {
+		id, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			log.Error("invalid store cordon key", zap.String("key", k), errs.ZapError(errs.ErrLoadStoreCordon))
+			return
+		}
+		var deadline time.Time
+		if err := json.Unmarshal([]byte(v), &deadline); err != nil {
+			log.Error("failed to unmarshal store cordon", zap.Uint64("store-id", id), zap.String("value", v), errs.ZapError(errs.ErrLoadStoreCordon))
+			return
+		}
+		store := c.GetStore(id)
+		if store == nil || store.IsTombstone() || (!deadline.IsZero() && time.Now().After(deadline)) {
+			if err := c.storage.DeleteStoreCordon(id); err != nil {
+				log.Warn("failed to delete the stale store cordon", zap.Uint64("store-id", id), errs.ZapError(err))
+			}
+			return
+		}
+		c.core.PutStore(store.Clone(core.SetCordon(deadline)))
+	})
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+const drainEvictLeaderDesc = "drain-evict-leader"
+
+type storeDrainStage int
+
+const (
+	// storeDrainEvictingLeader transfers the leaders out of the store.
+	storeDrainEvictingLeader storeDrainStage = iota
+	// storeDrainMigratingRegion sets the store offline, so that its regions
+	// are moved to the other stores by the checkers within the store limits.
+	storeDrainMigratingRegion
+	// storeDrainFinished means the store has become tombstone.
+	storeDrainFinished
+)
+
+var storeDrainStageNames = map[storeDrainStage]string{
+	storeDrainEvictingLeader:  "evicting-leader",
+	storeDrainMigratingRegion: "migrating-region",
+	storeDrainFinished:        "finished",
+}
+
+func (s storeDrainStage) String() string {
+	return storeDrainStageNames[s]
+}
+
+// StoreDrainProgress shows the progress of draining a store.
+type StoreDrainProgress struct {
+	StoreID     uint64    `json:"store_id"`
+	Stage       string    `json:"stage"`
+	StartTime   time.Time `json:"start_time"`
+	LeaderCount int       `json:"leader_count"`
+	RegionCount int       `json:"region_count"`
+	// TotalRegionCount is the region count of the store when the drain starts.
+	TotalRegionCount int `json:"total_region_count"`
+	// Progress is the ratio of the regions that have been moved out.
+	Progress float64 `json:"progress"`
+}
+
+// storeDrain is the drain of a store, which is persisted so that the drain
+// goes on after the PD leader changes.
+type storeDrain struct {
+	Stage            storeDrainStage `json:"stage"`
+	StartTime        time.Time       `json:"start_time"`
+	TotalRegionCount int             `json:"total_region_count"`
+}
+
+// storeDrainController drains the stores which are going to be taken down.
+// It works in the following stages for each store:
+//  1. The leaders are transferred out of the store by the coordinator.
+//  2. Once there is no leader left, the store is set offline, and its
+//     regions are moved out by the checkers within the store limits.
+//  3. The store is buried as tombstone by the cluster once it is empty.
+//
+// The draining stores are flagged, so that they are not selected as target of
+// any operator during the drain.
+type storeDrainController struct {
+	sync.RWMutex
+
+	cluster *RaftCluster
+	drains  map[uint64]*storeDrain
+}
+
+func newStoreDrainController(cluster *RaftCluster) *storeDrainController {
+	return &storeDrainController{
+		cluster: cluster,
+		drains:  make(map[uint64]*storeDrain),
+	}
+}
+
+// DrainStore starts draining the store. It does nothing if the store is
+// already being drained.
+func (c *RaftCluster) DrainStore(storeID uint64) error {
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsTombstone() {
+		return errs.ErrStoreTombstone.FastGenByArgs(storeID)
+	}
+
+	d := c.storeDrainController
+	d.Lock()
+	defer d.Unlock()
+	if drain, ok := d.drains[storeID]; ok && drain.Stage != storeDrainFinished {
+		return nil
+	}
+	drain := &storeDrain{
+		Stage:            storeDrainEvictingLeader,
+		StartTime:        time.Now(),
+		TotalRegionCount: store.GetRegionCount(),
+	}
+	// The leaders of an offline store are moved out along with its regions.
+	if store.IsOffline() {
+		drain.Stage = storeDrainMigratingRegion
+	}
+	if err := c.storage.SaveStoreDrain(storeID, drain); err != nil {
+		return err
+	}
+	d.drains[storeID] = drain
+	c.setStoreDraining(storeID, true)
+	log.Info("store drain is started", zap.Uint64("store-id", storeID), zap.Stringer("stage", drain.Stage))
+	return nil
+}
+
+// CancelStoreDrain stops draining the store. If the store has been set
+// offline by the drain, it is set up again.
+func (c *RaftCluster) CancelStoreDrain(storeID uint64) error {
+	d := c.storeDrainController
+	d.Lock()
+	defer d.Unlock()
+	drain, ok := d.drains[storeID]
+	if !ok {
+		return errs.ErrStoreNotDraining.FastGenByArgs(storeID)
+	}
+	if drain.Stage == storeDrainMigratingRegion {
+		if store := c.GetStore(storeID); store != nil && store.IsOffline() {
+			if err := c.SetStoreState(storeID, metapb.StoreState_Up); err != nil {
+				return err
+			}
+		}
+	}
+	if err := c.storage.DeleteStoreDrain(storeID); err != nil {
+		return err
+	}
+	delete(d.drains, storeID)
+	c.setStoreDraining(storeID, false)
+	log.Info("store drain is canceled", zap.Uint64("store-id", storeID), zap.Stringer("stage", drain.Stage))
+	return nil
+}
+
+// GetStoreDrainProgress returns the progress of draining the stores, sorted by
+// the store ID.
+func (c *RaftCluster) GetStoreDrainProgress() []*StoreDrainProgress {
+	d := c.storeDrainController
+	d.RLock()
+	defer d.RUnlock()
+	progress := make([]*StoreDrainProgress, 0, len(d.drains))
+	for id, drain := range d.drains {
+		p := &StoreDrainProgress{
+			StoreID:          id,
+			Stage:            drain.Stage.String(),
+			StartTime:        drain.StartTime,
+			TotalRegionCount: drain.TotalRegionCount,
+		}
+		if store := c.GetStore(id); store != nil && !store.IsTombstone() {
+			p.LeaderCount = store.GetLeaderCount()
+			p.RegionCount = store.GetRegionCount()
+		}
+		switch {
+		case drain.Stage == storeDrainFinished:
+			p.Progress = 1
+		case drain.TotalRegionCount > 0 && p.RegionCount < drain.TotalRegionCount:
+			p.Progress = 1 - float64(p.RegionCount)/float64(drain.TotalRegionCount)
+		}
+		progress = append(progress, p)
+	}
+	sort.Slice(progress, func(i, j int) bool { return progress[i].StoreID < progress[j].StoreID })
+	return progress
+}
+
+// evictingStores returns the stores whose leaders should be evicted.
+func (d *storeDrainController) evictingStores() []uint64 {
+	d.RLock()
+	defer d.RUnlock()
+	var stores []uint64
+	for id, drain := range d.drains {
+		if drain.Stage == storeDrainEvictingLeader {
+			stores = append(stores, id)
+		}
+	}
+	return stores
+}
+
+// checkStores moves the drains forward according to the states of the stores.
+func (d *storeDrainController) checkStores() {
+	d.Lock()
+	defer d.Unlock()
+	for id, drain := range d.drains {
+		store := d.cluster.GetStore(id)
+		if store == nil {
+			d.remove(id)
+			continue
+		}
+		switch drain.Stage {
+		case storeDrainEvictingLeader:
+			if store.IsTombstone() {
+				d.finish(id, drain)
+				continue
+			}
+			if store.IsUp() && store.GetLeaderCount() > 0 {
+				continue
+			}
+			if err := d.cluster.RemoveStore(id, false); err != nil {
+				log.Error("fail to set the draining store offline", zap.Uint64("store-id", id), errs.ZapError(err))
+				continue
+			}
+			drain.Stage = storeDrainMigratingRegion
+			d.save(id, drain)
+			log.Info("the leaders of the draining store are evicted", zap.Uint64("store-id", id))
+		case storeDrainMigratingRegion:
+			if store.IsTombstone() {
+				d.finish(id, drain)
+			} else if store.IsUp() {
+				// The store is set up by others, the drain is given up.
+				d.remove(id)
+				log.Warn("the draining store is up again, stop draining it", zap.Uint64("store-id", id))
+			}
+		}
+	}
+}
+
+func (d *storeDrainController) finish(id uint64, drain *storeDrain) {
+	drain.Stage = storeDrainFinished
+	d.save(id, drain)
+	d.cluster.setStoreDraining(id, false)
+	log.Info("store drain is finished", zap.Uint64("store-id", id), zap.Duration("cost", time.Since(drain.StartTime)))
+}
+
+func (d *storeDrainController) save(id uint64, drain *storeDrain) {
+	if err := d.cluster.storage.SaveStoreDrain(id, drain); err != nil {
+		log.Error("fail to persist the store drain", zap.Uint64("store-id", id), errs.ZapError(err))
+	}
+}
+
+func (d *storeDrainController) remove(id uint64) {
+	if err := d.cluster.storage.DeleteStoreDrain(id); err != nil {
+		log.Error("fail to remove the store drain", zap.Uint64("store-id", id), errs.ZapError(err))
+		return
+	}
+	delete(d.drains, id)
+	d.cluster.setStoreDraining(id, false)
+}
+
+// load loads the drains from storage, and flags the stores being drained. It
+// is called when the cluster starts, so the cluster is locked already.
+func (d *storeDrainController) load() error {
+	d.Lock()
+	defer d.Unlock()
+	return d.cluster.storage.LoadStoreDrains(func(k, v string) {
+		id, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			log.Error("invalid store drain key", zap.String("key", k), errs.ZapError(errs.ErrLoadStoreDrain))
+			return
+		}
+		drain := &storeDrain{}
+		if err := json.Unmarshal([]byte(v), drain); err != nil {
+			log.Error("failed to unmarshal store drain", zap.Uint64("store-id", id), zap.String("value", v), errs.ZapError(errs.ErrLoadStoreDrain))
+			return
+		}
+		d.drains[id] = drain
+		if store := d.cluster.GetStore(id); store != nil && drain.Stage != storeDrainFinished {
+			d.cluster.core.PutStore(store.Clone(core.SetDraining(true)))
+		}
+	})
+}
+
+// setStoreDraining flags the store if it is being drained.
+func (c *RaftCluster) setStoreDraining(storeID uint64, draining bool) {
+	c.Lock()
+	defer c.Unlock()
+	if store := c.GetStore(storeID); store != nil {
+		c.core.PutStore(store.Clone(core.SetDraining(draining)))
+	}
+}
+
+// evictDrainingLeaders transfers the leaders out of the draining stores.
+func (c *coordinator) evictDrainingLeaders() {
+	for _, id := range c.cluster.storeDrainController.evictingStores() {
+		store := c.cluster.GetStore(id)
+		if store == nil || !store.IsUp() || store.GetLeaderCount() == 0 {
+			continue
+		}
+		if !c.evictLeaders(store, drainEvictLeaderDesc) {
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+// PauseStoreScheduling prevents the store from being selected as source or
+// target of any new operator until the ttl expires, without changing its
+// state or labels. It is used for short maintenance of the store. The pause
+// never expires if the ttl is zero. The pause is persisted, so it survives the
+// restart of PD and the change of the leader.
+func (c *RaftCluster) PauseStoreScheduling(storeID uint64, ttl time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if store.IsTombstone() {
+		return errs.ErrStoreTombstone.FastGenByArgs(storeID)
+	}
+	deadline := core.TTLDeadline(ttl)
+	if err := c.storage.SaveStorePause(storeID, deadline); err != nil {
+		return err
+	}
+	c.core.PutStore(store.Clone(core.SetPauseDeadline(deadline)))
+	log.Info("store scheduling is paused",
+		zap.Uint64("store-id", storeID),
+		zap.Duration("ttl", ttl))
+	return nil
+}
+
+// ResumeStoreScheduling resumes the scheduling of the paused store.
+func (c *RaftCluster) ResumeStoreScheduling(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if err := c.storage.DeleteStorePause(storeID); err != nil {
+		return err
+	}
+	c.core.PutStore(store.Clone(core.ResetPauseDeadline()))
+	log.Info("store scheduling is resumed", zap.Uint64("store-id", storeID))
+	return nil
+}
+
+// loadStorePauses loads the scheduling pauses of the stores. The expired ones
+// are dropped. It is called with the cluster locked.
+func (c *RaftCluster) loadStorePauses() error {
+	return c.storage.LoadStorePauses(func(k, v string) {
+		id, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			log.Error("invalid store pause key", zap.String("key", k), errs.ZapError(errs.ErrLoadStorePause))
+			return
+		}
+		var deadline time.Time
+		if err := json.Unmarshal([]byte(v), &deadline); err != nil {
+			log.Error("failed to unmarshal store pause", zap.Uint64("store-id", id), zap.String("value", v), errs.ZapError(errs.ErrLoadStorePause))
+			return
+		}
+		store := c.GetStore(id)
+		if store == nil || store.IsTombstone() || (!deadline.IsZero() && time.Now().After(deadline)) {
+			if err := c.storage.DeleteStorePause(id); err != nil {
+				log.Warn("failed to delete the stale store pause", zap.Uint64("store-id", id), errs.ZapError(err))
+			}
+			return
+		}
+		c.core.PutStore(store.Clone(core.SetPauseDeadline(deadline)))
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+	"go.uber.org/zap"
+)
+
+const (
+	restartEvictLeaderDesc = "restart-evict-leader"
+	// restartEvictLeaderBatchSize is the max number of leaders evicted from
+	// a restarting store in one round.
+	restartEvictLeaderBatchSize = 3
+)
+
+// SetStoreRestart marks the store is going to restart. Its leaders are
+// evicted and its down peers are not replaced in the grace period. The store
+// returns to normal once it comes back or the grace period is over.
+func (c *RaftCluster) SetStoreRestart(storeID uint64, grace time.Duration) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	if !store.IsUp() {
+		return errs.ErrStoreNotUp.FastGenByArgs(storeID)
+	}
+	c.core.PutStore(store.Clone(core.SetRestartDeadline(time.Now().Add(grace))))
+	log.Info("store is going to restart",
+		zap.Uint64("store-id", storeID),
+		zap.Duration("grace-period", grace))
+	return nil
+}
+
+// CancelStoreRestart cleans the restarting state of the store.
+func (c *RaftCluster) CancelStoreRestart(storeID uint64) error {
+	c.Lock()
+	defer c.Unlock()
+
+	store := c.GetStore(storeID)
+	if store == nil {
+		return errs.ErrStoreNotFound.FastGenByArgs(storeID)
+	}
+	c.core.PutStore(store.Clone(core.ResetRestartDeadline()))
+	return nil
+}
+
+// isStoreRestarted checks if the restarting store has come back, which is
+// detected by the change of the start time in the store heartbeat.
+func isStoreRestarted(store *core.StoreInfo, startTime uint32) bool {
+	return !store.GetRestartDeadline().IsZero() && startTime != store.GetStoreStats().GetStartTime()
+}
+
+// evictRestartingLeaders transfers the leaders out of the restarting stores,
+// so that the stores can be stopped without affecting the availability.
+func (c *coordinator) evictRestartingLeaders() {
+	for _, store := range c.cluster.GetStores() {
+		if !store.IsRestarting() || store.GetLeaderCount() == 0 {
+			continue
+		}
+		if !c.evictLeaders(store, restartEvictLeaderDesc) {
+			return
+		}
+	}
+}
+
+// evictLeaders transfers at most restartEvictLeaderBatchSize leaders out of
+// the store with high priority. It returns false if the leader schedule limit
+// is reached.
+func (c *coordinator) evictLeaders(store *core.StoreInfo, desc string) bool {
+	opts := c.cluster.GetOpts()
+	for i := 0; i < restartEvictLeaderBatchSize; i++ {
+		if c.opController.OperatorCount(operator.OpLeader) >= opts.GetLeaderScheduleLimit() {
+			return false
+		}
+		region := c.cluster.RandLeaderRegion(store.GetID(), []core.KeyRange{core.NewKeyRange("", "")}, opt.HealthRegion(c.cluster))
+		if region == nil {
+			break
+		}
+		if c.opController.GetOperator(region.GetID()) != nil {
+			continue
+		}
+		target := filter.NewCandidates(c.cluster.GetFollowerStores(region)).
+			FilterTarget(opts, filter.StoreStateFilter{ActionScope: desc, TransferLeader: true}).
+			RandomPick()
+		if target == nil {
+			continue
+		}
+		op, err := operator.CreateTransferLeaderOperator(desc, c.cluster, region, store.GetID(), target.GetID(), operator.OpLeader)
+		if err != nil {
+			log.Debug("fail to create evict leader operator", errs.ZapError(err))
+			continue
+		}
+		op.SetPriorityLevel(core.HighPriority)
+		c.opController.AddWaitingOperator(op)
+	}
+	return true
+}
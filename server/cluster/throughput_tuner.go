@@ -0,0 +1,267 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"math"
+	"time"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/schedule"
+	"go.uber.org/zap"
+)
+
+const (
+	// throughputTuneInterval is the interval between two rounds of tuning, which
+	// leaves time for the operators to finish under the tuned limits.
+	throughputTuneInterval = time.Minute
+	// throughputRaiseRatio and throughputBackoffRatio are the ratios to scale the
+	// limits when raising and backing off them. The limits are backed off faster
+	// than raised, so the cluster recovers soon once it is overloaded.
+	throughputRaiseRatio   = 1.25
+	throughputBackoffRatio = 0.5
+)
+
+// throughputTuner tunes the default add-peer and remove-peer limits, the limits of
+// the stores following the default ones, and the region schedule limit within the
+// configured bounds, and persists the tuned limits. In each round, it backs off
+// the limits if any operator moving peers times out, the operators or the
+// snapshots are slower than expected, or any store is overloaded. Otherwise it
+// raises the limits if the cluster is idle. Once the tuning is disabled, the
+// limits before the tuning are restored.
+type throughputTuner struct {
+	cluster  *RaftCluster
+	state    *State
+	lastTune time.Time
+	// tuning is nil if no limit has been tuned.
+	tuning *throughputTuning
+}
+
+// throughputTuning is the persisted state of the tuning, so that the tuned
+// limits survive the restarts of PD, and the limits before the tuning can be
+// restored.
+type throughputTuning struct {
+	// StoreLimit and RegionScheduleLimit are the tuned limits.
+	StoreLimit          float64 `json:"store-limit"`
+	RegionScheduleLimit uint64  `json:"region-schedule-limit"`
+	// The default limits and the region schedule limit before the tuning.
+	UserAddPeerLimit        float64 `json:"user-add-peer-limit"`
+	UserRemovePeerLimit     float64 `json:"user-remove-peer-limit"`
+	UserRegionScheduleLimit uint64  `json:"user-region-schedule-limit"`
+	// Stores are the stores whose limits are tuned, which follow the default
+	// limits before the tuning.
+	Stores map[uint64]struct{} `json:"stores"`
+}
+
+func newThroughputTuning(addPeerLimit, removePeerLimit float64, regionScheduleLimit uint64) *throughputTuning {
+	return &throughputTuning{
+		StoreLimit:              addPeerLimit,
+		RegionScheduleLimit:     regionScheduleLimit,
+		UserAddPeerLimit:        addPeerLimit,
+		UserRemovePeerLimit:     removePeerLimit,
+		UserRegionScheduleLimit: regionScheduleLimit,
+		Stores:                  make(map[uint64]struct{}),
+	}
+}
+
+func newThroughputTuner(cluster *RaftCluster) *throughputTuner {
+	return &throughputTuner{
+		cluster: cluster,
+		state:   NewState(),
+	}
+}
+
+// load loads the state of the tuning from storage, and applies the tuned
+// default limits if the tuning is enabled.
+func (t *throughputTuner) load() error {
+	tuning := &throughputTuning{}
+	ok, err := t.cluster.storage.LoadThroughputTuning(tuning)
+	if err != nil || !ok {
+		return err
+	}
+	if tuning.Stores == nil {
+		tuning.Stores = make(map[uint64]struct{})
+	}
+	t.tuning = tuning
+	if t.cluster.opt.IsThroughputTuningEnabled() {
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, tuning.StoreLimit)
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, tuning.StoreLimit)
+	}
+	return nil
+}
+
+// collect collects the store statistics to calculate the load of the cluster.
+func (t *throughputTuner) collect(stats *pdpb.StoreStats) {
+	t.state.Collect((*StatEntry)(stats))
+}
+
+// tune runs a round of tuning if it is due.
+func (t *throughputTuner) tune() {
+	opController := t.cluster.coordinator.opController
+	if !t.cluster.opt.IsThroughputTuningEnabled() {
+		// Drop the operators ended while the tuning is disabled.
+		opController.TakeThroughput()
+		t.restore()
+		return
+	}
+	now := time.Now()
+	if now.Sub(t.lastTune) < throughputTuneInterval {
+		return
+	}
+	t.lastTune = now
+
+	throughput := opController.TakeThroughput()
+	busy := false
+	for _, store := range t.cluster.GetStores() {
+		if store.IsUp() && store.IsBusy() {
+			busy = true
+			break
+		}
+	}
+	cfg := t.cluster.opt.GetScheduleConfig()
+	ratio := throughputTuneRatio(cfg, throughput, t.state.State(), busy)
+	if ratio == 1 {
+		return
+	}
+
+	storeLimit := config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer)
+	newStoreLimit := math.Min(math.Max(storeLimit*ratio, cfg.ThroughputTuningMinStoreLimit), cfg.ThroughputTuningMaxStoreLimit)
+	regionLimit := cfg.RegionScheduleLimit
+	newRegionLimit := uint64(math.Min(math.Max(float64(regionLimit)*ratio, float64(cfg.ThroughputTuningMinRegionScheduleLimit)), float64(cfg.ThroughputTuningMaxRegionScheduleLimit)))
+	if newStoreLimit == storeLimit && newRegionLimit == regionLimit {
+		return
+	}
+	if t.tuning == nil {
+		t.tuning = newThroughputTuning(storeLimit, config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer), regionLimit)
+	}
+	t.setStoreLimits(newStoreLimit)
+	t.cluster.opt.SetRegionScheduleLimit(newRegionLimit)
+	t.tuning.RegionScheduleLimit = newRegionLimit
+	t.persist()
+	throughputTuningLimitGauge.WithLabelValues("store-limit").Set(newStoreLimit)
+	throughputTuningLimitGauge.WithLabelValues("region-schedule-limit").Set(float64(newRegionLimit))
+	log.Info("tune the store limits and the region schedule limit",
+		zap.Int("finished-operators", throughput.Finished),
+		zap.Int("timeout-operators", throughput.TimedOut),
+		zap.Duration("operator-latency", throughput.AvgRunningTime),
+		zap.Duration("snapshot-duration", throughput.AvgSnapshotDuration),
+		zap.Bool("busy", busy),
+		zap.Float64("store-limit", newStoreLimit),
+		zap.Uint64("region-schedule-limit", newRegionLimit))
+}
+
+// setStoreLimits sets the default add-peer and remove-peer limits, and the limits
+// of the up stores which follow the default ones. The stores with their own
+// limits are left alone, such as the ones set by users, the TiFlash stores, and
+// the offline stores whose remove-peer limits are lifted to move the peers out.
+// A tuned store whose limits are changed since the last round is no longer tuned.
+func (t *throughputTuner) setStoreLimits(newLimit float64) {
+	opt := t.cluster.opt
+	storeLimits := opt.GetAllStoresLimit()
+	addPeerLimit := config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer)
+	removePeerLimit := config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer)
+	for _, store := range t.cluster.GetStores() {
+		if !store.IsUp() || core.IsTiFlashStore(store.GetMeta()) {
+			continue
+		}
+		id := store.GetID()
+		limit, ok := storeLimits[id]
+		if _, tuned := t.tuning.Stores[id]; tuned {
+			if ok && (limit.AddPeer != t.tuning.StoreLimit || limit.RemovePeer != t.tuning.StoreLimit) {
+				delete(t.tuning.Stores, id)
+				continue
+			}
+		} else if ok && (limit.AddPeer != addPeerLimit || limit.RemovePeer != removePeerLimit) {
+			continue
+		}
+		t.tuning.Stores[id] = struct{}{}
+		opt.SetStoreLimit(id, storelimit.AddPeer, newLimit)
+		opt.SetStoreLimit(id, storelimit.RemovePeer, newLimit)
+	}
+	config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, newLimit)
+	config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, newLimit)
+	t.tuning.StoreLimit = newLimit
+}
+
+// restore restores the limits before the tuning. The limits changed since the
+// last round of tuning, such as by users, are kept.
+func (t *throughputTuner) restore() {
+	if t.tuning == nil {
+		return
+	}
+	opt := t.cluster.opt
+	storeLimits := opt.GetAllStoresLimit()
+	for id := range t.tuning.Stores {
+		limit, ok := storeLimits[id]
+		if !ok || t.cluster.GetStore(id) == nil {
+			continue
+		}
+		if limit.AddPeer == t.tuning.StoreLimit {
+			opt.SetStoreLimit(id, storelimit.AddPeer, t.tuning.UserAddPeerLimit)
+		}
+		if limit.RemovePeer == t.tuning.StoreLimit {
+			opt.SetStoreLimit(id, storelimit.RemovePeer, t.tuning.UserRemovePeerLimit)
+		}
+	}
+	if config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer) == t.tuning.StoreLimit {
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, t.tuning.UserAddPeerLimit)
+	}
+	if config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer) == t.tuning.StoreLimit {
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, t.tuning.UserRemovePeerLimit)
+	}
+	if opt.GetScheduleConfig().RegionScheduleLimit == t.tuning.RegionScheduleLimit {
+		opt.SetRegionScheduleLimit(t.tuning.UserRegionScheduleLimit)
+	}
+	if err := opt.Persist(t.cluster.storage); err != nil {
+		log.Error("persist the restored limits meet error", errs.ZapError(err))
+		return
+	}
+	if err := t.cluster.storage.DeleteThroughputTuning(); err != nil {
+		log.Error("delete the throughput tuning meet error", errs.ZapError(err))
+		return
+	}
+	t.tuning = nil
+	throughputTuningLimitGauge.Reset()
+	log.Info("restore the limits before the throughput tuning")
+}
+
+// persist persists the tuned limits and the state of the tuning.
+func (t *throughputTuner) persist() {
+	if err := t.cluster.opt.Persist(t.cluster.storage); err != nil {
+		log.Error("persist the tuned limits meet error", errs.ZapError(err))
+	}
+	if err := t.cluster.storage.SaveThroughputTuning(t.tuning); err != nil {
+		log.Error("persist the throughput tuning meet error", errs.ZapError(err))
+	}
+}
+
+// throughputTuneRatio returns the ratio to scale the limits by the throughput of
+// the operators and the load of the cluster.
+func throughputTuneRatio(cfg *config.ScheduleConfig, throughput schedule.OperatorThroughput, load LoadState, busy bool) float64 {
+	switch {
+	case throughput.TimedOut > 0, busy, load == LoadStateHigh,
+		throughput.AvgRunningTime > cfg.ThroughputTuningOperatorLatency.Duration,
+		throughput.AvgSnapshotDuration > cfg.ThroughputTuningSnapshotDuration.Duration:
+		return throughputBackoffRatio
+	case load == LoadStateIdle || load == LoadStateLow:
+		return throughputRaiseRatio
+	default:
+		return 1
+	}
+}
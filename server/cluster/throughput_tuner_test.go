@@ -0,0 +1,132 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/mock/mockid"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/core/storelimit"
+	"github.com/tikv/pd/server/kv"
+	"github.com/tikv/pd/server/schedule"
+)
+
+var _ = Suite(&testThroughputTunerSuite{})
+
+type testThroughputTunerSuite struct{}
+
+func (s *testThroughputTunerSuite) TestTuneRatio(c *C) {
+	cfg := config.NewTestOptions().GetScheduleConfig()
+	healthy := schedule.OperatorThroughput{
+		Finished:            10,
+		AvgRunningTime:      time.Minute,
+		AvgSnapshotDuration: 10 * time.Second,
+	}
+
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateIdle, false), Equals, throughputRaiseRatio)
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateLow, false), Equals, throughputRaiseRatio)
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateNormal, false), Equals, 1.0)
+	// The load is unknown before enough heartbeats are collected.
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateNone, false), Equals, 1.0)
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateHigh, false), Equals, throughputBackoffRatio)
+	c.Assert(throughputTuneRatio(cfg, healthy, LoadStateIdle, true), Equals, throughputBackoffRatio)
+
+	slowOperator := healthy
+	slowOperator.AvgRunningTime = 2 * cfg.ThroughputTuningOperatorLatency.Duration
+	c.Assert(throughputTuneRatio(cfg, slowOperator, LoadStateIdle, false), Equals, throughputBackoffRatio)
+	slowSnapshot := healthy
+	slowSnapshot.AvgSnapshotDuration = 2 * cfg.ThroughputTuningSnapshotDuration.Duration
+	c.Assert(throughputTuneRatio(cfg, slowSnapshot, LoadStateIdle, false), Equals, throughputBackoffRatio)
+	timeout := healthy
+	timeout.TimedOut = 1
+	c.Assert(throughputTuneRatio(cfg, timeout, LoadStateIdle, false), Equals, throughputBackoffRatio)
+}
+
+func (s *testThroughputTunerSuite) TestSetStoreLimits(c *C) {
+	addPeerLimit := config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer)
+	removePeerLimit := config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer)
+	defer func() {
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, addPeerLimit)
+		config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, removePeerLimit)
+	}()
+
+	_, opt, err := newTestScheduleConfig()
+	c.Assert(err, IsNil)
+	cluster := newTestRaftCluster(mockid.NewIDAllocator(), opt, core.NewStorage(kv.NewMemoryKV()), core.NewBasicCluster())
+	for _, store := range newTestStores(4) {
+		if store.GetID() == 4 {
+			store = store.Clone(core.SetStoreState(metapb.StoreState_Offline))
+		}
+		c.Assert(cluster.putStoreLocked(store), IsNil)
+	}
+	// Store 1 follows the default limits, store 2 is set by the user, and the
+	// remove-peer limit of the offline store 4 is lifted.
+	opt.SetStoreLimit(1, storelimit.AddPeer, addPeerLimit)
+	opt.SetStoreLimit(2, storelimit.AddPeer, 100)
+	opt.SetStoreLimit(4, storelimit.RemovePeer, storelimit.Unlimited)
+
+	tuner := newThroughputTuner(cluster)
+	regionLimit := opt.GetScheduleConfig().RegionScheduleLimit
+	tuner.tuning = newThroughputTuning(addPeerLimit, removePeerLimit, regionLimit)
+	tuner.setStoreLimits(30)
+	c.Assert(config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer), Equals, 30.0)
+	c.Assert(config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer), Equals, 30.0)
+	transferLeaderLimit := storelimit.Unlimited
+	c.Assert(opt.GetStoreLimit(1), DeepEquals, config.StoreLimitConfig{AddPeer: 30, RemovePeer: 30, TransferLeader: &transferLeaderLimit})
+	c.Assert(opt.GetStoreLimit(2), DeepEquals, config.StoreLimitConfig{AddPeer: 100, RemovePeer: removePeerLimit, TransferLeader: &transferLeaderLimit})
+	c.Assert(opt.GetStoreLimit(3), DeepEquals, config.StoreLimitConfig{AddPeer: 30, RemovePeer: 30, TransferLeader: &transferLeaderLimit})
+	c.Assert(opt.GetStoreLimit(4).RemovePeer, Equals, storelimit.Unlimited)
+	c.Assert(tuner.tuning.Stores, DeepEquals, map[uint64]struct{}{1: {}, 3: {}})
+
+	// The tuned limits are loaded after PD restarts.
+	opt.SetRegionScheduleLimit(regionLimit * 2)
+	tuner.tuning.RegionScheduleLimit = regionLimit * 2
+	tuner.persist()
+	config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, addPeerLimit)
+	config.DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, removePeerLimit)
+	cfg := opt.GetScheduleConfig().Clone()
+	cfg.EnableThroughputTuning = true
+	opt.SetScheduleConfig(cfg)
+	tuner = newThroughputTuner(cluster)
+	c.Assert(tuner.load(), IsNil)
+	c.Assert(config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer), Equals, 30.0)
+	c.Assert(tuner.tuning.Stores, DeepEquals, map[uint64]struct{}{1: {}, 3: {}})
+
+	// The store whose limit is changed by the user is no longer tuned.
+	opt.SetStoreLimit(3, storelimit.AddPeer, 50)
+	tuner.setStoreLimits(40)
+	c.Assert(opt.GetStoreLimit(1), DeepEquals, config.StoreLimitConfig{AddPeer: 40, RemovePeer: 40, TransferLeader: &transferLeaderLimit})
+	c.Assert(opt.GetStoreLimit(3), DeepEquals, config.StoreLimitConfig{AddPeer: 50, RemovePeer: 30, TransferLeader: &transferLeaderLimit})
+	c.Assert(tuner.tuning.Stores, DeepEquals, map[uint64]struct{}{1: {}})
+
+	// The limits before the tuning are restored once the tuning is disabled.
+	cfg = opt.GetScheduleConfig().Clone()
+	cfg.EnableThroughputTuning = false
+	opt.SetScheduleConfig(cfg)
+	tuner.restore()
+	c.Assert(tuner.tuning, IsNil)
+	c.Assert(config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer), Equals, addPeerLimit)
+	c.Assert(config.DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer), Equals, removePeerLimit)
+	c.Assert(opt.GetStoreLimit(1), DeepEquals, config.StoreLimitConfig{AddPeer: addPeerLimit, RemovePeer: removePeerLimit, TransferLeader: &transferLeaderLimit})
+	c.Assert(opt.GetStoreLimit(2).AddPeer, Equals, 100.0)
+	c.Assert(opt.GetStoreLimit(3).AddPeer, Equals, 50.0)
+	c.Assert(opt.GetScheduleConfig().RegionScheduleLimit, Equals, regionLimit)
+	ok, err := cluster.storage.LoadThroughputTuning(&throughputTuning{})
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+}
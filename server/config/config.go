@@ -32,6 +32,7 @@ import (
 	"github.com/tikv/pd/pkg/logutil"
 	"github.com/tikv/pd/pkg/metricutil"
 	"github.com/tikv/pd/pkg/typeutil"
+	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/core/storelimit"
 	"github.com/tikv/pd/server/versioninfo"
 
@@ -220,6 +221,10 @@ const (
 	defaultMaxResetTSGap    = 24 * time.Hour
 	defaultKeyType          = "table"
 
+	// defaultHeatmapMaxSnapshots keeps one day of the heatmap history.
+	defaultHeatmapMaxSnapshots = 1440
+	defaultHeatmapMaxSpans     = 4096
+
 	defaultStrictlyMatchLabel  = false
 	defaultEnableGRPCGateway   = true
 	defaultDisableErrorVerbose = true
@@ -240,10 +245,10 @@ var (
 	defaultEnableTelemetry = true
 	defaultRuntimeServices = []string{}
 	defaultLocationLabels  = []string{}
-	// DefaultStoreLimit is the default store limit of add peer and remove peer.
-	DefaultStoreLimit StoreLimit = StoreLimit{AddPeer: 15, RemovePeer: 15}
-	// DefaultTiFlashStoreLimit is the default TiFlash store limit of add peer and remove peer.
-	DefaultTiFlashStoreLimit StoreLimit = StoreLimit{AddPeer: 30, RemovePeer: 30}
+	// DefaultStoreLimit is the default store limit of add peer, remove peer and transfer leader.
+	DefaultStoreLimit StoreLimit = StoreLimit{AddPeer: 15, RemovePeer: 15, TransferLeader: storelimit.Unlimited}
+	// DefaultTiFlashStoreLimit is the default TiFlash store limit of add peer, remove peer and transfer leader.
+	DefaultTiFlashStoreLimit StoreLimit = StoreLimit{AddPeer: 30, RemovePeer: 30, TransferLeader: storelimit.Unlimited}
 )
 
 func init() {
@@ -263,6 +268,8 @@ type StoreLimit struct {
 	AddPeer float64
 	// RemovePeer is the default rate of removing peers for store limit (per minute).
 	RemovePeer float64
+	// TransferLeader is the default rate of transferring leaders in for store limit (per minute).
+	TransferLeader float64
 }
 
 // SetDefaultStoreLimit sets the default store limit for a given type.
@@ -274,6 +281,8 @@ func (sl *StoreLimit) SetDefaultStoreLimit(typ storelimit.Type, ratePerMin float
 		sl.AddPeer = ratePerMin
 	case storelimit.RemovePeer:
 		sl.RemovePeer = ratePerMin
+	case storelimit.TransferLeader:
+		sl.TransferLeader = ratePerMin
 	}
 }
 
@@ -286,11 +295,25 @@ func (sl *StoreLimit) GetDefaultStoreLimit(typ storelimit.Type) float64 {
 		return sl.AddPeer
 	case storelimit.RemovePeer:
 		return sl.RemovePeer
+	case storelimit.TransferLeader:
+		return sl.TransferLeader
 	default:
 		panic("invalid type")
 	}
 }
 
+// NewStoreLimitConfig returns the limit config of a store which follows the default limits.
+func (sl *StoreLimit) NewStoreLimitConfig() StoreLimitConfig {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	transferLeader := sl.TransferLeader
+	return StoreLimitConfig{
+		AddPeer:        sl.AddPeer,
+		RemovePeer:     sl.RemovePeer,
+		TransferLeader: &transferLeader,
+	}
+}
+
 func adjustString(v *string, defValue string) {
 	if len(*v) == 0 {
 		*v = defValue
@@ -412,6 +435,9 @@ func (c *Config) Validate() error {
 	if !strings.HasPrefix(rel, "..") {
 		return errors.New("log directory shouldn't be the subdirectory of data directory")
 	}
+	if err := c.Metric.Validate(); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -536,6 +562,7 @@ func (c *Config) Adjust(meta *toml.MetaData) error {
 	adjustDuration(&c.ElectionInterval, defaultElectionInterval)
 
 	adjustString(&c.Metric.PushJob, c.Name)
+	adjustString(&c.Metric.PushInstance, c.Name)
 
 	if err := c.Schedule.adjust(configMetaData.Child("schedule")); err != nil {
 		return err
@@ -616,9 +643,21 @@ type ScheduleConfig struct {
 	EnableCrossTableMerge bool `toml:"enable-cross-table-merge" json:"enable-cross-table-merge,string"`
 	// PatrolRegionInterval is the interval for scanning region during patrol.
 	PatrolRegionInterval typeutil.Duration `toml:"patrol-region-interval" json:"patrol-region-interval"`
+	// PatrolRegionBatchSize is the max number of regions checked in a round of patrol.
+	// Together with PatrolRegionInterval, it controls the speed of patrol.
+	PatrolRegionBatchSize uint64 `toml:"patrol-region-batch-size" json:"patrol-region-batch-size"`
+	// ScheduleTimeBudget is the max time a round of patrol or scheduling can take. The work
+	// beyond the budget is cut off and resumed in the next round, so a slow checker or scheduler
+	// does not hold the cluster for long. The abnormal regions take at most half of the budget of
+	// a round of patrol, and the rest is reserved for the regular patrol. 0 means no budget.
+	ScheduleTimeBudget typeutil.Duration `toml:"schedule-time-budget" json:"schedule-time-budget"`
 	// MaxStoreDownTime is the max duration after which
 	// a store will be considered to be down if it hasn't reported heartbeats.
 	MaxStoreDownTime typeutil.Duration `toml:"max-store-down-time" json:"max-store-down-time"`
+	// HeartbeatStalenessBound is the max duration since the last store heartbeat or region
+	// heartbeat of a store, beyond which the store can not be selected as the source or
+	// target of operators. 0 means no bound.
+	HeartbeatStalenessBound typeutil.Duration `toml:"heartbeat-staleness-bound" json:"heartbeat-staleness-bound"`
 	// LeaderScheduleLimit is the max coexist leader schedules.
 	LeaderScheduleLimit uint64 `toml:"leader-schedule-limit" json:"leader-schedule-limit"`
 	// LeaderSchedulePolicy is the option to balance leader, there are some policies supported: ["count", "size"], default: "count"
@@ -635,6 +674,16 @@ type ScheduleConfig struct {
 	// If the number of times a region hits the hot cache is greater than this
 	// threshold, it is considered a hot region.
 	HotRegionCacheHitsThreshold uint64 `toml:"hot-region-cache-hits-threshold" json:"hot-region-cache-hits-threshold"`
+	// HotRegionFlowEWMAAlpha is the weight of the newest flow in the exponentially weighted moving
+	// average of the flow of hot peers, which is in (0, 1]. The smaller it is, the less the hot region
+	// detection is affected by transient spikes. 1 means the latest reported flow is used directly.
+	HotRegionFlowEWMAAlpha float64 `toml:"hot-region-flow-ewma-alpha" json:"hot-region-flow-ewma-alpha"`
+	// HotRegionThresholdRatio is the ratio of the hot threshold of a store to the smallest flow of
+	// its top hot peers. The threshold is not lower than the minimum hot threshold.
+	HotRegionThresholdRatio float64 `toml:"hot-region-threshold-ratio" json:"hot-region-threshold-ratio"`
+	// HotRegionAntiCount is the number of times in a row a hot peer is found cold
+	// before it is removed from the hot cache.
+	HotRegionAntiCount uint64 `toml:"hot-region-anti-count" json:"hot-region-anti-count"`
 	// StoreBalanceRate is the maximum of balance rate for each store.
 	// WARN: StoreBalanceRate is deprecated.
 	StoreBalanceRate float64 `toml:"store-balance-rate" json:"store-balance-rate,omitempty"`
@@ -654,8 +703,37 @@ type ScheduleConfig struct {
 	// HighSpaceRatio is the highest usage ratio of store which regraded as high space.
 	// High space means there is a lot of spare capacity, and store region score varies directly with used size.
 	HighSpaceRatio float64 `toml:"high-space-ratio" json:"high-space-ratio"`
+	// RegionScoreFormulaVersion is the version of the formula to calculate the region score of stores.
+	// "v1" scores by the region size, "v2" scores by the weighted sum of the region size, the region
+	// count, the available space and the flow, which are all relative to the store capacity. "v2" is
+	// more stable for the stores with different capacities. Default: "v1"
+	RegionScoreFormulaVersion string `toml:"region-score-formula-version" json:"region-score-formula-version"`
+	// RegionScoreSizeWeight is the weight of the region size in the v2 region score.
+	RegionScoreSizeWeight float64 `toml:"region-score-size-weight" json:"region-score-size-weight"`
+	// RegionScoreCountWeight is the weight of the region count in the v2 region score.
+	RegionScoreCountWeight float64 `toml:"region-score-count-weight" json:"region-score-count-weight"`
+	// RegionScoreSpaceWeight is the weight of the available space in the v2 region score.
+	RegionScoreSpaceWeight float64 `toml:"region-score-space-weight" json:"region-score-space-weight"`
+	// RegionScoreFlowWeight is the weight of the flow in the v2 region score.
+	RegionScoreFlowWeight float64 `toml:"region-score-flow-weight" json:"region-score-flow-weight"`
+	// BalanceLabelGroup is the label key to group the stores for the balance schedulers. If it is set,
+	// the leaders and regions are only balanced between the stores with the same value of the label,
+	// e.g. "zone" avoids consuming the cross-zone bandwidth by the balance. Default: "" (disabled)
+	BalanceLabelGroup string `toml:"balance-label-group" json:"balance-label-group"`
 	// SchedulerMaxWaitingOperator is the max coexist operators for each scheduler.
 	SchedulerMaxWaitingOperator uint64 `toml:"scheduler-max-waiting-operator" json:"scheduler-max-waiting-operator"`
+	// TableOperatorShare is the max share of the schedule limit that the operators of the regions
+	// in one table can take, so a huge table cannot take all the slots. 0 disables the limit, which
+	// is the default.
+	TableOperatorShare float64 `toml:"table-operator-share" json:"table-operator-share"`
+	// ReplicaRepairReservedSlots is the number of the region schedule slots reserved for the replica
+	// repair. When any replica operator is running, the balance schedulers can only take the rest of
+	// the region schedule limit, so the balance traffic competes less with the re-replication. 0 disables it.
+	ReplicaRepairReservedSlots uint64 `toml:"replica-repair-reserved-slots" json:"replica-repair-reserved-slots"`
+	// ScheduleTimeWindows are the time windows during which the schedules of some kinds run at
+	// full limits. Outside the windows, their limits are reduced, so the heavy rebalancing only
+	// happens off-peak. Empty means the limits always apply in full.
+	ScheduleTimeWindows []ScheduleTimeWindow `toml:"schedule-time-windows" json:"schedule-time-windows"`
 	// WARN: DisableLearner is deprecated.
 	// DisableLearner is the option to disable using AddLearnerNode instead of AddNode.
 	DisableLearner bool `toml:"disable-raft-learner" json:"disable-raft-learner,string,omitempty"`
@@ -694,6 +772,10 @@ type ScheduleConfig struct {
 	EnableDebugMetrics bool `toml:"enable-debug-metrics" json:"enable-debug-metrics,string"`
 	// EnableJointConsensus is the option to enable using joint consensus as a operator step.
 	EnableJointConsensus bool `toml:"enable-joint-consensus" json:"enable-joint-consensus,string"`
+	// EnableNamespaceChecker is the option to enable the namespace checker, which moves the peers
+	// outside the namespace of the region back to the stores of the namespace. The balance schedulers
+	// without a namespace do not consider the namespaces, so they may move the peers out again.
+	EnableNamespaceChecker bool `toml:"enable-namespace-checker" json:"enable-namespace-checker,string"`
 
 	// Schedulers support for loading customized schedulers
 	Schedulers SchedulerConfigs `toml:"schedulers" json:"schedulers-v2"` // json v2 is for the sake of compatible upgrade
@@ -708,6 +790,44 @@ type ScheduleConfig struct {
 	// is overwritten, the value is fixed until it is deleted.
 	// Default: manual
 	StoreLimitMode string `toml:"store-limit-mode" json:"store-limit-mode"`
+
+	// EnableThroughputTuning is the option to let PD tune the default add-peer and remove-peer
+	// limits, the limits of the up stores following the default ones, and the region schedule
+	// limit within the bounds below. The limits are raised when the cluster is idle, and backed
+	// off when the operators or the snapshots slow down or the stores are overloaded. The stores
+	// with their own limits are not tuned. Once it is disabled, the limits before the tuning
+	// are restored unless they are changed since. It can not be used with the auto store limit
+	// mode.
+	EnableThroughputTuning bool `toml:"enable-throughput-tuning" json:"enable-throughput-tuning,string"`
+	// ThroughputTuningMinStoreLimit and ThroughputTuningMaxStoreLimit bound the tuned store limits.
+	ThroughputTuningMinStoreLimit float64 `toml:"throughput-tuning-min-store-limit" json:"throughput-tuning-min-store-limit"`
+	ThroughputTuningMaxStoreLimit float64 `toml:"throughput-tuning-max-store-limit" json:"throughput-tuning-max-store-limit"`
+	// ThroughputTuningMinRegionScheduleLimit and ThroughputTuningMaxRegionScheduleLimit bound
+	// the tuned region schedule limit.
+	ThroughputTuningMinRegionScheduleLimit uint64 `toml:"throughput-tuning-min-region-schedule-limit" json:"throughput-tuning-min-region-schedule-limit"`
+	ThroughputTuningMaxRegionScheduleLimit uint64 `toml:"throughput-tuning-max-region-schedule-limit" json:"throughput-tuning-max-region-schedule-limit"`
+	// ThroughputTuningOperatorLatency is the average running time of the operators moving peers,
+	// beyond which the tuned limits are backed off.
+	ThroughputTuningOperatorLatency typeutil.Duration `toml:"throughput-tuning-operator-latency" json:"throughput-tuning-operator-latency"`
+	// ThroughputTuningSnapshotDuration is the average time taken by the operators to add a peer,
+	// which is mostly spent on the snapshot, beyond which the tuned limits are backed off.
+	ThroughputTuningSnapshotDuration typeutil.Duration `toml:"throughput-tuning-snapshot-duration" json:"throughput-tuning-snapshot-duration"`
+
+	// HotRegionsWriteInterval is the interval to save the snapshot of hot regions.
+	HotRegionsWriteInterval typeutil.Duration `toml:"hot-regions-write-interval" json:"hot-regions-write-interval"`
+	// HotRegionsReservedDays is the days to keep the history of hot regions.
+	// 0 means the history is not recorded.
+	HotRegionsReservedDays uint64 `toml:"hot-regions-reserved-days" json:"hot-regions-reserved-days"`
+}
+
+// GetRegionScoreWeights returns the weights of the v2 region score.
+func (c *ScheduleConfig) GetRegionScoreWeights() core.RegionScoreWeights {
+	return core.RegionScoreWeights{
+		Size:  c.RegionScoreSizeWeight,
+		Count: c.RegionScoreCountWeight,
+		Space: c.RegionScoreSpaceWeight,
+		Flow:  c.RegionScoreFlowWeight,
+	}
 }
 
 // Clone returns a cloned scheduling configuration.
@@ -718,6 +838,10 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 	for k, v := range c.StoreLimit {
 		storeLimit[k] = v
 	}
+	var windows []ScheduleTimeWindow
+	for _, w := range c.ScheduleTimeWindows {
+		windows = append(windows, w.Clone())
+	}
 	return &ScheduleConfig{
 		MaxSnapshotCount:             c.MaxSnapshotCount,
 		MaxPendingPeerCount:          c.MaxPendingPeerCount,
@@ -725,7 +849,10 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 		MaxMergeRegionKeys:           c.MaxMergeRegionKeys,
 		SplitMergeInterval:           c.SplitMergeInterval,
 		PatrolRegionInterval:         c.PatrolRegionInterval,
+		PatrolRegionBatchSize:        c.PatrolRegionBatchSize,
+		ScheduleTimeBudget:           c.ScheduleTimeBudget,
 		MaxStoreDownTime:             c.MaxStoreDownTime,
+		HeartbeatStalenessBound:      c.HeartbeatStalenessBound,
 		LeaderScheduleLimit:          c.LeaderScheduleLimit,
 		LeaderSchedulePolicy:         c.LeaderSchedulePolicy,
 		RegionScheduleLimit:          c.RegionScheduleLimit,
@@ -735,11 +862,23 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 		EnableCrossTableMerge:        c.EnableCrossTableMerge,
 		HotRegionScheduleLimit:       c.HotRegionScheduleLimit,
 		HotRegionCacheHitsThreshold:  c.HotRegionCacheHitsThreshold,
+		HotRegionFlowEWMAAlpha:       c.HotRegionFlowEWMAAlpha,
+		HotRegionThresholdRatio:      c.HotRegionThresholdRatio,
+		HotRegionAntiCount:           c.HotRegionAntiCount,
 		StoreLimit:                   storeLimit,
 		TolerantSizeRatio:            c.TolerantSizeRatio,
 		LowSpaceRatio:                c.LowSpaceRatio,
 		HighSpaceRatio:               c.HighSpaceRatio,
+		RegionScoreFormulaVersion:    c.RegionScoreFormulaVersion,
+		RegionScoreSizeWeight:        c.RegionScoreSizeWeight,
+		RegionScoreCountWeight:       c.RegionScoreCountWeight,
+		RegionScoreSpaceWeight:       c.RegionScoreSpaceWeight,
+		RegionScoreFlowWeight:        c.RegionScoreFlowWeight,
+		BalanceLabelGroup:            c.BalanceLabelGroup,
 		SchedulerMaxWaitingOperator:  c.SchedulerMaxWaitingOperator,
+		TableOperatorShare:           c.TableOperatorShare,
+		ReplicaRepairReservedSlots:   c.ReplicaRepairReservedSlots,
+		ScheduleTimeWindows:          windows,
 		DisableLearner:               c.DisableLearner,
 		DisableRemoveDownReplica:     c.DisableRemoveDownReplica,
 		DisableReplaceOfflineReplica: c.DisableReplaceOfflineReplica,
@@ -753,8 +892,19 @@ func (c *ScheduleConfig) Clone() *ScheduleConfig {
 		EnableLocationReplacement:    c.EnableLocationReplacement,
 		EnableDebugMetrics:           c.EnableDebugMetrics,
 		EnableJointConsensus:         c.EnableJointConsensus,
+		EnableNamespaceChecker:       c.EnableNamespaceChecker,
 		StoreLimitMode:               c.StoreLimitMode,
+		HotRegionsWriteInterval:      c.HotRegionsWriteInterval,
+		HotRegionsReservedDays:       c.HotRegionsReservedDays,
 		Schedulers:                   schedulers,
+
+		EnableThroughputTuning:                 c.EnableThroughputTuning,
+		ThroughputTuningMinStoreLimit:          c.ThroughputTuningMinStoreLimit,
+		ThroughputTuningMaxStoreLimit:          c.ThroughputTuningMaxStoreLimit,
+		ThroughputTuningMinRegionScheduleLimit: c.ThroughputTuningMinRegionScheduleLimit,
+		ThroughputTuningMaxRegionScheduleLimit: c.ThroughputTuningMaxRegionScheduleLimit,
+		ThroughputTuningOperatorLatency:        c.ThroughputTuningOperatorLatency,
+		ThroughputTuningSnapshotDuration:       c.ThroughputTuningSnapshotDuration,
 	}
 }
 
@@ -766,6 +916,7 @@ const (
 	defaultMaxMergeRegionKeys     = 200000
 	defaultSplitMergeInterval     = 1 * time.Hour
 	defaultPatrolRegionInterval   = 100 * time.Millisecond
+	defaultPatrolRegionBatchSize  = 128 // It takes about 14 minutes to iterate 1 million regions.
 	defaultMaxStoreDownTime       = 30 * time.Minute
 	defaultLeaderScheduleLimit    = 4
 	defaultRegionScheduleLimit    = 2048
@@ -778,10 +929,25 @@ const (
 	// defaultHotRegionCacheHitsThreshold is the low hit number threshold of the
 	// hot region.
 	defaultHotRegionCacheHitsThreshold = 3
+	defaultHotRegionFlowEWMAAlpha      = 0.5
+	defaultHotRegionThresholdRatio     = 0.8
+	defaultHotRegionAntiCount          = 2
 	defaultSchedulerMaxWaitingOperator = 5
 	defaultLeaderSchedulePolicy        = "count"
+	defaultRegionScoreFormulaVersion   = "v1"
+	defaultRegionScoreSizeWeight       = 1
+	defaultRegionScoreSpaceWeight      = 1
 	defaultStoreLimitMode              = "manual"
 	defaultEnableJointConsensus        = true
+	defaultHotRegionsWriteInterval     = 10 * time.Minute
+	defaultHotRegionsReservedDays      = 7
+
+	defaultThroughputTuningMinStoreLimit          = 5
+	defaultThroughputTuningMaxStoreLimit          = 60
+	defaultThroughputTuningMinRegionScheduleLimit = 64
+	defaultThroughputTuningMaxRegionScheduleLimit = 4096
+	defaultThroughputTuningOperatorLatency        = 2 * time.Minute
+	defaultThroughputTuningSnapshotDuration       = 30 * time.Second
 )
 
 func (c *ScheduleConfig) adjust(meta *configMetaData) error {
@@ -799,6 +965,7 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	}
 	adjustDuration(&c.SplitMergeInterval, defaultSplitMergeInterval)
 	adjustDuration(&c.PatrolRegionInterval, defaultPatrolRegionInterval)
+	adjustUint64(&c.PatrolRegionBatchSize, defaultPatrolRegionBatchSize)
 	adjustDuration(&c.MaxStoreDownTime, defaultMaxStoreDownTime)
 	if !meta.IsDefined("leader-schedule-limit") {
 		adjustUint64(&c.LeaderScheduleLimit, defaultLeaderScheduleLimit)
@@ -818,6 +985,9 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("hot-region-cache-hits-threshold") {
 		adjustUint64(&c.HotRegionCacheHitsThreshold, defaultHotRegionCacheHitsThreshold)
 	}
+	adjustFloat64(&c.HotRegionFlowEWMAAlpha, defaultHotRegionFlowEWMAAlpha)
+	adjustFloat64(&c.HotRegionThresholdRatio, defaultHotRegionThresholdRatio)
+	adjustUint64(&c.HotRegionAntiCount, defaultHotRegionAntiCount)
 	if !meta.IsDefined("tolerant-size-ratio") {
 		adjustFloat64(&c.TolerantSizeRatio, defaultTolerantSizeRatio)
 	}
@@ -835,6 +1005,26 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	}
 	adjustFloat64(&c.LowSpaceRatio, defaultLowSpaceRatio)
 	adjustFloat64(&c.HighSpaceRatio, defaultHighSpaceRatio)
+	if !meta.IsDefined("region-score-formula-version") {
+		adjustString(&c.RegionScoreFormulaVersion, defaultRegionScoreFormulaVersion)
+	}
+	if !meta.IsDefined("region-score-size-weight") {
+		adjustFloat64(&c.RegionScoreSizeWeight, defaultRegionScoreSizeWeight)
+	}
+	if !meta.IsDefined("region-score-space-weight") {
+		adjustFloat64(&c.RegionScoreSpaceWeight, defaultRegionScoreSpaceWeight)
+	}
+	adjustDuration(&c.HotRegionsWriteInterval, defaultHotRegionsWriteInterval)
+	if !meta.IsDefined("hot-regions-reserved-days") {
+		adjustUint64(&c.HotRegionsReservedDays, defaultHotRegionsReservedDays)
+	}
+
+	adjustFloat64(&c.ThroughputTuningMinStoreLimit, defaultThroughputTuningMinStoreLimit)
+	adjustFloat64(&c.ThroughputTuningMaxStoreLimit, defaultThroughputTuningMaxStoreLimit)
+	adjustUint64(&c.ThroughputTuningMinRegionScheduleLimit, defaultThroughputTuningMinRegionScheduleLimit)
+	adjustUint64(&c.ThroughputTuningMaxRegionScheduleLimit, defaultThroughputTuningMaxRegionScheduleLimit)
+	adjustDuration(&c.ThroughputTuningOperatorLatency, defaultThroughputTuningOperatorLatency)
+	adjustDuration(&c.ThroughputTuningSnapshotDuration, defaultThroughputTuningSnapshotDuration)
 
 	adjustSchedulers(&c.Schedulers, DefaultSchedulers)
 
@@ -847,7 +1037,8 @@ func (c *ScheduleConfig) adjust(meta *configMetaData) error {
 	}
 
 	if c.StoreBalanceRate != 0 {
-		DefaultStoreLimit = StoreLimit{AddPeer: c.StoreBalanceRate, RemovePeer: c.StoreBalanceRate}
+		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, c.StoreBalanceRate)
+		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, c.StoreBalanceRate)
 		c.StoreBalanceRate = 0
 	}
 
@@ -887,7 +1078,8 @@ func (c *ScheduleConfig) parseDeprecatedFlag(meta *configMetaData, name string,
 func (c *ScheduleConfig) MigrateDeprecatedFlags() {
 	c.DisableLearner = false
 	if c.StoreBalanceRate != 0 {
-		DefaultStoreLimit = StoreLimit{AddPeer: c.StoreBalanceRate, RemovePeer: c.StoreBalanceRate}
+		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, c.StoreBalanceRate)
+		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, c.StoreBalanceRate)
 		c.StoreBalanceRate = 0
 	}
 	for _, b := range c.migrateConfigurationMap() {
@@ -912,6 +1104,55 @@ func (c *ScheduleConfig) Validate() error {
 	if c.LowSpaceRatio <= c.HighSpaceRatio {
 		return errors.New("low-space-ratio should be larger than high-space-ratio")
 	}
+	switch c.RegionScoreFormulaVersion {
+	case "", core.RegionScoreFormulaV1:
+	case core.RegionScoreFormulaV2:
+		if c.RegionScoreSizeWeight+c.RegionScoreCountWeight+c.RegionScoreSpaceWeight+c.RegionScoreFlowWeight == 0 {
+			return errors.New("region score weights should not be all zero")
+		}
+	default:
+		return errors.Errorf("region-score-formula-version should be %s or %s", core.RegionScoreFormulaV1, core.RegionScoreFormulaV2)
+	}
+	if c.RegionScoreSizeWeight < 0 || c.RegionScoreCountWeight < 0 || c.RegionScoreSpaceWeight < 0 || c.RegionScoreFlowWeight < 0 {
+		return errors.New("region score weights should be nonnegative")
+	}
+	if c.HotRegionFlowEWMAAlpha <= 0 || c.HotRegionFlowEWMAAlpha > 1 {
+		return errors.New("hot-region-flow-ewma-alpha should be in (0, 1]")
+	}
+	if c.HotRegionThresholdRatio <= 0 {
+		return errors.New("hot-region-threshold-ratio should be positive")
+	}
+	if c.HotRegionAntiCount == 0 {
+		return errors.New("hot-region-anti-count should be positive")
+	}
+	if c.PatrolRegionBatchSize == 0 {
+		return errors.New("patrol-region-batch-size should be positive")
+	}
+	if c.HotRegionsWriteInterval.Duration <= 0 {
+		return errors.New("hot-regions-write-interval should be positive")
+	}
+	if c.TableOperatorShare < 0 || c.TableOperatorShare > 1 {
+		return errors.New("table-operator-share should be in [0, 1]")
+	}
+	if c.BalanceLabelGroup != "" {
+		if err := validateFormat(c.BalanceLabelGroup, keyFormat); err != nil {
+			return err
+		}
+	}
+	if c.EnableThroughputTuning && c.StoreLimitMode == "auto" {
+		return errors.New("enable-throughput-tuning can not be used with the auto store-limit-mode")
+	}
+	if c.ThroughputTuningMinStoreLimit > c.ThroughputTuningMaxStoreLimit {
+		return errors.New("throughput-tuning-min-store-limit should not be larger than throughput-tuning-max-store-limit")
+	}
+	if c.ThroughputTuningMinRegionScheduleLimit > c.ThroughputTuningMaxRegionScheduleLimit {
+		return errors.New("throughput-tuning-min-region-schedule-limit should not be larger than throughput-tuning-max-region-schedule-limit")
+	}
+	for i := range c.ScheduleTimeWindows {
+		if err := c.ScheduleTimeWindows[i].Validate(); err != nil {
+			return err
+		}
+	}
 	for _, scheduleConfig := range c.Schedulers {
 		if !IsSchedulerRegistered(scheduleConfig.Type) {
 			return errors.Errorf("create func of %v is not registered, maybe misspelled", scheduleConfig.Type)
@@ -950,6 +1191,10 @@ func (c *ScheduleConfig) Deprecated() error {
 type StoreLimitConfig struct {
 	AddPeer    float64 `toml:"add-peer" json:"add-peer"`
 	RemovePeer float64 `toml:"remove-peer" json:"remove-peer"`
+	// TransferLeader limits the leaders transferred in, 0 stops transferring leaders
+	// in. It is nil in the configs persisted before it is introduced, which means
+	// the default.
+	TransferLeader *float64 `toml:"transfer-leader" json:"transfer-leader,omitempty"`
 }
 
 // SchedulerConfigs is a slice of customized scheduler configuration.
@@ -987,6 +1232,11 @@ func IsDefaultScheduler(typ string) bool {
 type ReplicationConfig struct {
 	// MaxReplicas is the number of replicas for each region.
 	MaxReplicas uint64 `toml:"max-replicas" json:"max-replicas"`
+	// MaxObservers is the number of the observers for each region, which are
+	// the learners on the stores labeled `specialUse=analytics`. They serve
+	// the analytical reads and are never promoted to voters. It can not be
+	// used with placement rules, which place the learners by the rules instead.
+	MaxObservers uint64 `toml:"max-observers" json:"max-observers"`
 
 	// The label keys specified the location of a store.
 	// The placement priorities is implied by the order of label keys.
@@ -1016,6 +1266,7 @@ func (c *ReplicationConfig) Clone() *ReplicationConfig {
 	copy(locationLabels, c.LocationLabels)
 	return &ReplicationConfig{
 		MaxReplicas:          c.MaxReplicas,
+		MaxObservers:         c.MaxObservers,
 		LocationLabels:       locationLabels,
 		StrictlyMatchLabel:   c.StrictlyMatchLabel,
 		EnablePlacementRules: c.EnablePlacementRules,
@@ -1039,6 +1290,9 @@ func (c *ReplicationConfig) Validate() error {
 	if c.IsolationLevel != "" && !foundIsolationLevel {
 		return errors.New("isolation-level must be one of location-labels or empty")
 	}
+	if c.MaxObservers > 0 && c.EnablePlacementRules {
+		return errors.New("max-observers can not be used with placement rules, please add a learner rule instead")
+	}
 	return nil
 }
 
@@ -1071,6 +1325,21 @@ type PDServerConfig struct {
 	DashboardAddress string `toml:"dashboard-address" json:"dashboard-address"`
 	// TraceRegionFlow the option to update flow information of regions
 	TraceRegionFlow bool `toml:"trace-region-flow" json:"trace-region-flow,string"`
+	// RegionCacheMemoryLimit is the memory used by the cached regions, beyond
+	// which the regions share the boundary keys with their neighbors and the key
+	// copies of the cold regions are dropped, until the memory falls well below
+	// it. Every region is still cached, so the memory may stay above it once all
+	// the keys are shared. 0 means no limit.
+	RegionCacheMemoryLimit typeutil.ByteSize `toml:"region-cache-memory-limit" json:"region-cache-memory-limit"`
+	// EnableHeatmap enables recording the flow of the regions every minute for
+	// the key space heatmap.
+	EnableHeatmap bool `toml:"enable-heatmap" json:"enable-heatmap,string"`
+	// HeatmapMaxSnapshots is the number of the recorded snapshots of the region
+	// flow, which is the minutes of the history kept by the heatmap.
+	HeatmapMaxSnapshots uint64 `toml:"heatmap-max-snapshots" json:"heatmap-max-snapshots"`
+	// HeatmapMaxSpans is the max number of the key ranges in a snapshot, beyond
+	// which the adjacent regions are merged.
+	HeatmapMaxSpans uint64 `toml:"heatmap-max-spans" json:"heatmap-max-spans"`
 }
 
 func (c *PDServerConfig) adjust(meta *configMetaData) error {
@@ -1090,6 +1359,8 @@ func (c *PDServerConfig) adjust(meta *configMetaData) error {
 	if !meta.IsDefined("trace-region-flow") {
 		c.TraceRegionFlow = defaultTraceRegionFlow
 	}
+	adjustUint64(&c.HeatmapMaxSnapshots, defaultHeatmapMaxSnapshots)
+	adjustUint64(&c.HeatmapMaxSpans, defaultHeatmapMaxSpans)
 	return c.Validate()
 }
 
@@ -1098,12 +1369,16 @@ func (c *PDServerConfig) Clone() *PDServerConfig {
 	runtimeServices := make(typeutil.StringSlice, len(c.RuntimeServices))
 	copy(runtimeServices, c.RuntimeServices)
 	return &PDServerConfig{
-		UseRegionStorage: c.UseRegionStorage,
-		MaxResetTSGap:    c.MaxResetTSGap,
-		KeyType:          c.KeyType,
-		MetricStorage:    c.MetricStorage,
-		DashboardAddress: c.DashboardAddress,
-		RuntimeServices:  runtimeServices,
+		UseRegionStorage:       c.UseRegionStorage,
+		MaxResetTSGap:          c.MaxResetTSGap,
+		KeyType:                c.KeyType,
+		MetricStorage:          c.MetricStorage,
+		DashboardAddress:       c.DashboardAddress,
+		RuntimeServices:        runtimeServices,
+		RegionCacheMemoryLimit: c.RegionCacheMemoryLimit,
+		EnableHeatmap:          c.EnableHeatmap,
+		HeatmapMaxSnapshots:    c.HeatmapMaxSnapshots,
+		HeatmapMaxSpans:        c.HeatmapMaxSpans,
 	}
 }
 
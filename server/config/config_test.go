@@ -24,6 +24,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/kv"
 )
@@ -134,6 +135,19 @@ func (s *testConfigSuite) TestValidation(c *C) {
 	c.Assert(cfg.Schedule.Validate(), IsNil)
 	cfg.Schedule.TolerantSizeRatio = -0.6
 	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.TolerantSizeRatio = 0
+	cfg.Schedule.PatrolRegionBatchSize = 0
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.PatrolRegionBatchSize = defaultPatrolRegionBatchSize
+	cfg.Schedule.HotRegionsWriteInterval = typeutil.NewDuration(0)
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	cfg.Schedule.HotRegionsWriteInterval = typeutil.NewDuration(-time.Minute)
+	c.Assert(cfg.Schedule.Validate(), NotNil)
+	// check replication config
+	cfg.Replication.MaxObservers = 1
+	c.Assert(cfg.Replication.Validate(), IsNil)
+	cfg.Replication.EnablePlacementRules = true
+	c.Assert(cfg.Replication.Validate(), NotNil)
 	// check quota
 	c.Assert(cfg.QuotaBackendBytes, Equals, defaultQuotaBackendBytes)
 }
@@ -230,6 +244,18 @@ address = "localhost:9090"
 
 	c.Assert(cfg.Metric.PushInterval.Duration, Equals, 35*time.Second)
 	c.Assert(cfg.Metric.PushAddress, Equals, "localhost:9090")
+	c.Assert(cfg.Metric.PushJob, Equals, cfg.Name)
+	c.Assert(cfg.Metric.PushInstance, Equals, cfg.Name)
+
+	cfgData = `
+[metric]
+type = "unknown"
+`
+	cfg = NewConfig()
+	meta, err = toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	err = cfg.Adjust(&meta)
+	c.Assert(err, NotNil)
 
 	// Test clamping TSOUpdatePhysicalInterval value
 	cfgData = `
@@ -434,3 +460,69 @@ wait-store-timeout = "120s"
 	c.Assert(err, IsNil)
 	c.Assert(cfg.ReplicationMode.ReplicationMode, Equals, "majority")
 }
+
+func (s *testConfigSuite) TestScheduleTimeWindows(c *C) {
+	cfgData := `
+[schedule]
+region-schedule-limit = 100
+leader-schedule-limit = 4
+[[schedule.schedule-time-windows]]
+kinds = ["region"]
+weekdays = ["fri"]
+start = "22:00"
+end = "06:00"
+time-zone = "UTC"
+outside-limit-ratio = 0.1
+`
+	cfg := NewConfig()
+	meta, err := toml.Decode(cfgData, &cfg)
+	c.Assert(err, IsNil)
+	c.Assert(cfg.Adjust(&meta), IsNil)
+	windows := cfg.Schedule.ScheduleTimeWindows
+	c.Assert(windows, HasLen, 1)
+
+	// 2021-06-04 is a Friday.
+	friday := func(clock string) time.Time {
+		t, err := time.Parse("2006-01-02 15:04", "2021-06-04 "+clock)
+		c.Assert(err, IsNil)
+		return t
+	}
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("21:59")), Equals, uint64(10))
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("22:00")), Equals, uint64(100))
+	// The window started on Friday lasts until Saturday morning.
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("22:00").Add(7*time.Hour)), Equals, uint64(100))
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("22:00").Add(8*time.Hour)), Equals, uint64(10))
+	// The window started on Thursday does not exist.
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("01:00")), Equals, uint64(10))
+	// The small limit is not rounded down to 0.
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 4, friday("21:59")), Equals, uint64(1))
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 0, friday("21:59")), Equals, uint64(0))
+	// The kinds not limited by any window are not affected.
+	c.Assert(scheduleLimitAt(windows, ScheduleKindLeader, 4, friday("12:00")), Equals, uint64(4))
+
+	// The time zone is applied before matching the window.
+	windows[0].TimeZone = "Asia/Shanghai"
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("14:00")), Equals, uint64(100))
+
+	// The larger outside ratio of the overlapped windows wins.
+	windows = append(windows, ScheduleTimeWindow{Kinds: []string{ScheduleKindRegion}, Start: "08:00", End: "09:00", OutsideLimitRatio: 0.5})
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("12:00")), Equals, uint64(50))
+	c.Assert(scheduleLimitAt(windows, ScheduleKindRegion, 100, friday("08:30")), Equals, uint64(100))
+
+	// The cloned config does not share the windows.
+	clone := cfg.Schedule.Clone()
+	clone.ScheduleTimeWindows[0].Kinds[0] = ScheduleKindMerge
+	c.Assert(cfg.Schedule.ScheduleTimeWindows[0].Kinds[0], Equals, ScheduleKindRegion)
+
+	for _, w := range []ScheduleTimeWindow{
+		{Start: "22:00", End: "06:00"},
+		{Kinds: []string{"balance"}, Start: "22:00", End: "06:00"},
+		{Kinds: []string{"region"}, Weekdays: []string{"someday"}, Start: "22:00", End: "06:00"},
+		{Kinds: []string{"region"}, Start: "25:00", End: "06:00"},
+		{Kinds: []string{"region"}, Start: "22:00", End: "06:00", TimeZone: "Mars/Olympus"},
+		{Kinds: []string{"region"}, Start: "22:00", End: "06:00", OutsideLimitRatio: 2},
+	} {
+		cfg.Schedule.ScheduleTimeWindows = []ScheduleTimeWindow{w}
+		c.Assert(cfg.Schedule.Validate(), NotNil)
+	}
+}
@@ -154,6 +154,11 @@ func (o *PersistOptions) SetMaxReplicas(replicas int) {
 	o.SetReplicationConfig(v)
 }
 
+// GetMaxObservers returns the number of the observers for each region.
+func (o *PersistOptions) GetMaxObservers() int {
+	return int(o.GetReplicationConfig().MaxObservers)
+}
+
 // GetMaxSnapshotCount returns the number of the max snapshot which is allowed to send.
 func (o *PersistOptions) GetMaxSnapshotCount() uint64 {
 	return o.GetScheduleConfig().MaxSnapshotCount
@@ -189,23 +194,17 @@ func (o *PersistOptions) SetSplitMergeInterval(splitMergeInterval time.Duration)
 // SetStoreLimit sets a store limit for a given type and rate.
 func (o *PersistOptions) SetStoreLimit(storeID uint64, typ storelimit.Type, ratePerMin float64) {
 	v := o.GetScheduleConfig().Clone()
-	var sc StoreLimitConfig
-	var rate float64
+	sc, ok := v.StoreLimit[storeID]
+	if !ok {
+		sc = DefaultStoreLimit.NewStoreLimitConfig()
+	}
 	switch typ {
 	case storelimit.AddPeer:
-		if _, ok := v.StoreLimit[storeID]; !ok {
-			rate = DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer)
-		} else {
-			rate = v.StoreLimit[storeID].RemovePeer
-		}
-		sc = StoreLimitConfig{AddPeer: ratePerMin, RemovePeer: rate}
+		sc.AddPeer = ratePerMin
 	case storelimit.RemovePeer:
-		if _, ok := v.StoreLimit[storeID]; !ok {
-			rate = DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer)
-		} else {
-			rate = v.StoreLimit[storeID].AddPeer
-		}
-		sc = StoreLimitConfig{AddPeer: rate, RemovePeer: ratePerMin}
+		sc.RemovePeer = ratePerMin
+	case storelimit.TransferLeader:
+		sc.TransferLeader = &ratePerMin
 	}
 	v.StoreLimit[storeID] = sc
 	o.SetScheduleConfig(v)
@@ -217,14 +216,20 @@ func (o *PersistOptions) SetAllStoresLimit(typ storelimit.Type, ratePerMin float
 	switch typ {
 	case storelimit.AddPeer:
 		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.AddPeer, ratePerMin)
-		for storeID := range v.StoreLimit {
-			sc := StoreLimitConfig{AddPeer: ratePerMin, RemovePeer: v.StoreLimit[storeID].RemovePeer}
+		for storeID, sc := range v.StoreLimit {
+			sc.AddPeer = ratePerMin
 			v.StoreLimit[storeID] = sc
 		}
 	case storelimit.RemovePeer:
 		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.RemovePeer, ratePerMin)
-		for storeID := range v.StoreLimit {
-			sc := StoreLimitConfig{AddPeer: v.StoreLimit[storeID].AddPeer, RemovePeer: ratePerMin}
+		for storeID, sc := range v.StoreLimit {
+			sc.RemovePeer = ratePerMin
+			v.StoreLimit[storeID] = sc
+		}
+	case storelimit.TransferLeader:
+		DefaultStoreLimit.SetDefaultStoreLimit(storelimit.TransferLeader, ratePerMin)
+		for storeID, sc := range v.StoreLimit {
+			sc.TransferLeader = &ratePerMin
 			v.StoreLimit[storeID] = sc
 		}
 	}
@@ -247,6 +252,22 @@ func (o *PersistOptions) GetPatrolRegionInterval() time.Duration {
 	return o.GetScheduleConfig().PatrolRegionInterval.Duration
 }
 
+// GetPatrolRegionBatchSize returns the max number of regions checked in a round of patrol.
+func (o *PersistOptions) GetPatrolRegionBatchSize() int {
+	return int(o.GetScheduleConfig().PatrolRegionBatchSize)
+}
+
+// GetScheduleTimeBudget returns the max time a round of patrol or scheduling can take.
+func (o *PersistOptions) GetScheduleTimeBudget() time.Duration {
+	return o.GetScheduleConfig().ScheduleTimeBudget.Duration
+}
+
+// GetHeartbeatStalenessBound returns the max duration since the last heartbeats of a store
+// which can be selected as the source or target of operators.
+func (o *PersistOptions) GetHeartbeatStalenessBound() time.Duration {
+	return o.GetScheduleConfig().HeartbeatStalenessBound.Duration
+}
+
 // GetMaxStoreDownTime returns the max down time of a store.
 func (o *PersistOptions) GetMaxStoreDownTime() time.Duration {
 	return o.GetScheduleConfig().MaxStoreDownTime.Duration
@@ -254,27 +275,32 @@ func (o *PersistOptions) GetMaxStoreDownTime() time.Duration {
 
 // GetLeaderScheduleLimit returns the limit for leader schedule.
 func (o *PersistOptions) GetLeaderScheduleLimit() uint64 {
-	return o.GetScheduleConfig().LeaderScheduleLimit
+	cfg := o.GetScheduleConfig()
+	return scheduleLimitAt(cfg.ScheduleTimeWindows, ScheduleKindLeader, cfg.LeaderScheduleLimit, time.Now())
 }
 
 // GetRegionScheduleLimit returns the limit for region schedule.
 func (o *PersistOptions) GetRegionScheduleLimit() uint64 {
-	return o.GetScheduleConfig().RegionScheduleLimit
+	cfg := o.GetScheduleConfig()
+	return scheduleLimitAt(cfg.ScheduleTimeWindows, ScheduleKindRegion, cfg.RegionScheduleLimit, time.Now())
 }
 
 // GetReplicaScheduleLimit returns the limit for replica schedule.
 func (o *PersistOptions) GetReplicaScheduleLimit() uint64 {
-	return o.GetScheduleConfig().ReplicaScheduleLimit
+	cfg := o.GetScheduleConfig()
+	return scheduleLimitAt(cfg.ScheduleTimeWindows, ScheduleKindReplica, cfg.ReplicaScheduleLimit, time.Now())
 }
 
 // GetMergeScheduleLimit returns the limit for merge schedule.
 func (o *PersistOptions) GetMergeScheduleLimit() uint64 {
-	return o.GetScheduleConfig().MergeScheduleLimit
+	cfg := o.GetScheduleConfig()
+	return scheduleLimitAt(cfg.ScheduleTimeWindows, ScheduleKindMerge, cfg.MergeScheduleLimit, time.Now())
 }
 
 // GetHotRegionScheduleLimit returns the limit for hot region schedule.
 func (o *PersistOptions) GetHotRegionScheduleLimit() uint64 {
-	return o.GetScheduleConfig().HotRegionScheduleLimit
+	cfg := o.GetScheduleConfig()
+	return scheduleLimitAt(cfg.ScheduleTimeWindows, ScheduleKindHotRegion, cfg.HotRegionScheduleLimit, time.Now())
 }
 
 // GetStoreLimit returns the limit of a store.
@@ -283,11 +309,7 @@ func (o *PersistOptions) GetStoreLimit(storeID uint64) StoreLimitConfig {
 		return limit
 	}
 	cfg := o.GetScheduleConfig().Clone()
-	sc := StoreLimitConfig{
-		AddPeer:    DefaultStoreLimit.GetDefaultStoreLimit(storelimit.AddPeer),
-		RemovePeer: DefaultStoreLimit.GetDefaultStoreLimit(storelimit.RemovePeer),
-	}
-	cfg.StoreLimit[storeID] = sc
+	cfg.StoreLimit[storeID] = DefaultStoreLimit.NewStoreLimitConfig()
 	o.SetScheduleConfig(cfg)
 	return o.GetScheduleConfig().StoreLimit[storeID]
 }
@@ -300,6 +322,11 @@ func (o *PersistOptions) GetStoreLimitByType(storeID uint64, typ storelimit.Type
 		return limit.AddPeer
 	case storelimit.RemovePeer:
 		return limit.RemovePeer
+	case storelimit.TransferLeader:
+		if limit.TransferLeader == nil {
+			return DefaultStoreLimit.GetDefaultStoreLimit(storelimit.TransferLeader)
+		}
+		return *limit.TransferLeader
 	default:
 		panic("no such limit type")
 	}
@@ -310,6 +337,24 @@ func (o *PersistOptions) GetAllStoresLimit() map[uint64]StoreLimitConfig {
 	return o.GetScheduleConfig().StoreLimit
 }
 
+// IsNamespaceCheckerEnabled returns if the namespace checker is enabled.
+func (o *PersistOptions) IsNamespaceCheckerEnabled() bool {
+	return o.GetScheduleConfig().EnableNamespaceChecker
+}
+
+// IsThroughputTuningEnabled returns if the store limits and the region schedule limit
+// are tuned by PD.
+func (o *PersistOptions) IsThroughputTuningEnabled() bool {
+	return o.GetScheduleConfig().EnableThroughputTuning
+}
+
+// SetRegionScheduleLimit sets the limit for region schedule.
+func (o *PersistOptions) SetRegionScheduleLimit(limit uint64) {
+	v := o.GetScheduleConfig().Clone()
+	v.RegionScheduleLimit = limit
+	o.SetScheduleConfig(v)
+}
+
 // GetStoreLimitMode returns the limit mode of store.
 func (o *PersistOptions) GetStoreLimitMode() string {
 	return o.GetScheduleConfig().StoreLimitMode
@@ -335,6 +380,31 @@ func (o *PersistOptions) GetSchedulerMaxWaitingOperator() uint64 {
 	return o.GetScheduleConfig().SchedulerMaxWaitingOperator
 }
 
+// GetTableOperatorShare returns the max share of the schedule limit of the operators in one table.
+func (o *PersistOptions) GetTableOperatorShare() float64 {
+	return o.GetScheduleConfig().TableOperatorShare
+}
+
+// GetReplicaRepairReservedSlots returns the number of the region schedule slots reserved for the replica repair.
+func (o *PersistOptions) GetReplicaRepairReservedSlots() uint64 {
+	return o.GetScheduleConfig().ReplicaRepairReservedSlots
+}
+
+// GetRegionScoreFormulaVersion returns the version of the region score formula.
+func (o *PersistOptions) GetRegionScoreFormulaVersion() string {
+	return o.GetScheduleConfig().RegionScoreFormulaVersion
+}
+
+// GetRegionScoreWeights returns the weights of the v2 region score.
+func (o *PersistOptions) GetRegionScoreWeights() core.RegionScoreWeights {
+	return o.GetScheduleConfig().GetRegionScoreWeights()
+}
+
+// GetBalanceLabelGroup returns the label key to group the stores for the balance schedulers.
+func (o *PersistOptions) GetBalanceLabelGroup() string {
+	return o.GetScheduleConfig().BalanceLabelGroup
+}
+
 // GetLeaderSchedulePolicy is to get leader schedule policy.
 func (o *PersistOptions) GetLeaderSchedulePolicy() core.SchedulePolicy {
 	return core.StringToSchedulePolicy(o.GetScheduleConfig().LeaderSchedulePolicy)
@@ -355,6 +425,26 @@ func (o *PersistOptions) GetDashboardAddress() string {
 	return o.GetPDServerConfig().DashboardAddress
 }
 
+// GetRegionCacheMemoryLimit returns the memory limit of the cached regions.
+func (o *PersistOptions) GetRegionCacheMemoryLimit() int64 {
+	return int64(o.GetPDServerConfig().RegionCacheMemoryLimit)
+}
+
+// IsHeatmapEnabled returns if the flow of the regions is recorded for the heatmap.
+func (o *PersistOptions) IsHeatmapEnabled() bool {
+	return o.GetPDServerConfig().EnableHeatmap
+}
+
+// GetHeatmapMaxSnapshots returns the number of the snapshots kept by the heatmap.
+func (o *PersistOptions) GetHeatmapMaxSnapshots() int {
+	return int(o.GetPDServerConfig().HeatmapMaxSnapshots)
+}
+
+// GetHeatmapMaxSpans returns the max number of the key ranges in a heatmap snapshot.
+func (o *PersistOptions) GetHeatmapMaxSpans() int {
+	return int(o.GetPDServerConfig().HeatmapMaxSpans)
+}
+
 // IsUseRegionStorage returns if the independent region storage is enabled.
 func (o *PersistOptions) IsUseRegionStorage() bool {
 	return o.GetPDServerConfig().UseRegionStorage
@@ -400,6 +490,31 @@ func (o *PersistOptions) GetHotRegionCacheHitsThreshold() int {
 	return int(o.GetScheduleConfig().HotRegionCacheHitsThreshold)
 }
 
+// GetHotRegionFlowEWMAAlpha returns the weight of the newest flow in the moving average of the hot peer flow.
+func (o *PersistOptions) GetHotRegionFlowEWMAAlpha() float64 {
+	return o.GetScheduleConfig().HotRegionFlowEWMAAlpha
+}
+
+// GetHotRegionThresholdRatio returns the ratio of the hot threshold to the smallest flow of the top hot peers.
+func (o *PersistOptions) GetHotRegionThresholdRatio() float64 {
+	return o.GetScheduleConfig().HotRegionThresholdRatio
+}
+
+// GetHotRegionAntiCount returns the number of times a hot peer is found cold before it is removed.
+func (o *PersistOptions) GetHotRegionAntiCount() int {
+	return int(o.GetScheduleConfig().HotRegionAntiCount)
+}
+
+// GetHotRegionsWriteInterval gets the interval to save the snapshot of hot regions.
+func (o *PersistOptions) GetHotRegionsWriteInterval() time.Duration {
+	return o.GetScheduleConfig().HotRegionsWriteInterval.Duration
+}
+
+// GetHotRegionsReservedDays gets the days to keep the history of hot regions.
+func (o *PersistOptions) GetHotRegionsReservedDays() uint64 {
+	return o.GetScheduleConfig().HotRegionsReservedDays
+}
+
 // GetSchedulers gets the scheduler configurations.
 func (o *PersistOptions) GetSchedulers() SchedulerConfigs {
 	return o.GetScheduleConfig().Schedulers
@@ -0,0 +1,210 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// The kinds of the schedules that can be limited by the schedule time windows.
+const (
+	ScheduleKindLeader    = "leader"
+	ScheduleKindRegion    = "region"
+	ScheduleKindReplica   = "replica"
+	ScheduleKindMerge     = "merge"
+	ScheduleKindHotRegion = "hot-region"
+)
+
+var scheduleKinds = []string{ScheduleKindLeader, ScheduleKindRegion, ScheduleKindReplica, ScheduleKindMerge, ScheduleKindHotRegion}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ScheduleTimeWindow is a daily time window during which the schedules of the given kinds
+// run at their full limits. Outside all the windows of a kind, its schedule limit is scaled
+// by OutsideLimitRatio, so the heavy rebalancing only happens off-peak.
+type ScheduleTimeWindow struct {
+	// Kinds are the kinds of the schedules limited by the window, which are
+	// "leader", "region", "replica", "merge" and "hot-region".
+	Kinds []string `toml:"kinds" json:"kinds"`
+	// Weekdays are the days the window starts on, e.g. ["sat", "sun"]. Empty means every day.
+	Weekdays []string `toml:"weekdays" json:"weekdays"`
+	// Start and End are the time of the day in the "15:04" format. The window crosses
+	// midnight if End is not later than Start.
+	Start string `toml:"start" json:"start"`
+	End   string `toml:"end" json:"end"`
+	// TimeZone is the IANA name of the time zone of the window. Empty means UTC.
+	TimeZone string `toml:"time-zone" json:"time-zone"`
+	// OutsideLimitRatio is the ratio of the schedule limits outside the window, 0 means
+	// the schedules of the kinds are stopped. A positive ratio never scales a limit below 1.
+	OutsideLimitRatio float64 `toml:"outside-limit-ratio" json:"outside-limit-ratio"`
+}
+
+// Validate checks if the window is well formed.
+func (w *ScheduleTimeWindow) Validate() error {
+	if len(w.Kinds) == 0 {
+		return errors.New("schedule time window should have at least one kind")
+	}
+	for _, kind := range w.Kinds {
+		if !isScheduleKind(kind) {
+			return errors.Errorf("unknown schedule kind %s, it should be one of %s", kind, strings.Join(scheduleKinds, ", "))
+		}
+	}
+	for _, day := range w.Weekdays {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return errors.Errorf("unknown weekday %s", day)
+		}
+	}
+	if _, err := parseMinuteOfDay(w.Start); err != nil {
+		return err
+	}
+	if _, err := parseMinuteOfDay(w.End); err != nil {
+		return err
+	}
+	if _, err := loadLocation(w.TimeZone); err != nil {
+		return errors.Errorf("unknown time zone %s", w.TimeZone)
+	}
+	if w.OutsideLimitRatio < 0 || w.OutsideLimitRatio > 1 {
+		return errors.New("outside-limit-ratio should be in [0, 1]")
+	}
+	return nil
+}
+
+// Clone returns a copy of the window.
+func (w ScheduleTimeWindow) Clone() ScheduleTimeWindow {
+	w.Kinds = append([]string(nil), w.Kinds...)
+	w.Weekdays = append([]string(nil), w.Weekdays...)
+	return w
+}
+
+// HasKind returns if the window limits the schedules of the kind.
+func (w *ScheduleTimeWindow) HasKind(kind string) bool {
+	for _, k := range w.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains returns if the time is inside the window.
+func (w *ScheduleTimeWindow) Contains(t time.Time) bool {
+	loc, err := loadLocation(w.TimeZone)
+	if err != nil {
+		return false
+	}
+	start, err := parseMinuteOfDay(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseMinuteOfDay(w.End)
+	if err != nil {
+		return false
+	}
+	t = t.In(loc)
+	minute, day := t.Hour()*60+t.Minute(), t.Weekday()
+	if end <= start {
+		// The window crosses midnight, so the part before the end belongs to the window
+		// started on the previous day.
+		if minute < end {
+			return w.startsOn((day + 6) % 7)
+		}
+		return minute >= start && w.startsOn(day)
+	}
+	return minute >= start && minute < end && w.startsOn(day)
+}
+
+func (w *ScheduleTimeWindow) startsOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, d := range w.Weekdays {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleLimitAt returns the schedule limit of the kind at the time. The limit is full
+// if the kind is not limited by any window or the time is inside one of its windows,
+// otherwise it is scaled by the largest outside ratio of its windows. A positive ratio
+// keeps at least 1, so the small limits are not stopped by rounding down.
+func scheduleLimitAt(windows []ScheduleTimeWindow, kind string, limit uint64, t time.Time) uint64 {
+	limited, ratio := false, 0.0
+	for i := range windows {
+		w := &windows[i]
+		if !w.HasKind(kind) {
+			continue
+		}
+		if w.Contains(t) {
+			return limit
+		}
+		limited = true
+		if w.OutsideLimitRatio > ratio {
+			ratio = w.OutsideLimitRatio
+		}
+	}
+	if !limited {
+		return limit
+	}
+	scaled := uint64(float64(limit) * ratio)
+	if scaled == 0 && limit > 0 && ratio > 0 {
+		scaled = 1
+	}
+	return scaled
+}
+
+func isScheduleKind(kind string) bool {
+	for _, k := range scheduleKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMinuteOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, errors.Errorf("invalid time of day %s, it should be in the format of 15:04", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// locations caches the loaded time zones, as loading one reads the zoneinfo database.
+var locations sync.Map
+
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
@@ -16,6 +16,7 @@ package core
 import (
 	"bytes"
 	"sync"
+	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/log"
@@ -145,6 +146,29 @@ func (bc *BasicCluster) ResumeLeaderTransfer(storeID uint64) {
 	bc.Stores.ResumeLeaderTransfer(storeID)
 }
 
+// SetStoreBackoff backs off the scheduling to a specific store until the deadline.
+func (bc *BasicCluster) SetStoreBackoff(storeID uint64, deadline time.Time) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.Stores.SetStoreBackoff(storeID, deadline)
+}
+
+// SetStoreRegionHeartbeatTS records the last time a region heartbeat is
+// received from the leaders on a specific store.
+func (bc *BasicCluster) SetStoreRegionHeartbeatTS(storeID uint64, ts time.Time) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.Stores.SetStoreRegionHeartbeatTS(storeID, ts)
+}
+
+// SetStoreHibernatesRegions marks that a specific store has reported
+// hibernated regions.
+func (bc *BasicCluster) SetStoreHibernatesRegions(storeID uint64) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.Stores.SetStoreHibernatesRegions(storeID)
+}
+
 // AttachAvailableFunc attaches an available function to a specific store.
 func (bc *BasicCluster) AttachAvailableFunc(storeID uint64, limitType storelimit.Type, f func() bool) {
 	bc.Lock()
@@ -268,6 +292,29 @@ func (bc *BasicCluster) GetAverageRegionSize() int64 {
 	return bc.Regions.GetAverageRegionSize()
 }
 
+// GetRegionMemorySize returns the estimated memory used by the cached regions.
+func (bc *BasicCluster) GetRegionMemorySize() int64 {
+	bc.RLock()
+	defer bc.RUnlock()
+	return bc.Regions.GetMemorySize()
+}
+
+// SetRegionKeysCompaction sets if the regions share the boundary keys with
+// their neighbors in the cache.
+func (bc *BasicCluster) SetRegionKeysCompaction(enable bool) {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.Regions.SetKeysCompaction(enable)
+}
+
+// CompactRegionKeys makes at most limit cached regions share the boundary keys
+// with their neighbors, and returns the number of the compacted regions.
+func (bc *BasicCluster) CompactRegionKeys(limit int) int {
+	bc.Lock()
+	defer bc.Unlock()
+	return bc.Regions.CompactKeys(limit)
+}
+
 // PutStore put a store.
 func (bc *BasicCluster) PutStore(store *StoreInfo) {
 	bc.Lock()
@@ -344,6 +391,13 @@ func (bc *BasicCluster) RemoveRegion(region *RegionInfo) {
 	bc.Regions.RemoveRegion(region)
 }
 
+// ResetRegionCache drops all cached regions.
+func (bc *BasicCluster) ResetRegionCache() {
+	bc.Lock()
+	defer bc.Unlock()
+	bc.Regions = NewRegionsInfo()
+}
+
 // SearchRegion searches RegionInfo from regionTree.
 func (bc *BasicCluster) SearchRegion(regionKey []byte) *RegionInfo {
 	bc.RLock()
@@ -403,6 +457,7 @@ type StoreSetController interface {
 	ResumeLeaderTransfer(id uint64)
 
 	AttachAvailableFunc(id uint64, limitType storelimit.Type, f func() bool)
+	SetStoreBackoff(id uint64, deadline time.Time)
 }
 
 // KeyRange is a key range.
@@ -0,0 +1,115 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/kv"
+)
+
+const hotRegionPath = "hot_region"
+
+// HistoryHotRegion is a snapshot of a hot peer at some time.
+type HistoryHotRegion struct {
+	UpdateTime    int64   `json:"update_time"`
+	RegionID      uint64  `json:"region_id"`
+	StoreID       uint64  `json:"store_id"`
+	IsLeader      bool    `json:"is_leader"`
+	HotRegionType string  `json:"hot_region_type"`
+	HotDegree     int     `json:"hot_degree"`
+	FlowBytes     float64 `json:"flow_bytes"`
+	KeyRate       float64 `json:"key_rate"`
+	StartKey      string  `json:"start_key"`
+	EndKey        string  `json:"end_key"`
+}
+
+// HotRegionStorage is used to save the history of hot regions.
+type HotRegionStorage struct {
+	*kv.LeveldbKV
+}
+
+// NewHotRegionStorage returns a storage that is used to save the history of hot regions.
+func NewHotRegionStorage(path string) (*HotRegionStorage, error) {
+	levelDB, err := kv.NewLeveldbKV(path)
+	if err != nil {
+		return nil, err
+	}
+	return &HotRegionStorage{LeveldbKV: levelDB}, nil
+}
+
+// The update time is the first part of the key so that the history can be
+// scanned by time.
+func hotRegionKey(updateTime int64, typ string, regionID, storeID uint64) string {
+	return path.Join(hotRegionPath, fmt.Sprintf("%020d", updateTime), typ, fmt.Sprintf("%020d", regionID), fmt.Sprintf("%020d", storeID))
+}
+
+func hotRegionTimeKey(t int64) string {
+	return path.Join(hotRegionPath, fmt.Sprintf("%020d", t))
+}
+
+// SaveHotRegions saves a batch of hot regions.
+func (s *HotRegionStorage) SaveHotRegions(regions []*HistoryHotRegion) error {
+	batch := new(leveldb.Batch)
+	for _, r := range regions {
+		value, err := json.Marshal(r)
+		if err != nil {
+			return errs.ErrJSONMarshal.Wrap(err).GenWithStackByCause()
+		}
+		batch.Put([]byte(hotRegionKey(r.UpdateTime, r.HotRegionType, r.RegionID, r.StoreID)), value)
+	}
+	if err := s.Write(batch, nil); err != nil {
+		return errs.ErrLevelDBWrite.Wrap(err).GenWithStackByCause()
+	}
+	return nil
+}
+
+// LoadHotRegions loads the hot regions whose update time is in [start, end).
+// The time is in milliseconds.
+func (s *HotRegionStorage) LoadHotRegions(start, end int64) ([]*HistoryHotRegion, error) {
+	_, values, err := s.LoadRange(hotRegionTimeKey(start), hotRegionTimeKey(end), 0)
+	if err != nil {
+		return nil, err
+	}
+	regions := make([]*HistoryHotRegion, 0, len(values))
+	for _, v := range values {
+		r := &HistoryHotRegion{}
+		if err := json.Unmarshal([]byte(v), r); err != nil {
+			return nil, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByCause()
+		}
+		regions = append(regions, r)
+	}
+	return regions, nil
+}
+
+// DeleteHotRegionsBefore deletes the hot regions updated before the time.
+func (s *HotRegionStorage) DeleteHotRegionsBefore(t time.Time) error {
+	keys, _, err := s.LoadRange(hotRegionTimeKey(0), hotRegionTimeKey(t.UnixNano()/int64(time.Millisecond)), 0)
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	for _, key := range keys {
+		batch.Delete([]byte(key))
+	}
+	if err := s.Write(batch, nil); err != nil {
+		return errs.ErrLevelDBWrite.Wrap(err).GenWithStackByCause()
+	}
+	return nil
+}
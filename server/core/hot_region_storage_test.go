@@ -0,0 +1,64 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testHotRegionStorageSuite{})
+
+type testHotRegionStorageSuite struct{}
+
+func (s *testHotRegionStorageSuite) TestHotRegionHistory(c *C) {
+	dir, err := ioutil.TempDir("/tmp", "hot_region")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	storage, err := NewHotRegionStorage(dir)
+	c.Assert(err, IsNil)
+	defer storage.Close()
+
+	base := time.Unix(1600000000, 0)
+	ms := func(t time.Time) int64 { return t.UnixNano() / int64(time.Millisecond) }
+	var regions []*HistoryHotRegion
+	for i := 0; i < 3; i++ {
+		updateTime := ms(base.Add(time.Duration(i) * time.Hour))
+		regions = append(regions,
+			&HistoryHotRegion{UpdateTime: updateTime, RegionID: uint64(i), StoreID: 1, HotRegionType: "read"},
+			&HistoryHotRegion{UpdateTime: updateTime, RegionID: uint64(i), StoreID: 2, HotRegionType: "write"},
+		)
+	}
+	c.Assert(storage.SaveHotRegions(regions), IsNil)
+
+	res, err := storage.LoadHotRegions(ms(base), ms(base.Add(3*time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(res, HasLen, 6)
+	res, err = storage.LoadHotRegions(ms(base.Add(time.Hour)), ms(base.Add(2*time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(res, HasLen, 2)
+	for _, r := range res {
+		c.Assert(r.RegionID, Equals, uint64(1))
+	}
+
+	// Remove the history out of the retention.
+	c.Assert(storage.DeleteHotRegionsBefore(base.Add(2*time.Hour)), IsNil)
+	res, err = storage.LoadHotRegions(0, ms(base.Add(3*time.Hour)))
+	c.Assert(err, IsNil)
+	c.Assert(res, HasLen, 2)
+	c.Assert(res[0].RegionID, Equals, uint64(2))
+}
@@ -52,6 +52,7 @@ type RegionInfo struct {
 	approximateKeys   int64
 	interval          *pdpb.TimeInterval
 	replicationStatus *replication_modepb.RegionReplicationStatus
+	hibernated        bool
 }
 
 // NewRegionInfo creates RegionInfo with region's meta and leader peer.
@@ -87,6 +88,34 @@ func classifyVoterAndLearner(region *RegionInfo) {
 // (heartbeat size <= 1MB).
 const EmptyRegionApproximateSize = 1
 
+const (
+	// regionMemoryOverhead is the estimated memory of a RegionInfo and its
+	// meta, except the keys and the peers.
+	regionMemoryOverhead = 320
+	// peerMemorySize is the estimated memory of a peer and the pointers to it.
+	peerMemorySize = 64
+	// peerStatsMemorySize is the estimated memory of a down peer stats.
+	peerStatsMemorySize = 96
+)
+
+// estimateMemorySize returns the estimated memory used by the region in the
+// cache except the keys, which may be shared with the neighbors and are counted
+// by the tree of the regions.
+func (r *RegionInfo) estimateMemorySize() int64 {
+	return regionMemoryOverhead +
+		int64(len(r.meta.GetPeers())+len(r.pendingPeers))*peerMemorySize +
+		int64(len(r.downPeers))*peerStatsMemorySize
+}
+
+// shallowClone returns a copy of the region with a copy of the meta, which shares
+// the peers and the other fields with the region.
+func (r *RegionInfo) shallowClone() *RegionInfo {
+	region := *r
+	meta := *r.meta
+	region.meta = &meta
+	return &region
+}
+
 // RegionFromHeartbeat constructs a Region from region heartbeat.
 func RegionFromHeartbeat(heartbeat *pdpb.RegionHeartbeatRequest) *RegionInfo {
 	// Convert unit to MB.
@@ -141,6 +170,7 @@ func (r *RegionInfo) Clone(opts ...RegionCreateOption) *RegionInfo {
 		approximateKeys:   r.approximateKeys,
 		interval:          proto.Clone(r.interval).(*pdpb.TimeInterval),
 		replicationStatus: r.replicationStatus,
+		hibernated:        r.hibernated,
 	}
 
 	for _, opt := range opts {
@@ -357,6 +387,12 @@ func (r *RegionInfo) GetInterval() *pdpb.TimeInterval {
 	return r.interval
 }
 
+// IsHibernated returns if the region is hibernated, which means its leader
+// reduces the heartbeat frequency because the region is idle.
+func (r *RegionInfo) IsHibernated() bool {
+	return r.hibernated
+}
+
 // GetDownPeers returns the down peers of the region.
 func (r *RegionInfo) GetDownPeers() []*pdpb.PeerStats {
 	return r.downPeers
@@ -377,6 +413,16 @@ func (r *RegionInfo) GetBytesWritten() uint64 {
 	return r.writtenBytes
 }
 
+// GetBytesRate returns the read and written bytes per second of the region
+// in the last report interval.
+func (r *RegionInfo) GetBytesRate() float64 {
+	interval := r.interval.GetEndTimestamp() - r.interval.GetStartTimestamp()
+	if interval == 0 {
+		return 0
+	}
+	return float64(r.readBytes+r.writtenBytes) / float64(interval)
+}
+
 // GetKeysWritten returns the written keys of the region.
 func (r *RegionInfo) GetKeysWritten() uint64 {
 	return r.writtenKeys
@@ -419,9 +465,10 @@ func (r *RegionInfo) GetReplicationStatus() *replication_modepb.RegionReplicatio
 
 // regionMap wraps a map[uint64]*core.RegionInfo and supports randomly pick a region.
 type regionMap struct {
-	m         map[uint64]*RegionInfo
-	totalSize int64
-	totalKeys int64
+	m           map[uint64]*RegionInfo
+	totalSize   int64
+	totalKeys   int64
+	totalMemory int64
 }
 
 func newRegionMap() *regionMap {
@@ -451,10 +498,12 @@ func (rm *regionMap) Put(region *RegionInfo) {
 	if old, ok := rm.m[region.GetID()]; ok {
 		rm.totalSize -= old.approximateSize
 		rm.totalKeys -= old.approximateKeys
+		rm.totalMemory -= old.estimateMemorySize()
 	}
 	rm.m[region.GetID()] = region
 	rm.totalSize += region.approximateSize
 	rm.totalKeys += region.approximateKeys
+	rm.totalMemory += region.estimateMemorySize()
 }
 
 func (rm *regionMap) Delete(id uint64) {
@@ -465,9 +514,17 @@ func (rm *regionMap) Delete(id uint64) {
 		delete(rm.m, id)
 		rm.totalSize -= old.approximateSize
 		rm.totalKeys -= old.approximateKeys
+		rm.totalMemory -= old.estimateMemorySize()
 	}
 }
 
+func (rm *regionMap) TotalMemory() int64 {
+	if rm.Len() == 0 {
+		return 0
+	}
+	return rm.totalMemory
+}
+
 func (rm *regionMap) TotalSize() int64 {
 	if rm.Len() == 0 {
 		return 0
@@ -566,12 +623,19 @@ type RegionsInfo struct {
 	followers    map[uint64]*regionSubTree // storeID -> regionSubTree
 	learners     map[uint64]*regionSubTree // storeID -> regionSubTree
 	pendingPeers map[uint64]*regionSubTree // storeID -> regionSubTree
+	// compactKeys makes the regions share the boundary keys with their
+	// neighbors in the cache, which trades the CPU for the memory.
+	compactKeys bool
+	// compactCursor is the start key of the next region to check by CompactKeys.
+	compactCursor []byte
 }
 
 // NewRegionsInfo creates RegionsInfo with tree, regions, leaders and followers
 func NewRegionsInfo() *RegionsInfo {
+	tree := newRegionTree()
+	tree.countKeys = true
 	return &RegionsInfo{
-		tree:         newRegionTree(),
+		tree:         tree,
 		regions:      newRegionMap(),
 		leaders:      make(map[uint64]*regionSubTree),
 		followers:    make(map[uint64]*regionSubTree),
@@ -629,12 +693,19 @@ func (r *RegionsInfo) AddRegion(region *RegionInfo) []*RegionInfo {
 			if bytes.Equal(regionOld.region.GetStartKey(), region.GetStartKey()) &&
 				bytes.Equal(regionOld.region.GetEndKey(), region.GetEndKey()) &&
 				regionOld.region.GetID() == region.GetID() {
-				regionOld.region = region
+				if r.compactKeys {
+					region.meta.StartKey, region.meta.EndKey = regionOld.region.GetStartKey(), regionOld.region.GetEndKey()
+					r.shareBoundaryKeys(region)
+				}
+				r.tree.replace(regionOld, region)
 				treeNeedAdd = false
 			}
 		}
 	}
 	if treeNeedAdd {
+		if r.compactKeys {
+			r.shareBoundaryKeys(region)
+		}
 		// Add to tree.
 		overlaps = r.tree.update(region)
 		for _, item := range overlaps {
@@ -690,6 +761,70 @@ func (r *RegionsInfo) AddRegion(region *RegionInfo) []*RegionInfo {
 	return overlaps
 }
 
+// shareBoundaryKeys makes the region reuse the end key of the previous
+// region and the start key of the next region if they are equal to its
+// keys. It must be called before the region is put into the cache, so that
+// nobody else can read its meta.
+func (r *RegionsInfo) shareBoundaryKeys(region *RegionInfo) {
+	prev, next := r.tree.getAdjacentRegions(region)
+	if prev != nil && len(region.GetStartKey()) > 0 && bytes.Equal(prev.region.GetEndKey(), region.GetStartKey()) {
+		region.meta.StartKey = prev.region.GetEndKey()
+	}
+	if next != nil && len(region.GetEndKey()) > 0 && bytes.Equal(next.region.GetStartKey(), region.GetEndKey()) {
+		region.meta.EndKey = next.region.GetStartKey()
+	}
+}
+
+// SetKeysCompaction sets if the regions share the boundary keys with their
+// neighbors when they are put into the cache.
+func (r *RegionsInfo) SetKeysCompaction(enable bool) {
+	r.compactKeys = enable
+}
+
+// CompactKeys makes the cached regions share the boundary keys with their
+// neighbors, so that the key copies of the regions cached before the compaction
+// is enabled, most of which are cold and not updated since, are dropped. The
+// dropped keys are equal to the ones of the neighbors, so there is nothing to
+// reload. It checks at most limit regions from the one after the last checked
+// region, and returns the number of the compacted regions.
+func (r *RegionsInfo) CompactKeys(limit int) int {
+	var regions []*RegionInfo
+	r.tree.scanRange(r.compactCursor, func(region *RegionInfo) bool {
+		if len(regions) >= limit {
+			return false
+		}
+		regions = append(regions, region)
+		return true
+	})
+	r.compactCursor = nil
+	if len(regions) == limit {
+		r.compactCursor = regions[limit-1].GetEndKey()
+	}
+	// The keys can be shared if they are equal but not shared yet.
+	canShare := func(key, other []byte) bool {
+		return len(key) > 0 && !sameBytes(key, other) && bytes.Equal(key, other)
+	}
+	compacted := 0
+	for _, region := range regions {
+		prev, next := r.tree.getAdjacentRegions(region)
+		if (prev == nil || !canShare(region.GetStartKey(), prev.region.GetEndKey())) &&
+			(next == nil || !canShare(region.GetEndKey(), next.region.GetStartKey())) {
+			continue
+		}
+		// The cached region is read-only, so a copy of it takes its place.
+		region = region.shallowClone()
+		r.shareBoundaryKeys(region)
+		r.AddRegion(region)
+		compacted++
+	}
+	return compacted
+}
+
+// GetMemorySize returns the estimated memory used by the cached regions.
+func (r *RegionsInfo) GetMemorySize() int64 {
+	return r.regions.TotalMemory() + r.tree.keysMemory
+}
+
 // RemoveRegion removes RegionInfo from regionTree and regionMap
 func (r *RegionsInfo) RemoveRegion(region *RegionInfo) {
 	// Remove from tree and regions.
@@ -1002,6 +1137,32 @@ func DiffRegionKeyInfo(origin *RegionInfo, other *RegionInfo) string {
 	return strings.Join(ret, ", ")
 }
 
+// RegionLabelValue returns the value of the label which is shared by most of
+// the stores of the region, the leader breaks the tie. It returns an empty
+// string if none of the stores has the label.
+func RegionLabelValue(region *RegionInfo, stores []*StoreInfo, key string) string {
+	counts := make(map[string]int)
+	var leaderValue string
+	for _, store := range stores {
+		value := store.GetLabelValue(key)
+		if value == "" {
+			continue
+		}
+		counts[value]++
+		if store.GetID() == region.GetLeader().GetStoreId() {
+			leaderValue = value
+		}
+	}
+	var best string
+	for value, count := range counts {
+		if best == "" || count > counts[best] ||
+			(count == counts[best] && (value == leaderValue || (best != leaderValue && value < best))) {
+			best = value
+		}
+	}
+	return best
+}
+
 func isInvolved(region *RegionInfo, startKey, endKey []byte) bool {
 	return bytes.Compare(region.GetStartKey(), startKey) >= 0 && (len(endKey) == 0 || (len(region.GetEndKey()) > 0 && bytes.Compare(region.GetEndKey(), endKey) <= 0))
 }
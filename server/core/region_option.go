@@ -232,6 +232,13 @@ func SetReplicationStatus(status *replication_modepb.RegionReplicationStatus) Re
 	}
 }
 
+// SetHibernated sets if the region is hibernated.
+func SetHibernated(hibernated bool) RegionCreateOption {
+	return func(region *RegionInfo) {
+		region.hibernated = hibernated
+	}
+}
+
 // WithAddPeer adds a peer for the region.
 func WithAddPeer(peer *metapb.Peer) RegionCreateOption {
 	return func(region *RegionInfo) {
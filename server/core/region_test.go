@@ -204,6 +204,65 @@ func (*testRegionKey) TestSetRegion(c *C) {
 	c.Assert(regions.regions.totalSize, Equals, int64(30))
 }
 
+func (*testRegionKey) TestRegionMemory(c *C) {
+	newRegion := func(id uint64, start, end string) *RegionInfo {
+		peer := &metapb.Peer{StoreId: 1, Id: id + 100}
+		return NewRegionInfo(&metapb.Region{
+			Id:       id,
+			Peers:    []*metapb.Peer{peer},
+			StartKey: []byte(start),
+			EndKey:   []byte(end),
+		}, peer)
+	}
+
+	regions := NewRegionsInfo()
+	c.Assert(regions.GetMemorySize(), Equals, int64(0))
+	r1 := newRegion(1, "", "aa")
+	regions.SetRegion(r1)
+	c.Assert(regions.GetMemorySize(), Equals, r1.estimateMemorySize()+2)
+	r3 := newRegion(3, "bb", "")
+	regions.SetRegion(r3)
+	c.Assert(regions.GetMemorySize(), Equals, r1.estimateMemorySize()+r3.estimateMemorySize()+4)
+
+	// The keys are not shared without compaction.
+	r2 := newRegion(2, "aa", "bb")
+	regions.SetRegion(r2)
+	c.Assert(sameBytes(r1.GetEndKey(), r2.GetStartKey()), IsFalse)
+	overhead := r1.estimateMemorySize() + r2.estimateMemorySize() + r3.estimateMemorySize()
+	c.Assert(regions.GetMemorySize(), Equals, overhead+8)
+
+	// The cached regions are compacted to share the keys, which are counted once.
+	regions.SetKeysCompaction(true)
+	c.Assert(regions.CompactKeys(10), Equals, 2)
+	c.Assert(sameBytes(regions.GetRegion(1).GetEndKey(), regions.GetRegion(2).GetStartKey()), IsTrue)
+	c.Assert(sameBytes(regions.GetRegion(2).GetEndKey(), regions.GetRegion(3).GetStartKey()), IsTrue)
+	c.Assert(regions.GetMemorySize(), Equals, overhead+4)
+	c.Assert(regions.CompactKeys(10), Equals, 0)
+	checkRegions(c, regions)
+
+	// The updated region keeps sharing the keys.
+	r2 = newRegion(2, "aa", "bb")
+	regions.SetRegion(r2)
+	c.Assert(sameBytes(regions.GetRegion(1).GetEndKey(), r2.GetStartKey()), IsTrue)
+	c.Assert(regions.GetMemorySize(), Equals, overhead+4)
+
+	// The new region shares the keys with the neighbors.
+	regions.RemoveRegion(r2)
+	c.Assert(regions.GetMemorySize(), Equals, r1.estimateMemorySize()+r3.estimateMemorySize()+4)
+	r2 = newRegion(2, "aa", "bb")
+	regions.SetRegion(r2)
+	c.Assert(sameBytes(regions.GetRegion(1).GetEndKey(), r2.GetStartKey()), IsTrue)
+	c.Assert(sameBytes(regions.GetRegion(3).GetStartKey(), r2.GetEndKey()), IsTrue)
+	c.Assert(regions.GetMemorySize(), Equals, overhead+4)
+	checkRegions(c, regions)
+
+	regions.RemoveRegion(r2)
+	regions.RemoveRegion(r3)
+	c.Assert(regions.GetMemorySize(), Equals, r1.estimateMemorySize()+2)
+	regions.RemoveRegion(r1)
+	c.Assert(regions.GetMemorySize(), Equals, int64(0))
+}
+
 func (*testRegionKey) TestShouldRemoveFromSubTree(c *C) {
 	regions := NewRegionsInfo()
 	peer1 := &metapb.Peer{StoreId: uint64(1), Id: uint64(1)}
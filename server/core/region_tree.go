@@ -49,6 +49,10 @@ const (
 
 type regionTree struct {
 	tree *btree.BTree
+	// countKeys makes the tree count the memory of the keys in keysMemory,
+	// which is only enabled for the tree of all the regions.
+	countKeys  bool
+	keysMemory int64
 }
 
 func newRegionTree() *regionTree {
@@ -93,6 +97,16 @@ func (t *regionTree) getOverlaps(region *RegionInfo) []*RegionInfo {
 // insert the region.
 func (t *regionTree) update(region *RegionInfo) []*RegionInfo {
 	overlaps := t.getOverlaps(region)
+	// The charges of the keys depend on the previous regions, so the ones of the
+	// overlapped regions and the next region are taken back before the tree changes.
+	var next *regionItem
+	if t.countKeys {
+		next = t.firstItemFrom(region.GetEndKey())
+		t.keysMemory -= t.keySize(next)
+		for _, item := range overlaps {
+			t.keysMemory -= t.keySize(&regionItem{item})
+		}
+	}
 	for _, item := range overlaps {
 		log.Debug("overlapping region",
 			zap.Uint64("region-id", item.GetID()),
@@ -101,11 +115,27 @@ func (t *regionTree) update(region *RegionInfo) []*RegionInfo {
 		t.tree.Delete(&regionItem{item})
 	}
 
-	t.tree.ReplaceOrInsert(&regionItem{region: region})
+	item := &regionItem{region: region}
+	t.tree.ReplaceOrInsert(item)
+	if t.countKeys {
+		t.keysMemory += t.keySize(item) + t.keySize(next)
+	}
 
 	return overlaps
 }
 
+// replace replaces the region of the item with the region of the same keys.
+func (t *regionTree) replace(item *regionItem, region *RegionInfo) {
+	if !t.countKeys {
+		item.region = region
+		return
+	}
+	_, next := t.getAdjacentRegions(item.region)
+	t.keysMemory -= t.keySize(item) + t.keySize(next)
+	item.region = region
+	t.keysMemory += t.keySize(item) + t.keySize(next)
+}
+
 // remove removes a region if the region is in the tree.
 // It will do nothing if it cannot find the region or the found region
 // is not the same with the region.
@@ -117,8 +147,49 @@ func (t *regionTree) remove(region *RegionInfo) btree.Item {
 	if result == nil || result.region.GetID() != region.GetID() {
 		return nil
 	}
+	if !t.countKeys {
+		return t.tree.Delete(result)
+	}
+
+	_, next := t.getAdjacentRegions(result.region)
+	t.keysMemory -= t.keySize(result) + t.keySize(next)
+	deleted := t.tree.Delete(result)
+	t.keysMemory += t.keySize(next)
+	return deleted
+}
+
+// keySize returns the memory of the keys charged to the item. The start key
+// shared with the end key of the previous region is charged to the previous
+// region, so that the shared keys are counted once.
+func (t *regionTree) keySize(item *regionItem) int64 {
+	if item == nil {
+		return 0
+	}
+	size := int64(len(item.region.GetEndKey()))
+	startKey := item.region.GetStartKey()
+	if prev, _ := t.getAdjacentRegions(item.region); prev == nil || !sameBytes(prev.region.GetEndKey(), startKey) {
+		size += int64(len(startKey))
+	}
+	return size
+}
+
+// firstItemFrom returns the first item whose start key is not less than the
+// key, or nil if the key is empty, which means the end of the key space.
+func (t *regionTree) firstItemFrom(key []byte) *regionItem {
+	if len(key) == 0 {
+		return nil
+	}
+	var result *regionItem
+	t.tree.AscendGreaterOrEqual(&regionItem{region: &RegionInfo{meta: &metapb.Region{StartKey: key}}}, func(i btree.Item) bool {
+		result = i.(*regionItem)
+		return false
+	})
+	return result
+}
 
-	return t.tree.Delete(result)
+// sameBytes returns if the two slices share the same underlying bytes.
+func sameBytes(a, b []byte) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
 }
 
 // search returns a region that contains the key.
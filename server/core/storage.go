@@ -41,10 +41,18 @@ const (
 	gcPath                   = "gc"
 	rulesPath                = "rules"
 	ruleGroupPath            = "rule_group"
+	regionWeightPath         = "region_weight"
+	storeConfigPath          = "store_config"
+	storeDrainPath           = "store_drain"
+	destroyedStorePath       = "store_destroyed"
+	storeCordonPath          = "store_cordon"
+	storePausePath           = "store_pause"
+	throughputTuningPath     = "throughput_tuning"
 	replicationPath          = "replication_mode"
 	componentPath            = "component"
 	customScheduleConfigPath = "scheduler_config"
-	encryptionKeysPath       = "encryption_keys"
+	schedulerNamespacePath   = "scheduler_namespace"
+	schedulerStoreListPath   = "scheduler_store_list"
 )
 
 const (
@@ -136,7 +144,7 @@ func (s *Storage) storeRegionWeightPath(storeID uint64) string {
 
 // EncryptionKeysPath returns the path to save encryption keys.
 func (s *Storage) EncryptionKeysPath() string {
-	return path.Join(encryptionKeysPath, "keys")
+	return encryptionkm.EncryptionKeysPath
 }
 
 // SaveScheduleConfig saves the config of scheduler.
@@ -151,6 +159,46 @@ func (s *Storage) RemoveScheduleConfig(scheduleName string) error {
 	return s.Remove(configPath)
 }
 
+// SaveSchedulerNamespace saves the namespace which the scheduler is bound to.
+func (s *Storage) SaveSchedulerNamespace(scheduleName, namespace string) error {
+	return s.Save(path.Join(schedulerNamespacePath, scheduleName), namespace)
+}
+
+// RemoveSchedulerNamespace removes the namespace binding of the scheduler.
+func (s *Storage) RemoveSchedulerNamespace(scheduleName string) error {
+	return s.Remove(path.Join(schedulerNamespacePath, scheduleName))
+}
+
+// LoadSchedulerNamespace loads the namespace which the scheduler is bound to.
+func (s *Storage) LoadSchedulerNamespace(scheduleName string) (string, error) {
+	return s.Load(path.Join(schedulerNamespacePath, scheduleName))
+}
+
+// SaveSchedulerStoreList saves the store lists which the scheduler is limited to.
+func (s *Storage) SaveSchedulerStoreList(scheduleName string, list interface{}) error {
+	return s.SaveJSON(schedulerStoreListPath, scheduleName, list)
+}
+
+// RemoveSchedulerStoreList removes the store lists of the scheduler.
+func (s *Storage) RemoveSchedulerStoreList(scheduleName string) error {
+	return s.Remove(path.Join(schedulerStoreListPath, scheduleName))
+}
+
+// LoadSchedulerStoreList loads the store lists which the scheduler is limited to.
+func (s *Storage) LoadSchedulerStoreList(scheduleName string, list interface{}) (bool, error) {
+	v, err := s.Load(path.Join(schedulerStoreListPath, scheduleName))
+	if err != nil {
+		return false, err
+	}
+	if v == "" {
+		return false, nil
+	}
+	if err = json.Unmarshal([]byte(v), list); err != nil {
+		return false, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return true, nil
+}
+
 // LoadScheduleConfig loads the config of scheduler.
 func (s *Storage) LoadScheduleConfig(scheduleName string) (string, error) {
 	configPath := path.Join(customScheduleConfigPath, scheduleName)
@@ -285,6 +333,126 @@ func (s *Storage) LoadRuleGroups(f func(k, v string)) error {
 	return s.LoadRangeByPrefix(ruleGroupPath+"/", f)
 }
 
+// SaveRegionWeight stores a region weight to storage.
+func (s *Storage) SaveRegionWeight(id string, weight interface{}) error {
+	return s.SaveJSON(regionWeightPath, id, weight)
+}
+
+// DeleteRegionWeight removes a region weight from storage.
+func (s *Storage) DeleteRegionWeight(id string) error {
+	return s.Remove(path.Join(regionWeightPath, id))
+}
+
+// LoadRegionWeights loads all region weights from storage.
+func (s *Storage) LoadRegionWeights(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(regionWeightPath+"/", f)
+}
+
+// SaveStoreConfig stores a config snippet of the stores to storage.
+func (s *Storage) SaveStoreConfig(key string, cfg interface{}) error {
+	return s.SaveJSON(storeConfigPath, key, cfg)
+}
+
+// DeleteStoreConfig removes a config snippet of the stores from storage.
+func (s *Storage) DeleteStoreConfig(key string) error {
+	return s.Remove(path.Join(storeConfigPath, key))
+}
+
+// LoadStoreConfigs loads all config snippets of the stores from storage.
+func (s *Storage) LoadStoreConfigs(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(storeConfigPath+"/", f)
+}
+
+// SaveStoreDrain stores the drain of a store to storage.
+func (s *Storage) SaveStoreDrain(storeID uint64, drain interface{}) error {
+	return s.SaveJSON(storeDrainPath, fmt.Sprintf("%020d", storeID), drain)
+}
+
+// DeleteStoreDrain removes the drain of a store from storage.
+func (s *Storage) DeleteStoreDrain(storeID uint64) error {
+	return s.Remove(path.Join(storeDrainPath, fmt.Sprintf("%020d", storeID)))
+}
+
+// LoadStoreDrains loads the drains of all stores from storage.
+func (s *Storage) LoadStoreDrains(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(storeDrainPath+"/", f)
+}
+
+// SaveStoreCordon stores the deadline of the cordon of a store to storage.
+func (s *Storage) SaveStoreCordon(storeID uint64, deadline time.Time) error {
+	return s.SaveJSON(storeCordonPath, fmt.Sprintf("%020d", storeID), deadline)
+}
+
+// DeleteStoreCordon removes the cordon of a store from storage.
+func (s *Storage) DeleteStoreCordon(storeID uint64) error {
+	return s.Remove(path.Join(storeCordonPath, fmt.Sprintf("%020d", storeID)))
+}
+
+// LoadStoreCordons loads the cordons of all stores from storage.
+func (s *Storage) LoadStoreCordons(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(storeCordonPath+"/", f)
+}
+
+// SaveStorePause stores the deadline of the scheduling pause of a store to storage.
+func (s *Storage) SaveStorePause(storeID uint64, deadline time.Time) error {
+	return s.SaveJSON(storePausePath, fmt.Sprintf("%020d", storeID), deadline)
+}
+
+// DeleteStorePause removes the scheduling pause of a store from storage.
+func (s *Storage) DeleteStorePause(storeID uint64) error {
+	return s.Remove(path.Join(storePausePath, fmt.Sprintf("%020d", storeID)))
+}
+
+// LoadStorePauses loads the scheduling pauses of all stores from storage.
+func (s *Storage) LoadStorePauses(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(storePausePath+"/", f)
+}
+
+// SaveThroughputTuning stores the state of the throughput tuning to storage.
+func (s *Storage) SaveThroughputTuning(tuning interface{}) error {
+	value, err := json.Marshal(tuning)
+	if err != nil {
+		return errs.ErrJSONMarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return s.Save(throughputTuningPath, string(value))
+}
+
+// DeleteThroughputTuning removes the state of the throughput tuning from storage.
+func (s *Storage) DeleteThroughputTuning() error {
+	return s.Remove(throughputTuningPath)
+}
+
+// LoadThroughputTuning loads the state of the throughput tuning from storage.
+func (s *Storage) LoadThroughputTuning(tuning interface{}) (bool, error) {
+	v, err := s.Load(throughputTuningPath)
+	if err != nil {
+		return false, err
+	}
+	if v == "" {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(v), tuning); err != nil {
+		return false, errs.ErrJSONUnmarshal.Wrap(err).GenWithStackByArgs()
+	}
+	return true, nil
+}
+
+// SaveDestroyedStore saves the address of a physically destroyed store to
+// storage. It is kept apart from the store meta, so that it outlives the store.
+func (s *Storage) SaveDestroyedStore(storeID uint64, address string) error {
+	return s.Save(path.Join(destroyedStorePath, fmt.Sprintf("%020d", storeID)), address)
+}
+
+// DeleteDestroyedStore removes a physically destroyed store from storage.
+func (s *Storage) DeleteDestroyedStore(storeID uint64) error {
+	return s.Remove(path.Join(destroyedStorePath, fmt.Sprintf("%020d", storeID)))
+}
+
+// LoadDestroyedStores loads the addresses of all physically destroyed stores from storage.
+func (s *Storage) LoadDestroyedStores(f func(k, v string)) error {
+	return s.LoadRangeByPrefix(destroyedStorePath+"/", f)
+}
+
 // SaveJSON saves json format data to storage.
 func (s *Storage) SaveJSON(prefix, key string, data interface{}) error {
 	value, err := json.Marshal(data)
@@ -518,6 +686,10 @@ func (s *Storage) LoadMinServiceGCSafePoint(now time.Time) (*ServiceSafePoint, e
 			min = ssp
 		}
 	}
+	// All service safe points are expired.
+	if min.SafePoint == math.MaxUint64 {
+		return &ServiceSafePoint{}, nil
+	}
 
 	return min, nil
 }
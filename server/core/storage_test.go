@@ -250,6 +250,12 @@ func (s *testKVSuite) TestLoadMinServiceGCSafePoint(c *C) {
 	c.Assert(ssp.ServiceID, Equals, "2")
 	c.Assert(ssp.ExpiredAt, Equals, expireAt)
 	c.Assert(ssp.SafePoint, Equals, uint64(2))
+
+	// All service safe points are expired.
+	ssp, err = storage.LoadMinServiceGCSafePoint(time.Now().Add(2000 * time.Second))
+	c.Assert(err, IsNil)
+	c.Assert(ssp.ServiceID, Equals, "")
+	c.Assert(ssp.SafePoint, Equals, uint64(0))
 }
 
 type KVWithMaxRangeLimit struct {
@@ -38,17 +38,46 @@ type StoreInfo struct {
 	meta                *metapb.Store
 	stats               *pdpb.StoreStats
 	pauseLeaderTransfer bool // not allow to be used as source or target of transfer leader
+	restartDeadline     time.Time
+	paused              ttlState  // not allow to be used as source or target of any operator
+	cordon              ttlState  // not allow to be used as target of any operator
+	draining            bool      // not allow to be used as target of any operator
+	backoffDeadline     time.Time // not allow to be used as target because operators keep failing
+	physicallyDestroyed bool      // the store will never come back
 	leaderCount         int
 	regionCount         int
 	leaderSize          int64
 	regionSize          int64
 	pendingPeerCount    int
 	lastPersistTime     time.Time
+	lastRegionHeartbeat time.Time // the last time a region heartbeat is received from the leaders on the store
+	hibernatesRegions   bool      // the store has reported hibernated regions, whose heartbeats are sparse
 	leaderWeight        float64
 	regionWeight        float64
 	available           map[storelimit.Type]func() bool
 }
 
+// ttlState is a state set on the store by users which lasts until the deadline,
+// such as the pause of scheduling and the cordon. It never expires if the
+// deadline is zero.
+type ttlState struct {
+	set      bool
+	deadline time.Time
+}
+
+func (s ttlState) isActive() bool {
+	return s.set && (s.deadline.IsZero() || time.Now().Before(s.deadline))
+}
+
+// TTLDeadline returns the deadline of a state which lasts for the ttl from now.
+// It is zero if the ttl is not positive, which means the state never expires.
+func TTLDeadline(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
 // NewStoreInfo creates StoreInfo with meta data.
 func NewStoreInfo(store *metapb.Store, opts ...StoreCreateOption) *StoreInfo {
 	storeInfo := &StoreInfo{
@@ -76,9 +105,17 @@ func (s *StoreInfo) Clone(opts ...StoreCreateOption) *StoreInfo {
 		regionSize:          s.regionSize,
 		pendingPeerCount:    s.pendingPeerCount,
 		lastPersistTime:     s.lastPersistTime,
+		lastRegionHeartbeat: s.lastRegionHeartbeat,
+		hibernatesRegions:   s.hibernatesRegions,
 		leaderWeight:        s.leaderWeight,
 		regionWeight:        s.regionWeight,
 		available:           s.available,
+		restartDeadline:     s.restartDeadline,
+		paused:              s.paused,
+		cordon:              s.cordon,
+		draining:            s.draining,
+		backoffDeadline:     s.backoffDeadline,
+		physicallyDestroyed: s.physicallyDestroyed,
 	}
 
 	for _, opt := range opts {
@@ -99,9 +136,17 @@ func (s *StoreInfo) ShallowClone(opts ...StoreCreateOption) *StoreInfo {
 		regionSize:          s.regionSize,
 		pendingPeerCount:    s.pendingPeerCount,
 		lastPersistTime:     s.lastPersistTime,
+		lastRegionHeartbeat: s.lastRegionHeartbeat,
+		hibernatesRegions:   s.hibernatesRegions,
 		leaderWeight:        s.leaderWeight,
 		regionWeight:        s.regionWeight,
 		available:           s.available,
+		restartDeadline:     s.restartDeadline,
+		paused:              s.paused,
+		cordon:              s.cordon,
+		draining:            s.draining,
+		backoffDeadline:     s.backoffDeadline,
+		physicallyDestroyed: s.physicallyDestroyed,
 	}
 
 	for _, opt := range opts {
@@ -116,6 +161,67 @@ func (s *StoreInfo) AllowLeaderTransfer() bool {
 	return !s.pauseLeaderTransfer
 }
 
+// IsPhysicallyDestroyed returns true if the store is removed and will never
+// come back. Its peers are replaced without waiting, and its address can not
+// be registered again.
+func (s *StoreInfo) IsPhysicallyDestroyed() bool {
+	return s.physicallyDestroyed
+}
+
+// IsRestarting returns true if the store is going to restart and is still in
+// the grace period. The leaders of a restarting store are evicted, and its
+// down peers are not replaced.
+func (s *StoreInfo) IsRestarting() bool {
+	return time.Now().Before(s.restartDeadline)
+}
+
+// GetRestartDeadline returns the end of the grace period of restarting.
+func (s *StoreInfo) GetRestartDeadline() time.Time {
+	return s.restartDeadline
+}
+
+// IsSchedulingPaused returns true if the store is paused for scheduling and
+// the pause is not expired. A paused store is not selected as source or target
+// of any new operator.
+func (s *StoreInfo) IsSchedulingPaused() bool {
+	return s.paused.isActive()
+}
+
+// GetPauseDeadline returns the time when the pause of scheduling expires.
+func (s *StoreInfo) GetPauseDeadline() time.Time {
+	return s.paused.deadline
+}
+
+// IsCordoned returns true if the store is cordoned and the cordon is not
+// expired. A cordoned store keeps serving its data, but it is not selected as
+// target of any new operator.
+func (s *StoreInfo) IsCordoned() bool {
+	return s.cordon.isActive()
+}
+
+// IsDraining returns true if the store is being drained, so that it is not
+// selected as target of any operator.
+func (s *StoreInfo) IsDraining() bool {
+	return s.draining
+}
+
+// GetCordonDeadline returns the time when the cordon expires, it is zero if
+// the cordon never expires.
+func (s *StoreInfo) GetCordonDeadline() time.Time {
+	return s.cordon.deadline
+}
+
+// IsBackingOff returns true if the operators targeting the store keep failing
+// recently, so the store is temporarily not selected as target.
+func (s *StoreInfo) IsBackingOff() bool {
+	return time.Now().Before(s.backoffDeadline)
+}
+
+// GetBackoffDeadline returns the time when the scheduling backoff expires.
+func (s *StoreInfo) GetBackoffDeadline() time.Time {
+	return s.backoffDeadline
+}
+
 // IsAvailable returns if the store bucket of limitation is available
 func (s *StoreInfo) IsAvailable(limitType storelimit.Type) bool {
 	if s.available != nil && s.available[limitType] != nil {
@@ -269,6 +375,17 @@ func (s *StoreInfo) GetRegionWeight() float64 {
 	return s.regionWeight
 }
 
+// GetLastRegionHeartbeatTS returns the last time a region heartbeat is received
+// from the leaders on the store.
+func (s *StoreInfo) GetLastRegionHeartbeatTS() time.Time {
+	return s.lastRegionHeartbeat
+}
+
+// HibernatesRegions returns if the store has reported hibernated regions.
+func (s *StoreInfo) HibernatesRegions() bool {
+	return s.hibernatesRegions
+}
+
 // GetLastHeartbeatTS returns the last heartbeat timestamp of the store.
 func (s *StoreInfo) GetLastHeartbeatTS() time.Time {
 	return time.Unix(0, s.meta.GetLastHeartbeat())
@@ -282,20 +399,140 @@ func (s *StoreInfo) NeedPersist() bool {
 const minWeight = 1e-6
 const maxScore = 1024 * 1024 * 1024
 
+// ScoreBreakdown is the components of a scheduling score of the store. The
+// score is the sum of the terms divided by the weight.
+type ScoreBreakdown struct {
+	// Formula is the leader schedule policy or the region score formula.
+	Formula   string
+	SizeTerm  float64
+	CountTerm float64
+	// SpaceTerm is the adjustment by the available space of the store.
+	SpaceTerm float64
+	FlowTerm  float64
+	Weight    float64
+	// Influence is the delta of the pending operators counted in the score.
+	Influence int64
+	Score     float64
+}
+
 // LeaderScore returns the store's leader score.
 func (s *StoreInfo) LeaderScore(policy SchedulePolicy, delta int64) float64 {
+	return s.LeaderScoreBreakdown(policy, delta).Score
+}
+
+// LeaderScoreBreakdown returns the components of the store's leader score.
+func (s *StoreInfo) LeaderScoreBreakdown(policy SchedulePolicy, delta int64) ScoreBreakdown {
+	b := ScoreBreakdown{
+		Formula:   policy.String(),
+		Weight:    math.Max(s.GetLeaderWeight(), minWeight),
+		Influence: delta,
+	}
 	switch policy {
 	case BySize:
-		return float64(s.GetLeaderSize()+delta) / math.Max(s.GetLeaderWeight(), minWeight)
+		b.SizeTerm = float64(s.GetLeaderSize() + delta)
+		b.Score = b.SizeTerm / b.Weight
 	case ByCount:
-		return float64(int64(s.GetLeaderCount())+delta) / math.Max(s.GetLeaderWeight(), minWeight)
-	default:
-		return 0
+		b.CountTerm = float64(int64(s.GetLeaderCount()) + delta)
+		b.Score = b.CountTerm / b.Weight
+	}
+	return b
+}
+
+// Versions of the region score formula.
+const (
+	// RegionScoreFormulaV1 scores the store by the region size, and by the
+	// available space when the space is low.
+	RegionScoreFormulaV1 = "v1"
+	// RegionScoreFormulaV2 scores the store by the weighted sum of the
+	// region size, the region count, the available space and the flow,
+	// which are all relative to the capacity of the store.
+	RegionScoreFormulaV2 = "v2"
+)
+
+// RegionScoreWeights is the weights of the dimensions in the v2 region score.
+type RegionScoreWeights struct {
+	Size  float64
+	Count float64
+	Space float64
+	Flow  float64
+}
+
+const (
+	// defaultRegionSize is the size of a region to estimate how many regions
+	// can be held by a store.
+	defaultRegionSize = 96 // MB
+	// defaultStoreHeartbeatInterval is used when the interval of the store
+	// heartbeat is not reported.
+	defaultStoreHeartbeatInterval = 10 // seconds
+)
+
+// RegionScore returns the store's region score calculated by the formula of
+// the version.
+func (s *StoreInfo) RegionScore(version string, weights RegionScoreWeights, highSpaceRatio, lowSpaceRatio float64, delta int64) float64 {
+	return s.RegionScoreBreakdown(version, weights, highSpaceRatio, lowSpaceRatio, delta).Score
+}
+
+// RegionScoreBreakdown returns the components of the store's region score.
+func (s *StoreInfo) RegionScoreBreakdown(version string, weights RegionScoreWeights, highSpaceRatio, lowSpaceRatio float64, delta int64) ScoreBreakdown {
+	if version == RegionScoreFormulaV2 && s.GetCapacity() > 0 {
+		return s.regionScoreV2(weights, delta)
 	}
+	return s.regionScoreV1(highSpaceRatio, lowSpaceRatio, delta)
 }
 
-// RegionScore returns the store's region score.
-func (s *StoreInfo) RegionScore(highSpaceRatio, lowSpaceRatio float64, delta int64) float64 {
+// regionScoreV2 makes the stores with different capacities comparable, so
+// the stores of a heterogeneous cluster are balanced by their utilization.
+// Each dimension is the ratio to the capacity:
+//   - size: the region size.
+//   - count: the space needed by the regions if they are all full.
+//   - space: the used space divided by the available space, which grows
+//     rapidly when the store is running out of space.
+//   - flow: the bytes written and read in an hour.
+func (s *StoreInfo) regionScoreV2(weights RegionScoreWeights, delta int64) ScoreBreakdown {
+	capacity := float64(s.GetCapacity()) / mb
+	available := float64(s.GetAvailable()) / mb
+	used := float64(s.GetUsedSize()) / mb
+	regionSize := float64(s.GetRegionSize())
+
+	amplification := 1.0
+	if regionSize > 0 && used > 0 {
+		// because of rocksdb compression, region size is larger than actual used size
+		amplification = regionSize / used
+	}
+	avgRegionSize := float64(defaultRegionSize)
+	if s.GetRegionCount() > 0 && regionSize > 0 {
+		avgRegionSize = regionSize / float64(s.GetRegionCount())
+	}
+
+	sizeRatio := (regionSize + float64(delta)) / capacity
+	countRatio := (float64(s.GetRegionCount()) + float64(delta)/avgRegionSize) * defaultRegionSize / capacity
+	available -= float64(delta) / amplification
+	spaceRatio := float64(maxScore)
+	if available > 0 {
+		spaceRatio = math.Max(capacity-available, 0) / available
+	}
+	interval := float64(s.GetStoreStats().GetInterval().GetEndTimestamp() - s.GetStoreStats().GetInterval().GetStartTimestamp())
+	if interval <= 0 {
+		interval = defaultStoreHeartbeatInterval
+	}
+	flowRatio := float64(s.GetBytesWritten()+s.GetBytesRead()) / mb / interval * 3600 / capacity
+
+	score := weights.Size*sizeRatio + weights.Count*countRatio + weights.Space*spaceRatio + weights.Flow*flowRatio
+	// Scale the score to be readable.
+	weight := math.Max(s.GetRegionWeight(), minWeight)
+	return ScoreBreakdown{
+		Formula:   RegionScoreFormulaV2,
+		SizeTerm:  weights.Size * sizeRatio * 100,
+		CountTerm: weights.Count * countRatio * 100,
+		SpaceTerm: weights.Space * spaceRatio * 100,
+		FlowTerm:  weights.Flow * flowRatio * 100,
+		Weight:    weight,
+		Influence: delta,
+		Score:     score * 100 / weight,
+	}
+}
+
+func (s *StoreInfo) regionScoreV1(highSpaceRatio, lowSpaceRatio float64, delta int64) ScoreBreakdown {
 	var score float64
 	var amplification float64
 	available := float64(s.GetAvailable()) / mb
@@ -337,7 +574,17 @@ func (s *StoreInfo) RegionScore(highSpaceRatio, lowSpaceRatio float64, delta int
 		score = k*float64(s.GetRegionSize()+delta) + b
 	}
 
-	return score / math.Max(s.GetRegionWeight(), minWeight)
+	weight := math.Max(s.GetRegionWeight(), minWeight)
+	sizeTerm := float64(s.GetRegionSize() + delta)
+	return ScoreBreakdown{
+		Formula: RegionScoreFormulaV1,
+		// The score grows faster than the size when the space is not enough.
+		SizeTerm:  sizeTerm,
+		SpaceTerm: score - sizeTerm,
+		Weight:    weight,
+		Influence: delta,
+		Score:     score / weight,
+	}
 }
 
 // StorageSize returns store's used storage size reported from tikv.
@@ -383,12 +630,12 @@ func (s *StoreInfo) ResourceSize(kind ResourceKind) int64 {
 }
 
 // ResourceScore returns score of leader/region in the store.
-func (s *StoreInfo) ResourceScore(scheduleKind ScheduleKind, highSpaceRatio, lowSpaceRatio float64, delta int64) float64 {
+func (s *StoreInfo) ResourceScore(scheduleKind ScheduleKind, version string, weights RegionScoreWeights, highSpaceRatio, lowSpaceRatio float64, delta int64) float64 {
 	switch scheduleKind.Resource {
 	case LeaderKind:
 		return s.LeaderScore(scheduleKind.Policy, delta)
 	case RegionKind:
-		return s.RegionScore(highSpaceRatio, lowSpaceRatio, delta)
+		return s.RegionScore(version, weights, highSpaceRatio, lowSpaceRatio, delta)
 	default:
 		return 0
 	}
@@ -443,6 +690,21 @@ func (s *StoreInfo) IsDisconnected() bool {
 	return s.DownTime() > storeDisconnectDuration
 }
 
+// IsHeartbeatStale checks if the last store heartbeat of the store, or the last
+// region heartbeat from its leaders, is older than the bound. The stores
+// without leaders do not send region heartbeats, and the stores hibernating
+// the idle regions leave long gaps between them, so only the store heartbeat
+// is checked for them.
+func (s *StoreInfo) IsHeartbeatStale(bound time.Duration) bool {
+	if bound <= 0 {
+		return false
+	}
+	if s.DownTime() > bound {
+		return true
+	}
+	return s.GetLeaderCount() > 0 && !s.hibernatesRegions && !s.lastRegionHeartbeat.IsZero() && time.Since(s.lastRegionHeartbeat) > bound
+}
+
 // IsUnhealthy checks if a store is unhealthy.
 func (s *StoreInfo) IsUnhealthy() bool {
 	return s.DownTime() > storeUnhealthyDuration
@@ -571,6 +833,29 @@ func (s *StoresInfo) ResumeLeaderTransfer(storeID uint64) {
 	s.stores[storeID] = store.Clone(ResumeLeaderTransfer())
 }
 
+// SetStoreBackoff backs off the scheduling to a specific store until the deadline.
+func (s *StoresInfo) SetStoreBackoff(storeID uint64, deadline time.Time) {
+	if store, ok := s.stores[storeID]; ok {
+		s.stores[storeID] = store.Clone(SetBackoffDeadline(deadline))
+	}
+}
+
+// SetStoreRegionHeartbeatTS records the last time a region heartbeat is
+// received from the leaders on a specific store.
+func (s *StoresInfo) SetStoreRegionHeartbeatTS(storeID uint64, ts time.Time) {
+	if store, ok := s.stores[storeID]; ok {
+		s.stores[storeID] = store.ShallowClone(SetLastRegionHeartbeatTS(ts))
+	}
+}
+
+// SetStoreHibernatesRegions marks that a specific store has reported
+// hibernated regions.
+func (s *StoresInfo) SetStoreHibernatesRegions(storeID uint64) {
+	if store, ok := s.stores[storeID]; ok {
+		s.stores[storeID] = store.ShallowClone(SetHibernatesRegions())
+	}
+}
+
 // AttachAvailableFunc attaches f to a specific store.
 func (s *StoresInfo) AttachAvailableFunc(storeID uint64, limitType storelimit.Type, f func() bool) {
 	if store, ok := s.stores[storeID]; ok {
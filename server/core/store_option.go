@@ -98,6 +98,71 @@ func ResumeLeaderTransfer() StoreCreateOption {
 	}
 }
 
+// SetPhysicallyDestroyed marks the store is physically destroyed.
+func SetPhysicallyDestroyed() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.physicallyDestroyed = true
+	}
+}
+
+// SetRestartDeadline marks the store is restarting until the deadline.
+func SetRestartDeadline(deadline time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.restartDeadline = deadline
+	}
+}
+
+// ResetRestartDeadline cleans the restarting state of the store.
+func ResetRestartDeadline() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.restartDeadline = time.Time{}
+	}
+}
+
+// SetPauseDeadline pauses the scheduling of the store until the deadline. The
+// pause never expires if the deadline is zero.
+func SetPauseDeadline(deadline time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.paused = ttlState{set: true, deadline: deadline}
+	}
+}
+
+// ResetPauseDeadline resumes the scheduling of the store.
+func ResetPauseDeadline() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.paused = ttlState{}
+	}
+}
+
+// SetBackoffDeadline stops using the store as target until the deadline.
+func SetBackoffDeadline(deadline time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.backoffDeadline = deadline
+	}
+}
+
+// SetCordon cordons the store until the deadline. The cordon never expires if
+// the deadline is zero.
+func SetCordon(deadline time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.cordon = ttlState{set: true, deadline: deadline}
+	}
+}
+
+// SetDraining sets if the store is being drained.
+func SetDraining(draining bool) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.draining = draining
+	}
+}
+
+// ResetCordon uncordons the store.
+func ResetCordon() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.cordon = ttlState{}
+	}
+}
+
 // SetLeaderCount sets the leader count for the store.
 func SetLeaderCount(leaderCount int) StoreCreateOption {
 	return func(store *StoreInfo) {
@@ -154,6 +219,21 @@ func SetLastHeartbeatTS(lastHeartbeatTS time.Time) StoreCreateOption {
 	}
 }
 
+// SetLastRegionHeartbeatTS sets the time of the last region heartbeat from the
+// leaders on the store.
+func SetLastRegionHeartbeatTS(lastRegionHeartbeatTS time.Time) StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.lastRegionHeartbeat = lastRegionHeartbeatTS
+	}
+}
+
+// SetHibernatesRegions marks that the store has reported hibernated regions.
+func SetHibernatesRegions() StoreCreateOption {
+	return func(store *StoreInfo) {
+		store.hibernatesRegions = true
+	}
+}
+
 // SetLastPersistTime updates the time of last persistent.
 func SetLastPersistTime(lastPersist time.Time) StoreCreateOption {
 	return func(store *StoreInfo) {
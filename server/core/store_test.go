@@ -110,7 +110,70 @@ func (s *testStoreSuite) TestRegionScore(c *C) {
 		SetStoreStats(stats),
 		SetRegionSize(1),
 	)
-	score := store.RegionScore(0.7, 0.9, 0)
+	score := store.RegionScore(RegionScoreFormulaV1, RegionScoreWeights{}, 0.7, 0.9, 0)
 	// Region score should never be NaN, or /store API would fail.
 	c.Assert(math.IsNaN(score), Equals, false)
 }
+
+func (s *testStoreSuite) TestRegionScoreV2(c *C) {
+	weights := RegionScoreWeights{Size: 1, Space: 1}
+	newStore := func(capacity, available, regionSize uint64) *StoreInfo {
+		return NewStoreInfo(
+			&metapb.Store{Id: 1},
+			SetStoreStats(&pdpb.StoreStats{
+				Capacity:  capacity * (1 << 20),
+				Available: available * (1 << 20),
+				UsedSize:  (capacity - available) * (1 << 20),
+			}),
+			SetRegionSize(int64(regionSize)),
+		)
+	}
+	// The stores with the same utilization have the same score.
+	small := newStore(1000, 600, 400)
+	large := newStore(4000, 2400, 1600)
+	scoreSmall := small.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.9, 0)
+	scoreLarge := large.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.9, 0)
+	c.Assert(math.Abs(scoreSmall-scoreLarge), Less, 1e-6)
+	// The store running out of space has a higher score.
+	full := newStore(1000, 100, 400)
+	c.Assert(full.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.9, 0), Greater, scoreSmall)
+	// The delta is applied to the score.
+	c.Assert(small.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.9, 100), Greater, scoreSmall)
+	// Fallback to v1 if the capacity is unknown.
+	unknown := newStore(0, 0, 400)
+	c.Assert(unknown.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.9, 0),
+		Equals, unknown.RegionScore(RegionScoreFormulaV1, weights, 0.7, 0.9, 0))
+}
+
+func (s *testStoreSuite) TestScoreBreakdown(c *C) {
+	store := NewStoreInfo(
+		&metapb.Store{Id: 1},
+		SetStoreStats(&pdpb.StoreStats{
+			Capacity:  1000 * (1 << 20),
+			Available: 100 * (1 << 20),
+			UsedSize:  900 * (1 << 20),
+		}),
+		SetRegionSize(900),
+		SetLeaderCount(10),
+		SetLeaderWeight(2),
+	)
+	b := store.LeaderScoreBreakdown(ByCount, 2)
+	c.Assert(b.Formula, Equals, "count")
+	c.Assert(b.CountTerm, Equals, float64(12))
+	c.Assert(b.Influence, Equals, int64(2))
+	c.Assert(b.Score, Equals, float64(6))
+	c.Assert(b.Score, Equals, store.LeaderScore(ByCount, 2))
+
+	// The store running out of space gets the space adjustment.
+	b = store.RegionScoreBreakdown(RegionScoreFormulaV1, RegionScoreWeights{}, 0.7, 0.8, 0)
+	c.Assert(b.SizeTerm, Equals, float64(900))
+	c.Assert(b.SpaceTerm > 0, IsTrue)
+	c.Assert(math.Abs((b.SizeTerm+b.SpaceTerm)/b.Weight-b.Score) < 1e-6, IsTrue)
+
+	weights := RegionScoreWeights{Size: 1, Count: 1, Space: 1}
+	b = store.RegionScoreBreakdown(RegionScoreFormulaV2, weights, 0.7, 0.8, 0)
+	c.Assert(b.Formula, Equals, RegionScoreFormulaV2)
+	c.Assert(b.FlowTerm, Equals, float64(0))
+	c.Assert(math.Abs((b.SizeTerm+b.CountTerm+b.SpaceTerm)/b.Weight-b.Score) < 1e-6, IsTrue)
+	c.Assert(b.Score, Equals, store.RegionScore(RegionScoreFormulaV2, weights, 0.7, 0.8, 0))
+}
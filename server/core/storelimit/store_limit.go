@@ -28,15 +28,17 @@ const (
 
 // RegionInfluence represents the influence of a operator step, which is used by store limit.
 var RegionInfluence = map[Type]int64{
-	AddPeer:    1000,
-	RemovePeer: 1000,
+	AddPeer:        1000,
+	RemovePeer:     1000,
+	TransferLeader: 1000,
 }
 
 // SmallRegionInfluence represents the influence of a operator step
 // when the region size is smaller than smallRegionThreshold, which is used by store limit.
 var SmallRegionInfluence = map[Type]int64{
-	AddPeer:    200,
-	RemovePeer: 200,
+	AddPeer:        200,
+	RemovePeer:     200,
+	TransferLeader: 1000,
 }
 
 // Type indicates the type of store limit
@@ -47,12 +49,16 @@ const (
 	AddPeer Type = iota
 	// RemovePeer indicates the type of store limit that limits the removing peer rate
 	RemovePeer
+	// TransferLeader indicates the type of store limit that limits the rate
+	// of transferring leaders in
+	TransferLeader
 )
 
 // TypeNameValue indicates the name of store limit type and the enum value
 var TypeNameValue = map[string]Type{
-	"add-peer":    AddPeer,
-	"remove-peer": RemovePeer,
+	"add-peer":        AddPeer,
+	"remove-peer":     RemovePeer,
+	"transfer-leader": TransferLeader,
 }
 
 // String returns the representation of the Type
@@ -74,6 +80,11 @@ type StoreLimit struct {
 
 // NewStoreLimit returns a StoreLimit object
 func NewStoreLimit(ratePerSec float64, regionInfluence int64) *StoreLimit {
+	// A zero rate stops the operators, which is represented by a nil bucket as
+	// the bucket cannot be created without filling tokens.
+	if ratePerSec <= 0 {
+		return &StoreLimit{regionInfluence: regionInfluence, ratePerSec: ratePerSec}
+	}
 	capacity := regionInfluence
 	rate := ratePerSec
 	// unlimited
@@ -94,6 +105,9 @@ func NewStoreLimit(ratePerSec float64, regionInfluence int64) *StoreLimit {
 
 // Available returns the number of available tokens
 func (l *StoreLimit) Available() int64 {
+	if l.bucket == nil {
+		return 0
+	}
 	return l.bucket.Available()
 }
 
@@ -104,5 +118,8 @@ func (l *StoreLimit) Rate() float64 {
 
 // Take takes count tokens from the bucket without blocking.
 func (l *StoreLimit) Take(count int64) time.Duration {
+	if l.bucket == nil {
+		return 0
+	}
 	return l.bucket.Take(count)
 }
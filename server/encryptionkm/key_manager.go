@@ -14,41 +14,318 @@
 package encryptionkm
 
 import (
+	"context"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
 	"github.com/pingcap/kvproto/pkg/encryptionpb"
+	"github.com/pingcap/log"
 	lib "github.com/tikv/pd/pkg/encryption"
+	"github.com/tikv/pd/pkg/errs"
 	"github.com/tikv/pd/server/election"
 	"github.com/tikv/pd/server/kv"
+	"go.etcd.io/etcd/clientv3"
+	"go.uber.org/zap"
+)
+
+const (
+	// EncryptionKeysPath is the path to save the encryption keys.
+	EncryptionKeysPath = "encryption_keys/keys"
+	// keyRotationCheckPeriod is how often the leader checks if the current
+	// data key needs to be rotated.
+	keyRotationCheckPeriod = time.Minute
 )
 
 // KeyManager maintains the list to encryption keys. It handles encryption key generation and
 // rotation, persisting and loading encryption keys.
-type KeyManager struct{}
+//
+// The data keys are saved in etcd encrypted by the master key. Only the
+// PD leader generates and rotates the data keys, the followers reload the
+// keys from etcd when they meet an unknown key. The keys are saved by a txn
+// conditioned on the leadership, so a PD which has just lost its leadership
+// can not overwrite the keys saved by the new leader.
+type KeyManager struct {
+	kv kv.Base
+	// keysPath is the full etcd path of the keys, which is used by the txn.
+	keysPath string
+	// Encryption method of the new data keys.
+	method encryptionpb.EncryptionMethod
+	// Master key config used to encrypt the data keys.
+	masterKeyMeta         *encryptionpb.MasterKey
+	dataKeyRotationPeriod time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
 
-// NewKeyManager creates a new key manager.
-func NewKeyManager(kv kv.Base, config *lib.Config) (*KeyManager, error) {
-	// TODO: Implement
-	return &KeyManager{}, nil
+	mu struct {
+		sync.RWMutex
+		keys *encryptionpb.KeyDictionary
+		// The master key config the keys are encrypted by in the KV.
+		keysMasterKeyMeta *encryptionpb.MasterKey
+		// Stops the rotation loop of the previous leadership.
+		leaderCancel context.CancelFunc
+	}
+	// now is used to mock the time in tests.
+	now func() time.Time
+}
+
+// NewKeyManager creates a new key manager which saves the keys under the root path.
+func NewKeyManager(client *clientv3.Client, rootPath string, config *lib.Config) (*KeyManager, error) {
+	method, err := config.GetMethod()
+	if err != nil {
+		return nil, err
+	}
+	masterKeyMeta, err := config.GetMasterKey()
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the master key is available.
+	if _, err = lib.NewMasterKey(masterKeyMeta); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &KeyManager{
+		kv:                    kv.NewEtcdKVBase(client, rootPath),
+		keysPath:              path.Join(rootPath, EncryptionKeysPath),
+		method:                method,
+		masterKeyMeta:         masterKeyMeta,
+		dataKeyRotationPeriod: config.DataKeyRotationPeriod.Duration,
+		ctx:                   ctx,
+		cancel:                cancel,
+		now:                   time.Now,
+	}
+	if err = m.reloadKeys(); err != nil {
+		cancel()
+		return nil, err
+	}
+	return m, nil
 }
 
 // GetCurrentKey get the current encryption key. The key is nil if encryption is not enabled.
 func (m *KeyManager) GetCurrentKey() (keyID uint64, key *encryptionpb.DataKey, err error) {
-	// TODO: Implement
-	return 0, nil, nil
+	if m == nil {
+		return 0, nil, nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := m.mu.keys
+	if keys == nil || keys.CurrentKeyId == 0 {
+		return 0, nil, nil
+	}
+	key, ok := keys.Keys[keys.CurrentKeyId]
+	if !ok {
+		return 0, nil, errs.ErrEncryptionKeyNotFound.FastGenByArgs(keys.CurrentKeyId)
+	}
+	return keys.CurrentKeyId, key, nil
 }
 
 // GetKey get the encryption key with the specific key id.
 func (m *KeyManager) GetKey(keyID uint64) (key *encryptionpb.DataKey, err error) {
-	// TODO: Implement
-	return nil, nil
+	if m == nil {
+		return nil, errs.ErrEncryptionKeyNotFound.FastGenByArgs(keyID)
+	}
+	if key = m.getKey(keyID); key != nil {
+		return key, nil
+	}
+	// The key may be generated by the leader recently.
+	if err = m.reloadKeys(); err != nil {
+		return nil, err
+	}
+	if key = m.getKey(keyID); key != nil {
+		return key, nil
+	}
+	return nil, errs.ErrEncryptionKeyNotFound.FastGenByArgs(keyID)
+}
+
+func (m *KeyManager) getKey(keyID uint64) *encryptionpb.DataKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.mu.keys == nil {
+		return nil
+	}
+	return m.mu.keys.Keys[keyID]
 }
 
 // SetLeadership sets the PD leadership of the current node. PD leader is responsible to update
 // encryption keys, e.g. key rotation.
 func (m *KeyManager) SetLeadership(leadership *election.Leadership) {
-	// TODO: Implement
+	if m == nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.mu.Lock()
+	if m.mu.leaderCancel != nil {
+		m.mu.leaderCancel()
+	}
+	m.mu.leaderCancel = cancel
+	m.mu.Unlock()
+	go m.rotateLoop(ctx, leadership)
+}
+
+// rotateLoop rotates the data key periodically until the leadership is lost.
+func (m *KeyManager) rotateLoop(ctx context.Context, leadership *election.Leadership) {
+	ticker := time.NewTicker(keyRotationCheckPeriod)
+	defer ticker.Stop()
+	for {
+		if leadership == nil || !leadership.Check() {
+			return
+		}
+		if err := m.checkAndRotate(leadership); err != nil {
+			log.Warn("failed to rotate data key", errs.ZapError(err))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkAndRotate generates a new data key if encryption is enabled and the
+// current key is expired or of a different method, and clears the current
+// key if encryption is disabled. The old keys are kept to decrypt the
+// regions saved before.
+func (m *KeyManager) checkAndRotate(leadership *election.Leadership) error {
+	// The keys may be rotated by the previous leader.
+	if err := m.reloadKeys(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := &encryptionpb.KeyDictionary{Keys: make(map[uint64]*encryptionpb.DataKey)}
+	if m.mu.keys != nil {
+		keys = proto.Clone(m.mu.keys).(*encryptionpb.KeyDictionary)
+		if keys.Keys == nil {
+			keys.Keys = make(map[uint64]*encryptionpb.DataKey)
+		}
+	}
+	// Re-encrypt the keys if the master key is changed.
+	needSave := m.mu.keysMasterKeyMeta != nil && !proto.Equal(m.mu.keysMasterKeyMeta, m.masterKeyMeta)
+
+	current := keys.Keys[keys.CurrentKeyId]
+	if m.method == encryptionpb.EncryptionMethod_PLAINTEXT {
+		if keys.CurrentKeyId != 0 {
+			keys.CurrentKeyId = 0
+			needSave = true
+			log.Info("encryption is disabled, stop using the current data key")
+		}
+	} else if current == nil || current.Method != m.method ||
+		m.now().Sub(time.Unix(int64(current.CreationTime), 0)) >= m.dataKeyRotationPeriod {
+		keyID, key, err := m.newDataKey(keys)
+		if err != nil {
+			return err
+		}
+		keys.Keys[keyID] = key
+		keys.CurrentKeyId = keyID
+		needSave = true
+		log.Info("data key is rotated", zap.Uint64("key-id", keyID), zap.Stringer("method", m.method))
+	}
+	if !needSave {
+		return nil
+	}
+	if err := m.saveKeys(leadership, keys); err != nil {
+		return err
+	}
+	m.mu.keys = keys
+	m.mu.keysMasterKeyMeta = m.masterKeyMeta
+	return nil
+}
+
+// newDataKey generates a data key whose id is not used.
+func (m *KeyManager) newDataKey(keys *encryptionpb.KeyDictionary) (uint64, *encryptionpb.DataKey, error) {
+	for {
+		keyID, key, err := lib.NewDataKey(m.method)
+		if err != nil {
+			return 0, nil, err
+		}
+		if _, ok := keys.Keys[keyID]; ok || keyID == 0 {
+			continue
+		}
+		key.CreationTime = uint64(m.now().Unix())
+		return keyID, key, nil
+	}
+}
+
+// reloadKeys loads the data keys from etcd.
+func (m *KeyManager) reloadKeys() error {
+	value, err := m.kv.Load(EncryptionKeysPath)
+	if err != nil {
+		return errs.ErrEncryptionLoadKeys.Wrap(err).GenWithStackByCause()
+	}
+	if value == "" {
+		return nil
+	}
+	content := &encryptionpb.EncryptedContent{}
+	if err = proto.Unmarshal([]byte(value), content); err != nil {
+		return errs.ErrEncryptionLoadKeys.Wrap(err).GenWithStack("fail to unmarshal encrypted keys")
+	}
+	// The keys are decrypted by the master key they are encrypted with, so
+	// the master key can be changed by the config.
+	masterKey, err := lib.NewMasterKey(content.MasterKey)
+	if err != nil {
+		return err
+	}
+	plaintext, err := masterKey.Decrypt(content.Content, content.Iv)
+	if err != nil {
+		return err
+	}
+	keys := &encryptionpb.KeyDictionary{}
+	if err = proto.Unmarshal(plaintext, keys); err != nil {
+		return errs.ErrEncryptionLoadKeys.Wrap(err).GenWithStack("fail to unmarshal keys")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mu.keys = keys
+	m.mu.keysMasterKeyMeta = content.MasterKey
+	return nil
+}
+
+// saveKeys encrypts the data keys with the master key and saves them if the
+// leadership is still held.
+func (m *KeyManager) saveKeys(leadership *election.Leadership, keys *encryptionpb.KeyDictionary) error {
+	masterKey, err := lib.NewMasterKey(m.masterKeyMeta)
+	if err != nil {
+		return err
+	}
+	if masterKey.IsPlaintext() {
+		// The keys are saved without encryption, so they are exposed.
+		for _, key := range keys.Keys {
+			key.WasExposed = true
+		}
+	}
+	plaintext, err := proto.Marshal(keys)
+	if err != nil {
+		return errs.ErrEncryptionSaveDataKeys.Wrap(err).GenWithStack("fail to marshal keys")
+	}
+	ciphertext, iv, err := masterKey.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+	value, err := proto.Marshal(&encryptionpb.EncryptedContent{
+		Content:   ciphertext,
+		MasterKey: m.masterKeyMeta,
+		Iv:        iv,
+	})
+	if err != nil {
+		return errs.ErrEncryptionSaveDataKeys.Wrap(err).GenWithStack("fail to marshal encrypted keys")
+	}
+	resp, err := leadership.LeaderTxn().Then(clientv3.OpPut(m.keysPath, string(value))).Commit()
+	if err != nil {
+		return errs.ErrEncryptionSaveDataKeys.Wrap(err).GenWithStackByCause()
+	}
+	if !resp.Succeeded {
+		return errs.ErrEtcdTxn.FastGenByArgs()
+	}
+	return nil
 }
 
 // Close close the key manager on PD server shutdown
 func (m *KeyManager) Close() {
-	// TODO: Implement
+	if m == nil {
+		return
+	}
+	m.cancel()
 }
@@ -0,0 +1,241 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryptionkm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	lib "github.com/tikv/pd/pkg/encryption"
+	"github.com/tikv/pd/pkg/tempurl"
+	"github.com/tikv/pd/pkg/typeutil"
+	"github.com/tikv/pd/server/election"
+	"github.com/tikv/pd/server/kv"
+	"go.etcd.io/etcd/clientv3"
+	"go.etcd.io/etcd/embed"
+)
+
+func TestKeyManager(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testKeyManagerSuite{})
+
+type testKeyManagerSuite struct {
+	dir     string
+	etcdCfg *embed.Config
+	etcd    *embed.Etcd
+	client  *clientv3.Client
+	// clusterID separates the keys of the tests.
+	clusterID uint64
+}
+
+func (s *testKeyManagerSuite) SetUpSuite(c *C) {
+	cfg := embed.NewConfig()
+	cfg.Name = "test_etcd"
+	cfg.Dir, _ = ioutil.TempDir("/tmp", "test_etcd")
+	cfg.Logger = "zap"
+	cfg.LogOutputs = []string{"stdout"}
+	pu, _ := url.Parse(tempurl.Alloc())
+	cfg.LPUrls = []url.URL{*pu}
+	cfg.APUrls = cfg.LPUrls
+	cu, _ := url.Parse(tempurl.Alloc())
+	cfg.LCUrls = []url.URL{*cu}
+	cfg.ACUrls = cfg.LCUrls
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, &cfg.LPUrls[0])
+	cfg.ClusterState = embed.ClusterStateFlagNew
+	etcd, err := embed.StartEtcd(cfg)
+	c.Assert(err, IsNil)
+	<-etcd.Server.ReadyNotify()
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{cfg.LCUrls[0].String()}})
+	c.Assert(err, IsNil)
+	s.etcdCfg, s.etcd, s.client = cfg, etcd, client
+}
+
+func (s *testKeyManagerSuite) TearDownSuite(c *C) {
+	s.client.Close()
+	s.etcd.Close()
+	os.RemoveAll(s.etcdCfg.Dir)
+}
+
+func (s *testKeyManagerSuite) SetUpTest(c *C) {
+	dir, err := ioutil.TempDir("", "key_manager_test")
+	c.Assert(err, IsNil)
+	s.dir = dir
+	s.clusterID++
+}
+
+func (s *testKeyManagerSuite) rootPath() string {
+	return path.Join("/pd", fmt.Sprint(s.clusterID))
+}
+
+// campaign gets the leadership of the test cluster.
+func (s *testKeyManagerSuite) campaign(c *C, name string) *election.Leadership {
+	leadership := election.NewLeadership(s.client, path.Join(s.rootPath(), "leader"), name)
+	c.Assert(leadership.Campaign(3, name), IsNil)
+	return leadership
+}
+
+func (s *testKeyManagerSuite) TearDownTest(c *C) {
+	os.RemoveAll(s.dir)
+}
+
+func (s *testKeyManagerSuite) newConfig(c *C, method string) *lib.Config {
+	path := filepath.Join(s.dir, "master_key")
+	err := ioutil.WriteFile(path, []byte("2f07ec61e5a50284f47f2b402a962ec672e500b26cb3aa568bb1531300c74806"), 0600)
+	c.Assert(err, IsNil)
+	config := &lib.Config{
+		DataEncryptionMethod:  method,
+		DataKeyRotationPeriod: typeutil.NewDuration(time.Hour),
+		MasterKey: lib.MasterKeyConfig{
+			Type:                "file",
+			MasterKeyFileConfig: lib.MasterKeyFileConfig{FilePath: path},
+		},
+	}
+	c.Assert(config.Adjust(), IsNil)
+	return config
+}
+
+func (s *testKeyManagerSuite) TestEncryptionDisabled(c *C) {
+	config := &lib.Config{}
+	c.Assert(config.Adjust(), IsNil)
+	m, err := NewKeyManager(s.client, s.rootPath(), config)
+	c.Assert(err, IsNil)
+	c.Assert(m.checkAndRotate(s.campaign(c, "pd1")), IsNil)
+	keyID, key, err := m.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(keyID, Equals, uint64(0))
+	c.Assert(key, IsNil)
+
+	// nil key manager is treated as encryption disabled.
+	var nilManager *KeyManager
+	_, key, err = nilManager.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(key, IsNil)
+}
+
+func (s *testKeyManagerSuite) TestRotateAndReload(c *C) {
+	leadership := s.campaign(c, "pd1")
+	leader, err := NewKeyManager(s.client, s.rootPath(), s.newConfig(c, "aes128-ctr"))
+	c.Assert(err, IsNil)
+	follower, err := NewKeyManager(s.client, s.rootPath(), s.newConfig(c, "aes128-ctr"))
+	c.Assert(err, IsNil)
+
+	c.Assert(leader.checkAndRotate(leadership), IsNil)
+	keyID, key, err := leader.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(key, NotNil)
+	c.Assert(key.WasExposed, IsFalse)
+	// The keys are not saved in plaintext.
+	value, err := kv.NewEtcdKVBase(s.client, s.rootPath()).Load(EncryptionKeysPath)
+	c.Assert(err, IsNil)
+	c.Assert(bytes.Contains([]byte(value), key.Key), IsFalse)
+
+	// The region encrypted by the leader can be decrypted by the follower.
+	region := &metapb.Region{Id: 1, StartKey: []byte("abc"), EndKey: []byte("xyz")}
+	encrypted, err := lib.EncryptRegion(region, leader)
+	c.Assert(err, IsNil)
+	c.Assert(encrypted.EncryptionMeta.KeyId, Equals, keyID)
+	c.Assert(encrypted.StartKey, Not(DeepEquals), region.StartKey)
+	c.Assert(lib.DecryptRegion(encrypted, follower), IsNil)
+	c.Assert(encrypted.StartKey, DeepEquals, region.StartKey)
+	c.Assert(encrypted.EndKey, DeepEquals, region.EndKey)
+
+	// The key is not rotated before it expires.
+	c.Assert(leader.checkAndRotate(leadership), IsNil)
+	newKeyID, _, err := leader.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(newKeyID, Equals, keyID)
+
+	// The key is rotated after it expires, and the old key is kept.
+	leader.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	c.Assert(leader.checkAndRotate(leadership), IsNil)
+	newKeyID, _, err = leader.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(newKeyID, Not(Equals), keyID)
+	_, err = follower.GetKey(keyID)
+	c.Assert(err, IsNil)
+	_, err = follower.GetKey(newKeyID)
+	c.Assert(err, IsNil)
+
+	// Disabling encryption clears the current key only.
+	disabled, err := NewKeyManager(s.client, s.rootPath(), s.newConfig(c, "plaintext"))
+	c.Assert(err, IsNil)
+	c.Assert(disabled.checkAndRotate(leadership), IsNil)
+	_, key, err = disabled.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(key, IsNil)
+	_, err = disabled.GetKey(newKeyID)
+	c.Assert(err, IsNil)
+}
+
+func (s *testKeyManagerSuite) TestLostLeadership(c *C) {
+	oldLeadership := s.campaign(c, "pd1")
+	oldLeader, err := NewKeyManager(s.client, s.rootPath(), s.newConfig(c, "aes128-ctr"))
+	c.Assert(err, IsNil)
+	c.Assert(oldLeader.checkAndRotate(oldLeadership), IsNil)
+
+	// Another PD becomes the leader and rotates the key.
+	c.Assert(oldLeadership.DeleteLeader(), IsNil)
+	leadership := s.campaign(c, "pd2")
+	leader, err := NewKeyManager(s.client, s.rootPath(), s.newConfig(c, "aes128-ctr"))
+	c.Assert(err, IsNil)
+	leader.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	c.Assert(leader.checkAndRotate(leadership), IsNil)
+	keyID, _, err := leader.GetCurrentKey()
+	c.Assert(err, IsNil)
+
+	// The old leader can not overwrite the keys of the new leader.
+	oldLeader.now = func() time.Time { return time.Now().Add(4 * time.Hour) }
+	c.Assert(oldLeader.checkAndRotate(oldLeadership), NotNil)
+	c.Assert(leader.reloadKeys(), IsNil)
+	newKeyID, _, err := leader.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(newKeyID, Equals, keyID)
+}
+
+func (s *testKeyManagerSuite) TestPlaintextMasterKey(c *C) {
+	config := &lib.Config{DataEncryptionMethod: "aes256-ctr"}
+	c.Assert(config.Adjust(), IsNil)
+	leadership := s.campaign(c, "pd1")
+	m, err := NewKeyManager(s.client, s.rootPath(), config)
+	c.Assert(err, IsNil)
+	c.Assert(m.checkAndRotate(leadership), IsNil)
+	keyID, key, err := m.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(key, NotNil)
+	// The key saved without encryption is marked exposed, but not rotated
+	// again and again.
+	c.Assert(key.WasExposed, IsTrue)
+	c.Assert(m.checkAndRotate(leadership), IsNil)
+	newKeyID, _, err := m.GetCurrentKey()
+	c.Assert(err, IsNil)
+	c.Assert(newKeyID, Equals, keyID)
+}
+
+func (s *testKeyManagerSuite) TestKMSNotSupported(c *C) {
+	config := &lib.Config{MasterKey: lib.MasterKeyConfig{Type: "kms"}}
+	c.Assert(config.Adjust(), IsNil)
+	_, err := NewKeyManager(s.client, s.rootPath(), config)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,129 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+// SafePointManager is the manager of the cluster GC safe point. The GC
+// worker of TiDB coordinates through it, and the safe point never goes back.
+type SafePointManager struct {
+	sync.Mutex
+	storage *core.Storage
+}
+
+// NewSafePointManager creates a SafePointManager.
+func NewSafePointManager(storage *core.Storage) *SafePointManager {
+	return &SafePointManager{storage: storage}
+}
+
+// LoadGCSafePoint loads the current GC safe point.
+func (m *SafePointManager) LoadGCSafePoint() (uint64, error) {
+	return m.storage.LoadGCSafePoint()
+}
+
+// UpdateGCSafePoint updates the GC safe point if the new one is greater than
+// the old one. The new one is clamped to the minimum safe point of the
+// unexpired services, so the GC never removes the data still needed by any
+// service. It returns the old safe point and the safe point after the update.
+func (m *SafePointManager) UpdateGCSafePoint(newSafePoint uint64, now time.Time) (oldSafePoint, safePoint uint64, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	oldSafePoint, err = m.storage.LoadGCSafePoint()
+	if err != nil {
+		return
+	}
+	min, err := m.storage.LoadMinServiceGCSafePoint(now)
+	if err != nil {
+		return
+	}
+	if min.ServiceID != "" && newSafePoint > min.SafePoint {
+		log.Info("gc safe point is limited by the service safe point",
+			zap.Uint64("new-safe-point", newSafePoint),
+			zap.String("service-id", min.ServiceID),
+			zap.Uint64("service-safe-point", min.SafePoint))
+		newSafePoint = min.SafePoint
+	}
+	safePoint = oldSafePoint
+	if newSafePoint > oldSafePoint {
+		if err = m.storage.SaveGCSafePoint(newSafePoint); err != nil {
+			return
+		}
+		safePoint = newSafePoint
+		gcSafePointGauge.Set(float64(newSafePoint))
+		log.Info("updated gc safe point",
+			zap.Uint64("safe-point", newSafePoint))
+	} else if newSafePoint < oldSafePoint {
+		log.Warn("trying to update gc safe point",
+			zap.Uint64("old-safe-point", oldSafePoint),
+			zap.Uint64("new-safe-point", newSafePoint))
+	}
+	return
+}
+
+// UpdateServiceGCSafePoint updates the safe point of the service, which
+// expires after ttl seconds. A service with a non-positive ttl is removed.
+// The safe point is rejected if it is less than the minimum safe point of
+// the unexpired services. It returns the minimum service safe point after
+// the update.
+func (m *SafePointManager) UpdateServiceGCSafePoint(serviceID string, newSafePoint uint64, ttl int64, now time.Time) (*core.ServiceSafePoint, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if ttl <= 0 {
+		if err := m.storage.RemoveServiceGCSafePoint(serviceID); err != nil {
+			return nil, err
+		}
+	}
+
+	min, err := m.storage.LoadMinServiceGCSafePoint(now)
+	if err != nil {
+		return nil, err
+	}
+	if ttl <= 0 || newSafePoint < min.SafePoint {
+		return min, nil
+	}
+
+	ssp := &core.ServiceSafePoint{
+		ServiceID: serviceID,
+		ExpiredAt: now.Unix() + ttl,
+		SafePoint: newSafePoint,
+	}
+	if err := m.storage.SaveServiceGCSafePoint(ssp); err != nil {
+		return nil, err
+	}
+	log.Info("update service GC safe point",
+		zap.String("service-id", ssp.ServiceID),
+		zap.Int64("expire-at", ssp.ExpiredAt),
+		zap.Uint64("safepoint", ssp.SafePoint))
+	// If the min safepoint is updated, load the next one.
+	if serviceID == min.ServiceID {
+		min, err = m.storage.LoadMinServiceGCSafePoint(now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// If ssp is the first safepoint, it is the min value now.
+	if min.SafePoint == 0 {
+		min = ssp
+	}
+	return min, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/kv"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testSafePointSuite{})
+
+type testSafePointSuite struct{}
+
+func (s *testSafePointSuite) TestUpdateGCSafePoint(c *C) {
+	m := NewSafePointManager(core.NewStorage(kv.NewMemoryKV()))
+	safePoint, err := m.LoadGCSafePoint()
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(0))
+
+	now := time.Now()
+	old, safePoint, err := m.UpdateGCSafePoint(100, now)
+	c.Assert(err, IsNil)
+	c.Assert(old, Equals, uint64(0))
+	c.Assert(safePoint, Equals, uint64(100))
+	old, safePoint, err = m.UpdateGCSafePoint(50, now)
+	c.Assert(err, IsNil)
+	c.Assert(old, Equals, uint64(100))
+	c.Assert(safePoint, Equals, uint64(100))
+	safePoint, err = m.LoadGCSafePoint()
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(100))
+}
+
+func (s *testSafePointSuite) TestUpdateGCSafePointWithServices(c *C) {
+	m := NewSafePointManager(core.NewStorage(kv.NewMemoryKV()))
+	now := time.Now()
+	_, err := m.UpdateServiceGCSafePoint("br", 100, 100, now)
+	c.Assert(err, IsNil)
+	_, err = m.UpdateServiceGCSafePoint("cdc", 200, 1000, now)
+	c.Assert(err, IsNil)
+
+	// The safe point is clamped to the minimum service safe point.
+	_, safePoint, err := m.UpdateGCSafePoint(150, now)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(100))
+	safePoint, err = m.LoadGCSafePoint()
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(100))
+
+	// The expired services do not limit it.
+	_, safePoint, err = m.UpdateGCSafePoint(300, now.Add(200*time.Second))
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(200))
+
+	// Nor do the removed ones.
+	_, err = m.UpdateServiceGCSafePoint("cdc", 0, 0, now)
+	c.Assert(err, IsNil)
+	_, safePoint, err = m.UpdateGCSafePoint(300, now)
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(300))
+}
+
+func (s *testSafePointSuite) TestConcurrentUpdate(c *C) {
+	m := NewSafePointManager(core.NewStorage(kv.NewMemoryKV()))
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func(safePoint uint64) {
+			defer wg.Done()
+			_, _, err := m.UpdateGCSafePoint(safePoint, time.Now())
+			c.Assert(err, IsNil)
+		}(uint64(i))
+	}
+	wg.Wait()
+	safePoint, err := m.LoadGCSafePoint()
+	c.Assert(err, IsNil)
+	c.Assert(safePoint, Equals, uint64(100))
+}
+
+func (s *testSafePointSuite) TestUpdateServiceGCSafePoint(c *C) {
+	m := NewSafePointManager(core.NewStorage(kv.NewMemoryKV()))
+	now := time.Now()
+	min, err := m.UpdateServiceGCSafePoint("br", 10, 100, now)
+	c.Assert(err, IsNil)
+	c.Assert(min.ServiceID, Equals, "br")
+	c.Assert(min.SafePoint, Equals, uint64(10))
+	min, err = m.UpdateServiceGCSafePoint("cdc", 20, 1000, now)
+	c.Assert(err, IsNil)
+	c.Assert(min.ServiceID, Equals, "br")
+
+	// The safe point less than the minimum one is rejected.
+	min, err = m.UpdateServiceGCSafePoint("analyze", 5, 1000, now)
+	c.Assert(err, IsNil)
+	c.Assert(min.SafePoint, Equals, uint64(10))
+
+	// The minimum one is the min of the unexpired services.
+	min, err = m.UpdateServiceGCSafePoint("cdc", 20, 1000, now.Add(200*time.Second))
+	c.Assert(err, IsNil)
+	c.Assert(min.ServiceID, Equals, "cdc")
+	c.Assert(min.SafePoint, Equals, uint64(20))
+
+	// Remove the service with a non-positive TTL.
+	min, err = m.UpdateServiceGCSafePoint("cdc", 20, 0, now)
+	c.Assert(err, IsNil)
+	c.Assert(min.ServiceID, Equals, "")
+	c.Assert(min.SafePoint, Equals, uint64(0))
+	min, err = m.UpdateServiceGCSafePoint("analyze", 5, 1000, now)
+	c.Assert(err, IsNil)
+	c.Assert(min.ServiceID, Equals, "analyze")
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/log"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const (
+	// healthServiceName is the name of the standard gRPC health service.
+	healthServiceName = "grpc.health.v1.Health"
+	// leaderHealthService is the service name checked by the health clients
+	// which want to know if the PD server is the leader ready to serve, the
+	// empty service name reports if the PD server is running.
+	leaderHealthService = "pdpb.PD"
+)
+
+func newHealthServer() *health.Server {
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus(leaderHealthService, healthpb.HealthCheckResponse_NOT_SERVING)
+	return hs
+}
+
+// registerIntrospectionServices registers the PD health server as the
+// standard gRPC health service and the server reflection to the gRPC server,
+// so that the standard tools, such as grpcurl and the gRPC health probes, can
+// work with PD.
+func (s *Server) registerIntrospectionServices(gs *grpc.Server) {
+	reflection.Register(gs)
+	if err := replaceHealthServer(gs, s.healthServer); err != nil {
+		log.Error("failed to register the health service", zap.Error(err))
+	}
+}
+
+// replaceHealthServer makes the standard gRPC health service of gs served by
+// hs. The embedded etcd registers its own health server, which always reports
+// SERVING, before PD registers its services and gRPC doesn't allow to register
+// a service twice, so the server of the registered service is replaced.
+func replaceHealthServer(gs *grpc.Server, hs healthpb.HealthServer) error {
+	if _, ok := gs.GetServiceInfo()[healthServiceName]; !ok {
+		healthpb.RegisterHealthServer(gs, hs)
+		return nil
+	}
+	services := reflect.ValueOf(gs).Elem().FieldByName("m")
+	if services.Kind() != reflect.Map {
+		return errors.New("services of the gRPC server not found")
+	}
+	service := services.MapIndex(reflect.ValueOf(healthServiceName))
+	if service.Kind() != reflect.Ptr || service.IsNil() {
+		return errors.Errorf("service %s not found", healthServiceName)
+	}
+	server := service.Elem().FieldByName("server")
+	if server.Kind() != reflect.Interface {
+		return errors.Errorf("server of service %s not found", healthServiceName)
+	}
+	reflect.NewAt(server.Type(), unsafe.Pointer(server.UnsafeAddr())).Elem().Set(reflect.ValueOf(hs))
+	return nil
+}
+
+// setServingStatus updates the status reported by the PD health service.
+func (s *Server) setServingStatus(service string, serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.healthServer.SetServingStatus(service, status)
+}
@@ -731,7 +731,7 @@ func (s *Server) GetGCSafePoint(ctx context.Context, request *pdpb.GetGCSafePoin
 		return &pdpb.GetGCSafePointResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	safePoint, err := s.storage.LoadGCSafePoint()
+	safePoint, err := s.gcSafePointManager.LoadGCSafePoint()
 	if err != nil {
 		return nil, err
 	}
@@ -761,25 +761,15 @@ func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSa
 		return &pdpb.UpdateGCSafePointResponse{Header: s.notBootstrappedHeader()}, nil
 	}
 
-	oldSafePoint, err := s.storage.LoadGCSafePoint()
+	nowTSO, err := s.tsoAllocatorManager.HandleTSORequest(config.GlobalDCLocation, 1)
 	if err != nil {
 		return nil, err
 	}
-
-	newSafePoint := request.SafePoint
-
-	// Only save the safe point if it's greater than the previous one
-	if newSafePoint > oldSafePoint {
-		if err := s.storage.SaveGCSafePoint(newSafePoint); err != nil {
-			return nil, err
-		}
-		log.Info("updated gc safe point",
-			zap.Uint64("safe-point", newSafePoint))
-	} else if newSafePoint < oldSafePoint {
-		log.Warn("trying to update gc safe point",
-			zap.Uint64("old-safe-point", oldSafePoint),
-			zap.Uint64("new-safe-point", newSafePoint))
-		newSafePoint = oldSafePoint
+	now, _ := tsoutil.ParseTimestamp(nowTSO)
+	// The safe point never goes back, nor goes beyond any service safe point.
+	_, newSafePoint, err := s.gcSafePointManager.UpdateGCSafePoint(request.SafePoint, now)
+	if err != nil {
+		return nil, err
 	}
 
 	return &pdpb.UpdateGCSafePointResponse{
@@ -790,9 +780,6 @@ func (s *Server) UpdateGCSafePoint(ctx context.Context, request *pdpb.UpdateGCSa
 
 // UpdateServiceGCSafePoint update the safepoint for specific service
 func (s *Server) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb.UpdateServiceGCSafePointRequest) (*pdpb.UpdateServiceGCSafePointResponse, error) {
-	s.serviceSafePointLock.Lock()
-	defer s.serviceSafePointLock.Unlock()
-
 	if err := s.validateRequest(request.GetHeader()); err != nil {
 		return nil, err
 	}
@@ -801,48 +788,17 @@ func (s *Server) UpdateServiceGCSafePoint(ctx context.Context, request *pdpb.Upd
 	if rc == nil {
 		return &pdpb.UpdateServiceGCSafePointResponse{Header: s.notBootstrappedHeader()}, nil
 	}
-	if request.TTL <= 0 {
-		if err := s.storage.RemoveServiceGCSafePoint(string(request.ServiceId)); err != nil {
-			return nil, err
-		}
-	}
 
 	nowTSO, err := s.tsoAllocatorManager.HandleTSORequest(config.GlobalDCLocation, 1)
 	if err != nil {
 		return nil, err
 	}
 	now, _ := tsoutil.ParseTimestamp(nowTSO)
-	min, err := s.storage.LoadMinServiceGCSafePoint(now)
+	min, err := s.gcSafePointManager.UpdateServiceGCSafePoint(string(request.ServiceId), request.SafePoint, request.TTL, now)
 	if err != nil {
 		return nil, err
 	}
 
-	if request.TTL > 0 && request.SafePoint >= min.SafePoint {
-		ssp := &core.ServiceSafePoint{
-			ServiceID: string(request.ServiceId),
-			ExpiredAt: now.Unix() + request.TTL,
-			SafePoint: request.SafePoint,
-		}
-		if err := s.storage.SaveServiceGCSafePoint(ssp); err != nil {
-			return nil, err
-		}
-		log.Info("update service GC safe point",
-			zap.String("service-id", string(ssp.ServiceID)),
-			zap.Int64("expire-at", ssp.ExpiredAt),
-			zap.Uint64("safepoint", ssp.SafePoint))
-		// If the min safepoint is updated, load the next one
-		if string(request.ServiceId) == min.ServiceID {
-			min, err = s.storage.LoadMinServiceGCSafePoint(now)
-			if err != nil {
-				return nil, err
-			}
-		}
-		// If ssp is the first safepoint, it is the min value now
-		if min.SafePoint == 0 {
-			min = ssp
-		}
-	}
-
 	return &pdpb.UpdateServiceGCSafePointResponse{
 		Header:       s.header(),
 		ServiceId:    []byte(min.ServiceID),
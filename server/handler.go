@@ -15,6 +15,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
 	"net/http"
@@ -161,6 +162,15 @@ func (h *Handler) GetHotReadRegions() *statistics.StoreHotPeersInfos {
 	return c.GetHotReadRegions()
 }
 
+// GetHistoryHotRegions gets the history of hot regions in [start, end).
+func (h *Handler) GetHistoryHotRegions(start, end time.Time) ([]*core.HistoryHotRegion, error) {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetHistoryHotRegions(start, end)
+}
+
 // GetHotBytesWriteStores gets all hot write stores stats.
 func (h *Handler) GetHotBytesWriteStores() map[uint64]float64 {
 	rc := h.s.GetRaftCluster()
@@ -247,6 +257,39 @@ func (h *Handler) PauseOrResumeScheduler(name string, t int64) error {
 	return err
 }
 
+// SetSchedulerNamespace binds a scheduler to a namespace.
+func (h *Handler) SetSchedulerNamespace(name, namespace string) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.SetSchedulerNamespace(name, namespace); err != nil {
+		log.Error("can not set scheduler namespace", zap.String("scheduler-name", name), zap.String("namespace", namespace), errs.ZapError(err))
+	}
+	return err
+}
+
+// SetSchedulerStoreList limits the stores used by a scheduler.
+func (h *Handler) SetSchedulerStoreList(name string, list *schedule.StoreList) error {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return err
+	}
+	if err = c.SetSchedulerStoreList(name, list); err != nil {
+		log.Error("can not set scheduler store list", zap.String("scheduler-name", name), errs.ZapError(err))
+	}
+	return err
+}
+
+// GetSchedulerStoreList returns the store list which a scheduler is limited to.
+func (h *Handler) GetSchedulerStoreList(name string) (*schedule.StoreList, error) {
+	c, err := h.GetRaftCluster()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetSchedulerStoreList(name)
+}
+
 // AddBalanceLeaderScheduler adds a balance-leader-scheduler.
 func (h *Handler) AddBalanceLeaderScheduler() error {
 	return h.AddScheduler(schedulers.BalanceLeaderType)
@@ -302,6 +345,11 @@ func (h *Handler) AddRandomMergeScheduler() error {
 	return h.AddScheduler(schedulers.RandomMergeType)
 }
 
+// AddLoadSplitScheduler adds a load-split-scheduler.
+func (h *Handler) AddLoadSplitScheduler() error {
+	return h.AddScheduler(schedulers.LoadSplitType)
+}
+
 // GetOperator returns the region operator.
 func (h *Handler) GetOperator(regionID uint64) (*operator.Operator, error) {
 	c, err := h.GetOperatorController()
@@ -317,6 +365,19 @@ func (h *Handler) GetOperator(regionID uint64) (*operator.Operator, error) {
 	return op, nil
 }
 
+// GetOperatorDetail returns the detail of the running operator of the region.
+func (h *Handler) GetOperatorDetail(regionID uint64) (*operator.Detail, error) {
+	rc, err := h.GetRaftCluster()
+	if err != nil {
+		return nil, err
+	}
+	op := rc.GetOperatorController().GetOperator(regionID)
+	if op == nil {
+		return nil, ErrOperatorNotFound
+	}
+	return op.GetDetail(rc.GetRegion(regionID)), nil
+}
+
 // GetOperatorStatus returns the status of the region operator.
 func (h *Handler) GetOperatorStatus(regionID uint64) (*schedule.OperatorWithStatus, error) {
 	c, err := h.GetOperatorController()
@@ -332,6 +393,42 @@ func (h *Handler) GetOperatorStatus(regionID uint64) (*schedule.OperatorWithStat
 	return op, nil
 }
 
+// waitOperatorInterval is the interval to check the status of the operator
+// in WaitOperator.
+const waitOperatorInterval = 100 * time.Millisecond
+
+// WaitOperator waits until the running operator of the region ends or the
+// timeout, and returns the operator and its status. If there is no running
+// operator, it returns the last finished one.
+func (h *Handler) WaitOperator(ctx context.Context, regionID uint64, timeout time.Duration) (*schedule.OperatorWithStatus, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return nil, err
+	}
+
+	op := c.GetOperator(regionID)
+	if op == nil {
+		status := c.GetOperatorStatus(regionID)
+		if status == nil {
+			return nil, ErrOperatorNotFound
+		}
+		return status, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(waitOperatorInterval)
+	defer ticker.Stop()
+	for !op.IsEnd() {
+		select {
+		case <-ctx.Done():
+			return schedule.NewOperatorWithStatus(op), nil
+		case <-ticker.C:
+		}
+	}
+	return schedule.NewOperatorWithStatus(op), nil
+}
+
 // RemoveOperator removes the region operator.
 func (h *Handler) RemoveOperator(regionID uint64) error {
 	c, err := h.GetOperatorController()
@@ -348,6 +445,27 @@ func (h *Handler) RemoveOperator(regionID uint64) error {
 	return nil
 }
 
+// IsOperatorIdempotencyKeyUsed returns true if the operators with the
+// idempotency key have been created recently.
+func (h *Handler) IsOperatorIdempotencyKeyUsed(key string) (bool, error) {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return false, err
+	}
+	return c.IsIdempotencyKeyUsed(key), nil
+}
+
+// SetOperatorIdempotencyKey marks the operators of the regions with the
+// idempotency key.
+func (h *Handler) SetOperatorIdempotencyKey(key string, regionIDs ...uint64) error {
+	c, err := h.GetOperatorController()
+	if err != nil {
+		return err
+	}
+	c.SetIdempotencyKey(key, regionIDs...)
+	return nil
+}
+
 // GetOperators returns the running operators.
 func (h *Handler) GetOperators() ([]*operator.Operator, error) {
 	c, err := h.GetOperatorController()
@@ -452,6 +570,18 @@ func (h *Handler) SetStoreLimit(storeID uint64, ratePerMin float64, limitType st
 	return nil
 }
 
+// addAdminOperator validates the operator created by the admin against the
+// region and adds it.
+func (h *Handler) addAdminOperator(c *cluster.RaftCluster, region *core.RegionInfo, op *operator.Operator) error {
+	if err := operator.ValidatePlan(c, region, op); err != nil {
+		return err
+	}
+	if ok := c.GetOperatorController().AddOperator(op); !ok {
+		return errors.WithStack(ErrAddOperator)
+	}
+	return nil
+}
+
 // AddTransferLeaderOperator adds an operator to transfer leader to the store.
 func (h *Handler) AddTransferLeaderOperator(regionID uint64, storeID uint64) error {
 	c, err := h.GetRaftCluster()
@@ -474,10 +604,7 @@ func (h *Handler) AddTransferLeaderOperator(regionID uint64, storeID uint64) err
 		log.Debug("fail to create transfer leader operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // AddTransferRegionOperator adds an operator to transfer region to the stores.
@@ -522,10 +649,7 @@ func (h *Handler) AddTransferRegionOperator(regionID uint64, storeIDs map[uint64
 		log.Debug("fail to create move region operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // AddTransferPeerOperator adds an operator to transfer peer.
@@ -559,10 +683,7 @@ func (h *Handler) AddTransferPeerOperator(regionID uint64, fromStoreID, toStoreI
 		log.Debug("fail to create move peer operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // checkAdminAddPeerOperator checks adminAddPeer operator with given region ID and store ID.
@@ -605,10 +726,7 @@ func (h *Handler) AddAddPeerOperator(regionID uint64, toStoreID uint64) error {
 		log.Debug("fail to create add peer operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // AddAddLearnerOperator adds an operator to add learner.
@@ -628,10 +746,7 @@ func (h *Handler) AddAddLearnerOperator(regionID uint64, toStoreID uint64) error
 		log.Debug("fail to create add learner operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // AddRemovePeerOperator adds an operator to remove peer.
@@ -655,10 +770,7 @@ func (h *Handler) AddRemovePeerOperator(regionID uint64, fromStoreID uint64) err
 		log.Debug("fail to create move peer operator", errs.ZapError(err))
 		return err
 	}
-	if ok := c.GetOperatorController().AddOperator(op); !ok {
-		return errors.WithStack(ErrAddOperator)
-	}
-	return nil
+	return h.addAdminOperator(c, region, op)
 }
 
 // AddMergeRegionOperator adds an operator to merge region.
@@ -768,11 +880,12 @@ func (h *Handler) AddScatterRegionOperator(regionID uint64, group string) error
 	return nil
 }
 
-// AddScatterRegionsOperators add operators to scatter regions and return the processed percentage and error
-func (h *Handler) AddScatterRegionsOperators(regionIDs []uint64, startRawKey, endRawKey, group string, retryLimit int) (int, error) {
+// AddScatterRegionsOperators add operators to scatter regions and return the processed percentage,
+// the regions whose operators are added and error
+func (h *Handler) AddScatterRegionsOperators(regionIDs []uint64, startRawKey, endRawKey, group string, retryLimit int) (int, []uint64, error) {
 	c, err := h.GetRaftCluster()
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	var failureRegionID []string
 	var regions []*core.RegionInfo
@@ -780,11 +893,11 @@ func (h *Handler) AddScatterRegionsOperators(regionIDs []uint64, startRawKey, en
 	if len(startRawKey) > 0 && len(endRawKey) > 0 {
 		startKey, err := hex.DecodeString(startRawKey)
 		if err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 		endKey, err := hex.DecodeString(endRawKey)
 		if err != nil {
-			return 0, err
+			return 0, nil, err
 		}
 		regions = c.ScanRegions(startKey, endKey, -1)
 	} else {
@@ -809,12 +922,15 @@ func (h *Handler) AddScatterRegionsOperators(regionIDs []uint64, startRawKey, en
 		failureRegionID = append(failureRegionID, fmt.Sprintf("%v", regionID))
 	}
 	// If there existed any operator failed to be added into Operator Controller, add its regions into unProcessedRegions
+	var scattered []uint64
 	for _, op := range ops {
 		if ok := c.GetOperatorController().AddOperator(op); !ok {
 			failureRegionID = append(failureRegionID, fmt.Sprintf("%v", op.RegionID()))
+			continue
 		}
+		scattered = append(scattered, op.RegionID())
 	}
-	return 100 - (len(failureRegionID) * 100 / len(regions)), errors.New("unprocessed regions:[" + strings.Join(failureRegionID, ",") + "]")
+	return 100 - (len(failureRegionID) * 100 / len(regions)), scattered, errors.New("unprocessed regions:[" + strings.Join(failureRegionID, ",") + "]")
 }
 
 // GetRegionsByType gets the region with specified type.
@@ -0,0 +1,293 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heatmap
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/server/core"
+)
+
+// Tag is the kind of flow shown in the heatmap.
+type Tag int
+
+// Tags of the heatmap.
+const (
+	WrittenBytes Tag = iota
+	ReadBytes
+	WrittenKeys
+	ReadKeys
+	tagLen
+)
+
+var tagNames = [tagLen]string{"written_bytes", "read_bytes", "written_keys", "read_keys"}
+
+func (t Tag) String() string {
+	if t < 0 || t >= tagLen {
+		return "unknown"
+	}
+	return tagNames[t]
+}
+
+// ParseTag parses the tag from the name.
+func ParseTag(name string) (Tag, error) {
+	for i, n := range tagNames {
+		if n == name {
+			return Tag(i), nil
+		}
+	}
+	return 0, errors.Errorf("unknown heatmap tag %s", name)
+}
+
+const (
+	// DefaultRecordInterval is the interval to record the flow of regions.
+	DefaultRecordInterval = time.Minute
+	// DefaultKeyBuckets is the default number of rows of the heatmap.
+	DefaultKeyBuckets = 256
+)
+
+// span is the flow of a continuous key range.
+type span struct {
+	startKey, endKey []byte
+	values           [tagLen]uint64
+}
+
+type snapshot struct {
+	time  time.Time
+	spans []span
+}
+
+// Matrix is the heatmap of the key space over time. Data[i][j] is the flow
+// of key range [Keys[j], Keys[j+1]) in time range (Times[i-1], Times[i]].
+type Matrix struct {
+	Keys  []string   `json:"keys"`
+	Times []int64    `json:"times"`
+	Data  [][]uint64 `json:"data"`
+}
+
+// Query is the range and resolution of the requested heatmap.
+type Query struct {
+	Tag                Tag
+	StartKey, EndKey   []byte
+	StartTime, EndTime time.Time
+	KeyBuckets         int
+	TimeBuckets        int
+}
+
+// Recorder aggregates the flow of regions over time.
+type Recorder struct {
+	sync.RWMutex
+	snapshots    []*snapshot
+	maxSnapshots int
+	// maxSpans limits the memory used by a snapshot. Adjacent regions are
+	// merged into one span if there are too many regions.
+	maxSpans int
+	// keys are the boundary keys of the latest snapshot, which are reused by
+	// the next snapshot instead of copying the same keys again.
+	keys map[string][]byte
+}
+
+// NewRecorder creates a recorder which keeps at most maxSnapshots snapshots of
+// at most maxSpans spans.
+func NewRecorder(maxSnapshots, maxSpans int) *Recorder {
+	return &Recorder{maxSnapshots: maxSnapshots, maxSpans: maxSpans}
+}
+
+// SetLimits changes the max number of snapshots and the max number of spans in
+// a snapshot. The oldest snapshots beyond the new limit are dropped.
+func (r *Recorder) SetLimits(maxSnapshots, maxSpans int) {
+	r.Lock()
+	defer r.Unlock()
+	r.maxSnapshots, r.maxSpans = maxSnapshots, maxSpans
+	r.trim()
+}
+
+// Reset drops all the snapshots.
+func (r *Recorder) Reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.snapshots, r.keys = nil, nil
+}
+
+// Record records the flow of regions, which should be sorted by start key.
+// The boundary keys are copied, so the recorder does not keep the keys of the
+// cached regions alive.
+func (r *Recorder) Record(now time.Time, regions []*core.RegionInfo) {
+	if len(regions) == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if r.maxSnapshots <= 0 || r.maxSpans <= 0 {
+		return
+	}
+	keys := make(map[string][]byte, len(r.keys))
+	intern := func(key []byte) []byte {
+		if k, ok := keys[string(key)]; ok {
+			return k
+		}
+		k, ok := r.keys[string(key)]
+		if !ok {
+			k = append([]byte(nil), key...)
+		}
+		keys[string(k)] = k
+		return k
+	}
+	// Merge adjacent regions to bound the memory usage.
+	step := (len(regions) + r.maxSpans - 1) / r.maxSpans
+	spans := make([]span, 0, (len(regions)+step-1)/step)
+	for i := 0; i < len(regions); i += step {
+		group := regions[i:minInt(i+step, len(regions))]
+		s := span{
+			startKey: intern(group[0].GetStartKey()),
+			endKey:   intern(group[len(group)-1].GetEndKey()),
+		}
+		for _, region := range group {
+			s.values[WrittenBytes] += region.GetBytesWritten()
+			s.values[ReadBytes] += region.GetBytesRead()
+			s.values[WrittenKeys] += region.GetKeysWritten()
+			s.values[ReadKeys] += region.GetKeysRead()
+		}
+		spans = append(spans, s)
+	}
+	r.keys = keys
+	r.snapshots = append(r.snapshots, &snapshot{time: now, spans: spans})
+	r.trim()
+}
+
+func (r *Recorder) trim() {
+	if n := len(r.snapshots) - r.maxSnapshots; n > 0 {
+		// Copy the kept snapshots, so the dropped ones are not referenced by
+		// the backing array any more.
+		r.snapshots = append([]*snapshot(nil), r.snapshots[n:]...)
+	}
+}
+
+// Heatmap generates the heatmap matrix for the query.
+func (r *Recorder) Heatmap(q *Query) *Matrix {
+	r.RLock()
+	var snapshots []*snapshot
+	for _, s := range r.snapshots {
+		if !s.time.Before(q.StartTime) && !s.time.After(q.EndTime) {
+			snapshots = append(snapshots, s)
+		}
+	}
+	r.RUnlock()
+
+	keys := buildKeyAxis(snapshots, q.StartKey, q.EndKey, q.KeyBuckets)
+	groups := groupSnapshots(snapshots, q.TimeBuckets)
+	m := &Matrix{
+		Keys:  make([]string, 0, len(keys)),
+		Times: make([]int64, 0, len(groups)),
+		Data:  make([][]uint64, 0, len(groups)),
+	}
+	for _, key := range keys {
+		m.Keys = append(m.Keys, core.HexRegionKeyStr(key))
+	}
+	for _, group := range groups {
+		row := make([]uint64, len(keys)-1)
+		for _, s := range group {
+			for _, sp := range s.spans {
+				distribute(row, keys, sp.startKey, sp.endKey, sp.values[q.Tag])
+			}
+		}
+		m.Times = append(m.Times, group[len(group)-1].time.Unix())
+		m.Data = append(m.Data, row)
+	}
+	return m
+}
+
+// buildKeyAxis returns the boundaries of the rows. An empty end key means
+// the end of the key space.
+func buildKeyAxis(snapshots []*snapshot, startKey, endKey []byte, buckets int) [][]byte {
+	inRange := func(key []byte) bool {
+		return len(key) > 0 && bytes.Compare(key, startKey) > 0 &&
+			(len(endKey) == 0 || bytes.Compare(key, endKey) < 0)
+	}
+	var inner [][]byte
+	for _, s := range snapshots {
+		for _, sp := range s.spans {
+			if inRange(sp.startKey) {
+				inner = append(inner, sp.startKey)
+			}
+			if inRange(sp.endKey) {
+				inner = append(inner, sp.endKey)
+			}
+		}
+	}
+	sort.Slice(inner, func(i, j int) bool { return bytes.Compare(inner[i], inner[j]) < 0 })
+	keys := [][]byte{startKey}
+	for _, key := range inner {
+		if !bytes.Equal(key, keys[len(keys)-1]) {
+			keys = append(keys, key)
+		}
+	}
+	keys = append(keys, endKey)
+
+	if buckets <= 0 {
+		buckets = DefaultKeyBuckets
+	}
+	if n := len(keys) - 1; n > buckets {
+		sampled := make([][]byte, 0, buckets+1)
+		for i := 0; i < buckets; i++ {
+			sampled = append(sampled, keys[i*n/buckets])
+		}
+		keys = append(sampled, endKey)
+	}
+	return keys
+}
+
+// groupSnapshots divides the snapshots into at most buckets continuous groups.
+func groupSnapshots(snapshots []*snapshot, buckets int) [][]*snapshot {
+	if buckets <= 0 || buckets > len(snapshots) {
+		buckets = len(snapshots)
+	}
+	groups := make([][]*snapshot, 0, buckets)
+	for i := 0; i < buckets; i++ {
+		groups = append(groups, snapshots[i*len(snapshots)/buckets:(i+1)*len(snapshots)/buckets])
+	}
+	return groups
+}
+
+// distribute adds the value of key range [startKey, endKey) to the rows it
+// overlaps evenly.
+func distribute(row []uint64, keys [][]byte, startKey, endKey []byte, value uint64) {
+	n := len(row)
+	lo := sort.Search(n, func(i int) bool {
+		return (i+1 == n && len(keys[n]) == 0) || bytes.Compare(keys[i+1], startKey) > 0
+	})
+	hi := n
+	if len(endKey) > 0 {
+		hi = sort.Search(n, func(i int) bool { return bytes.Compare(keys[i], endKey) >= 0 })
+	}
+	if hi <= lo {
+		return
+	}
+	avg, rest := value/uint64(hi-lo), value%uint64(hi-lo)
+	for i := lo; i < hi; i++ {
+		row[i] += avg
+	}
+	row[lo] += rest
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
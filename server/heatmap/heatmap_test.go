@@ -0,0 +1,102 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heatmap
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/core"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testHeatmapSuite{})
+
+type testHeatmapSuite struct{}
+
+func newTestRegions(keys []string, written ...uint64) []*core.RegionInfo {
+	regions := make([]*core.RegionInfo, 0, len(written))
+	for i, w := range written {
+		r := core.NewTestRegionInfo([]byte(keys[i]), []byte(keys[i+1]))
+		regions = append(regions, r.Clone(core.SetWrittenBytes(w), core.SetReadBytes(w*2)))
+	}
+	return regions
+}
+
+func (s *testHeatmapSuite) TestHeatmap(c *C) {
+	r := NewRecorder(3, 4096)
+	base := time.Unix(1600000000, 0)
+	keys := []string{"", "b", "d", "f", ""}
+	for i := 0; i < 4; i++ {
+		r.Record(base.Add(time.Duration(i)*time.Minute), newTestRegions(keys, 1, 2, 3, uint64(i)))
+	}
+	// Only the latest 3 snapshots are kept.
+	m := r.Heatmap(&Query{StartTime: base, EndTime: base.Add(time.Hour)})
+	c.Assert(m.Times, DeepEquals, []int64{base.Unix() + 60, base.Unix() + 120, base.Unix() + 180})
+	c.Assert(m.Keys, HasLen, 5)
+	c.Assert(m.Data, DeepEquals, [][]uint64{{1, 2, 3, 1}, {1, 2, 3, 2}, {1, 2, 3, 3}})
+
+	// Downsample by time and key, and choose the tag.
+	m = r.Heatmap(&Query{Tag: ReadBytes, StartTime: base, EndTime: base.Add(time.Hour), KeyBuckets: 2, TimeBuckets: 1})
+	c.Assert(m.Keys, HasLen, 3)
+	c.Assert(m.Data, DeepEquals, [][]uint64{{18, 30}})
+
+	// Limit the key range, the flow of a region partially in range is counted
+	// in the overlapped rows.
+	m = r.Heatmap(&Query{StartKey: []byte("c"), EndKey: []byte("e"), StartTime: base.Add(3 * time.Minute), EndTime: base.Add(time.Hour)})
+	c.Assert(m.Data, DeepEquals, [][]uint64{{2, 3}})
+}
+
+func (s *testHeatmapSuite) TestRecorderLimits(c *C) {
+	r := NewRecorder(3, 2)
+	base := time.Unix(1600000000, 0)
+	keys := []string{"", "b", "d", "f", ""}
+	regions := newTestRegions(keys, 1, 2, 3, 4)
+	r.Record(base, regions)
+	r.Record(base.Add(time.Minute), regions)
+	// The adjacent regions are merged into 2 spans.
+	c.Assert(r.snapshots[0].spans, HasLen, 2)
+	c.Assert(r.snapshots[0].spans[0].values[WrittenBytes], Equals, uint64(3))
+	c.Assert(string(r.snapshots[0].spans[0].endKey), Equals, "d")
+	// The boundary keys are copied from the regions and shared by the snapshots.
+	c.Assert(&r.snapshots[0].spans[0].endKey[0] != &regions[1].GetEndKey()[0], IsTrue)
+	c.Assert(&r.snapshots[0].spans[0].endKey[0] == &r.snapshots[1].spans[0].endKey[0], IsTrue)
+	c.Assert(&r.snapshots[0].spans[0].endKey[0] == &r.snapshots[0].spans[1].startKey[0], IsTrue)
+
+	r.SetLimits(1, 4)
+	c.Assert(r.snapshots, HasLen, 1)
+	c.Assert(r.snapshots[0].time, Equals, base.Add(time.Minute))
+	r.Record(base.Add(2*time.Minute), regions)
+	c.Assert(r.snapshots, HasLen, 1)
+	c.Assert(r.snapshots[0].spans, HasLen, 4)
+
+	r.Reset()
+	c.Assert(r.snapshots, HasLen, 0)
+	c.Assert(r.Heatmap(&Query{StartTime: base, EndTime: base.Add(time.Hour)}).Data, HasLen, 0)
+}
+
+func (s *testHeatmapSuite) TestDistribute(c *C) {
+	keys := [][]byte{[]byte(""), []byte("b"), []byte("d"), []byte("")}
+	row := make([]uint64, 3)
+	distribute(row, keys, []byte("a"), []byte("c"), 5)
+	c.Assert(row, DeepEquals, []uint64{3, 2, 0})
+	distribute(row, keys, []byte("d"), []byte(""), 4)
+	c.Assert(row, DeepEquals, []uint64{3, 2, 4})
+	distribute(row, keys, []byte("x"), []byte(""), 1)
+	c.Assert(row, DeepEquals, []uint64{3, 2, 5})
+}
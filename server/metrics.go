@@ -57,6 +57,14 @@ var (
 			Help:      "Etcd raft states.",
 		}, []string{"type"})
 
+	tlsCertExpireGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "server",
+			Name:      "tls_cert_expire_time",
+			Help:      "The expiration time of the TLS certificate in unix seconds.",
+		})
+
 	tsoHandleDuration = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "pd",
@@ -94,4 +102,5 @@ func init() {
 	prometheus.MustRegister(tsoHandleDuration)
 	prometheus.MustRegister(regionHeartbeatHandleDuration)
 	prometheus.MustRegister(storeHeartbeatHandleDuration)
+	prometheus.MustRegister(tlsCertExpireGauge)
 }
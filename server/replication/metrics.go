@@ -31,4 +31,18 @@ var (
 			Name:      "dr_recover_progress",
 			Help:      "Progress of sync_recover process",
 		})
+
+	drUnplacedRegionsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "replication",
+			Name:      "dr_unplaced_regions",
+			Help:      "Number of regions not placed across primary and dr",
+		})
 )
+
+func init() {
+	prometheus.MustRegister(drTickCounter)
+	prometheus.MustRegister(drRecoverProgressGauge)
+	prometheus.MustRegister(drUnplacedRegionsGauge)
+}
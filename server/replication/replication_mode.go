@@ -78,6 +78,12 @@ type ModeManager struct {
 	drTotalRegion        int // number of all regions
 
 	drMemberWaitAsyncTime map[uint64]time.Time // last sync time with follower nodes
+
+	// the placement check also runs in background and scans a batch of
+	// regions every tick.
+	drPlacementKey    []byte // regions that has startKey < drPlacementKey are checked in this round
+	drPlacementMissed int    // number of regions not placed across both label groups in this round
+	drUnplacedRegions int    // result of the last completed round
 }
 
 // NewReplicationModeManager creates the replicate mode manager.
@@ -170,6 +176,7 @@ type HTTPReplicationStatus struct {
 		TotalRegions    int     `json:"total_regions,omitempty"`
 		SyncedRegions   int     `json:"synced_regions,omitempty"`
 		RecoverProgress float32 `json:"recover_progress,omitempty"`
+		UnplacedRegions int     `json:"unplaced_regions,omitempty"`
 	} `json:"dr-auto-sync,omitempty"`
 }
 
@@ -188,6 +195,7 @@ func (m *ModeManager) GetReplicationStatusHTTP() *HTTPReplicationStatus {
 		status.DrAutoSync.RecoverProgress = m.drAutoSync.RecoverProgress
 		status.DrAutoSync.TotalRegions = m.drAutoSync.TotalRegions
 		status.DrAutoSync.SyncedRegions = m.drAutoSync.SyncedRegions
+		status.DrAutoSync.UnplacedRegions = m.drUnplacedRegions
 	}
 	return &status
 }
@@ -394,6 +402,8 @@ func (m *ModeManager) tickDR() {
 		m.drSwitchToSyncRecover()
 	}
 
+	m.checkPlacement()
+
 	if m.drGetState() == drStateSyncRecover {
 		m.updateProgress()
 		progress := m.estimateProgress()
@@ -424,6 +434,55 @@ func (m *ModeManager) checkStoreStatus() (primaryFailCount, drFailCount int) {
 	return
 }
 
+// checkPlacement checks a batch of regions to find the ones whose voters are
+// not placed across both the primary and the DR label groups. Such regions
+// can not get the acknowledgment from both groups in the sync state. The
+// number of them is published after all regions are checked.
+func (m *ModeManager) checkPlacement() {
+	regions := m.cluster.ScanRegions(m.drPlacementKey, nil, regionScanBatchSize)
+	for _, r := range regions {
+		if !m.isRegionPlaced(r) {
+			m.drPlacementMissed++
+		}
+	}
+	if len(regions) > 0 {
+		m.drPlacementKey = regions[len(regions)-1].GetEndKey()
+	}
+	if len(regions) > 0 && len(m.drPlacementKey) > 0 {
+		return
+	}
+	// finish the round.
+	if m.drPlacementMissed > 0 {
+		log.Warn("found regions not placed across primary and dr",
+			zap.String("replicate-mode", modeDRAutoSync),
+			zap.Int("count", m.drPlacementMissed))
+	}
+	drUnplacedRegionsGauge.Set(float64(m.drPlacementMissed))
+	m.Lock()
+	m.drUnplacedRegions = m.drPlacementMissed
+	m.Unlock()
+	m.drPlacementKey, m.drPlacementMissed = nil, 0
+}
+
+func (m *ModeManager) isRegionPlaced(region *core.RegionInfo) bool {
+	m.RLock()
+	defer m.RUnlock()
+	var inPrimary, inDR bool
+	for _, peer := range region.GetVoters() {
+		store := m.cluster.GetStore(peer.GetStoreId())
+		if store == nil {
+			continue
+		}
+		switch store.GetLabelValue(m.config.DRAutoSync.LabelKey) {
+		case m.config.DRAutoSync.Primary:
+			inPrimary = true
+		case m.config.DRAutoSync.DR:
+			inDR = true
+		}
+	}
+	return inPrimary && inDR
+}
+
 var (
 	regionScanBatchSize = 1024
 	regionMinSampleSize = 512
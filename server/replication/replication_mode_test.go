@@ -20,6 +20,7 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
 	pb "github.com/pingcap/kvproto/pkg/replication_modepb"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/typeutil"
@@ -278,6 +279,41 @@ func (s *testReplicationMode) TestAsynctimeout(c *C) {
 	c.Assert(rep.drGetState(), Equals, drStateAsync)
 }
 
+func (s *testReplicationMode) TestPlacementCheck(c *C) {
+	store := core.NewStorage(kv.NewMemoryKV())
+	conf := config.ReplicationModeConfig{ReplicationMode: modeDRAutoSync, DRAutoSync: config.DRAutoSyncReplicationConfig{
+		LabelKey:         "zone",
+		Primary:          "zone1",
+		DR:               "zone2",
+		PrimaryReplicas:  2,
+		DRReplicas:       1,
+		WaitStoreTimeout: typeutil.Duration{Duration: time.Minute},
+		WaitSyncTimeout:  typeutil.Duration{Duration: time.Minute},
+	}}
+	cluster := mockcluster.NewCluster(config.NewTestOptions())
+	rep, err := NewReplicationModeManager(conf, store, cluster, nil)
+	c.Assert(err, IsNil)
+
+	cluster.AddLabelsStore(1, 1, map[string]string{"zone": "zone1"})
+	cluster.AddLabelsStore(2, 1, map[string]string{"zone": "zone1"})
+	cluster.AddLabelsStore(3, 1, map[string]string{"zone": "zone2"})
+	cluster.AddLeaderRegion(1, 1, 2, 3)
+	cluster.AddLeaderRegion(2, 1, 2)
+	cluster.AddLeaderRegion(3, 3)
+
+	// The result is published after a round covers all regions.
+	rep.tickDR()
+	rep.tickDR()
+	c.Assert(rep.GetReplicationStatusHTTP().DrAutoSync.UnplacedRegions, Equals, 2)
+
+	// The learner is not counted to acknowledge.
+	cluster.PutRegion(cluster.GetRegion(2).Clone(core.WithAddPeer(&metapb.Peer{Id: 100, StoreId: 3, Role: metapb.PeerRole_Learner})))
+	cluster.AddLeaderRegion(3, 3, 1)
+	rep.tickDR()
+	rep.tickDR()
+	c.Assert(rep.GetReplicationStatusHTTP().DrAutoSync.UnplacedRegions, Equals, 1)
+}
+
 func (s *testReplicationMode) setStoreState(cluster *mockcluster.Cluster, id uint64, state string) {
 	store := cluster.GetStore(id)
 	if state == "down" {
@@ -33,9 +33,13 @@ func NewLearnerChecker(cluster opt.Cluster) *LearnerChecker {
 	}
 }
 
-// Check verifies a region's role, creating an Operator if need.
+// Check verifies a region's role, creating an Operator if need. The
+// observers are never promoted.
 func (l *LearnerChecker) Check(region *core.RegionInfo) *operator.Operator {
 	for _, p := range region.GetLearners() {
+		if IsObserverStore(l.cluster.GetStore(p.GetStoreId())) {
+			continue
+		}
 		op, err := operator.CreatePromoteLearnerOperator("promote-learner", l.cluster, region, p)
 		if err != nil {
 			log.Debug("fail to create promote learner operator", errs.ZapError(err))
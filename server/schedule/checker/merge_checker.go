@@ -28,6 +28,7 @@ import (
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/opt"
 	"github.com/tikv/pd/server/schedule/placement"
+	"github.com/tikv/pd/server/versioninfo"
 )
 
 // MergeChecker ensures region to merge with adjacent region when size is small
@@ -60,6 +61,11 @@ func (m *MergeChecker) RecordRegionSplit(regionIDs []uint64) {
 
 // Check verifies a region's replicas, creating an Operator if need.
 func (m *MergeChecker) Check(region *core.RegionInfo) []*operator.Operator {
+	if !m.cluster.IsFeatureSupported(versioninfo.RegionMerge) {
+		checkerCounter.WithLabelValues("merge_checker", "feature-not-supported").Inc()
+		return nil
+	}
+
 	expireTime := m.startTime.Add(m.opts.GetSplitMergeInterval())
 	if time.Now().Before(expireTime) {
 		checkerCounter.WithLabelValues("merge_checker", "recently-start").Inc()
@@ -169,7 +175,11 @@ func AllowMerge(cluster opt.Cluster, region *core.RegionInfo, adjacent *core.Reg
 		if cluster.GetOpts().IsCrossTableMergeEnabled() {
 			return true
 		}
-		return isTableIDSame(region, adjacent)
+		if !isTableIDSame(region, adjacent) {
+			checkerCounter.WithLabelValues("merge_checker", "cross-table").Inc()
+			return false
+		}
+		return true
 	case core.Raw:
 		return true
 	case core.Txn:
@@ -179,6 +189,9 @@ func AllowMerge(cluster opt.Cluster, region *core.RegionInfo, adjacent *core.Reg
 	}
 }
 
+// isTableIDSame checks if the two regions start in the same table. As the
+// start key of the latter region is the boundary removed by the merge, the
+// merged region does not cross a table boundary only if they are the same.
 func isTableIDSame(region *core.RegionInfo, adjacent *core.RegionInfo) bool {
 	return codec.Key(region.GetStartKey()).TableID() == codec.Key(adjacent.GetStartKey()).TableID()
 }
@@ -21,6 +21,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server/config"
@@ -196,6 +197,71 @@ func (s *testMergeCheckerSuite) TestBasic(c *C) {
 	c.Assert(ops, IsNil)
 }
 
+func (s *testMergeCheckerSuite) TestFeatureNotSupported(c *C) {
+	s.cluster.SetSplitMergeInterval(0)
+	c.Assert(s.mc.Check(s.regions[2]), NotNil)
+	// Stores which do not support merge should not receive merge commands.
+	s.cluster.DisableFeature(versioninfo.RegionMerge)
+	c.Assert(s.mc.Check(s.regions[2]), IsNil)
+}
+
+func (s *testMergeCheckerSuite) TestCrossTableMerge(c *C) {
+	newRegion := func(id uint64, start, end []byte) *core.RegionInfo {
+		meta := &metapb.Region{Id: id, Peers: []*metapb.Peer{{Id: id + 100, StoreId: 1}}}
+		if start != nil {
+			meta.StartKey = codec.EncodeBytes(start)
+		}
+		if end != nil {
+			meta.EndKey = codec.EncodeBytes(end)
+		}
+		return core.NewRegionInfo(meta, meta.Peers[0])
+	}
+	r1 := newRegion(1, codec.GenerateRowKey(1, 1), codec.GenerateRowKey(1, 100))
+	r2 := newRegion(2, codec.GenerateRowKey(1, 100), codec.GenerateTableKey(2))
+	r3 := newRegion(3, codec.GenerateTableKey(2), codec.GenerateRowKey(2, 100))
+
+	// The regions in the same table can be merged.
+	c.Assert(AllowMerge(s.cluster, r1, r2), IsTrue)
+	c.Assert(AllowMerge(s.cluster, r2, r1), IsTrue)
+	// The boundary between table 1 and 2 is kept.
+	c.Assert(AllowMerge(s.cluster, r2, r3), IsFalse)
+	c.Assert(AllowMerge(s.cluster, r3, r2), IsFalse)
+	// The regions which are not adjacent can not be merged.
+	c.Assert(AllowMerge(s.cluster, r1, r3), IsFalse)
+
+	s.cluster.SetEnableCrossTableMerge(true)
+	c.Assert(AllowMerge(s.cluster, r2, r3), IsTrue)
+	s.cluster.SetEnableCrossTableMerge(false)
+
+	// The table boundaries are ignored if the keys are not encoded by TiDB.
+	s.cluster.SetKeyType(core.Raw.String())
+	c.Assert(AllowMerge(s.cluster, r2, r3), IsTrue)
+}
+
+func (s *testMergeCheckerSuite) TestMergeThreshold(c *C) {
+	s.cluster.SetSplitMergeInterval(0)
+
+	// The size of region 2 exceeds the threshold.
+	ops := s.mc.Check(s.regions[1])
+	c.Assert(ops, IsNil)
+
+	// Both size and keys should be small enough.
+	s.cluster.SetMaxMergeRegionSize(200)
+	ops = s.mc.Check(s.regions[1])
+	c.Assert(ops, IsNil)
+	s.cluster.SetMaxMergeRegionKeys(200)
+	ops = s.mc.Check(s.regions[1])
+	c.Assert(ops, NotNil)
+	c.Assert(ops[0].RegionID(), Equals, s.regions[1].GetID())
+	c.Assert(ops[1].RegionID(), Equals, s.regions[2].GetID())
+
+	// Setting the size threshold to zero stops merging.
+	s.cluster.SetMaxMergeRegionSize(0)
+	for _, region := range s.regions {
+		c.Assert(s.mc.Check(region), IsNil)
+	}
+}
+
 func (s *testMergeCheckerSuite) checkSteps(c *C, op *operator.Operator, steps []operator.OpStep) {
 	c.Assert(op.Kind()&operator.OpMerge, Not(Equals), 0)
 	c.Assert(steps, NotNil)
@@ -0,0 +1,109 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+	"go.uber.org/zap"
+)
+
+const namespaceCheckerName = "namespace-checker"
+
+// NamespaceChecker ensures the peers of a region are placed on the stores of
+// the namespace the region belongs to. The namespace of a store is the value
+// of its namespace label, and the namespace of a region is the one which
+// holds most of its peers, the leader breaks the tie.
+type NamespaceChecker struct {
+	cluster  opt.Cluster
+	labelKey string
+}
+
+// NewNamespaceChecker creates a namespace checker. labelKey is the store
+// label key that assigns a store to a namespace.
+func NewNamespaceChecker(cluster opt.Cluster, labelKey string) *NamespaceChecker {
+	return &NamespaceChecker{
+		cluster:  cluster,
+		labelKey: labelKey,
+	}
+}
+
+// Check moves a peer which is outside the namespace of the region back to a
+// store of the namespace.
+func (n *NamespaceChecker) Check(region *core.RegionInfo) *operator.Operator {
+	checkerCounter.WithLabelValues("namespace_checker", "check").Inc()
+	namespace, leaderStore := n.regionNamespace(region)
+	if namespace == "" {
+		return nil
+	}
+	regionStores := n.cluster.GetRegionStores(region)
+	for _, peer := range region.GetPeers() {
+		store := n.cluster.GetStore(peer.GetStoreId())
+		if store == nil || store.GetLabelValue(n.labelKey) == namespace {
+			continue
+		}
+		strategy := &ReplicaStrategy{
+			checkerName:    namespaceCheckerName,
+			cluster:        n.cluster,
+			locationLabels: n.cluster.GetOpts().GetLocationLabels(),
+			isolationLevel: n.cluster.GetOpts().GetIsolationLevel(),
+			region:         region,
+			extraFilters:   []filter.Filter{filter.NewLabelGroupFilter(namespaceCheckerName, n.labelKey, leaderStore)},
+		}
+		newStore := strategy.SelectStoreToReplace(regionStores, peer.GetStoreId())
+		if newStore == 0 {
+			log.Debug("no store in the namespace to move the peer to",
+				zap.Uint64("region-id", region.GetID()),
+				zap.String("namespace", namespace),
+				zap.Uint64("store-id", peer.GetStoreId()))
+			checkerCounter.WithLabelValues("namespace_checker", "no-target-store").Inc()
+			continue
+		}
+		newPeer := &metapb.Peer{StoreId: newStore, Role: peer.GetRole()}
+		op, err := operator.CreateMovePeerOperator("move-peer-into-namespace", n.cluster, region, operator.OpReplica, peer.GetStoreId(), newPeer)
+		if err != nil {
+			log.Debug("fail to create move peer into namespace operator", errs.ZapError(err))
+			checkerCounter.WithLabelValues("namespace_checker", "create-operator-fail").Inc()
+			return nil
+		}
+		checkerCounter.WithLabelValues("namespace_checker", "new-operator").Inc()
+		return op
+	}
+	return nil
+}
+
+// regionNamespace returns the namespace of the region and a store of the
+// namespace which holds a peer of the region.
+func (n *NamespaceChecker) regionNamespace(region *core.RegionInfo) (string, *core.StoreInfo) {
+	stores := n.cluster.GetRegionStores(region)
+	namespace := core.RegionLabelValue(region, stores, n.labelKey)
+	if namespace == "" {
+		return "", nil
+	}
+	var namespaceStore *core.StoreInfo
+	for _, store := range stores {
+		if store.GetLabelValue(n.labelKey) != namespace {
+			continue
+		}
+		if namespaceStore == nil || store.GetID() == region.GetLeader().GetStoreId() {
+			namespaceStore = store
+		}
+	}
+	return namespace, namespaceStore
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/testutil"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/versioninfo"
+)
+
+var _ = Suite(&testNamespaceCheckerSuite{})
+
+type testNamespaceCheckerSuite struct{}
+
+func (s *testNamespaceCheckerSuite) TestNamespaceChecker(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	tc.DisableFeature(versioninfo.JointConsensus)
+	nc := NewNamespaceChecker(tc, "namespace")
+	tc.AddLabelsStore(1, 10, map[string]string{"namespace": "a"})
+	tc.AddLabelsStore(2, 10, map[string]string{"namespace": "a"})
+	tc.AddLabelsStore(3, 10, map[string]string{"namespace": "a"})
+	tc.AddLabelsStore(4, 5, map[string]string{"namespace": "a"})
+	tc.AddLabelsStore(5, 1, map[string]string{"namespace": "b"})
+	tc.AddLabelsStore(6, 1, map[string]string{"namespace": "b"})
+	tc.AddLabelsStore(7, 1, map[string]string{})
+
+	// All peers are in the namespace.
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	c.Assert(nc.Check(tc.GetRegion(1)), IsNil)
+
+	// The peer outside the namespace is moved to the store with the least regions.
+	tc.AddLeaderRegion(2, 1, 2, 5)
+	testutil.CheckTransferPeer(c, nc.Check(tc.GetRegion(2)), operator.OpReplica, 5, 4)
+	tc.AddLeaderRegion(3, 1, 2, 7)
+	testutil.CheckTransferPeer(c, nc.Check(tc.GetRegion(3)), operator.OpReplica, 7, 4)
+
+	// The namespace which holds most of the peers wins, even if the leader
+	// is outside of it.
+	tc.AddLeaderRegion(4, 5, 1, 2)
+	testutil.CheckTransferPeer(c, nc.Check(tc.GetRegion(4)), operator.OpReplica, 5, 4)
+
+	// The leader breaks the tie.
+	tc.AddLeaderRegion(5, 5, 1, 7)
+	testutil.CheckTransferPeer(c, nc.Check(tc.GetRegion(5)), operator.OpReplica, 1, 6)
+
+	// No store in the namespace is available.
+	tc.SetStoreOffline(4)
+	tc.AddLeaderRegion(6, 1, 2, 3, 5)
+	c.Assert(nc.Check(tc.GetRegion(6)), IsNil)
+
+	// The regions without namespace are skipped.
+	tc.AddLeaderRegion(7, 7)
+	c.Assert(nc.Check(tc.GetRegion(7)), IsNil)
+}
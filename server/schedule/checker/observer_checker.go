@@ -0,0 +1,166 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/filter"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/schedule/placement"
+	"go.uber.org/zap"
+)
+
+const observerCheckerName = "observer-checker"
+
+// ObserverChecker ensures the region has the configured number of observers,
+// which are the learners on the analytics stores. The observers are never
+// promoted and are not counted as the replicas of the region.
+type ObserverChecker struct {
+	cluster opt.Cluster
+}
+
+// NewObserverChecker creates an observer checker.
+func NewObserverChecker(cluster opt.Cluster) *ObserverChecker {
+	return &ObserverChecker{
+		cluster: cluster,
+	}
+}
+
+// IsObserverStore returns if the store only holds the observers.
+func IsObserverStore(store *core.StoreInfo) bool {
+	return store != nil && store.GetLabelValue(filter.SpecialUseKey) == filter.SpecialUseAnalytics
+}
+
+// isObserver returns if the peer is an observer of the region.
+func (o *ObserverChecker) isObserver(region *core.RegionInfo, peer *metapb.Peer) bool {
+	return region.GetStoreLearner(peer.GetStoreId()) != nil && IsObserverStore(o.cluster.GetStore(peer.GetStoreId()))
+}
+
+// ExcludeObservers returns the region without the observers, which is used
+// by the other checkers so that the observers are out of the replica count
+// and the quorum.
+func (o *ObserverChecker) ExcludeObservers(region *core.RegionInfo) *core.RegionInfo {
+	var observers []uint64
+	for _, peer := range region.GetLearners() {
+		if o.isObserver(region, peer) {
+			observers = append(observers, peer.GetStoreId())
+		}
+	}
+	if len(observers) == 0 {
+		return region
+	}
+	excluded := func(storeID uint64) bool {
+		for _, id := range observers {
+			if id == storeID {
+				return true
+			}
+		}
+		return false
+	}
+	var downPeers []*pdpb.PeerStats
+	for _, stats := range region.GetDownPeers() {
+		if !excluded(stats.GetPeer().GetStoreId()) {
+			downPeers = append(downPeers, stats)
+		}
+	}
+	var pendingPeers []*metapb.Peer
+	for _, peer := range region.GetPendingPeers() {
+		if !excluded(peer.GetStoreId()) {
+			pendingPeers = append(pendingPeers, peer)
+		}
+	}
+	opts := []core.RegionCreateOption{core.WithDownPeers(downPeers), core.WithPendingPeers(pendingPeers)}
+	for _, id := range observers {
+		opts = append(opts, core.WithRemoveStorePeer(id))
+	}
+	return region.Clone(opts...)
+}
+
+// Check verifies the observers of the region, creating an operator if need.
+func (o *ObserverChecker) Check(region *core.RegionInfo) *operator.Operator {
+	checkerCounter.WithLabelValues("observer_checker", "check").Inc()
+	var observers, unhealthy []uint64
+	for _, peer := range region.GetLearners() {
+		if !o.isObserver(region, peer) {
+			continue
+		}
+		if o.isHealthy(region, peer) {
+			observers = append(observers, peer.GetStoreId())
+		} else {
+			unhealthy = append(unhealthy, peer.GetStoreId())
+		}
+	}
+	maxObservers := o.cluster.GetOpts().GetMaxObservers()
+	switch {
+	case len(unhealthy) > 0:
+		// The new observer is added after the unhealthy one is removed.
+		return o.removeObserver(region, "remove-unhealthy-observer", unhealthy[0])
+	case len(observers) < maxObservers:
+		return o.addObserver(region)
+	case len(observers) > maxObservers:
+		return o.removeObserver(region, "remove-extra-observer", observers[len(observers)-1])
+	}
+	return nil
+}
+
+func (o *ObserverChecker) isHealthy(region *core.RegionInfo, peer *metapb.Peer) bool {
+	store := o.cluster.GetStore(peer.GetStoreId())
+	if store == nil || !store.IsUp() {
+		return false
+	}
+	return region.GetDownPeer(peer.GetId()) == nil || store.DownTime() < o.cluster.GetOpts().GetMaxStoreDownTime()
+}
+
+func (o *ObserverChecker) addObserver(region *core.RegionInfo) *operator.Operator {
+	filters := []filter.Filter{
+		filter.NewExcludedFilter(observerCheckerName, nil, region.GetStoreIds()),
+		filter.NewLabelConstaintFilter(observerCheckerName, []placement.LabelConstraint{
+			{Key: filter.SpecialUseKey, Op: placement.In, Values: []string{filter.SpecialUseAnalytics}},
+		}),
+		filter.NewStorageThresholdFilter(observerCheckerName),
+		filter.StoreStateFilter{ActionScope: observerCheckerName, MoveRegion: true},
+	}
+	target := filter.NewCandidates(o.cluster.GetStores()).
+		FilterTarget(o.cluster.GetOpts(), filters...).
+		Sort(filter.RegionScoreComparer(o.cluster.GetOpts())).PickFirst()
+	if target == nil {
+		checkerCounter.WithLabelValues("observer_checker", "no-target-store").Inc()
+		return nil
+	}
+	newPeer := &metapb.Peer{StoreId: target.GetID(), Role: metapb.PeerRole_Learner}
+	op, err := operator.CreateAddPeerOperator("add-observer", o.cluster, region, newPeer, operator.OpReplica)
+	if err != nil {
+		log.Debug("fail to create add observer operator", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+		checkerCounter.WithLabelValues("observer_checker", "create-operator-fail").Inc()
+		return nil
+	}
+	checkerCounter.WithLabelValues("observer_checker", "new-operator").Inc()
+	return op
+}
+
+func (o *ObserverChecker) removeObserver(region *core.RegionInfo, desc string, storeID uint64) *operator.Operator {
+	op, err := operator.CreateRemovePeerOperator(desc, o.cluster, operator.OpReplica, region, storeID)
+	if err != nil {
+		log.Debug("fail to create remove observer operator", zap.Uint64("region-id", region.GetID()), errs.ZapError(err))
+		checkerCounter.WithLabelValues("observer_checker", "create-operator-fail").Inc()
+		return nil
+	}
+	checkerCounter.WithLabelValues("observer_checker", "new-operator").Inc()
+	return op
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checker
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/versioninfo"
+)
+
+var _ = Suite(&testObserverCheckerSuite{})
+
+type testObserverCheckerSuite struct {
+	cluster *mockcluster.Cluster
+	oc      *ObserverChecker
+}
+
+func (s *testObserverCheckerSuite) SetUpTest(c *C) {
+	s.cluster = mockcluster.NewCluster(config.NewTestOptions())
+	s.cluster.DisableFeature(versioninfo.JointConsensus)
+	s.oc = NewObserverChecker(s.cluster)
+	for id := uint64(1); id <= 3; id++ {
+		s.cluster.PutStoreWithLabels(id)
+	}
+	s.cluster.AddLabelsStore(4, 0, map[string]string{"specialUse": "analytics"})
+	s.cluster.AddLabelsStore(5, 0, map[string]string{"specialUse": "analytics"})
+}
+
+func (s *testObserverCheckerSuite) TestAddObserver(c *C) {
+	region := s.cluster.AddLeaderRegion(1, 1, 2, 3)
+	c.Assert(s.oc.Check(region), IsNil)
+
+	s.cluster.SetMaxObservers(1)
+	op := s.oc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "add-observer")
+	c.Assert(op.Step(0), FitsTypeOf, operator.AddLearner{})
+	target := op.Step(0).(operator.AddLearner).ToStore
+	c.Assert(target == 4 || target == 5, IsTrue)
+
+	// The observer is neither promoted nor counted as a replica.
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: 104, StoreId: 4, Role: metapb.PeerRole_Learner}))
+	c.Assert(s.oc.Check(region), IsNil)
+	c.Assert(NewLearnerChecker(s.cluster).Check(region), IsNil)
+	view := s.oc.ExcludeObservers(region)
+	c.Assert(view.GetPeers(), HasLen, 3)
+	c.Assert(view.GetStorePeer(4), IsNil)
+	c.Assert(region.GetStorePeer(4), NotNil)
+}
+
+func (s *testObserverCheckerSuite) TestRemoveObserver(c *C) {
+	region := s.cluster.AddLeaderRegion(1, 1, 2, 3).Clone(
+		core.WithAddPeer(&metapb.Peer{Id: 104, StoreId: 4, Role: metapb.PeerRole_Learner}),
+		core.WithAddPeer(&metapb.Peer{Id: 105, StoreId: 5, Role: metapb.PeerRole_Learner}),
+	)
+	s.cluster.SetMaxObservers(1)
+	op := s.oc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-extra-observer")
+	c.Assert(op.Step(0), FitsTypeOf, operator.RemovePeer{})
+
+	// The observer on an offline store is removed first.
+	s.cluster.SetMaxObservers(2)
+	c.Assert(s.oc.Check(region), IsNil)
+	s.cluster.SetStoreOffline(5)
+	op = s.oc.Check(region)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "remove-unhealthy-observer")
+	c.Assert(op.Step(0).(operator.RemovePeer).FromStore, Equals, uint64(5))
+}
@@ -87,6 +87,14 @@ func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *operator.Operat
 		return nil
 	}
 
+	// The peers on a physically destroyed store never come back, so they
+	// are replaced without waiting for them to be down.
+	for _, peer := range region.GetPeers() {
+		if r.isDestroyedPeer(region, peer) {
+			return r.fixPeer(region, peer.GetStoreId(), downStatus)
+		}
+	}
+
 	for _, stats := range region.GetDownPeers() {
 		peer := stats.GetPeer()
 		if peer == nil {
@@ -98,6 +106,10 @@ func (r *ReplicaChecker) checkDownPeer(region *core.RegionInfo) *operator.Operat
 			log.Warn("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", storeID))
 			return nil
 		}
+		// The store is restarting for an upgrade, wait for it to come back.
+		if store.IsRestarting() {
+			continue
+		}
 		if store.DownTime() < r.opts.GetMaxStoreDownTime() {
 			continue
 		}
@@ -130,6 +142,11 @@ func (r *ReplicaChecker) checkOfflinePeer(region *core.RegionInfo) *operator.Ope
 		if store.IsUp() {
 			continue
 		}
+		// The leader can not be transferred from a physically destroyed
+		// store, wait for a new leader to be elected.
+		if store.IsPhysicallyDestroyed() && storeID == region.GetLeader().GetStoreId() {
+			continue
+		}
 
 		return r.fixPeer(region, storeID, offlineStatus)
 	}
@@ -245,6 +262,15 @@ func (r *ReplicaChecker) fixPeer(region *core.RegionInfo, storeID uint64, status
 	return op
 }
 
+// isDestroyedPeer checks if the peer is a follower on a physically destroyed store.
+func (r *ReplicaChecker) isDestroyedPeer(region *core.RegionInfo, peer *metapb.Peer) bool {
+	if peer.GetStoreId() == region.GetLeader().GetStoreId() {
+		return false
+	}
+	store := r.cluster.GetStore(peer.GetStoreId())
+	return store != nil && store.IsPhysicallyDestroyed()
+}
+
 func (r *ReplicaChecker) strategy(region *core.RegionInfo) *ReplicaStrategy {
 	return &ReplicaStrategy{
 		checkerName:    replicaCheckerName,
@@ -132,6 +132,38 @@ func (s *testReplicaCheckerSuite) TestReplaceOfflinePeer(c *C) {
 	c.Assert(op.Step(3).(operator.RemovePeer).FromStore, Equals, uint64(1))
 }
 
+func (s *testReplicaCheckerSuite) TestReplaceDestroyedPeer(c *C) {
+	s.cluster.PutStore(s.cluster.GetStore(1).Clone(core.SetPhysicallyDestroyed()))
+	peers := []*metapb.Peer{
+		{
+			Id:      4,
+			StoreId: 1,
+		},
+		{
+			Id:      5,
+			StoreId: 2,
+		},
+		{
+			Id:      6,
+			StoreId: 3,
+		},
+	}
+	// The peer is replaced as a down peer without transferring the leader.
+	r := core.NewRegionInfo(&metapb.Region{Id: 2, Peers: peers}, peers[1])
+	s.cluster.PutRegion(r)
+	op := s.rc.Check(r)
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "replace-down-replica")
+	c.Assert(op.Step(0).(operator.AddLearner).ToStore, Equals, uint64(4))
+	c.Assert(op.Step(1).(operator.PromoteLearner).ToStore, Equals, uint64(4))
+	c.Assert(op.Step(2).(operator.RemovePeer).FromStore, Equals, uint64(1))
+
+	// Wait for a new leader if the leader is on the destroyed store.
+	r = core.NewRegionInfo(&metapb.Region{Id: 2, Peers: peers}, peers[0])
+	s.cluster.PutRegion(r)
+	c.Assert(s.rc.Check(r), IsNil)
+}
+
 func (s *testReplicaCheckerSuite) TestOfflineWithOneReplica(c *C) {
 	s.cluster.SetMaxReplicas(1)
 	peers := []*metapb.Peer{
@@ -212,6 +244,10 @@ func (s *testReplicaCheckerSuite) TestBasic(c *C) {
 
 	region = region.Clone(core.WithDownPeers(append(region.GetDownPeers(), downPeer)))
 	testutil.CheckTransferPeer(c, rc.Check(region), operator.OpReplica, 2, 1)
+	// The down peer is not replaced if the store is restarting.
+	tc.PutStore(tc.GetStore(2).Clone(core.SetRestartDeadline(time.Now().Add(time.Hour))))
+	c.Assert(rc.Check(region), IsNil)
+	tc.PutStore(tc.GetStore(2).Clone(core.ResetRestartDeadline()))
 	region = region.Clone(core.WithDownPeers(nil))
 	c.Assert(rc.Check(region), IsNil)
 
@@ -82,11 +82,11 @@ func (s *ReplicaStrategy) SelectStoreToAdd(coLocationStores []*core.StoreInfo, e
 
 // SelectStoreToReplace returns a store to replace oldStore. The location
 // placement after scheduling should be not worse than original.
-func (s *ReplicaStrategy) SelectStoreToReplace(coLocationStores []*core.StoreInfo, old uint64) uint64 {
+func (s *ReplicaStrategy) SelectStoreToReplace(coLocationStores []*core.StoreInfo, old uint64, extraFilters ...filter.Filter) uint64 {
 	// trick to avoid creating a slice with `old` removed.
 	s.swapStoreToFirst(coLocationStores, old)
 	safeGuard := filter.NewLocationSafeguard(s.checkerName, s.locationLabels, coLocationStores, s.cluster.GetStore(old))
-	return s.SelectStoreToAdd(coLocationStores[1:], safeGuard)
+	return s.SelectStoreToAdd(coLocationStores[1:], append(extraFilters, safeGuard)...)
 }
 
 // SelectStoreToImprove returns a store to replace oldStore. The location
@@ -87,16 +87,25 @@ func (c *RuleChecker) fixRulePeer(region *core.RegionInfo, fit *placement.Region
 		return c.addRulePeer(region, rf)
 	}
 	// fix down/offline peers.
+	var offlinePeers []*metapb.Peer
 	for _, peer := range rf.Peers {
 		if c.isDownPeer(region, peer) {
 			checkerCounter.WithLabelValues("rule_checker", "replace-down").Inc()
 			return c.replaceRulePeer(region, rf, peer, downStatus)
 		}
 		if c.isOfflinePeer(region, peer) {
-			checkerCounter.WithLabelValues("rule_checker", "replace-offline").Inc()
-			return c.replaceRulePeer(region, rf, peer, offlineStatus)
+			offlinePeers = append(offlinePeers, peer)
 		}
 	}
+	// The offline peers are still alive, so they can be replaced at once.
+	if len(offlinePeers) == 1 {
+		checkerCounter.WithLabelValues("rule_checker", "replace-offline").Inc()
+		return c.replaceRulePeer(region, rf, offlinePeers[0], offlineStatus)
+	}
+	if len(offlinePeers) > 1 {
+		checkerCounter.WithLabelValues("rule_checker", "replace-offline").Inc()
+		return c.replaceRulePeers(region, rf, offlinePeers, offlineStatus)
+	}
 	// fix loose matched peers.
 	for _, peer := range rf.PeersWithDifferentRole {
 		op, err := c.fixLooseMatchPeer(region, fit, rf, peer)
@@ -133,6 +142,34 @@ func (c *RuleChecker) replaceRulePeer(region *core.RegionInfo, rf *placement.Rul
 	return operator.CreateMovePeerOperator("replace-rule-"+status+"-peer", c.cluster, region, operator.OpReplica, peer.StoreId, newPeer)
 }
 
+// replaceRulePeers replaces several peers of the rule in one operator. If only
+// some of them can be replaced, the rest are left to the following checks.
+func (c *RuleChecker) replaceRulePeers(region *core.RegionInfo, rf *placement.RuleFit, peers []*metapb.Peer, status string) (*operator.Operator, error) {
+	ruleStores := c.getRuleFitStores(rf)
+	strategy := c.strategy(region, rf.Rule)
+	replacements := make(map[uint64]*metapb.Peer, len(peers))
+	selected := make(map[uint64]struct{}, len(peers))
+	for _, peer := range peers {
+		store := strategy.SelectStoreToReplace(ruleStores, peer.GetStoreId(), filter.NewExcludedFilter(c.name, nil, selected))
+		if store == 0 {
+			continue
+		}
+		selected[store] = struct{}{}
+		replacements[peer.GetStoreId()] = &metapb.Peer{StoreId: store, Role: rf.Rule.Role.MetaPeerRole()}
+		// The new store takes the place of the old one in the following selections.
+		for i, s := range ruleStores {
+			if s.GetID() == peer.GetStoreId() {
+				ruleStores[i] = c.cluster.GetStore(store)
+			}
+		}
+	}
+	if len(replacements) == 0 {
+		checkerCounter.WithLabelValues("rule_checker", "no-store-replace").Inc()
+		return nil, errors.New("no store to replace peer")
+	}
+	return operator.CreateReplacePeersOperator("replace-rule-"+status+"-peers", c.cluster, region, operator.OpReplica, replacements)
+}
+
 func (c *RuleChecker) fixLooseMatchPeer(region *core.RegionInfo, fit *placement.RegionFit, rf *placement.RuleFit, peer *metapb.Peer) (*operator.Operator, error) {
 	if core.IsLearner(peer) && rf.Rule.Role != placement.Learner {
 		checkerCounter.WithLabelValues("rule_checker", "fix-peer-role").Inc()
@@ -210,6 +247,10 @@ func (c *RuleChecker) fixOrphanPeers(region *core.RegionInfo, fit *placement.Reg
 }
 
 func (c *RuleChecker) isDownPeer(region *core.RegionInfo, peer *metapb.Peer) bool {
+	// The peers on a physically destroyed store never come back.
+	if store := c.cluster.GetStore(peer.GetStoreId()); store != nil && store.IsPhysicallyDestroyed() {
+		return peer.GetStoreId() != region.GetLeader().GetStoreId()
+	}
 	for _, stats := range region.GetDownPeers() {
 		if stats.GetPeer().GetId() != peer.GetId() {
 			continue
@@ -220,6 +261,10 @@ func (c *RuleChecker) isDownPeer(region *core.RegionInfo, peer *metapb.Peer) boo
 			log.Warn("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", storeID))
 			return false
 		}
+		// The store is restarting for an upgrade, wait for it to come back.
+		if store.IsRestarting() {
+			continue
+		}
 		if store.DownTime() < c.cluster.GetOpts().GetMaxStoreDownTime() {
 			continue
 		}
@@ -237,6 +282,11 @@ func (c *RuleChecker) isOfflinePeer(region *core.RegionInfo, peer *metapb.Peer)
 		log.Warn("lost the store, maybe you are recovering the PD cluster", zap.Uint64("store-id", peer.StoreId))
 		return false
 	}
+	// The leader can not be transferred from a physically destroyed store,
+	// wait for a new leader to be elected.
+	if store.IsPhysicallyDestroyed() && peer.GetStoreId() == region.GetLeader().GetStoreId() {
+		return false
+	}
 	return !store.IsUp()
 }
 
@@ -135,6 +135,29 @@ func (s *testRuleCheckerSuite) TestFixPeer(c *C) {
 	c.Assert(op.Step(0), FitsTypeOf, add)
 }
 
+func (s *testRuleCheckerSuite) TestFixOfflinePeers(c *C) {
+	s.cluster.AddLeaderStore(1, 1)
+	s.cluster.AddLeaderStore(2, 1)
+	s.cluster.AddLeaderStore(3, 1)
+	s.cluster.AddLeaderStore(4, 1)
+	s.cluster.AddLeaderStore(5, 1)
+	s.cluster.AddLeaderRegionWithRange(1, "", "", 1, 2, 3)
+	s.cluster.SetStoreOffline(2)
+	s.cluster.SetStoreOffline(3)
+
+	// Both of the offline peers are replaced by one operator.
+	op := s.rc.Check(s.cluster.GetRegion(1))
+	c.Assert(op, NotNil)
+	c.Assert(op.Desc(), Equals, "replace-rule-offline-peers")
+	added := make(map[uint64]struct{})
+	for i := 0; i < op.Len(); i++ {
+		if step, ok := op.Step(i).(operator.AddLearner); ok {
+			added[step.ToStore] = struct{}{}
+		}
+	}
+	c.Assert(added, DeepEquals, map[uint64]struct{}{4: {}, 5: {}})
+}
+
 func (s *testRuleCheckerSuite) TestFixOrphanPeers(c *C) {
 	s.cluster.AddLeaderStore(1, 1)
 	s.cluster.AddLeaderStore(2, 1)
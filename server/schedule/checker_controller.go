@@ -30,10 +30,12 @@ type CheckerController struct {
 	opts              *config.PersistOptions
 	opController      *OperatorController
 	learnerChecker    *checker.LearnerChecker
+	observerChecker   *checker.ObserverChecker
 	replicaChecker    *checker.ReplicaChecker
 	ruleChecker       *checker.RuleChecker
 	mergeChecker      *checker.MergeChecker
 	jointStateChecker *checker.JointStateChecker
+	namespaceChecker  *checker.NamespaceChecker
 }
 
 // NewCheckerController create a new CheckerController.
@@ -44,10 +46,12 @@ func NewCheckerController(ctx context.Context, cluster opt.Cluster, ruleManager
 		opts:              cluster.GetOpts(),
 		opController:      opController,
 		learnerChecker:    checker.NewLearnerChecker(cluster),
+		observerChecker:   checker.NewObserverChecker(cluster),
 		replicaChecker:    checker.NewReplicaChecker(cluster),
 		ruleChecker:       checker.NewRuleChecker(cluster, ruleManager),
 		mergeChecker:      checker.NewMergeChecker(ctx, cluster),
 		jointStateChecker: checker.NewJointStateChecker(cluster),
+		namespaceChecker:  checker.NewNamespaceChecker(cluster, NamespaceLabelKey),
 	}
 }
 
@@ -75,9 +79,20 @@ func (c *CheckerController) CheckRegion(region *core.RegionInfo) (bool, []*opera
 		}
 		if opController.OperatorCount(operator.OpReplica) < c.opts.GetReplicaScheduleLimit() {
 			checkerIsBusy = false
-			if op := c.replicaChecker.Check(region); op != nil {
+			// The observers are out of the replica count and the quorum.
+			if op := c.replicaChecker.Check(c.observerChecker.ExcludeObservers(region)); op != nil {
 				return checkerIsBusy, []*operator.Operator{op}
 			}
+			if op := c.observerChecker.Check(region); op != nil {
+				return checkerIsBusy, []*operator.Operator{op}
+			}
+			// The placement rules decide the placement by themselves, so the
+			// namespace is only enforced when they are disabled.
+			if c.opts.IsNamespaceCheckerEnabled() {
+				if op := c.namespaceChecker.Check(region); op != nil {
+					return checkerIsBusy, []*operator.Operator{op}
+				}
+			}
 		}
 	}
 
@@ -147,6 +147,41 @@ func (f *storageThresholdFilter) Target(opt *config.PersistOptions, store *core.
 	return !store.IsLowSpace(opt.GetLowSpaceRatio())
 }
 
+type snapshotCountFilter struct{ scope string }
+
+// NewSnapshotCountFilter creates a Filter that filters all stores that are
+// already handling too many snapshots. Unlike StoreStateFilter, the receiving
+// and applying snapshots of a target store are counted together, so that the
+// operators requiring snapshots are spread out instead of piling up on the
+// same store, e.g. the newly added stores. Both filters share the boundary of
+// exceedSnapshotCount.
+func NewSnapshotCountFilter(scope string) Filter {
+	return &snapshotCountFilter{scope: scope}
+}
+
+func (f *snapshotCountFilter) Scope() string {
+	return f.scope
+}
+
+func (f *snapshotCountFilter) Type() string {
+	return "snapshot-count-filter"
+}
+
+func (f *snapshotCountFilter) Source(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !exceedSnapshotCount(opt, uint64(store.GetSendingSnapCount()))
+}
+
+func (f *snapshotCountFilter) Target(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !exceedSnapshotCount(opt, uint64(store.GetReceivingSnapCount())+uint64(store.GetApplyingSnapCount()))
+}
+
+// exceedSnapshotCount returns true if the snapshots are more than
+// max-snapshot-count, a store handling exactly max-snapshot-count snapshots
+// can still be selected.
+func exceedSnapshotCount(opt *config.PersistOptions, count uint64) bool {
+	return count > opt.GetMaxSnapshotCount()
+}
+
 // distinctScoreFilter ensures that distinct score will not decrease.
 type distinctScoreFilter struct {
 	scope     string
@@ -260,10 +295,34 @@ func (f StoreStateFilter) pauseLeaderTransfer(opt *config.PersistOptions, store
 	return !store.AllowLeaderTransfer()
 }
 
+func (f StoreStateFilter) isRestarting(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.IsRestarting()
+}
+
+func (f StoreStateFilter) isPaused(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !f.AllowTemporaryStates && store.IsSchedulingPaused()
+}
+
+func (f StoreStateFilter) isBackingOff(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !f.AllowTemporaryStates && store.IsBackingOff()
+}
+
+func (f StoreStateFilter) isCordoned(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.IsCordoned()
+}
+
+func (f StoreStateFilter) isDraining(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.IsDraining()
+}
+
 func (f StoreStateFilter) isDisconnected(opt *config.PersistOptions, store *core.StoreInfo) bool {
 	return !f.AllowTemporaryStates && store.IsDisconnected()
 }
 
+func (f StoreStateFilter) isHeartbeatStale(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.IsHeartbeatStale(opt.GetHeartbeatStalenessBound())
+}
+
 func (f StoreStateFilter) isBusy(opt *config.PersistOptions, store *core.StoreInfo) bool {
 	return !f.AllowTemporaryStates && store.IsBusy()
 }
@@ -276,10 +335,14 @@ func (f StoreStateFilter) exceedAddLimit(opt *config.PersistOptions, store *core
 	return !f.AllowTemporaryStates && !store.IsAvailable(storelimit.AddPeer)
 }
 
+func (f StoreStateFilter) exceedTransferLeaderLimit(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return !f.AllowTemporaryStates && !store.IsAvailable(storelimit.TransferLeader)
+}
+
 func (f StoreStateFilter) tooManySnapshots(opt *config.PersistOptions, store *core.StoreInfo) bool {
-	return !f.AllowTemporaryStates && (uint64(store.GetSendingSnapCount()) > opt.GetMaxSnapshotCount() ||
-		uint64(store.GetReceivingSnapCount()) > opt.GetMaxSnapshotCount() ||
-		uint64(store.GetApplyingSnapCount()) > opt.GetMaxSnapshotCount())
+	return !f.AllowTemporaryStates && (exceedSnapshotCount(opt, uint64(store.GetSendingSnapCount())) ||
+		exceedSnapshotCount(opt, uint64(store.GetReceivingSnapCount())) ||
+		exceedSnapshotCount(opt, uint64(store.GetApplyingSnapCount())))
 }
 
 func (f StoreStateFilter) tooManyPendingPeers(opt *config.PersistOptions, store *core.StoreInfo) bool {
@@ -297,13 +360,13 @@ func (f StoreStateFilter) hasRejectLeaderProperty(opts *config.PersistOptions, s
 // N: the condition is expected to be true for a long time.
 // X means when the condition is true, the store CANNOT be selected.
 //
-// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject
-// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N
+// Condition    Down Offline Tomb Pause Disconn Busy RmLimit AddLimit Snap Pending Reject Restart Paused Backoff Stale Cordon LdrLimit Drain
+// IsTemporary  N    N       N    N     Y       Y    Y       Y        Y    Y       N      Y       Y      Y       N     N      Y        N
 //
-// LeaderSource X            X    X     X
-// RegionSource                                 X    X                X
-// LeaderTarget X    X       X    X     X       X                                  X
-// RegionTarget X    X       X          X       X            X        X    X
+// LeaderSource X            X    X     X                                                  X              X
+// RegionSource                                 X    X                X                    X              X
+// LeaderTarget X    X       X    X     X       X                                  X      X       X      X       X     X      X        X
+// RegionTarget X    X       X          X       X            X        X    X              X       X      X       X     X               X
 
 const (
 	leaderSource = iota
@@ -316,15 +379,17 @@ func (f StoreStateFilter) anyConditionMatch(typ int, opt *config.PersistOptions,
 	var funcs []conditionFunc
 	switch typ {
 	case leaderSource:
-		funcs = []conditionFunc{f.isTombstone, f.isDown, f.pauseLeaderTransfer, f.isDisconnected}
+		funcs = []conditionFunc{f.isTombstone, f.isDown, f.pauseLeaderTransfer, f.isDisconnected, f.isPaused, f.isHeartbeatStale}
 	case regionSource:
-		funcs = []conditionFunc{f.isBusy, f.exceedRemoveLimit, f.tooManySnapshots}
+		funcs = []conditionFunc{f.isBusy, f.exceedRemoveLimit, f.tooManySnapshots, f.isPaused, f.isHeartbeatStale}
 	case leaderTarget:
 		funcs = []conditionFunc{f.isTombstone, f.isOffline, f.isDown, f.pauseLeaderTransfer,
-			f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty}
+			f.isDisconnected, f.isBusy, f.hasRejectLeaderProperty, f.isRestarting, f.isPaused, f.isBackingOff, f.isHeartbeatStale,
+			f.isCordoned, f.exceedTransferLeaderLimit, f.isDraining}
 	case regionTarget:
 		funcs = []conditionFunc{f.isTombstone, f.isOffline, f.isDown, f.isDisconnected, f.isBusy,
-			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers}
+			f.exceedAddLimit, f.tooManySnapshots, f.tooManyPendingPeers, f.isRestarting, f.isPaused, f.isBackingOff,
+			f.isHeartbeatStale, f.isCordoned, f.isDraining}
 	}
 	for _, cf := range funcs {
 		if cf(opt, store) {
@@ -528,6 +593,39 @@ func (f *engineFilter) Target(opt *config.PersistOptions, store *core.StoreInfo)
 	return f.constraint.MatchStore(store)
 }
 
+type labelGroupFilter struct {
+	scope string
+	key   string
+	value string
+}
+
+// NewLabelGroupFilter creates a filter that only keeps the stores with the
+// same value of the label as the given store. It is used to restrict the
+// balance in a group of stores, such as a zone.
+func NewLabelGroupFilter(scope, key string, store *core.StoreInfo) Filter {
+	return &labelGroupFilter{
+		scope: scope,
+		key:   key,
+		value: store.GetLabelValue(key),
+	}
+}
+
+func (f *labelGroupFilter) Scope() string {
+	return f.scope
+}
+
+func (f *labelGroupFilter) Type() string {
+	return "label-group-filter"
+}
+
+func (f *labelGroupFilter) Source(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.GetLabelValue(f.key) == f.value
+}
+
+func (f *labelGroupFilter) Target(opt *config.PersistOptions, store *core.StoreInfo) bool {
+	return store.GetLabelValue(f.key) == f.value
+}
+
 type ordinaryEngineFilter struct {
 	scope      string
 	constraint placement.LabelConstraint
@@ -604,6 +702,9 @@ const (
 	SpecialUseHotRegion = "hotRegion"
 	// SpecialUseReserved is the reserved value of special use label
 	SpecialUseReserved = "reserved"
+	// SpecialUseAnalytics is the analytics value of special use label, the
+	// stores with it only hold the observers of the regions.
+	SpecialUseAnalytics = "analytics"
 
 	// EngineKey is the label key used to indicate engine.
 	EngineKey = "engine"
@@ -611,7 +712,7 @@ const (
 	EngineTiFlash = "tiflash"
 )
 
-var allSpecialUses = []string{SpecialUseHotRegion, SpecialUseReserved}
+var allSpecialUses = []string{SpecialUseHotRegion, SpecialUseReserved, SpecialUseAnalytics}
 var allSpeicalEngines = []string{EngineTiFlash}
 
 type isolationFilter struct {
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/pkg/typeutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/placement"
@@ -179,6 +180,116 @@ func (s *testFiltersSuite) TestStoreStateFilter(c *C) {
 		{3, true, true},
 	}
 	check(store, testCases)
+
+	// Paused
+	store = store.Clone(core.SetStoreStats(&pdpb.StoreStats{})).
+		Clone(core.SetPauseDeadline(time.Now().Add(time.Minute)))
+	testCases = []testCase{
+		{0, false, false},
+		{1, false, false},
+		{2, false, false},
+		{3, true, true},
+	}
+	check(store, testCases)
+
+	// The pause is expired.
+	store = store.Clone(core.SetPauseDeadline(time.Now().Add(-time.Minute)))
+	testCases = []testCase{
+		{2, true, true},
+	}
+	check(store, testCases)
+
+	// Cordoned, the store is still a source.
+	store = store.Clone(core.SetCordon(time.Time{}))
+	testCases = []testCase{
+		{0, true, false},
+		{1, true, false},
+		{2, true, false},
+		{3, true, false},
+	}
+	check(store, testCases)
+
+	// The cordon is expired or cleared.
+	store = store.Clone(core.SetCordon(time.Now().Add(-time.Minute)))
+	testCases = []testCase{
+		{2, true, true},
+	}
+	check(store, testCases)
+	store = store.Clone(core.SetCordon(time.Now().Add(time.Minute)), core.ResetCordon())
+	check(store, testCases)
+
+	// Draining, the store is still a source.
+	store = store.Clone(core.SetDraining(true))
+	testCases = []testCase{
+		{0, true, false},
+		{1, true, false},
+		{2, true, false},
+		{3, true, false},
+	}
+	check(store, testCases)
+	store = store.Clone(core.SetDraining(false))
+	testCases = []testCase{
+		{2, true, true},
+	}
+	check(store, testCases)
+
+	// The heartbeats are stale.
+	cfg := opt.GetScheduleConfig().Clone()
+	cfg.HeartbeatStalenessBound = typeutil.NewDuration(time.Minute)
+	opt.SetScheduleConfig(cfg)
+	check(store, testCases)
+	store = store.Clone(core.SetLeaderCount(1), core.SetLastRegionHeartbeatTS(time.Now().Add(-2*time.Minute)))
+	testCases = []testCase{
+		{0, false, false},
+		{1, false, false},
+		{3, false, false},
+	}
+	check(store, testCases)
+	// The stores without leaders do not send region heartbeats.
+	store = store.Clone(core.SetLeaderCount(0))
+	testCases = []testCase{
+		{2, true, true},
+	}
+	check(store, testCases)
+	store = store.Clone(core.SetLastHeartbeatTS(time.Now().Add(-2 * time.Minute)))
+	testCases = []testCase{
+		{3, false, false},
+	}
+	check(store, testCases)
+}
+
+func (s *testFiltersSuite) TestSnapshotCountFilter(c *C) {
+	opt := config.NewTestOptions()
+	f := NewSnapshotCountFilter("")
+	store := core.NewStoreInfoWithLabel(1, 0, map[string]string{})
+
+	testCases := []struct {
+		sending, receiving, applying uint32
+		sourceRes, targetRes         bool
+	}{
+		{0, 0, 0, true, true},
+		// The stores at max-snapshot-count are still selected, the same as
+		// StoreStateFilter.
+		{3, 3, 0, true, true},
+		{4, 0, 0, false, true},
+		{0, 4, 0, true, false},
+		{0, 0, 4, true, false},
+		// The receiving and applying snapshots are counted together.
+		{0, 2, 1, true, true},
+		{0, 2, 2, true, false},
+	}
+	for _, tc := range testCases {
+		store = store.Clone(core.SetStoreStats(&pdpb.StoreStats{
+			SendingSnapCount:   tc.sending,
+			ReceivingSnapCount: tc.receiving,
+			ApplyingSnapCount:  tc.applying,
+		}))
+		c.Assert(f.Source(opt, store), Equals, tc.sourceRes)
+		c.Assert(f.Target(opt, store), Equals, tc.targetRes)
+		// StoreStateFilter agrees on the boundary of each count.
+		stateFilter := &StoreStateFilter{}
+		c.Assert(stateFilter.tooManySnapshots(opt, store), Equals, !tc.sourceRes || tc.receiving > 3 || tc.applying > 3)
+	}
 }
 
 func (s *testFiltersSuite) TestIsolationFilter(c *C) {
@@ -33,11 +33,27 @@ import (
 const (
 	heartbeatStreamKeepAliveInterval = time.Minute
 	heartbeatChanCapacity            = 1024
+	// heartbeatStreamStaleFactor is the number of bind intervals a stream can
+	// stay without being rebound or a store heartbeat before it is regarded as stale.
+	heartbeatStreamStaleFactor = 3
 )
 
+// syncMessage is a message whose sender waits for the result of sending.
+type syncMessage struct {
+	msg    *pdpb.RegionHeartbeatResponse
+	sentCh chan bool
+}
+
 type streamUpdate struct {
-	storeID uint64
-	stream  opt.HeartbeatStream
+	storeID  uint64
+	stream   opt.HeartbeatStream
+	bindTime time.Time
+}
+
+// streamInfo records the stream of a store and when it was bound last time.
+type streamInfo struct {
+	stream   opt.HeartbeatStream
+	bindTime time.Time
 }
 
 // HeartbeatStreams is the bridge of communication with TIKV instance.
@@ -46,32 +62,39 @@ type HeartbeatStreams struct {
 	hbStreamCtx    context.Context
 	hbStreamCancel context.CancelFunc
 	clusterID      uint64
-	streams        map[uint64]opt.HeartbeatStream
+	streams        map[uint64]*streamInfo
+	staleTimeout   time.Duration
 	msgCh          chan *pdpb.RegionHeartbeatResponse
+	syncMsgCh      chan syncMessage
 	streamCh       chan streamUpdate
 	storeInformer  core.StoreSetInformer
 	needRun        bool // For test only.
 }
 
 // NewHeartbeatStreams creates a new HeartbeatStreams which enable background running by default.
-func NewHeartbeatStreams(ctx context.Context, clusterID uint64, storeInformer core.StoreSetInformer) *HeartbeatStreams {
-	return newHbStreams(ctx, clusterID, storeInformer, true)
+// A stream whose store neither rebinds it nor sends a store heartbeat within several
+// bindInterval is regarded as stale and removed, so that it will be recreated by the next
+// region heartbeat of the store.
+func NewHeartbeatStreams(ctx context.Context, clusterID uint64, storeInformer core.StoreSetInformer, bindInterval time.Duration) *HeartbeatStreams {
+	return newHbStreams(ctx, clusterID, storeInformer, heartbeatStreamStaleFactor*bindInterval, true)
 }
 
 // NewTestHeartbeatStreams creates a new HeartbeatStreams for test purpose only.
 // Please use NewHeartbeatStreams for other usage.
 func NewTestHeartbeatStreams(ctx context.Context, clusterID uint64, storeInformer core.StoreSetInformer, needRun bool) *HeartbeatStreams {
-	return newHbStreams(ctx, clusterID, storeInformer, needRun)
+	return newHbStreams(ctx, clusterID, storeInformer, 0, needRun)
 }
 
-func newHbStreams(ctx context.Context, clusterID uint64, storeInformer core.StoreSetInformer, needRun bool) *HeartbeatStreams {
+func newHbStreams(ctx context.Context, clusterID uint64, storeInformer core.StoreSetInformer, staleTimeout time.Duration, needRun bool) *HeartbeatStreams {
 	hbStreamCtx, hbStreamCancel := context.WithCancel(ctx)
 	hs := &HeartbeatStreams{
 		hbStreamCtx:    hbStreamCtx,
 		hbStreamCancel: hbStreamCancel,
 		clusterID:      clusterID,
-		streams:        make(map[uint64]opt.HeartbeatStream),
+		streams:        make(map[uint64]*streamInfo),
+		staleTimeout:   staleTimeout,
 		msgCh:          make(chan *pdpb.RegionHeartbeatResponse, heartbeatChanCapacity),
+		syncMsgCh:      make(chan syncMessage),
 		streamCh:       make(chan streamUpdate, 1),
 		storeInformer:  storeInformer,
 		needRun:        needRun,
@@ -96,49 +119,28 @@ func (s *HeartbeatStreams) run() {
 	for {
 		select {
 		case update := <-s.streamCh:
-			s.streams[update.storeID] = update.stream
+			s.streams[update.storeID] = &streamInfo{stream: update.stream, bindTime: update.bindTime}
 		case msg := <-s.msgCh:
-			storeID := msg.GetTargetPeer().GetStoreId()
-			storeLabel := strconv.FormatUint(storeID, 10)
-			store := s.storeInformer.GetStore(storeID)
-			if store == nil {
-				log.Error("failed to get store",
-					zap.Uint64("region-id", msg.RegionId),
-					zap.Uint64("store-id", storeID), errs.ZapError(errs.ErrGetSourceStore))
-				delete(s.streams, storeID)
-				continue
-			}
-			storeAddress := store.GetAddress()
-			if stream, ok := s.streams[storeID]; ok {
-				if err := stream.Send(msg); err != nil {
-					log.Error("send heartbeat message fail",
-						zap.Uint64("region-id", msg.RegionId), errs.ZapError(errs.ErrGRPCSend.Wrap(err).GenWithStackByArgs()))
-					delete(s.streams, storeID)
-					heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "err").Inc()
-				} else {
-					heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "ok").Inc()
-				}
-			} else {
-				log.Debug("heartbeat stream not found, skip send message",
-					zap.Uint64("region-id", msg.RegionId),
-					zap.Uint64("store-id", storeID))
-				heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "skip").Inc()
-			}
+			heartbeatStreamQueueGauge.Set(float64(len(s.msgCh)))
+			s.send(msg)
+		case m := <-s.syncMsgCh:
+			m.sentCh <- s.send(m.msg)
 		case <-keepAliveTicker.C:
-			for storeID, stream := range s.streams {
+			s.removeStaleStreams(time.Now())
+			for storeID, info := range s.streams {
 				store := s.storeInformer.GetStore(storeID)
 				if store == nil {
 					log.Error("failed to get store", zap.Uint64("store-id", storeID), errs.ZapError(errs.ErrGetSourceStore))
-					delete(s.streams, storeID)
+					s.removeStream(storeID)
 					continue
 				}
 				storeAddress := store.GetAddress()
 				storeLabel := strconv.FormatUint(storeID, 10)
-				if err := stream.Send(keepAlive); err != nil {
+				if err := info.stream.Send(keepAlive); err != nil {
 					log.Warn("send keepalive message fail, store maybe disconnected",
 						zap.Uint64("target-store-id", storeID),
 						errs.ZapError(err))
-					delete(s.streams, storeID)
+					s.removeStream(storeID)
 					heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "keepalive", "err").Inc()
 				} else {
 					heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "keepalive", "ok").Inc()
@@ -150,6 +152,73 @@ func (s *HeartbeatStreams) run() {
 	}
 }
 
+// send sends the message to the store of the target peer, and returns if the
+// message is sent.
+func (s *HeartbeatStreams) send(msg *pdpb.RegionHeartbeatResponse) bool {
+	storeID := msg.GetTargetPeer().GetStoreId()
+	storeLabel := strconv.FormatUint(storeID, 10)
+	store := s.storeInformer.GetStore(storeID)
+	if store == nil {
+		log.Error("failed to get store",
+			zap.Uint64("region-id", msg.RegionId),
+			zap.Uint64("store-id", storeID), errs.ZapError(errs.ErrGetSourceStore))
+		s.removeStream(storeID)
+		return false
+	}
+	storeAddress := store.GetAddress()
+	info, ok := s.streams[storeID]
+	if !ok {
+		log.Debug("heartbeat stream not found, skip send message",
+			zap.Uint64("region-id", msg.RegionId),
+			zap.Uint64("store-id", storeID))
+		heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "skip").Inc()
+		return false
+	}
+	if err := info.stream.Send(msg); err != nil {
+		log.Error("send heartbeat message fail",
+			zap.Uint64("region-id", msg.RegionId), errs.ZapError(errs.ErrGRPCSend.Wrap(err).GenWithStackByArgs()))
+		s.removeStream(storeID)
+		heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "err").Inc()
+		return false
+	}
+	heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "push", "ok").Inc()
+	return true
+}
+
+// removeStaleStreams removes the streams of the stores which have neither rebound
+// the stream nor sent a store heartbeat for a long time. The stores whose regions
+// are idle may not rebind the stream, so the store heartbeats show they are still
+// alive, while a broken stream of an alive store is removed once sending fails.
+// The removed stream will be recreated by the next region heartbeat of the store.
+func (s *HeartbeatStreams) removeStaleStreams(now time.Time) {
+	for storeID, info := range s.streams {
+		lastActive := info.bindTime
+		var storeAddress string
+		if store := s.storeInformer.GetStore(storeID); store != nil {
+			storeAddress = store.GetAddress()
+			if ts := store.GetLastHeartbeatTS(); ts.After(lastActive) {
+				lastActive = ts
+			}
+		}
+		age := now.Sub(lastActive)
+		storeLabel := strconv.FormatUint(storeID, 10)
+		if s.staleTimeout <= 0 || age <= s.staleTimeout {
+			heartbeatStreamAgeGauge.WithLabelValues(storeLabel).Set(age.Seconds())
+			continue
+		}
+		log.Warn("heartbeat stream is stale, remove it",
+			zap.Uint64("store-id", storeID),
+			zap.Duration("age", age))
+		s.removeStream(storeID)
+		heartbeatStreamCounter.WithLabelValues(storeAddress, storeLabel, "keepalive", "stale").Inc()
+	}
+}
+
+func (s *HeartbeatStreams) removeStream(storeID uint64) {
+	delete(s.streams, storeID)
+	heartbeatStreamAgeGauge.DeleteLabelValues(strconv.FormatUint(storeID, 10))
+}
+
 // Close closes background running.
 func (s *HeartbeatStreams) Close() {
 	s.hbStreamCancel()
@@ -159,8 +228,9 @@ func (s *HeartbeatStreams) Close() {
 // BindStream binds a stream with a specified store.
 func (s *HeartbeatStreams) BindStream(storeID uint64, stream opt.HeartbeatStream) {
 	update := streamUpdate{
-		storeID: storeID,
-		stream:  stream,
+		storeID:  storeID,
+		stream:   stream,
+		bindTime: time.Now(),
 	}
 	select {
 	case s.streamCh <- update:
@@ -185,6 +255,33 @@ func (s *HeartbeatStreams) SendMsg(region *core.RegionInfo, msg *pdpb.RegionHear
 	}
 }
 
+// SendMsgAndWait sends a message to related store like SendMsg, but waits
+// until the message is sent. It returns false if the store has no stream or
+// the message fails to be sent.
+func (s *HeartbeatStreams) SendMsgAndWait(region *core.RegionInfo, msg *pdpb.RegionHeartbeatResponse) bool {
+	if region.GetLeader() == nil || !s.needRun {
+		return false
+	}
+
+	msg.Header = &pdpb.ResponseHeader{ClusterId: s.clusterID}
+	msg.RegionId = region.GetID()
+	msg.RegionEpoch = region.GetRegionEpoch()
+	msg.TargetPeer = region.GetLeader()
+
+	m := syncMessage{msg: msg, sentCh: make(chan bool, 1)}
+	select {
+	case s.syncMsgCh <- m:
+	case <-s.hbStreamCtx.Done():
+		return false
+	}
+	select {
+	case sent := <-m.sentCh:
+		return sent
+	case <-s.hbStreamCtx.Done():
+		return false
+	}
+}
+
 // SendErr sends a error message to related store.
 func (s *HeartbeatStreams) SendErr(errType pdpb.ErrorType, errMsg string, targetPeer *metapb.Peer) {
 	msg := &pdpb.RegionHeartbeatResponse{
@@ -16,6 +16,7 @@ package hbstream
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	. "github.com/pingcap/check"
@@ -26,6 +27,7 @@ import (
 	"github.com/tikv/pd/pkg/mock/mockhbstream"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
 )
 
 func TestHeaertbeatStreams(t *testing.T) {
@@ -77,3 +79,34 @@ func (s *testHeartbeatStreamSuite) TestActivity(c *C) {
 		return stream1.Recv() != nil && stream2.Recv() == nil
 	})
 }
+
+func (s *testHeartbeatStreamSuite) TestRemoveStaleStreams(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cluster := mockcluster.NewCluster(config.NewTestOptions())
+	now := time.Now()
+	for id := uint64(1); id <= 3; id++ {
+		cluster.AddRegionStore(id, 1)
+	}
+	// Store 3 stops sending heartbeats.
+	cluster.PutStore(cluster.GetStore(3).Clone(core.SetLastHeartbeatTS(now.Add(-2 * time.Minute))))
+
+	hbs := newHbStreams(ctx, cluster.ID, cluster, time.Minute, false)
+	hbs.streams[1] = &streamInfo{stream: mockhbstream.NewHeartbeatStream(), bindTime: now.Add(-30 * time.Second)}
+	hbs.streams[2] = &streamInfo{stream: mockhbstream.NewHeartbeatStream(), bindTime: now.Add(-2 * time.Minute)}
+	hbs.streams[3] = &streamInfo{stream: mockhbstream.NewHeartbeatStream(), bindTime: now.Add(-2 * time.Minute)}
+
+	// The stream of the store which still sends heartbeats is kept even if it is
+	// not rebound, only the one of the silent store is removed.
+	hbs.removeStaleStreams(now)
+	c.Assert(hbs.streams, HasLen, 2)
+	c.Assert(hbs.streams[1], NotNil)
+	c.Assert(hbs.streams[2], NotNil)
+
+	// A stream never becomes stale if the timeout is not set.
+	hbs = newHbStreams(ctx, cluster.ID, cluster, 0, false)
+	hbs.streams[2] = &streamInfo{stream: mockhbstream.NewHeartbeatStream(), bindTime: now.Add(-time.Hour)}
+	hbs.removeStaleStreams(now)
+	c.Assert(hbs.streams, HasLen, 1)
+}
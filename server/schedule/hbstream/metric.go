@@ -23,8 +23,26 @@ var (
 			Name:      "region_message",
 			Help:      "Counter of message hbstream sent.",
 		}, []string{"address", "store", "type", "status"})
+
+	heartbeatStreamAgeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "hbstream",
+			Name:      "stream_age_seconds",
+			Help:      "The time since the heartbeat stream of the store was bound or the store sent a heartbeat last time.",
+		}, []string{"store"})
+
+	heartbeatStreamQueueGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "hbstream",
+			Name:      "send_queue_length",
+			Help:      "The length of the queue of messages waiting to be sent.",
+		})
 )
 
 func init() {
 	prometheus.MustRegister(heartbeatStreamCounter)
+	prometheus.MustRegister(heartbeatStreamAgeGauge)
+	prometheus.MustRegister(heartbeatStreamQueueGauge)
 }
@@ -73,11 +73,20 @@ var (
 			Name:      "store_limit_cost",
 			Help:      "limit rate cost of store.",
 		}, []string{"store", "limit_type"})
+
+	storeBackoffCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "pd",
+			Subsystem: "schedule",
+			Name:      "store_backoff_count",
+			Help:      "Counter of stores backed off because operators keep failing.",
+		}, []string{"store"})
 )
 
 func init() {
 	prometheus.MustRegister(operatorCounter)
 	prometheus.MustRegister(operatorDuration)
+	prometheus.MustRegister(storeBackoffCounter)
 	prometheus.MustRegister(operatorWaitDuration)
 	prometheus.MustRegister(storeLimitAvailableGauge)
 	prometheus.MustRegister(storeLimitRateGauge)
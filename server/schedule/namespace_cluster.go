@@ -0,0 +1,190 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/statistics"
+)
+
+// NamespaceLabelKey is the store label key that assigns a store to a namespace.
+const NamespaceLabelKey = "namespace"
+
+// NamespaceCluster isolates the cluster by namespace. A store belongs to a
+// namespace if its namespace label matches, and a region belongs to a
+// namespace if all of its peers are on the stores of the namespace.
+type NamespaceCluster struct {
+	opt.Cluster
+	namespace string
+	stores    map[uint64]struct{}
+}
+
+// GenNamespaceCluster gets a cluster that can only know the stores and the
+// regions of the namespace.
+func GenNamespaceCluster(cluster opt.Cluster, namespace string) *NamespaceCluster {
+	stores := make(map[uint64]struct{})
+	for _, s := range cluster.GetStores() {
+		if s.GetLabelValue(NamespaceLabelKey) == namespace {
+			stores[s.GetID()] = struct{}{}
+		}
+	}
+	return &NamespaceCluster{
+		Cluster:   cluster,
+		namespace: namespace,
+		stores:    stores,
+	}
+}
+
+// GetNamespace returns the namespace of the cluster.
+func (n *NamespaceCluster) GetNamespace() string {
+	return n.namespace
+}
+
+func (n *NamespaceCluster) inNamespace(storeID uint64) bool {
+	_, ok := n.stores[storeID]
+	return ok
+}
+
+// regionInNamespace checks if all peers of the region are in the namespace.
+func (n *NamespaceCluster) regionInNamespace(region *core.RegionInfo) bool {
+	for _, p := range region.GetPeers() {
+		if !n.inNamespace(p.GetStoreId()) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStores returns the stores in the namespace.
+func (n *NamespaceCluster) GetStores() []*core.StoreInfo {
+	return n.filterStores(n.Cluster.GetStores())
+}
+
+// GetStore returns the store if it is in the namespace.
+func (n *NamespaceCluster) GetStore(id uint64) *core.StoreInfo {
+	if !n.inNamespace(id) {
+		return nil
+	}
+	return n.Cluster.GetStore(id)
+}
+
+// GetRegionStores returns the stores in the namespace which have a peer of the region.
+func (n *NamespaceCluster) GetRegionStores(region *core.RegionInfo) []*core.StoreInfo {
+	return n.filterStores(n.Cluster.GetRegionStores(region))
+}
+
+// GetFollowerStores returns the stores in the namespace which have a follower of the region.
+func (n *NamespaceCluster) GetFollowerStores(region *core.RegionInfo) []*core.StoreInfo {
+	return n.filterStores(n.Cluster.GetFollowerStores(region))
+}
+
+// GetLeaderStore returns the store of the region leader if it is in the namespace.
+func (n *NamespaceCluster) GetLeaderStore(region *core.RegionInfo) *core.StoreInfo {
+	store := n.Cluster.GetLeaderStore(region)
+	if store == nil || !n.inNamespace(store.GetID()) {
+		return nil
+	}
+	return store
+}
+
+func (n *NamespaceCluster) filterStores(stores []*core.StoreInfo) []*core.StoreInfo {
+	ret := make([]*core.StoreInfo, 0, len(stores))
+	for _, s := range stores {
+		if n.inNamespace(s.GetID()) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// GetRegion returns the region if it belongs to the namespace.
+func (n *NamespaceCluster) GetRegion(id uint64) *core.RegionInfo {
+	region := n.Cluster.GetRegion(id)
+	if region == nil || !n.regionInNamespace(region) {
+		return nil
+	}
+	return region
+}
+
+// RandFollowerRegion returns a random region of the namespace that has a follower on the store.
+func (n *NamespaceCluster) RandFollowerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !n.inNamespace(storeID) {
+		return nil
+	}
+	return n.Cluster.RandFollowerRegion(storeID, ranges, append(opts, n.regionInNamespace)...)
+}
+
+// RandLeaderRegion returns a random region of the namespace that has leader on the store.
+func (n *NamespaceCluster) RandLeaderRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !n.inNamespace(storeID) {
+		return nil
+	}
+	return n.Cluster.RandLeaderRegion(storeID, ranges, append(opts, n.regionInNamespace)...)
+}
+
+// RandLearnerRegion returns a random region of the namespace that has a learner peer on the store.
+func (n *NamespaceCluster) RandLearnerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !n.inNamespace(storeID) {
+		return nil
+	}
+	return n.Cluster.RandLearnerRegion(storeID, ranges, append(opts, n.regionInNamespace)...)
+}
+
+// RandPendingRegion returns a random region of the namespace that has a pending peer on the store.
+func (n *NamespaceCluster) RandPendingRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !n.inNamespace(storeID) {
+		return nil
+	}
+	return n.Cluster.RandPendingRegion(storeID, ranges, append(opts, n.regionInNamespace)...)
+}
+
+// RandHotRegionFromStore randomly picks a hot region of the namespace in specified store.
+func (n *NamespaceCluster) RandHotRegionFromStore(store uint64, kind statistics.FlowKind) *core.RegionInfo {
+	if !n.inNamespace(store) {
+		return nil
+	}
+	region := n.Cluster.RandHotRegionFromStore(store, kind)
+	if region == nil || !n.regionInNamespace(region) {
+		return nil
+	}
+	return region
+}
+
+// RegionWriteStats returns the write stats of the hot peers in the namespace.
+func (n *NamespaceCluster) RegionWriteStats() map[uint64][]*statistics.HotPeerStat {
+	return n.filterHotPeers(n.Cluster.RegionWriteStats())
+}
+
+// RegionReadStats returns the read stats of the hot peers in the namespace.
+func (n *NamespaceCluster) RegionReadStats() map[uint64][]*statistics.HotPeerStat {
+	return n.filterHotPeers(n.Cluster.RegionReadStats())
+}
+
+func (n *NamespaceCluster) filterHotPeers(stats map[uint64][]*statistics.HotPeerStat) map[uint64][]*statistics.HotPeerStat {
+	ret := make(map[uint64][]*statistics.HotPeerStat, len(stats))
+	for storeID, peers := range stats {
+		if !n.inNamespace(storeID) {
+			continue
+		}
+		filtered := make([]*statistics.HotPeerStat, 0, len(peers))
+		for _, peer := range peers {
+			if region := n.Cluster.GetRegion(peer.RegionID); region != nil && n.regionInNamespace(region) {
+				filtered = append(filtered, peer)
+			}
+		}
+		ret[storeID] = filtered
+	}
+	return ret
+}
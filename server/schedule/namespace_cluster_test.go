@@ -0,0 +1,87 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/pkg/mock/mockcluster"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+)
+
+var _ = Suite(&testNamespaceClusterSuite{})
+
+type testNamespaceClusterSuite struct{}
+
+func (s *testNamespaceClusterSuite) TestNamespaceCluster(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	for id := uint64(1); id <= 3; id++ {
+		tc.AddLabelsStore(id, 0, map[string]string{NamespaceLabelKey: "analytics"})
+	}
+	tc.AddLabelsStore(4, 0, map[string]string{})
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 4, 1, 2)
+
+	nc := GenNamespaceCluster(tc, "analytics")
+	c.Assert(nc.GetNamespace(), Equals, "analytics")
+	c.Assert(nc.GetStores(), HasLen, 3)
+	c.Assert(nc.GetRegion(1), NotNil)
+	c.Assert(nc.GetRegion(2), IsNil)
+	ranges := []core.KeyRange{core.NewKeyRange("", "")}
+	c.Assert(nc.RandLeaderRegion(1, ranges).GetID(), Equals, uint64(1))
+	c.Assert(nc.RandLeaderRegion(4, ranges), IsNil)
+	// Region 2 has a follower on store 1 but it is not in the namespace.
+	c.Assert(tc.RandFollowerRegion(1, ranges), NotNil)
+	c.Assert(nc.RandFollowerRegion(1, ranges), IsNil)
+	// The stores outside the namespace are hidden.
+	c.Assert(nc.GetStore(1), NotNil)
+	c.Assert(nc.GetStore(4), IsNil)
+	c.Assert(nc.GetFollowerStores(tc.GetRegion(2)), HasLen, 2)
+	c.Assert(nc.GetRegionStores(tc.GetRegion(2)), HasLen, 2)
+	c.Assert(nc.GetLeaderStore(tc.GetRegion(2)), IsNil)
+	c.Assert(nc.GetLeaderStore(tc.GetRegion(1)).GetID(), Equals, uint64(1))
+
+	// No store is in the namespace.
+	nc = GenNamespaceCluster(tc, "oltp")
+	c.Assert(nc.GetStores(), HasLen, 0)
+	c.Assert(nc.RandLeaderRegion(1, ranges), IsNil)
+}
+
+func (s *testNamespaceClusterSuite) TestStoreListCluster(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	for id := uint64(1); id <= 4; id++ {
+		tc.PutStoreWithLabels(id)
+	}
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 4, 1, 2)
+	ranges := []core.KeyRange{core.NewKeyRange("", "")}
+
+	list := &StoreList{}
+	c.Assert(list.IsEmpty(), IsTrue)
+	c.Assert(list.IsAllowed(1), IsTrue)
+
+	lc := GenStoreListCluster(tc, &StoreList{Deny: []uint64{4}})
+	c.Assert(lc.GetStores(), HasLen, 3)
+	c.Assert(lc.RandLeaderRegion(4, ranges), IsNil)
+	// The regions with a peer on the denied store are still visible.
+	c.Assert(lc.GetRegion(2), NotNil)
+	c.Assert(lc.RandFollowerRegion(1, ranges), NotNil)
+	c.Assert(lc.GetFollowerStores(tc.GetRegion(2)), HasLen, 2)
+
+	lc = GenStoreListCluster(tc, &StoreList{Allow: []uint64{1, 2, 4}, Deny: []uint64{4}})
+	c.Assert(lc.GetStores(), HasLen, 2)
+	c.Assert(lc.RandLeaderRegion(1, ranges).GetID(), Equals, uint64(1))
+	c.Assert(lc.RandFollowerRegion(3, ranges), IsNil)
+	c.Assert(lc.GetFollowerStores(tc.GetRegion(1)), HasLen, 1)
+}
@@ -57,6 +57,7 @@ type Builder struct {
 	// build flags
 	allowDemote       bool
 	useJointConsensus bool
+	useLearner        bool
 	lightWeight       bool
 	forceTargetLeader bool
 
@@ -147,6 +148,7 @@ func NewBuilder(desc string, cluster opt.Cluster, region *core.RegionInfo, opts
 	b.targetPeers = originPeers.Copy()
 	b.allowDemote = supportJointConsensus
 	b.useJointConsensus = supportJointConsensus && cluster.GetOpts().IsUseJointConsensus()
+	b.useLearner = cluster.IsFeatureSupported(versioninfo.Learner)
 	b.err = err
 	return b
 }
@@ -604,13 +606,22 @@ func (b *Builder) execDemoteFollower(peer *metapb.Peer) {
 }
 
 func (b *Builder) execAddPeer(peer *metapb.Peer) {
-	if b.lightWeight {
-		b.steps = append(b.steps, AddLightLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+	// Stores which do not support learner can only add a voter directly.
+	if !b.useLearner && !core.IsLearner(peer) {
+		if b.lightWeight {
+			b.steps = append(b.steps, AddLightPeer{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		} else {
+			b.steps = append(b.steps, AddPeer{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		}
 	} else {
-		b.steps = append(b.steps, AddLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
-	}
-	if !core.IsLearner(peer) {
-		b.steps = append(b.steps, PromoteLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		if b.lightWeight {
+			b.steps = append(b.steps, AddLightLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		} else {
+			b.steps = append(b.steps, AddLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		}
+		if !core.IsLearner(peer) {
+			b.steps = append(b.steps, PromoteLearner{ToStore: peer.GetStoreId(), PeerID: peer.GetId()})
+		}
 	}
 	b.currentPeers.Set(peer)
 	b.peerAddStep[peer.GetStoreId()] = len(b.steps)
@@ -21,6 +21,7 @@ import (
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/versioninfo"
 )
 
 var _ = Suite(&testBuilderSuite{})
@@ -444,6 +445,26 @@ func (s *testBuilderSuite) TestBuild(c *C) {
 	}
 }
 
+func (s *testBuilderSuite) TestBuildWithoutLearner(c *C) {
+	s.cluster.DisableFeature(versioninfo.Learner, versioninfo.JointConsensus)
+	peers := []*metapb.Peer{{Id: 11, StoreId: 1}, {Id: 12, StoreId: 2}}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	op, err := NewBuilder("test", s.cluster, region).
+		AddPeer(&metapb.Peer{Id: 13, StoreId: 3}).
+		Build(0)
+	c.Assert(err, IsNil)
+	c.Assert(op.Len(), Equals, 1)
+	c.Assert(op.Step(0), DeepEquals, AddPeer{ToStore: 3, PeerID: 13})
+
+	// A learner can still be added if it is required explicitly.
+	op, err = NewBuilder("test", s.cluster, region).
+		AddPeer(&metapb.Peer{Id: 13, StoreId: 3, Role: metapb.PeerRole_Learner}).
+		Build(0)
+	c.Assert(err, IsNil)
+	c.Assert(op.Len(), Equals, 1)
+	c.Assert(op.Step(0), DeepEquals, AddLearner{ToStore: 3, PeerID: 13})
+}
+
 // Test for issue 3039
 func (s *testBuilderSuite) TestPromoteUnhealthyPeer(c *C) {
 	p := &metapb.Peer{Id: 2, StoreId: 2, Role: metapb.PeerRole_Learner}
@@ -17,6 +17,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
@@ -68,6 +69,24 @@ func CreateMoveRegionOperator(desc string, cluster opt.Cluster, region *core.Reg
 		Build(kind)
 }
 
+// CreateReplacePeersOperator creates an operator that replaces several old peers
+// with new peers at once. The keys of replacements are the stores of the old peers.
+func CreateReplacePeersOperator(desc string, cluster opt.Cluster, region *core.RegionInfo, kind OpKind, replacements map[uint64]*metapb.Peer) (*Operator, error) {
+	oldStores := make([]uint64, 0, len(replacements))
+	for storeID := range replacements {
+		oldStores = append(oldStores, storeID)
+	}
+	sort.Slice(oldStores, func(i, j int) bool { return oldStores[i] < oldStores[j] })
+	b := NewBuilder(desc, cluster, region)
+	for _, storeID := range oldStores {
+		b.RemovePeer(storeID)
+	}
+	for _, storeID := range oldStores {
+		b.AddPeer(replacements[storeID])
+	}
+	return b.Build(kind)
+}
+
 // CreateMovePeerOperator creates an operator that replaces an old peer with a new peer.
 func CreateMovePeerOperator(desc string, cluster opt.Cluster, region *core.RegionInfo, kind OpKind, oldStore uint64, peer *metapb.Peer) (*Operator, error) {
 	return NewBuilder(desc, cluster, region).
@@ -20,6 +20,7 @@ import (
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/versioninfo"
 )
 
 var _ = Suite(&testCreateOperatorSuite{})
@@ -142,3 +143,51 @@ func (s *testCreateOperatorSuite) TestCreateLeaveJointStateOperator(c *C) {
 		}
 	}
 }
+
+func (s *testCreateOperatorSuite) TestCreateReplacePeersOperator(c *C) {
+	s.cluster.DisableFeature(versioninfo.JointConsensus)
+	peers := []*metapb.Peer{
+		{Id: 1, StoreId: 1, Role: metapb.PeerRole_Voter},
+		{Id: 2, StoreId: 2, Role: metapb.PeerRole_Voter},
+		{Id: 3, StoreId: 3, Role: metapb.PeerRole_Voter},
+	}
+	region := core.NewRegionInfo(&metapb.Region{Id: 1, Peers: peers}, peers[0])
+	op, err := CreateReplacePeersOperator("test", s.cluster, region, OpReplica, map[uint64]*metapb.Peer{
+		1: {StoreId: 6, Role: metapb.PeerRole_Voter},
+		2: {StoreId: 8, Role: metapb.PeerRole_Voter},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(op.Kind()&OpRegion, Equals, OpRegion)
+
+	// The voters are never fewer than before, and the leader is transferred
+	// before its peer is removed.
+	voters, leader := 3, uint64(1)
+	added := make(map[uint64]bool)
+	removed := make(map[uint64]bool)
+	for i := 0; i < op.Len(); i++ {
+		switch step := op.Step(i).(type) {
+		case AddPeer:
+			added[step.ToStore] = true
+			voters++
+		case AddLearner:
+			added[step.ToStore] = true
+		case PromoteLearner:
+			voters++
+		case RemovePeer:
+			c.Assert(step.FromStore, Not(Equals), leader)
+			removed[step.FromStore] = true
+			voters--
+		case TransferLeader:
+			leader = step.ToStore
+		}
+		c.Assert(voters >= 3, IsTrue)
+	}
+	c.Assert(added, DeepEquals, map[uint64]bool{6: true, 8: true})
+	c.Assert(removed, DeepEquals, map[uint64]bool{1: true, 2: true})
+
+	// The old peer must exist.
+	_, err = CreateReplacePeersOperator("test", s.cluster, region, OpReplica, map[uint64]*metapb.Peer{
+		4: {StoreId: 6, Role: metapb.PeerRole_Voter},
+	})
+	c.Assert(err, NotNil)
+}
@@ -39,7 +39,9 @@ type StoreInfluence struct {
 	RegionCount int64
 	LeaderSize  int64
 	LeaderCount int64
-	StepCost    map[storelimit.Type]int64
+	// ByteRate is the read and written bytes per second of the moving regions.
+	ByteRate float64
+	StepCost map[storelimit.Type]int64
 }
 
 // ResourceProperty returns delta size of leader/region by influence.
@@ -70,16 +70,28 @@ func (k OpKind) String() string {
 	return strings.Join(flagNames, ",")
 }
 
+// OpKindNames returns the canonical names of all the operator kinds, in the
+// order of their flags.
+func OpKindNames() []string {
+	names := make([]string, 0, len(flagToName))
+	for flag := OpKind(1); flag < opMax; flag <<= 1 {
+		names = append(names, flagToName[flag])
+	}
+	return names
+}
+
 // ParseOperatorKind converts string (flag name list concat by ',') to OpKind.
+// The spaces around the names are ignored, and the names can be in any order,
+// so it is the reverse of String.
 func ParseOperatorKind(str string) (OpKind, error) {
 	var k OpKind
 	for _, flagName := range strings.Split(str, ",") {
+		flagName = strings.TrimSpace(flagName)
 		flag, ok := nameToFlag[flagName]
 		if !ok {
-			return 0, errors.Errorf("unknown flag name: %s", flagName)
+			return 0, errors.Errorf("unknown flag name: %q, it should be one of %s", flagName, strings.Join(OpKindNames(), ", "))
 		}
 		k |= flag
 	}
 	return k, nil
-
 }
@@ -53,6 +53,10 @@ type Operator struct {
 	Counters         []prometheus.Counter
 	FinishedCounters []prometheus.Counter
 	AdditionalInfos  map[string]string
+	// idempotencyKey is supplied by the client that creates the operator
+	// manually, which is used to avoid creating duplicate operators when
+	// the request is retried.
+	idempotencyKey atomic.Value
 }
 
 // NewOperator creates a new operator.
@@ -81,6 +85,9 @@ func (o *Operator) String() string {
 		stepStrs[i] = o.steps[i].String()
 	}
 	s := fmt.Sprintf("%s {%s} (kind:%s, region:%v(%v,%v), createAt:%s, startAt:%s, currentStep:%v, steps:[%s])", o.desc, o.brief, o.kind, o.regionID, o.regionEpoch.GetVersion(), o.regionEpoch.GetConfVer(), o.GetCreateTime(), o.GetStartTime(), atomic.LoadInt32(&o.currentStep), strings.Join(stepStrs, ", "))
+	if key := o.GetIdempotencyKey(); key != "" {
+		s = s + " idempotency-key:" + key
+	}
 	if o.CheckSuccess() {
 		s = s + " finished"
 	}
@@ -95,6 +102,17 @@ func (o *Operator) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + o.String() + `"`), nil
 }
 
+// SetIdempotencyKey sets the idempotency key of the operator.
+func (o *Operator) SetIdempotencyKey(key string) {
+	o.idempotencyKey.Store(key)
+}
+
+// GetIdempotencyKey returns the idempotency key of the operator.
+func (o *Operator) GetIdempotencyKey() string {
+	key, _ := o.idempotencyKey.Load().(string)
+	return key
+}
+
 // Desc returns the operator's short description.
 func (o *Operator) Desc() string {
 	return o.desc
@@ -168,6 +186,30 @@ func (o *Operator) RunningTime() time.Duration {
 	return 0
 }
 
+// SnapshotDuration returns the time taken by the finished steps which add peers. As
+// these steps finish once the new peers are no longer pending, it is mostly spent on
+// generating, sending and applying the snapshots.
+func (o *Operator) SnapshotDuration() time.Duration {
+	var total time.Duration
+	for i, step := range o.steps {
+		finish := atomic.LoadInt64(&(o.stepsTime[i]))
+		if finish == 0 {
+			break
+		}
+		switch step.(type) {
+		case AddPeer, AddLearner, AddLightPeer, AddLightLearner:
+		default:
+			continue
+		}
+		start := o.GetStartTime()
+		if i > 0 {
+			start = time.Unix(0, atomic.LoadInt64(&(o.stepsTime[i-1])))
+		}
+		total += time.Unix(0, finish).Sub(start)
+	}
+	return total
+}
+
 // IsEnd checks if the operator is at and end status.
 func (o *Operator) IsEnd() bool {
 	return o.status.IsEnd()
@@ -288,6 +330,74 @@ func (o *Operator) TotalInfluence(opInfluence OpInfluence, region *core.RegionIn
 	}
 }
 
+// StepDetail is the detail of an operator step.
+type StepDetail struct {
+	Step string `json:"step"`
+	// StartTime and FinishTime are nil if the step is not started or finished.
+	StartTime  *time.Time `json:"start-time,omitempty"`
+	FinishTime *time.Time `json:"finish-time,omitempty"`
+}
+
+// Detail is the structured detail of an operator, which is used to track the
+// progress of the operator.
+type Detail struct {
+	Desc            string            `json:"desc"`
+	Brief           string            `json:"brief"`
+	Kind            string            `json:"kind"`
+	RegionID        uint64            `json:"region-id"`
+	Status          string            `json:"status"`
+	CreateTime      time.Time         `json:"create-time"`
+	StartTime       *time.Time        `json:"start-time,omitempty"`
+	CurrentStep     int               `json:"current-step"`
+	Steps           []StepDetail      `json:"steps"`
+	AdditionalInfos map[string]string `json:"additional-infos,omitempty"`
+	// Influence is the influence on the stores made by the unfinished steps.
+	Influence map[uint64]*StoreInfluence `json:"influence"`
+}
+
+// GetDetail returns the detail of the operator. The influence is computed
+// against the region, and it is empty if the region is nil.
+func (o *Operator) GetDetail(region *core.RegionInfo) *Detail {
+	detail := &Detail{
+		Desc:            o.desc,
+		Brief:           o.brief,
+		Kind:            o.kind.String(),
+		RegionID:        o.regionID,
+		Status:          OpStatusToString(o.Status()),
+		CreateTime:      o.GetCreateTime(),
+		CurrentStep:     int(atomic.LoadInt32(&o.currentStep)),
+		Steps:           make([]StepDetail, len(o.steps)),
+		AdditionalInfos: make(map[string]string, len(o.AdditionalInfos)),
+	}
+	for k, v := range o.AdditionalInfos {
+		detail.AdditionalInfos[k] = v
+	}
+	var lastFinish *time.Time
+	if o.HasStarted() {
+		startTime := o.GetStartTime()
+		detail.StartTime = &startTime
+		lastFinish = &startTime
+	}
+	for i, step := range o.steps {
+		detail.Steps[i].Step = step.String()
+		// A step starts when the previous step finishes.
+		detail.Steps[i].StartTime = lastFinish
+		if finish := atomic.LoadInt64(&o.stepsTime[i]); finish != 0 {
+			finishTime := time.Unix(0, finish)
+			detail.Steps[i].FinishTime = &finishTime
+			lastFinish = &finishTime
+		} else {
+			lastFinish = nil
+		}
+	}
+	opInfluence := OpInfluence{StoresInfluence: make(map[uint64]*StoreInfluence)}
+	if region != nil {
+		o.UnfinishedInfluence(opInfluence, region)
+	}
+	detail.Influence = opInfluence.StoresInfluence
+	return detail
+}
+
 // OpHistory is used to log and visualize completed operators.
 type OpHistory struct {
 	FinishTime time.Time
@@ -172,7 +172,7 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000, storelimit.TransferLeader: 1000},
 	})
 
 	RemovePeer{FromStore: 1}.Influence(opInfluence, region)
@@ -188,7 +188,7 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000, storelimit.TransferLeader: 1000},
 	})
 
 	MergeRegion{IsPassive: false}.Influence(opInfluence, region)
@@ -204,7 +204,7 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 1,
-		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000, storelimit.TransferLeader: 1000},
 	})
 
 	MergeRegion{IsPassive: true}.Influence(opInfluence, region)
@@ -220,10 +220,53 @@ func (s *testOperatorSuite) TestInfluence(c *C) {
 		LeaderCount: 1,
 		RegionSize:  50,
 		RegionCount: 0,
-		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000},
+		StepCost:    map[storelimit.Type]int64{storelimit.AddPeer: 1000, storelimit.TransferLeader: 1000},
 	})
 }
 
+func (s *testOperatorSuite) TestDetail(c *C) {
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
+	steps := []OpStep{
+		AddPeer{ToStore: 3, PeerID: 3},
+		TransferLeader{FromStore: 1, ToStore: 3},
+		RemovePeer{FromStore: 1},
+	}
+	op := s.newTestOperator(1, OpRegion|OpLeader, steps...)
+	op.AdditionalInfos["reason"] = "test"
+
+	detail := op.GetDetail(region)
+	c.Assert(detail.RegionID, Equals, uint64(1))
+	c.Assert(detail.Status, Equals, "Created")
+	c.Assert(detail.StartTime, IsNil)
+	c.Assert(detail.CurrentStep, Equals, 0)
+	c.Assert(detail.Steps, HasLen, 3)
+	c.Assert(detail.Steps[0].Step, Equals, steps[0].String())
+	c.Assert(detail.Steps[0].StartTime, IsNil)
+	c.Assert(detail.AdditionalInfos, DeepEquals, map[string]string{"reason": "test"})
+	c.Assert(detail.Influence[3].RegionCount, Equals, int64(1))
+	c.Assert(detail.Influence[1].RegionCount, Equals, int64(-1))
+
+	// The first step is started with the operator, and the next step is
+	// started when the first step is finished.
+	c.Assert(op.Start(), IsTrue)
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: 3, StoreId: 3}))
+	op.Check(region)
+	detail = op.GetDetail(region)
+	c.Assert(detail.Status, Equals, "Started")
+	c.Assert(detail.CurrentStep, Equals, 1)
+	c.Assert(*detail.Steps[0].StartTime, Equals, op.GetStartTime())
+	c.Assert(detail.Steps[0].FinishTime, NotNil)
+	c.Assert(*detail.Steps[1].StartTime, Equals, *detail.Steps[0].FinishTime)
+	c.Assert(detail.Steps[1].FinishTime, IsNil)
+	c.Assert(detail.Steps[2].StartTime, IsNil)
+	// Only the unfinished steps make influence.
+	c.Assert(detail.Influence[3].RegionCount, Equals, int64(0))
+	c.Assert(detail.Influence[1].RegionCount, Equals, int64(-1))
+
+	// No influence without the region.
+	c.Assert(op.GetDetail(nil).Influence, HasLen, 0)
+}
+
 func (s *testOperatorSuite) TestOperatorKind(c *C) {
 	c.Assert((OpLeader | OpReplica).String(), Equals, "leader,replica")
 	c.Assert(OpKind(0).String(), Equals, "unknown")
@@ -234,6 +277,24 @@ func (s *testOperatorSuite) TestOperatorKind(c *C) {
 	c.Assert(err, IsNil)
 	_, err = ParseOperatorKind("foobar")
 	c.Assert(err, NotNil)
+	_, err = ParseOperatorKind("")
+	c.Assert(err, NotNil)
+	k, err = ParseOperatorKind(" admin, hot-region ")
+	c.Assert(err, IsNil)
+	c.Assert(k, Equals, OpAdmin|OpHotRegion)
+
+	// All the kinds can be parsed from their names.
+	all := OpKind(0)
+	for _, name := range OpKindNames() {
+		k, err = ParseOperatorKind(name)
+		c.Assert(err, IsNil)
+		c.Assert(k.String(), Equals, name)
+		all |= k
+	}
+	c.Assert(all, Equals, opMax-1)
+	k, err = ParseOperatorKind(all.String())
+	c.Assert(err, IsNil)
+	c.Assert(k, Equals, all)
 }
 
 func (s *testOperatorSuite) TestCheckSuccess(c *C) {
@@ -374,3 +435,53 @@ func (s *testOperatorSuite) TestCheck(c *C) {
 		c.Assert(op.Status(), Equals, SUCCESS)
 	}
 }
+
+func (s *testOperatorSuite) TestValidatePlan(c *C) {
+	region := s.newTestRegion(1, 1, [2]uint64{1, 1}, [2]uint64{2, 2})
+	s.cluster.SetStoreOffline(5)
+	s.cluster.SetStoreDown(6)
+
+	testCases := []struct {
+		steps []OpStep
+		valid bool
+	}{
+		{[]OpStep{AddLearner{ToStore: 3, PeerID: 3}, PromoteLearner{ToStore: 3, PeerID: 3}, TransferLeader{FromStore: 1, ToStore: 3}, RemovePeer{FromStore: 1, PeerID: 1}}, true},
+		{[]OpStep{AddLearner{ToStore: 3, PeerID: 3}, ChangePeerV2Enter{
+			PromoteLearners: []PromoteLearner{{ToStore: 3, PeerID: 3}},
+			DemoteVoters:    []DemoteVoter{{ToStore: 1, PeerID: 1}},
+		}, TransferLeader{FromStore: 1, ToStore: 3}, RemovePeer{FromStore: 1, PeerID: 1}}, true},
+		// The peer already exists.
+		{[]OpStep{AddPeer{ToStore: 2, PeerID: 3}}, false},
+		// The stores are not up.
+		{[]OpStep{AddPeer{ToStore: 5, PeerID: 3}}, false},
+		{[]OpStep{AddLearner{ToStore: 6, PeerID: 3}}, false},
+		{[]OpStep{AddPeer{ToStore: 10, PeerID: 3}}, false},
+		// The leader is not on the store.
+		{[]OpStep{TransferLeader{FromStore: 2, ToStore: 1}}, false},
+		// The target of transferring leader is a learner.
+		{[]OpStep{AddLearner{ToStore: 3, PeerID: 3}, TransferLeader{FromStore: 1, ToStore: 3}}, false},
+		// Removing the leader or a missing peer.
+		{[]OpStep{RemovePeer{FromStore: 1, PeerID: 1}}, false},
+		{[]OpStep{RemovePeer{FromStore: 3, PeerID: 3}}, false},
+		// Promoting a voter.
+		{[]OpStep{PromoteLearner{ToStore: 2, PeerID: 2}}, false},
+	}
+	for i, t := range testCases {
+		op := NewOperator("test", "test", 1, &metapb.RegionEpoch{}, OpAdmin, t.steps...)
+		err := ValidatePlan(s.cluster, region, op)
+		c.Assert(err == nil, Equals, t.valid, Commentf("case %d: %v", i, err))
+	}
+
+	// Too many steps.
+	var steps []OpStep
+	for i := 0; i <= MaxPlanSteps/2; i++ {
+		steps = append(steps, TransferLeader{FromStore: 1, ToStore: 2}, TransferLeader{FromStore: 2, ToStore: 1})
+	}
+	op := NewOperator("test", "test", 1, &metapb.RegionEpoch{}, OpAdmin, steps...)
+	c.Assert(ValidatePlan(s.cluster, region, op), NotNil)
+	op = NewOperator("test", "test", 1, &metapb.RegionEpoch{}, OpAdmin, steps[:MaxPlanSteps]...)
+	c.Assert(ValidatePlan(s.cluster, region, op), IsNil)
+	// The operator is for another region.
+	op = NewOperator("test", "test", 2, &metapb.RegionEpoch{}, OpAdmin)
+	c.Assert(ValidatePlan(s.cluster, region, op), NotNil)
+}
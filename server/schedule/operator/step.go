@@ -78,6 +78,9 @@ func (tl TransferLeader) Influence(opInfluence OpInfluence, region *core.RegionI
 	from.LeaderCount--
 	to.LeaderSize += region.GetApproximateSize()
 	to.LeaderCount++
+	// The cost does not depend on the region size, as the store limit is
+	// used to protect the store whose caches are not warmed up.
+	to.addStepCost(storelimit.TransferLeader, storelimit.RegionInfluence[storelimit.TransferLeader])
 }
 
 // AddPeer is an OpStep that adds a region peer.
@@ -114,6 +117,7 @@ func (ap AddPeer) Influence(opInfluence OpInfluence, region *core.RegionInfo) {
 	regionSize := region.GetApproximateSize()
 	to.RegionSize += regionSize
 	to.RegionCount++
+	to.ByteRate += region.GetBytesRate()
 	to.AdjustStepCost(storelimit.AddPeer, regionSize)
 }
 
@@ -175,6 +179,7 @@ func (al AddLearner) Influence(opInfluence OpInfluence, region *core.RegionInfo)
 	regionSize := region.GetApproximateSize()
 	to.RegionSize += regionSize
 	to.RegionCount++
+	to.ByteRate += region.GetBytesRate()
 	to.AdjustStepCost(storelimit.AddPeer, regionSize)
 }
 
@@ -259,6 +264,7 @@ func (rp RemovePeer) Influence(opInfluence OpInfluence, region *core.RegionInfo)
 	regionSize := region.GetApproximateSize()
 	from.RegionSize -= regionSize
 	from.RegionCount--
+	from.ByteRate -= region.GetBytesRate()
 	from.AdjustStepCost(storelimit.RemovePeer, regionSize)
 }
 
@@ -0,0 +1,161 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/opt"
+)
+
+// MaxPlanSteps is the max number of the steps of an externally created operator.
+const MaxPlanSteps = 32
+
+// planPeer is the state of a peer while replaying the steps of an operator.
+type planPeer struct {
+	learner bool
+}
+
+// plan replays the steps of an operator on the peers of a region.
+type plan struct {
+	cluster opt.Cluster
+	peers   map[uint64]*planPeer // store ID -> peer
+	leader  uint64
+}
+
+// ValidatePlan checks if the operator can be executed on the current region,
+// i.e. the steps are bounded in count, every step is consistent with the
+// peers left by the former steps, and the stores of the new peers are up.
+func ValidatePlan(cluster opt.Cluster, region *core.RegionInfo, op *Operator) error {
+	if op.Len() > MaxPlanSteps {
+		return errs.ErrInvalidOperatorPlan.FastGenByArgs(fmt.Sprintf("the operator has %d steps, more than %d", op.Len(), MaxPlanSteps))
+	}
+	if region.GetID() != op.RegionID() {
+		return errs.ErrInvalidOperatorPlan.FastGenByArgs(fmt.Sprintf("the operator is for region %d instead of region %d", op.RegionID(), region.GetID()))
+	}
+	p := &plan{
+		cluster: cluster,
+		peers:   make(map[uint64]*planPeer),
+		leader:  region.GetLeader().GetStoreId(),
+	}
+	for _, peer := range region.GetPeers() {
+		p.peers[peer.GetStoreId()] = &planPeer{learner: region.GetStoreLearner(peer.GetStoreId()) != nil}
+	}
+	for i := 0; i < op.Len(); i++ {
+		if err := p.apply(op.Step(i)); err != nil {
+			return errs.ErrInvalidOperatorPlan.FastGenByArgs(fmt.Sprintf("step %d (%s): %s", i, op.Step(i), err))
+		}
+	}
+	return nil
+}
+
+func (p *plan) apply(step OpStep) error {
+	switch s := step.(type) {
+	case TransferLeader:
+		if p.leader != s.FromStore {
+			return errors.Errorf("the leader is not on store %d", s.FromStore)
+		}
+		if peer, ok := p.peers[s.ToStore]; !ok || peer.learner {
+			return errors.Errorf("there is no voter on store %d", s.ToStore)
+		}
+		if err := p.checkStore(s.ToStore); err != nil {
+			return err
+		}
+		p.leader = s.ToStore
+	case AddPeer:
+		return p.addPeer(s.ToStore, false)
+	case AddLightPeer:
+		return p.addPeer(s.ToStore, false)
+	case AddLearner:
+		return p.addPeer(s.ToStore, true)
+	case AddLightLearner:
+		return p.addPeer(s.ToStore, true)
+	case PromoteLearner:
+		return p.promote(s.ToStore)
+	case DemoteFollower:
+		return p.demote(s.ToStore, false)
+	case ChangePeerV2Enter:
+		for _, pl := range s.PromoteLearners {
+			if err := p.promote(pl.ToStore); err != nil {
+				return err
+			}
+		}
+		// The leader can be demoted in the joint state and transferred later.
+		for _, dv := range s.DemoteVoters {
+			if err := p.demote(dv.ToStore, true); err != nil {
+				return err
+			}
+		}
+	case RemovePeer:
+		if _, ok := p.peers[s.FromStore]; !ok {
+			return errors.Errorf("there is no peer on store %d", s.FromStore)
+		}
+		if p.leader == s.FromStore {
+			return errors.Errorf("the peer on store %d is the leader", s.FromStore)
+		}
+		delete(p.peers, s.FromStore)
+	}
+	return nil
+}
+
+func (p *plan) addPeer(storeID uint64, learner bool) error {
+	if _, ok := p.peers[storeID]; ok {
+		return errors.Errorf("there is already a peer on store %d", storeID)
+	}
+	if err := p.checkStore(storeID); err != nil {
+		return err
+	}
+	p.peers[storeID] = &planPeer{learner: learner}
+	return nil
+}
+
+func (p *plan) promote(storeID uint64) error {
+	if peer, ok := p.peers[storeID]; !ok || !peer.learner {
+		return errors.Errorf("there is no learner on store %d", storeID)
+	}
+	p.peers[storeID].learner = false
+	return nil
+}
+
+func (p *plan) demote(storeID uint64, allowLeader bool) error {
+	if peer, ok := p.peers[storeID]; !ok || peer.learner {
+		return errors.Errorf("there is no voter on store %d", storeID)
+	}
+	if !allowLeader && p.leader == storeID {
+		return errors.Errorf("the peer on store %d is the leader", storeID)
+	}
+	p.peers[storeID].learner = true
+	return nil
+}
+
+// checkStore checks if the store is up to hold a new peer or the leader.
+func (p *plan) checkStore(storeID uint64) error {
+	store := p.cluster.GetStore(storeID)
+	switch {
+	case store == nil:
+		return errors.Errorf("store %d is not found", storeID)
+	case store.IsTombstone():
+		return errors.Errorf("store %d is tombstone", storeID)
+	case store.IsOffline():
+		return errors.Errorf("store %d is offline", storeID)
+	case store.DownTime() > p.cluster.GetOpts().GetMaxStoreDownTime():
+		return errors.Errorf("store %d is down", storeID)
+	case store.IsDisconnected():
+		return errors.Errorf("store %d is disconnected", storeID)
+	}
+	return nil
+}
@@ -52,6 +52,10 @@ var (
 	PushOperatorTickInterval = 500 * time.Millisecond
 	// StoreBalanceBaseTime represents the base time of balance rate.
 	StoreBalanceBaseTime float64 = 60
+	// idempotencyKeyTTL is the duration that an idempotency key is remembered.
+	// The keys are not persisted, as the operators they guard are lost as well
+	// when the PD leader changes.
+	idempotencyKeyTTL = 10 * time.Minute
 )
 
 // OperatorController is used to limit the speed of scheduling.
@@ -68,6 +72,9 @@ type OperatorController struct {
 	wop             WaitingOperator
 	wopStatus       *WaitingOperatorStatus
 	opNotifierQueue operatorQueue
+	idempotencyKeys map[string]time.Time
+	storeBackoff    *storeBackoff
+	throughput      operatorThroughputRecorder
 }
 
 // NewOperatorController creates a OperatorController.
@@ -84,6 +91,8 @@ func NewOperatorController(ctx context.Context, cluster opt.Cluster, hbStreams *
 		wop:             NewRandBuckets(),
 		wopStatus:       NewWaitingOperatorStatus(),
 		opNotifierQueue: make(operatorQueue, 0),
+		idempotencyKeys: make(map[string]time.Time),
+		storeBackoff:    newStoreBackoff(),
 	}
 }
 
@@ -154,6 +163,11 @@ func (oc *OperatorController) checkStaleOperator(op *operator.Operator, step ope
 	if err != nil {
 		if oc.RemoveOperator(op, zap.String("reason", err.Error())) {
 			operatorCounter.WithLabelValues(op.Desc(), "stale").Inc()
+			// The step conflicts with the peer on its target store, so the
+			// cancel is counted as a failure of the store.
+			if storeID, ok := stepTargetStore(step); ok {
+				oc.recordStoreFailure(storeID)
+			}
 			oc.PromoteWaitingOperator()
 			return true
 		}
@@ -302,7 +316,9 @@ func (oc *OperatorController) AddWaitingOperator(ops ...*operator.Operator) int
 	return added
 }
 
-// AddOperator adds operators to the running operators.
+// AddOperator adds operators to the running operators. Unlike the waiting
+// operators, the operators conflicting with the running ones on the stores
+// are canceled rather than deferred.
 func (oc *OperatorController) AddOperator(ops ...*operator.Operator) bool {
 	oc.Lock()
 	defer oc.Unlock()
@@ -315,6 +331,17 @@ func (oc *OperatorController) AddOperator(ops ...*operator.Operator) bool {
 		}
 		return false
 	}
+	if reason := oc.checkStoreConflict(ops...); reason != "" {
+		log.Info("operator conflicts with the running operators, cancel it",
+			zap.Uint64("region-id", ops[0].RegionID()),
+			zap.String("reason", reason))
+		for _, op := range ops {
+			operatorCounter.WithLabelValues(op.Desc(), "conflict").Inc()
+			_ = op.Cancel()
+			oc.buryOperator(op)
+		}
+		return false
+	}
 	for _, op := range ops {
 		if !oc.addOperatorLocked(op) {
 			return false
@@ -323,11 +350,49 @@ func (oc *OperatorController) AddOperator(ops ...*operator.Operator) bool {
 	return true
 }
 
+// IsIdempotencyKeyUsed returns true if the operators with the idempotency
+// key have been created recently.
+func (oc *OperatorController) IsIdempotencyKeyUsed(key string) bool {
+	oc.RLock()
+	defer oc.RUnlock()
+	t, ok := oc.idempotencyKeys[key]
+	return ok && time.Since(t) < idempotencyKeyTTL
+}
+
+// SetIdempotencyKey marks the running operators of the regions with the
+// idempotency key, and remembers the key for a while.
+func (oc *OperatorController) SetIdempotencyKey(key string, regionIDs ...uint64) {
+	oc.Lock()
+	defer oc.Unlock()
+	now := time.Now()
+	for k, t := range oc.idempotencyKeys {
+		if now.Sub(t) >= idempotencyKeyTTL {
+			delete(oc.idempotencyKeys, k)
+		}
+	}
+	oc.idempotencyKeys[key] = now
+	for _, id := range regionIDs {
+		if op, ok := oc.operators[id]; ok {
+			op.SetIdempotencyKey(key)
+		}
+	}
+}
+
 // PromoteWaitingOperator promotes operators from waiting operators.
 func (oc *OperatorController) PromoteWaitingOperator() {
 	oc.Lock()
 	defer oc.Unlock()
 	var ops []*operator.Operator
+	var deferred [][]*operator.Operator
+	defer func() {
+		// The deferred operators keep waiting until the conflicts are gone or
+		// they are expired.
+		for _, ops := range deferred {
+			for _, op := range ops {
+				oc.wop.PutOperator(op)
+			}
+		}
+	}()
 	for {
 		// GetOperator returns one operator or two merge operators
 		ops = oc.wop.GetOperator()
@@ -345,6 +410,20 @@ func (oc *OperatorController) PromoteWaitingOperator() {
 			oc.wopStatus.ops[ops[0].Desc()]--
 			continue
 		}
+		if reason := oc.checkStoreConflict(ops...); reason != "" {
+			log.Debug("operator conflicts with the running operators, defer it",
+				zap.Uint64("region-id", ops[0].RegionID()),
+				zap.String("reason", reason))
+			for _, op := range ops {
+				op.AdditionalInfos["deferReason"] = reason
+				operatorWaitCounter.WithLabelValues(op.Desc(), "deferred").Inc()
+			}
+			deferred = append(deferred, ops)
+			continue
+		}
+		for _, op := range ops {
+			delete(op.AdditionalInfos, "deferReason")
+		}
 		oc.wopStatus.ops[ops[0].Desc()]--
 		break
 	}
@@ -404,6 +483,13 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 			operatorWaitCounter.WithLabelValues(op.Desc(), "exceed_max").Inc()
 			return false
 		}
+		if oc.exceedTableShare(op, region) {
+			log.Debug("exceed the share of the table, cancel add operator",
+				zap.Uint64("region-id", op.RegionID()),
+				zap.Float64("share", oc.cluster.GetOpts().GetTableOperatorShare()))
+			operatorWaitCounter.WithLabelValues(op.Desc(), "exceed_table_share").Inc()
+			return false
+		}
 	}
 	expired := false
 	for _, op := range ops {
@@ -415,6 +501,39 @@ func (oc *OperatorController) checkAddOperator(ops ...*operator.Operator) bool {
 	return !expired
 }
 
+// checkStoreConflict checks if the operators conflict with the running
+// operators on the stores they share, besides the same region which is checked
+// by checkAddOperator. The operators adding peers to the same store may make
+// it run out of space together, even though each of them is fine alone. It
+// returns the reason of the conflict, or an empty string if there is none.
+func (oc *OperatorController) checkStoreConflict(ops ...*operator.Operator) string {
+	var running operator.OpInfluence
+	lowSpaceRatio := oc.cluster.GetOpts().GetLowSpaceRatio()
+	for storeID, influence := range NewTotalOpInfluence(ops, oc.cluster).StoresInfluence {
+		if influence.RegionSize <= 0 {
+			continue
+		}
+		store := oc.cluster.GetStore(storeID)
+		if store == nil || store.GetCapacity() == 0 {
+			continue
+		}
+		if running.StoresInfluence == nil {
+			running = oc.getOpInfluenceLocked(oc.cluster)
+		}
+		runningSize := running.GetStoreInfluence(storeID).RegionSize
+		if runningSize <= 0 {
+			continue
+		}
+		// The available space after both the running operators and the new
+		// ones are finished.
+		available := float64(store.GetAvailable()) - float64(runningSize+influence.RegionSize)*(1<<20)
+		if available < float64(store.GetCapacity())*(1-lowSpaceRatio) {
+			return fmt.Sprintf("store %d may run out of space, %d MB is being added by the running operators", storeID, runningSize)
+		}
+	}
+	return ""
+}
+
 func isHigherPriorityOperator(new, old *operator.Operator) bool {
 	return new.GetPriorityLevel() > old.GetPriorityLevel()
 }
@@ -545,6 +664,8 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 		for _, counter := range op.FinishedCounters {
 			counter.Inc()
 		}
+		oc.updateStoreBackoff(op, true)
+		oc.throughput.recordSuccess(op)
 	case operator.REPLACED:
 		log.Info("replace old operator",
 			zap.Uint64("region-id", op.RegionID()),
@@ -563,6 +684,8 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 			zap.Duration("takes", op.RunningTime()),
 			zap.Reflect("operator", op))
 		operatorCounter.WithLabelValues(op.Desc(), "timeout").Inc()
+		oc.updateStoreBackoff(op, false)
+		oc.throughput.recordTimeout(op)
 	case operator.CANCELED:
 		fields := []zap.Field{
 			zap.Uint64("region-id", op.RegionID()),
@@ -579,6 +702,36 @@ func (oc *OperatorController) buryOperator(op *operator.Operator, extraFields ..
 	oc.opRecords.Put(op)
 }
 
+// updateStoreBackoff records the result of the operator for the stores it
+// targets, and backs off the stores where the operators keep failing.
+func (oc *OperatorController) updateStoreBackoff(op *operator.Operator, success bool) {
+	for _, storeID := range operatorTargetStores(op) {
+		if success {
+			oc.storeBackoff.recordSuccess(storeID)
+			continue
+		}
+		oc.recordStoreFailure(storeID)
+	}
+}
+
+// recordStoreFailure records a failed operator targeting the store, and backs
+// off the store if the operators keep failing on it.
+func (oc *OperatorController) recordStoreFailure(storeID uint64) {
+	if deadline, ok := oc.storeBackoff.recordFailure(storeID); ok {
+		oc.cluster.SetStoreBackoff(storeID, deadline)
+		storeBackoffCounter.WithLabelValues(strconv.FormatUint(storeID, 10)).Inc()
+		log.Warn("operators keep failing on the store, back off scheduling to it",
+			zap.Uint64("store-id", storeID),
+			zap.Time("deadline", deadline))
+	}
+}
+
+// TakeThroughput returns the throughput of the operators moving peers which end
+// since the last call.
+func (oc *OperatorController) TakeThroughput() OperatorThroughput {
+	return oc.throughput.take()
+}
+
 // GetOperatorStatus gets the operator and its status with the specify id.
 func (oc *OperatorController) GetOperatorStatus(id uint64) *OperatorWithStatus {
 	oc.Lock()
@@ -813,11 +966,15 @@ func (oc *OperatorController) OperatorCount(mask operator.OpKind) uint64 {
 
 // GetOpInfluence gets OpInfluence.
 func (oc *OperatorController) GetOpInfluence(cluster opt.Cluster) operator.OpInfluence {
+	oc.RLock()
+	defer oc.RUnlock()
+	return oc.getOpInfluenceLocked(cluster)
+}
+
+func (oc *OperatorController) getOpInfluenceLocked(cluster opt.Cluster) operator.OpInfluence {
 	influence := operator.OpInfluence{
 		StoresInfluence: make(map[uint64]*operator.StoreInfluence),
 	}
-	oc.RLock()
-	defer oc.RUnlock()
 	for _, op := range oc.operators {
 		if !op.CheckTimeout() && !op.CheckSuccess() {
 			region := cluster.GetRegion(op.RegionID())
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/codec"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
@@ -407,6 +408,116 @@ func (t *testOperatorControllerSuite) TestStoreLimit(c *C) {
 	c.Assert(oc.RemoveOperator(op), IsFalse)
 }
 
+func (t *testOperatorControllerSuite) TestTransferLeaderStoreLimit(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	for i := uint64(1); i <= 40; i++ {
+		tc.AddLeaderRegion(i, 1, 2)
+	}
+	transferLeader := func(regionID uint64) *operator.Operator {
+		return operator.NewOperator("test", "test", regionID, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 2})
+	}
+
+	// The leaders transferred in are not limited by default.
+	c.Assert(tc.GetOpts().GetStoreLimitByType(2, storelimit.TransferLeader), Equals, storelimit.Unlimited)
+	for i := uint64(1); i <= 20; i++ {
+		op := transferLeader(i)
+		c.Assert(oc.AddOperator(op), IsTrue)
+		checkRemoveOperatorSuccess(c, oc, op)
+	}
+
+	tc.SetStoreLimit(2, storelimit.TransferLeader, 60)
+	op := transferLeader(21)
+	c.Assert(oc.AddOperator(op), IsTrue)
+	checkRemoveOperatorSuccess(c, oc, op)
+	c.Assert(oc.AddOperator(transferLeader(22)), IsFalse)
+	c.Assert(tc.GetStore(2).IsAvailable(storelimit.TransferLeader), IsFalse)
+	// The other limits are not affected.
+	c.Assert(tc.GetOpts().GetStoreLimitByType(2, storelimit.AddPeer), Equals, float64(60))
+	op = operator.NewOperator("test", "test", 22, &metapb.RegionEpoch{}, operator.OpRegion, operator.AddPeer{ToStore: 2, PeerID: 100})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	checkRemoveOperatorSuccess(c, oc, op)
+
+	tc.SetAllStoresLimit(storelimit.TransferLeader, 120)
+	c.Assert(tc.GetOpts().GetStoreLimitByType(1, storelimit.TransferLeader), Equals, float64(120))
+	for i := uint64(23); i <= 24; i++ {
+		op = transferLeader(i)
+		c.Assert(oc.AddOperator(op), IsTrue)
+		checkRemoveOperatorSuccess(c, oc, op)
+	}
+	c.Assert(oc.AddOperator(transferLeader(25)), IsFalse)
+
+	// A zero limit stops transferring leaders in.
+	tc.AddLeaderStore(3, 0)
+	tc.AddLeaderRegion(26, 1, 3)
+	tc.SetStoreLimit(3, storelimit.TransferLeader, 0)
+	c.Assert(tc.GetOpts().GetStoreLimitByType(3, storelimit.TransferLeader), Equals, float64(0))
+	op = operator.NewOperator("test", "test", 26, &metapb.RegionEpoch{}, operator.OpLeader, operator.TransferLeader{FromStore: 1, ToStore: 3})
+	c.Assert(oc.AddOperator(op), IsFalse)
+	c.Assert(tc.GetStore(3).IsAvailable(storelimit.TransferLeader), IsFalse)
+
+	// The limits persisted without the transfer-leader limit follow the default.
+	cfg := tc.GetScheduleConfig().Clone()
+	cfg.StoreLimit[3] = config.StoreLimitConfig{AddPeer: 60, RemovePeer: 60}
+	tc.SetScheduleConfig(cfg)
+	c.Assert(tc.GetOpts().GetStoreLimitByType(3, storelimit.TransferLeader), Equals, storelimit.Unlimited)
+}
+
+func (t *testOperatorControllerSuite) TestStoreConflict(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	// The capacity of the stores is 1000 MB, and a store is low space once its
+	// available space is less than 200 MB.
+	tc.AddRegionStore(1, 0)
+	tc.AddRegionStore(2, 0)
+	tc.AddRegionStore(3, 0)
+	tc.SetStoreLimit(3, storelimit.AddPeer, storelimit.Unlimited)
+	for id, size := range map[uint64]int64{1: 450, 2: 400, 3: 100} {
+		tc.PutRegion(tc.AddLeaderRegion(id, 1, 2).Clone(core.SetApproximateSize(size)))
+	}
+	newAddPeer := func(regionID uint64) *operator.Operator {
+		region := tc.GetRegion(regionID)
+		return operator.NewOperator("test", "test", regionID, region.GetRegionEpoch(), operator.OpRegion, operator.AddPeer{ToStore: 3, PeerID: 10 + regionID})
+	}
+
+	op1 := newAddPeer(1)
+	c.Assert(oc.AddWaitingOperator(op1), Equals, 1)
+	c.Assert(oc.GetOperator(1), Equals, op1)
+
+	// Adding 850 MB to store 3 makes it low space, so the later one is deferred.
+	op2 := newAddPeer(2)
+	c.Assert(oc.AddWaitingOperator(op2), Equals, 1)
+	c.Assert(oc.GetOperator(2), IsNil)
+	c.Assert(oc.GetWaitingOperators(), HasLen, 1)
+	c.Assert(op2.AdditionalInfos["deferReason"], Matches, "store 3 may run out of space.*")
+
+	// The operator without conflict is not affected.
+	op3 := newAddPeer(3)
+	c.Assert(oc.AddWaitingOperator(op3), Equals, 1)
+	c.Assert(oc.GetOperator(3), Equals, op3)
+	c.Assert(oc.GetWaitingOperators(), HasLen, 1)
+
+	// The deferred operator is promoted once the conflict is gone.
+	checkRemoveOperatorSuccess(c, oc, op1)
+	oc.PromoteWaitingOperator()
+	c.Assert(oc.GetOperator(2), Equals, op2)
+	c.Assert(oc.GetWaitingOperators(), HasLen, 0)
+	c.Assert(op2.AdditionalInfos, Not(HasKey), "deferReason")
+
+	// The operator added directly has no waiting queue to be deferred in, so
+	// it is canceled.
+	op4 := newAddPeer(1)
+	c.Assert(oc.AddOperator(op4), IsFalse)
+	c.Assert(oc.GetOperator(1), IsNil)
+	c.Assert(op4.Status(), Equals, operator.CANCELED)
+}
+
 // #1652
 func (t *testOperatorControllerSuite) TestDispatchOutdatedRegion(c *C) {
 	cluster := mockcluster.NewCluster(config.NewTestOptions())
@@ -460,6 +571,29 @@ func (t *testOperatorControllerSuite) TestDispatchOutdatedRegion(c *C) {
 	c.Assert(stream.MsgLength(), Equals, 3)
 }
 
+func (t *testOperatorControllerSuite) TestStaleOperatorBackoff(c *C) {
+	cluster := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, cluster.ID, cluster, false /* no need to run */)
+	controller := NewOperatorController(t.ctx, cluster, stream)
+	cluster.AddLeaderStore(1, 0)
+	cluster.AddLeaderStore(2, 0)
+	region := cluster.AddLeaderRegion(1, 1, 2)
+
+	// The operator trying to add another peer on store 2 is canceled as
+	// stale, which is counted as a failure of store 2.
+	for i := 0; i < storeBackoffThreshold; i++ {
+		op := operator.NewOperator("test", "test", 1,
+			&metapb.RegionEpoch{ConfVer: 0, Version: 0},
+			operator.OpRegion, operator.AddLearner{ToStore: 2, PeerID: 100})
+		c.Assert(controller.AddOperator(op), IsTrue)
+		c.Assert(cluster.GetStore(2).IsBackingOff(), IsFalse)
+		controller.Dispatch(region, DispatchFromHeartBeat)
+		c.Assert(op.Status(), Equals, operator.CANCELED)
+	}
+	c.Assert(cluster.GetStore(2).IsBackingOff(), IsTrue)
+	c.Assert(cluster.GetStore(1).IsBackingOff(), IsFalse)
+}
+
 func (t *testOperatorControllerSuite) TestDispatchUnfinishedStep(c *C) {
 	cluster := mockcluster.NewCluster(config.NewTestOptions())
 	stream := hbstream.NewTestHeartbeatStreams(t.ctx, cluster.ID, cluster, false /* no need to run */)
@@ -632,6 +766,64 @@ func (t *testOperatorControllerSuite) TestStoreLimitWithMerge(c *C) {
 	}
 }
 
+func (t *testOperatorControllerSuite) TestTableOperatorShare(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.SetLeaderScheduleLimit(4)
+	tc.SetTableOperatorShare(0.5)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	putRegion := func(id uint64, tableID int64) {
+		start := codec.EncodeBytes(codec.GenerateRowKey(tableID, int64(id)))
+		end := codec.EncodeBytes(codec.GenerateRowKey(tableID, int64(id)+1))
+		tc.PutRegion(newRegionInfo(id, string(start), string(end), 1, 1, []uint64{id*10 + 1, 1}, []uint64{id*10 + 1, 1}, []uint64{id*10 + 2, 2}))
+	}
+	for i := uint64(1); i <= 5; i++ {
+		putRegion(i, 1)
+	}
+	putRegion(6, 2)
+	newOp := func(id uint64, kind operator.OpKind) *operator.Operator {
+		return operator.NewOperator("test", "test", id, tc.GetRegion(id).GetRegionEpoch(), kind, operator.TransferLeader{FromStore: 1, ToStore: 2})
+	}
+
+	// The operators of the regions in a table can take 2 of the 4 slots.
+	c.Assert(oc.AddOperator(newOp(1, operator.OpLeader)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(2, operator.OpLeader)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(3, operator.OpLeader)), IsFalse)
+	// The other tables, the other schedule limits and the admin operators are not restricted.
+	c.Assert(oc.AddOperator(newOp(6, operator.OpLeader)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(3, operator.OpRegion)), IsTrue)
+	c.Assert(oc.AddOperator(newOp(4, operator.OpLeader|operator.OpAdmin)), IsTrue)
+
+	// Disable the restriction.
+	tc.SetTableOperatorShare(0)
+	c.Assert(oc.AddOperator(newOp(5, operator.OpLeader)), IsTrue)
+}
+
+func (t *testOperatorControllerSuite) TestReplicaRepairReservedSlots(c *C) {
+	tc := mockcluster.NewCluster(config.NewTestOptions())
+	stream := hbstream.NewTestHeartbeatStreams(t.ctx, tc.ID, tc, false /* no need to run */)
+	oc := NewOperatorController(t.ctx, tc, stream)
+	tc.SetRegionScheduleLimit(4)
+	tc.SetReplicaRepairReservedSlots(3)
+	tc.AddLeaderStore(1, 0)
+	tc.AddLeaderStore(2, 0)
+	tc.AddLeaderRegion(1, 1, 2)
+	opts := tc.GetOpts()
+
+	// Nothing is reserved if no replica is being repaired.
+	c.Assert(oc.GetBalanceRegionScheduleLimit(opts), Equals, uint64(4))
+	op := operator.NewOperator("test", "test", 1, tc.GetRegion(1).GetRegionEpoch(), operator.OpReplica|operator.OpRegion, operator.RemovePeer{FromStore: 2})
+	c.Assert(oc.AddOperator(op), IsTrue)
+	c.Assert(oc.GetBalanceRegionScheduleLimit(opts), Equals, uint64(1))
+
+	tc.SetReplicaRepairReservedSlots(5)
+	c.Assert(oc.GetBalanceRegionScheduleLimit(opts), Equals, uint64(0))
+	tc.SetReplicaRepairReservedSlots(0)
+	c.Assert(oc.GetBalanceRegionScheduleLimit(opts), Equals, uint64(4))
+}
+
 func newRegionInfo(id uint64, startKey, endKey string, size, keys int64, leader []uint64, peers ...[]uint64) *core.RegionInfo {
 	prs := make([]*metapb.Peer, 0, len(peers))
 	for _, peer := range peers {
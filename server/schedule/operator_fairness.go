@@ -0,0 +1,89 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"math"
+
+	"github.com/tikv/pd/pkg/codec"
+	"github.com/tikv/pd/server/config"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// scheduleLimitOf returns the kinds counted by the schedule limit which the
+// operator is restricted by, and the value of the limit.
+func scheduleLimitOf(opts *config.PersistOptions, kind operator.OpKind) (operator.OpKind, uint64) {
+	switch {
+	case kind&operator.OpMerge != 0:
+		return operator.OpMerge, opts.GetMergeScheduleLimit()
+	case kind&operator.OpReplica != 0:
+		return operator.OpReplica, opts.GetReplicaScheduleLimit()
+	case kind&operator.OpHotRegion != 0:
+		return operator.OpHotRegion, opts.GetHotRegionScheduleLimit()
+	case kind&operator.OpRegion != 0:
+		return operator.OpRegion, opts.GetRegionScheduleLimit()
+	case kind&operator.OpLeader != 0:
+		return operator.OpLeader, opts.GetLeaderScheduleLimit()
+	}
+	return 0, 0
+}
+
+// GetBalanceRegionScheduleLimit returns the region schedule limit which the
+// balance schedulers can take. When the replicas are being repaired, the slots
+// reserved for the replica repair are excluded, so the balance does not compete
+// with the re-replication.
+func (oc *OperatorController) GetBalanceRegionScheduleLimit(opts *config.PersistOptions) uint64 {
+	limit := opts.GetRegionScheduleLimit()
+	reserved := opts.GetReplicaRepairReservedSlots()
+	if reserved == 0 || oc.OperatorCount(operator.OpReplica) == 0 {
+		return limit
+	}
+	if reserved >= limit {
+		return 0
+	}
+	return limit - reserved
+}
+
+// exceedTableShare checks if the running operators of the regions in the same
+// table as the region have taken the max share of the schedule limit, so the
+// regions of other tables can still be scheduled when a huge table is being
+// scheduled. The admin operators and the regions out of tables are not
+// restricted. It should be called with the lock held.
+func (oc *OperatorController) exceedTableShare(op *operator.Operator, region *core.RegionInfo) bool {
+	opts := oc.cluster.GetOpts()
+	share := opts.GetTableOperatorShare()
+	if share <= 0 || share >= 1 || op.Kind()&operator.OpAdmin != 0 {
+		return false
+	}
+	tableID := codec.Key(region.GetStartKey()).TableID()
+	if tableID == 0 {
+		return false
+	}
+	mask, limit := scheduleLimitOf(opts, op.Kind())
+	if mask == 0 || limit == 0 {
+		return false
+	}
+	maxCount := uint64(math.Ceil(float64(limit) * share))
+	var count uint64
+	for regionID, running := range oc.operators {
+		if regionID == op.RegionID() || running.Kind()&mask == 0 {
+			continue
+		}
+		if r := oc.cluster.GetRegion(regionID); r != nil && codec.Key(r.GetStartKey()).TableID() == tableID {
+			count++
+		}
+	}
+	return count >= maxCount
+}
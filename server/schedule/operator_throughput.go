@@ -0,0 +1,85 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+// OperatorThroughput summarizes the operators moving peers which end in a period.
+type OperatorThroughput struct {
+	Finished int
+	TimedOut int
+	// AvgRunningTime is the average running time of the finished operators.
+	AvgRunningTime time.Duration
+	// AvgSnapshotDuration is the average time taken by the finished operators
+	// to add peers, only the operators adding peers are counted.
+	AvgSnapshotDuration time.Duration
+}
+
+// operatorThroughputRecorder accumulates the operators moving peers until the
+// throughput is taken.
+type operatorThroughputRecorder struct {
+	sync.Mutex
+	finished         int
+	timedOut         int
+	snapshots        int
+	runningTime      time.Duration
+	snapshotDuration time.Duration
+}
+
+func (r *operatorThroughputRecorder) recordSuccess(op *operator.Operator) {
+	if op.Kind()&operator.OpRegion == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.finished++
+	r.runningTime += op.RunningTime()
+	if d := op.SnapshotDuration(); d > 0 {
+		r.snapshots++
+		r.snapshotDuration += d
+	}
+}
+
+func (r *operatorThroughputRecorder) recordTimeout(op *operator.Operator) {
+	if op.Kind()&operator.OpRegion == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.timedOut++
+}
+
+// take returns the throughput since the last call and resets the recorder.
+func (r *operatorThroughputRecorder) take() OperatorThroughput {
+	r.Lock()
+	defer r.Unlock()
+	t := OperatorThroughput{
+		Finished: r.finished,
+		TimedOut: r.timedOut,
+	}
+	if r.finished > 0 {
+		t.AvgRunningTime = r.runningTime / time.Duration(r.finished)
+	}
+	if r.snapshots > 0 {
+		t.AvgSnapshotDuration = r.snapshotDuration / time.Duration(r.snapshots)
+	}
+	r.finished, r.timedOut, r.snapshots = 0, 0, 0
+	r.runningTime, r.snapshotDuration = 0, 0
+	return t
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"sync"
+
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/core"
+)
+
+// fitCacheEntry is the fit result of a region together with everything the
+// result depends on. The result can be reused as long as none of them changes.
+type fitCacheEntry struct {
+	ruleVersion uint64
+	epoch       *metapb.RegionEpoch
+	leaderID    uint64
+	peers       []*metapb.Peer
+	storeLabels [][]*metapb.StoreLabel
+	fit         *RegionFit
+}
+
+// regionFitCache caches the fit results of the regions, so that the fit is
+// not computed again for the regions which are not changed. It is thread
+// safe.
+type regionFitCache struct {
+	sync.RWMutex
+	entries map[uint64]*fitCacheEntry
+}
+
+func newRegionFitCache() *regionFitCache {
+	return &regionFitCache{entries: make(map[uint64]*fitCacheEntry)}
+}
+
+// isCacheable checks if the fit result of the region can be cached. The
+// regions created temporarily to evaluate the schedule, e.g. by the rule fit
+// filter, do not have an ID and an epoch.
+func isCacheable(region *core.RegionInfo) bool {
+	return region.GetID() != 0 && region.GetRegionEpoch() != nil
+}
+
+func (c *regionFitCache) get(stores StoreSet, region *core.RegionInfo, ruleVersion uint64) *RegionFit {
+	if !isCacheable(region) {
+		return nil
+	}
+	c.RLock()
+	entry, ok := c.entries[region.GetID()]
+	c.RUnlock()
+	if !ok || !entry.isValid(stores, region, ruleVersion) {
+		return nil
+	}
+	return entry.fit
+}
+
+func (c *regionFitCache) put(stores StoreSet, region *core.RegionInfo, ruleVersion uint64, fit *RegionFit) {
+	if !isCacheable(region) {
+		return
+	}
+	entry := &fitCacheEntry{
+		ruleVersion: ruleVersion,
+		epoch:       region.GetRegionEpoch(),
+		leaderID:    region.GetLeader().GetId(),
+		peers:       region.GetPeers(),
+		storeLabels: make([][]*metapb.StoreLabel, 0, len(region.GetPeers())),
+		fit:         fit,
+	}
+	for _, p := range region.GetPeers() {
+		store := stores.GetStore(p.GetStoreId())
+		if store == nil {
+			return
+		}
+		entry.storeLabels = append(entry.storeLabels, store.GetLabels())
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.entries[region.GetID()] = entry
+}
+
+func (c *regionFitCache) remove(regionID uint64) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, regionID)
+}
+
+func (c *regionFitCache) clear() {
+	c.Lock()
+	defer c.Unlock()
+	c.entries = make(map[uint64]*fitCacheEntry)
+}
+
+func (e *fitCacheEntry) isValid(stores StoreSet, region *core.RegionInfo, ruleVersion uint64) bool {
+	if e.ruleVersion != ruleVersion ||
+		e.epoch.GetVersion() != region.GetRegionEpoch().GetVersion() ||
+		e.epoch.GetConfVer() != region.GetRegionEpoch().GetConfVer() ||
+		e.leaderID != region.GetLeader().GetId() {
+		return false
+	}
+	peers := region.GetPeers()
+	if len(peers) != len(e.peers) {
+		return false
+	}
+	for i, p := range peers {
+		if p.GetId() != e.peers[i].GetId() || p.GetStoreId() != e.peers[i].GetStoreId() || p.GetRole() != e.peers[i].GetRole() {
+			return false
+		}
+		store := stores.GetStore(p.GetStoreId())
+		if store == nil || !isSameLabels(store.GetLabels(), e.storeLabels[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSameLabels(a, b []*metapb.StoreLabel) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].GetKey() != b[i].GetKey() || a[i].GetValue() != b[i].GetValue() {
+			return false
+		}
+	}
+	return true
+}
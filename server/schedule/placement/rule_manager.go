@@ -37,6 +37,10 @@ type RuleManager struct {
 	initialized bool
 	ruleConfig  *ruleConfig
 	ruleList    ruleList
+	// ruleVersion is increased once the rule list is changed, which
+	// invalidates the cached fit results.
+	ruleVersion uint64
+	fitCache    *regionFitCache
 }
 
 // NewRuleManager creates a RuleManager instance.
@@ -44,6 +48,7 @@ func NewRuleManager(store *core.Storage) *RuleManager {
 	return &RuleManager{
 		store:      store,
 		ruleConfig: newRuleConfig(),
+		fitCache:   newRegionFitCache(),
 	}
 }
 
@@ -82,6 +87,7 @@ func (m *RuleManager) Initialize(maxReplica int, locationLabels []string) error
 		return err
 	}
 	m.ruleList = ruleList
+	m.ruleVersion++
 	m.initialized = true
 	return nil
 }
@@ -260,10 +266,26 @@ func (m *RuleManager) GetRulesForApplyRegion(region *core.RegionInfo) []*Rule {
 	return m.ruleList.getRulesForApplyRegion(region.GetStartKey(), region.GetEndKey())
 }
 
-// FitRegion fits a region to the rules it matches.
+// FitRegion fits a region to the rules it matches. The result is cached until
+// the region, the labels of its stores or the rules are changed. The returned
+// RegionFit should not be modified.
 func (m *RuleManager) FitRegion(stores StoreSet, region *core.RegionInfo) *RegionFit {
-	rules := m.GetRulesForApplyRegion(region)
-	return FitRegion(stores, region, rules)
+	m.RLock()
+	rules := m.ruleList.getRulesForApplyRegion(region.GetStartKey(), region.GetEndKey())
+	version := m.ruleVersion
+	m.RUnlock()
+	if fit := m.fitCache.get(stores, region, version); fit != nil {
+		return fit
+	}
+	fit := FitRegion(stores, region, rules)
+	m.fitCache.put(stores, region, version, fit)
+	return fit
+}
+
+// InvalidateFitCache removes the cached fit result of the region, it should be
+// called once the region is removed.
+func (m *RuleManager) InvalidateFitCache(regionID uint64) {
+	m.fitCache.remove(regionID)
 }
 
 func (m *RuleManager) beginPatch() *ruleConfigPatch {
@@ -289,6 +311,8 @@ func (m *RuleManager) tryCommitPatch(patch *ruleConfigPatch) error {
 	// update in-memory state
 	patch.commit()
 	m.ruleList = ruleList
+	m.ruleVersion++
+	m.fitCache.clear()
 	return nil
 }
 
@@ -337,6 +337,51 @@ func (s *testManagerSuite) TestCheckApplyRules(c *C) {
 	c.Assert(err, ErrorMatches, "needs at least one leader or voter")
 }
 
+func (s *testManagerSuite) TestFitCache(c *C) {
+	stores := core.NewStoresInfo()
+	for id := uint64(1); id <= 4; id++ {
+		stores.SetStore(core.NewStoreInfoWithLabel(id, 0, map[string]string{"zone": "z1"}))
+	}
+	peers := []*metapb.Peer{{Id: 1, StoreId: 1}, {Id: 2, StoreId: 2}, {Id: 3, StoreId: 3}}
+	region := core.NewRegionInfo(&metapb.Region{
+		Id:          1,
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 1, Version: 1},
+	}, peers[0])
+
+	fit := s.manager.FitRegion(stores, region)
+	c.Assert(fit.IsSatisfied(), IsTrue)
+	// The result is reused if nothing is changed.
+	c.Assert(s.manager.FitRegion(stores, region), Equals, fit)
+	c.Assert(s.manager.FitRegion(stores, region.Clone()), Equals, fit)
+
+	// The cache is invalidated once the peers are changed.
+	region = region.Clone(core.WithAddPeer(&metapb.Peer{Id: 4, StoreId: 4}), core.WithIncConfVer())
+	fit = s.manager.FitRegion(stores, region)
+	c.Assert(fit.IsSatisfied(), IsFalse)
+	c.Assert(fit.OrphanPeers, HasLen, 1)
+	c.Assert(s.manager.FitRegion(stores, region), Equals, fit)
+
+	// The cache is invalidated once the labels of the stores are changed.
+	stores.SetStore(stores.GetStore(4).Clone(core.SetStoreLabels([]*metapb.StoreLabel{{Key: "zone", Value: "z2"}})))
+	newFit := s.manager.FitRegion(stores, region)
+	c.Assert(newFit, Not(Equals), fit)
+	c.Assert(s.manager.FitRegion(stores, region), Equals, newFit)
+
+	// The cache is invalidated once the rules are changed.
+	c.Assert(s.manager.SetRule(&Rule{GroupID: "pd", ID: "default", Role: Voter, Count: 4}), IsNil)
+	fit = s.manager.FitRegion(stores, region)
+	c.Assert(fit, Not(Equals), newFit)
+	c.Assert(fit.IsSatisfied(), IsTrue)
+
+	s.manager.InvalidateFitCache(region.GetID())
+	c.Assert(s.manager.FitRegion(stores, region), Not(Equals), fit)
+
+	// The regions without an ID are not cached.
+	tmp := core.NewRegionInfo(&metapb.Region{Peers: peers}, peers[0])
+	c.Assert(s.manager.FitRegion(stores, tmp), Not(Equals), s.manager.FitRegion(stores, tmp))
+}
+
 func (s *testManagerSuite) dhex(hk string) []byte {
 	k, err := hex.DecodeString(hk)
 	if err != nil {
@@ -350,6 +350,7 @@ func (r *RegionScatterer) collectAvailableStores(group string, region *core.Regi
 	filters := []filter.Filter{
 		filter.NewExcludedFilter(r.name, nil, region.GetStoreIds()),
 		filter.StoreStateFilter{ActionScope: r.name, MoveRegion: true},
+		filter.NewSnapshotCountFilter(r.name),
 	}
 	filters = append(filters, context.filters...)
 	filters = append(filters, context.selectedPeer.newFilters(r.name, group)...)
@@ -0,0 +1,155 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+const (
+	// storeBackoffThreshold is the number of recent operator failures after
+	// which a store is backed off.
+	storeBackoffThreshold = 3
+	// storeBackoffBase is the first backoff duration of a store, it doubles
+	// each time the store is backed off again until storeBackoffMax.
+	storeBackoffBase = 30 * time.Second
+	storeBackoffMax  = 10 * time.Minute
+	// storeFailureHalfLife is the time for the failure count of a store to
+	// decay by half.
+	storeFailureHalfLife = 5 * time.Minute
+	// storeBackoffRecovery is the time for the backoff of a store to step
+	// down to the previous level if it is not backed off again.
+	storeBackoffRecovery = 2 * storeBackoffMax
+)
+
+type storeFailure struct {
+	count      float64
+	lastUpdate time.Time
+	// backoffs is the level of the backoff, which increases each time the
+	// store is backed off and decreases every storeBackoffRecovery after the
+	// last backoff.
+	backoffs    int
+	lastBackoff time.Time
+}
+
+// storeBackoff tracks the failures of the operators targeting each store, and
+// decides how long a store should not be selected as target when operators
+// keep failing on it. The failure count decays with time, and a successful
+// operator reduces it. The backoff level steps down with time, so a store
+// recovers from backoff gradually instead of being forgotten at once.
+type storeBackoff struct {
+	sync.Mutex
+	failures map[uint64]*storeFailure
+	now      func() time.Time
+}
+
+func newStoreBackoff() *storeBackoff {
+	return &storeBackoff{
+		failures: make(map[uint64]*storeFailure),
+		now:      time.Now,
+	}
+}
+
+// get returns the failure record of the store after decaying.
+func (b *storeBackoff) get(storeID uint64) *storeFailure {
+	now := b.now()
+	f, ok := b.failures[storeID]
+	if !ok {
+		f = &storeFailure{lastUpdate: now}
+		b.failures[storeID] = f
+		return f
+	}
+	elapsed := now.Sub(f.lastUpdate)
+	f.count *= math.Pow(0.5, float64(elapsed)/float64(storeFailureHalfLife))
+	f.lastUpdate = now
+	if f.backoffs > 0 {
+		if steps := int(now.Sub(f.lastBackoff) / storeBackoffRecovery); steps >= f.backoffs {
+			f.backoffs = 0
+		} else if steps > 0 {
+			f.backoffs -= steps
+			f.lastBackoff = f.lastBackoff.Add(time.Duration(steps) * storeBackoffRecovery)
+		}
+	}
+	return f
+}
+
+// recordFailure records a failed operator targeting the store. It returns the
+// backoff deadline if the store needs to be backed off.
+func (b *storeBackoff) recordFailure(storeID uint64) (time.Time, bool) {
+	b.Lock()
+	defer b.Unlock()
+	f := b.get(storeID)
+	f.count++
+	if f.count < storeBackoffThreshold {
+		return time.Time{}, false
+	}
+	backoff := storeBackoffMax
+	if f.backoffs < 32 && storeBackoffBase<<uint(f.backoffs) < storeBackoffMax {
+		backoff = storeBackoffBase << uint(f.backoffs)
+	}
+	f.backoffs++
+	f.lastBackoff = f.lastUpdate
+	// Start counting again, the store is backed off longer if operators keep
+	// failing after the backoff.
+	f.count = 0
+	return f.lastUpdate.Add(backoff), true
+}
+
+// recordSuccess records a successful operator targeting the store. It only
+// reduces the failure count, and the backoff level is kept until it steps
+// down with time, so a store flapping between success and failure is still
+// backed off longer and longer.
+func (b *storeBackoff) recordSuccess(storeID uint64) {
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.failures[storeID]; !ok {
+		return
+	}
+	f := b.get(storeID)
+	f.count = math.Max(f.count-1, 0)
+}
+
+// operatorTargetStores returns the stores the operator adds peers to or
+// transfers the leader to.
+func operatorTargetStores(op *operator.Operator) []uint64 {
+	var stores []uint64
+	for i := 0; i < op.Len(); i++ {
+		if storeID, ok := stepTargetStore(op.Step(i)); ok {
+			stores = append(stores, storeID)
+		}
+	}
+	return stores
+}
+
+// stepTargetStore returns the store the step adds a peer to or transfers the
+// leader to.
+func stepTargetStore(step operator.OpStep) (uint64, bool) {
+	switch step := step.(type) {
+	case operator.AddPeer:
+		return step.ToStore, true
+	case operator.AddLearner:
+		return step.ToStore, true
+	case operator.AddLightPeer:
+		return step.ToStore, true
+	case operator.AddLightLearner:
+		return step.ToStore, true
+	case operator.TransferLeader:
+		return step.ToStore, true
+	}
+	return 0, false
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/schedule/operator"
+)
+
+var _ = Suite(&testStoreBackoffSuite{})
+
+type testStoreBackoffSuite struct{}
+
+func (s *testStoreBackoffSuite) TestBackoff(c *C) {
+	now := time.Now()
+	b := newStoreBackoff()
+	b.now = func() time.Time { return now }
+
+	// The store is backed off after the failures reach the threshold.
+	for i := 0; i < storeBackoffThreshold-1; i++ {
+		_, ok := b.recordFailure(1)
+		c.Assert(ok, IsFalse)
+	}
+	deadline, ok := b.recordFailure(1)
+	c.Assert(ok, IsTrue)
+	c.Assert(deadline, Equals, now.Add(storeBackoffBase))
+
+	// The backoff doubles if the operators keep failing.
+	for i := 0; i < storeBackoffThreshold-1; i++ {
+		_, ok = b.recordFailure(1)
+		c.Assert(ok, IsFalse)
+	}
+	deadline, ok = b.recordFailure(1)
+	c.Assert(ok, IsTrue)
+	c.Assert(deadline, Equals, now.Add(2*storeBackoffBase))
+
+	// The failures decay with time.
+	for i := 0; i < storeBackoffThreshold-1; i++ {
+		_, ok = b.recordFailure(2)
+		c.Assert(ok, IsFalse)
+	}
+	now = now.Add(storeFailureHalfLife)
+	_, ok = b.recordFailure(2)
+	c.Assert(ok, IsFalse)
+
+	// The successful operators reduce the failures.
+	b.recordSuccess(2)
+	b.recordSuccess(2)
+	_, ok = b.recordFailure(2)
+	c.Assert(ok, IsFalse)
+	b.recordSuccess(2)
+	b.recordSuccess(2)
+	c.Assert(b.failures[2].count, Equals, 0.0)
+
+	// The backoff does not exceed the max.
+	for i := 0; i < 10*storeBackoffThreshold; i++ {
+		deadline, _ = b.recordFailure(3)
+	}
+	c.Assert(deadline, Equals, now.Add(storeBackoffMax))
+}
+
+func (s *testStoreBackoffSuite) TestBackoffRecovery(c *C) {
+	now := time.Now()
+	b := newStoreBackoff()
+	b.now = func() time.Time { return now }
+	backoff := func() time.Time {
+		var deadline time.Time
+		for i := 0; i < storeBackoffThreshold; i++ {
+			deadline, _ = b.recordFailure(1)
+		}
+		return deadline
+	}
+	c.Assert(backoff(), Equals, now.Add(storeBackoffBase))
+
+	// The successful operators do not reset the backoff level.
+	for i := 0; i < storeBackoffThreshold; i++ {
+		b.recordSuccess(1)
+	}
+	c.Assert(backoff(), Equals, now.Add(2*storeBackoffBase))
+
+	// The backoff level steps down with time.
+	now = now.Add(storeBackoffRecovery)
+	c.Assert(backoff(), Equals, now.Add(2*storeBackoffBase))
+	now = now.Add(2 * storeBackoffRecovery)
+	c.Assert(backoff(), Equals, now.Add(storeBackoffBase))
+}
+
+func (s *testStoreBackoffSuite) TestOperatorTargetStores(c *C) {
+	op := operator.NewOperator("test", "test", 1, nil, operator.OpRegion,
+		operator.AddLearner{ToStore: 2, PeerID: 2},
+		operator.PromoteLearner{ToStore: 2, PeerID: 2},
+		operator.TransferLeader{FromStore: 1, ToStore: 3},
+		operator.RemovePeer{FromStore: 1},
+	)
+	c.Assert(operatorTargetStores(op), DeepEquals, []uint64{2, 3})
+}
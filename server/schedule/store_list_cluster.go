@@ -0,0 +1,153 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/statistics"
+)
+
+// StoreList limits the stores used by a scheduler. If Allow is not empty,
+// only the stores in it can be used. The stores in Deny are never used.
+type StoreList struct {
+	Allow []uint64 `json:"allow,omitempty"`
+	Deny  []uint64 `json:"deny,omitempty"`
+}
+
+// IsEmpty returns true if the list does not limit any store.
+func (l *StoreList) IsEmpty() bool {
+	return l == nil || (len(l.Allow) == 0 && len(l.Deny) == 0)
+}
+
+// IsAllowed checks if the store can be used.
+func (l *StoreList) IsAllowed(storeID uint64) bool {
+	if l == nil {
+		return true
+	}
+	for _, id := range l.Deny {
+		if id == storeID {
+			return false
+		}
+	}
+	if len(l.Allow) == 0 {
+		return true
+	}
+	for _, id := range l.Allow {
+		if id == storeID {
+			return true
+		}
+	}
+	return false
+}
+
+// StoreListCluster hides the stores which are not allowed by the store list.
+// Unlike NamespaceCluster, the regions are still visible, but the stores
+// which are not allowed are never picked as the source or target.
+type StoreListCluster struct {
+	opt.Cluster
+	list *StoreList
+}
+
+// GenStoreListCluster gets a cluster that can only use the allowed stores.
+func GenStoreListCluster(cluster opt.Cluster, list *StoreList) *StoreListCluster {
+	return &StoreListCluster{
+		Cluster: cluster,
+		list:    list,
+	}
+}
+
+// GetStores returns the allowed stores.
+func (l *StoreListCluster) GetStores() []*core.StoreInfo {
+	stores := l.Cluster.GetStores()
+	ret := make([]*core.StoreInfo, 0, len(stores))
+	for _, s := range stores {
+		if l.list.IsAllowed(s.GetID()) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// GetFollowerStores returns the allowed stores which have a follower of the region.
+func (l *StoreListCluster) GetFollowerStores(region *core.RegionInfo) []*core.StoreInfo {
+	stores := l.Cluster.GetFollowerStores(region)
+	ret := make([]*core.StoreInfo, 0, len(stores))
+	for _, s := range stores {
+		if l.list.IsAllowed(s.GetID()) {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// RandFollowerRegion returns a random region that has a follower on the store.
+func (l *StoreListCluster) RandFollowerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !l.list.IsAllowed(storeID) {
+		return nil
+	}
+	return l.Cluster.RandFollowerRegion(storeID, ranges, opts...)
+}
+
+// RandLeaderRegion returns a random region that has leader on the store.
+func (l *StoreListCluster) RandLeaderRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !l.list.IsAllowed(storeID) {
+		return nil
+	}
+	return l.Cluster.RandLeaderRegion(storeID, ranges, opts...)
+}
+
+// RandLearnerRegion returns a random region that has a learner peer on the store.
+func (l *StoreListCluster) RandLearnerRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !l.list.IsAllowed(storeID) {
+		return nil
+	}
+	return l.Cluster.RandLearnerRegion(storeID, ranges, opts...)
+}
+
+// RandPendingRegion returns a random region that has a pending peer on the store.
+func (l *StoreListCluster) RandPendingRegion(storeID uint64, ranges []core.KeyRange, opts ...core.RegionOption) *core.RegionInfo {
+	if !l.list.IsAllowed(storeID) {
+		return nil
+	}
+	return l.Cluster.RandPendingRegion(storeID, ranges, opts...)
+}
+
+// RandHotRegionFromStore randomly picks a hot region in specified store.
+func (l *StoreListCluster) RandHotRegionFromStore(store uint64, kind statistics.FlowKind) *core.RegionInfo {
+	if !l.list.IsAllowed(store) {
+		return nil
+	}
+	return l.Cluster.RandHotRegionFromStore(store, kind)
+}
+
+// RegionWriteStats returns the write stats of the hot peers on the allowed stores.
+func (l *StoreListCluster) RegionWriteStats() map[uint64][]*statistics.HotPeerStat {
+	return l.filterHotPeers(l.Cluster.RegionWriteStats())
+}
+
+// RegionReadStats returns the read stats of the hot peers on the allowed stores.
+func (l *StoreListCluster) RegionReadStats() map[uint64][]*statistics.HotPeerStat {
+	return l.filterHotPeers(l.Cluster.RegionReadStats())
+}
+
+func (l *StoreListCluster) filterHotPeers(stats map[uint64][]*statistics.HotPeerStat) map[uint64][]*statistics.HotPeerStat {
+	ret := make(map[uint64][]*statistics.HotPeerStat, len(stats))
+	for storeID, peers := range stats {
+		if l.list.IsAllowed(storeID) {
+			ret[storeID] = peers
+		}
+	}
+	return ret
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weight
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+// DefaultWeight is the weight of the regions which are not covered by any
+// key range weight.
+const DefaultWeight = 1.0
+
+// KeyRangeWeight assigns a scheduling weight to the regions in a key range.
+// The regions with a larger weight are repaired and de-hotspotted first, and
+// are kept from being moved only for balance.
+type KeyRangeWeight struct {
+	ID          string  `json:"id"`
+	StartKeyHex string  `json:"start_key"` // hex format start key, for marshal/unmarshal
+	EndKeyHex   string  `json:"end_key"`   // hex format end key, for marshal/unmarshal
+	Weight      float64 `json:"weight"`
+
+	startKey, endKey []byte
+}
+
+func (w *KeyRangeWeight) String() string {
+	b, _ := json.Marshal(w)
+	return string(b)
+}
+
+// Adjust decodes the keys and validates the key range weight.
+func (w *KeyRangeWeight) Adjust() error {
+	var err error
+	w.startKey, err = hex.DecodeString(w.StartKeyHex)
+	if err != nil {
+		return errs.ErrHexDecodingString.FastGenByArgs(w.StartKeyHex)
+	}
+	w.endKey, err = hex.DecodeString(w.EndKeyHex)
+	if err != nil {
+		return errs.ErrHexDecodingString.FastGenByArgs(w.EndKeyHex)
+	}
+	if len(w.endKey) > 0 && bytes.Compare(w.endKey, w.startKey) <= 0 {
+		return errs.ErrRegionWeightContent.FastGenByArgs("endKey should be greater than startKey")
+	}
+	if w.ID == "" {
+		return errs.ErrRegionWeightContent.FastGenByArgs("ID should not be empty")
+	}
+	if w.Weight <= 0 {
+		return errs.ErrRegionWeightContent.FastGenByArgs(fmt.Sprintf("invalid weight %v", w.Weight))
+	}
+	return nil
+}
+
+// overlaps checks if the key range overlaps with the region.
+func (w *KeyRangeWeight) overlaps(region *core.RegionInfo) bool {
+	start, end := region.GetStartKey(), region.GetEndKey()
+	return (len(w.endKey) == 0 || bytes.Compare(start, w.endKey) < 0) &&
+		(len(end) == 0 || bytes.Compare(w.startKey, end) < 0)
+}
+
+// Manager is responsible for the key range weights of the regions.
+type Manager struct {
+	sync.RWMutex
+	storage *core.Storage
+	weights map[string]*KeyRangeWeight
+}
+
+// NewManager creates a Manager instance.
+func NewManager(storage *core.Storage) *Manager {
+	return &Manager{
+		storage: storage,
+		weights: make(map[string]*KeyRangeWeight),
+	}
+}
+
+// Initialize loads the key range weights from storage.
+func (m *Manager) Initialize() error {
+	m.Lock()
+	defer m.Unlock()
+	var toDelete []string
+	err := m.storage.LoadRegionWeights(func(k, v string) {
+		var w KeyRangeWeight
+		if err := json.Unmarshal([]byte(v), &w); err != nil {
+			log.Error("failed to unmarshal region weight value", zap.String("weight-key", k), zap.String("weight-value", v), errs.ZapError(errs.ErrLoadRegionWeight))
+			toDelete = append(toDelete, k)
+			return
+		}
+		if err := w.Adjust(); err != nil {
+			log.Error("region weight is in bad format", zap.String("weight-key", k), zap.String("weight-value", v), errs.ZapError(errs.ErrLoadRegionWeight, err))
+			toDelete = append(toDelete, k)
+			return
+		}
+		m.weights[w.ID] = &w
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range toDelete {
+		if err := m.storage.DeleteRegionWeight(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetWeight inserts or updates a key range weight.
+func (m *Manager) SetWeight(w *KeyRangeWeight) error {
+	if err := w.Adjust(); err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	if err := m.storage.SaveRegionWeight(w.ID, w); err != nil {
+		return err
+	}
+	m.weights[w.ID] = w
+	log.Info("region weight updated", zap.Stringer("weight", w))
+	return nil
+}
+
+// DeleteWeight removes a key range weight.
+func (m *Manager) DeleteWeight(id string) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.weights[id]; !ok {
+		return nil
+	}
+	if err := m.storage.DeleteRegionWeight(id); err != nil {
+		return err
+	}
+	delete(m.weights, id)
+	log.Info("region weight is removed", zap.String("id", id))
+	return nil
+}
+
+// GetWeight returns the key range weight with the ID.
+func (m *Manager) GetWeight(id string) *KeyRangeWeight {
+	m.RLock()
+	defer m.RUnlock()
+	return m.weights[id]
+}
+
+// GetWeights returns all key range weights sorted by ID.
+func (m *Manager) GetWeights() []*KeyRangeWeight {
+	m.RLock()
+	defer m.RUnlock()
+	weights := make([]*KeyRangeWeight, 0, len(m.weights))
+	for _, w := range m.weights {
+		weights = append(weights, w)
+	}
+	sort.Slice(weights, func(i, j int) bool { return weights[i].ID < weights[j].ID })
+	return weights
+}
+
+// GetRegionWeight returns the weight of the region, which is the largest
+// weight of the key ranges overlapping with it, or DefaultWeight if there
+// is none.
+func (m *Manager) GetRegionWeight(region *core.RegionInfo) float64 {
+	m.RLock()
+	defer m.RUnlock()
+	weight, found := 0.0, false
+	for _, w := range m.weights {
+		if w.overlaps(region) && (!found || w.Weight > weight) {
+			weight, found = w.Weight, true
+		}
+	}
+	if !found {
+		return DefaultWeight
+	}
+	return weight
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package weight
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/kv"
+)
+
+func TestWeight(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testWeightSuite{})
+
+type testWeightSuite struct{}
+
+func newTestRegion(start, end string) *core.RegionInfo {
+	return core.NewRegionInfo(&metapb.Region{Id: 1, StartKey: []byte(start), EndKey: []byte(end)}, nil)
+}
+
+func (s *testWeightSuite) TestAdjust(c *C) {
+	testCases := []struct {
+		weight *KeyRangeWeight
+		valid  bool
+	}{
+		{&KeyRangeWeight{ID: "a", StartKeyHex: "61", EndKeyHex: "62", Weight: 2}, true},
+		{&KeyRangeWeight{ID: "a", StartKeyHex: "", EndKeyHex: "", Weight: 0.5}, true},
+		{&KeyRangeWeight{ID: "", StartKeyHex: "61", EndKeyHex: "62", Weight: 2}, false},
+		{&KeyRangeWeight{ID: "a", StartKeyHex: "62", EndKeyHex: "61", Weight: 2}, false},
+		{&KeyRangeWeight{ID: "a", StartKeyHex: "6x", EndKeyHex: "62", Weight: 2}, false},
+		{&KeyRangeWeight{ID: "a", StartKeyHex: "61", EndKeyHex: "62", Weight: 0}, false},
+	}
+	for _, t := range testCases {
+		c.Assert(t.weight.Adjust() == nil, Equals, t.valid)
+	}
+}
+
+func (s *testWeightSuite) TestManager(c *C) {
+	storage := core.NewStorage(kv.NewMemoryKV())
+	m := NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetRegionWeight(newTestRegion("a", "b")), Equals, DefaultWeight)
+
+	c.Assert(m.SetWeight(&KeyRangeWeight{ID: "w1", StartKeyHex: "61", EndKeyHex: "63", Weight: 2}), IsNil) // [a, c)
+	c.Assert(m.SetWeight(&KeyRangeWeight{ID: "w2", StartKeyHex: "62", EndKeyHex: "64", Weight: 3}), IsNil) // [b, d)
+	c.Assert(m.SetWeight(&KeyRangeWeight{ID: "w3", StartKeyHex: "66", EndKeyHex: "", Weight: 0.5}), IsNil) // [f, +inf)
+	c.Assert(m.SetWeight(&KeyRangeWeight{ID: "w4", StartKeyHex: "66", EndKeyHex: "", Weight: 0}), NotNil)
+	c.Assert(m.GetWeights(), HasLen, 3)
+
+	c.Assert(m.GetRegionWeight(newTestRegion("", "a")), Equals, DefaultWeight)
+	c.Assert(m.GetRegionWeight(newTestRegion("a", "b")), Equals, 2.0)
+	c.Assert(m.GetRegionWeight(newTestRegion("a", "c")), Equals, 3.0)
+	c.Assert(m.GetRegionWeight(newTestRegion("d", "f")), Equals, DefaultWeight)
+	c.Assert(m.GetRegionWeight(newTestRegion("g", "")), Equals, 0.5)
+
+	// The weights are loaded from storage.
+	m = NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetWeights(), HasLen, 3)
+	c.Assert(m.GetWeight("w2").Weight, Equals, 3.0)
+	c.Assert(m.GetRegionWeight(newTestRegion("c", "d")), Equals, 3.0)
+
+	c.Assert(m.DeleteWeight("w2"), IsNil)
+	c.Assert(m.DeleteWeight("w5"), IsNil)
+	c.Assert(m.GetWeight("w2"), IsNil)
+	c.Assert(m.GetRegionWeight(newTestRegion("c", "d")), Equals, DefaultWeight)
+	m = NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	c.Assert(m.GetWeights(), HasLen, 2)
+}
@@ -14,6 +14,7 @@
 package schedulers
 
 import (
+	"net/http"
 	"sort"
 	"strconv"
 
@@ -50,12 +51,13 @@ func init() {
 			}
 			conf.Ranges = ranges
 			conf.Name = BalanceLeaderName
+			conf.Batch = defaultBalanceLeaderBatch
 			return nil
 		}
 	})
 
 	schedule.RegisterScheduler(BalanceLeaderType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
-		conf := &balanceLeaderSchedulerConfig{}
+		conf := &balanceLeaderSchedulerConfig{storage: storage}
 		if err := decoder(conf); err != nil {
 			return nil, err
 		}
@@ -63,11 +65,6 @@ func init() {
 	})
 }
 
-type balanceLeaderSchedulerConfig struct {
-	Name   string          `json:"name"`
-	Ranges []core.KeyRange `json:"ranges"`
-}
-
 type balanceLeaderScheduler struct {
 	*BaseScheduler
 	conf         *balanceLeaderSchedulerConfig
@@ -114,6 +111,10 @@ func WithBalanceLeaderName(name string) BalanceLeaderCreateOption {
 	}
 }
 
+func (l *balanceLeaderScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.conf.ServeHTTP(w, r)
+}
+
 func (l *balanceLeaderScheduler) GetName() string {
 	return l.conf.Name
 }
@@ -123,7 +124,7 @@ func (l *balanceLeaderScheduler) GetType() string {
 }
 
 func (l *balanceLeaderScheduler) EncodeConfig() ([]byte, error) {
-	return schedule.EncodeConfig(l.conf)
+	return l.conf.EncodeConfig()
 }
 
 func (l *balanceLeaderScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
@@ -133,11 +134,39 @@ func (l *balanceLeaderScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
 	schedulerCounter.WithLabelValues(l.GetName(), "schedule").Inc()
 
+	// Creates at most `batch` operators in one round, so that a store with
+	// lots of excess leaders can be drained quickly. Each operator is taken
+	// into account by the following ones through the op influence, thus the
+	// batch stops early once the stores become balanced.
+	batch := l.conf.GetBatch()
+	if left := int(cluster.GetOpts().GetLeaderScheduleLimit()) - int(l.opController.OperatorCount(operator.OpLeader)); left < batch {
+		batch = left
+	}
+	opInfluence := l.opController.GetOpInfluence(cluster)
+	usedRegions := make(map[uint64]struct{})
+	var ops []*operator.Operator
+	for len(ops) < batch {
+		op := l.scheduleOnce(cluster, opInfluence, usedRegions)
+		if op == nil {
+			break
+		}
+		ops = append(ops, op)
+		usedRegions[op.RegionID()] = struct{}{}
+		op.UnfinishedInfluence(opInfluence, cluster.GetRegion(op.RegionID()))
+	}
+	if len(ops) > 1 {
+		schedulerCounter.WithLabelValues(l.GetName(), "batch").Inc()
+	}
+	return ops
+}
+
+// scheduleOnce creates a transfer leader operator for a region that is not in
+// usedRegions, or returns nil if the stores are balanced.
+func (l *balanceLeaderScheduler) scheduleOnce(cluster opt.Cluster, opInfluence operator.OpInfluence, usedRegions map[uint64]struct{}) *operator.Operator {
 	leaderSchedulePolicy := l.opController.GetLeaderSchedulePolicy()
 	stores := cluster.GetStores()
 	sources := filter.SelectSourceStores(stores, l.filters, cluster.GetOpts())
 	targets := filter.SelectTargetStores(stores, l.filters, cluster.GetOpts())
-	opInfluence := l.opController.GetOpInfluence(cluster)
 	kind := core.NewScheduleKind(core.LeaderKind, leaderSchedulePolicy)
 	sort.Slice(sources, func(i, j int) bool {
 		iOp := opInfluence.GetStoreInfluence(sources[i].GetID()).ResourceProperty(kind)
@@ -160,9 +189,9 @@ func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 			sourceStoreLabel := strconv.FormatUint(sourceID, 10)
 			l.counter.WithLabelValues("high-score", sourceStoreLabel).Inc()
 			for j := 0; j < balanceLeaderRetryLimit; j++ {
-				if ops := l.transferLeaderOut(cluster, source, opInfluence); len(ops) > 0 {
+				if ops := l.transferLeaderOut(cluster, source, opInfluence, usedRegions); len(ops) > 0 {
 					ops[0].Counters = append(ops[0].Counters, l.counter.WithLabelValues("transfer-out", sourceStoreLabel))
-					return ops
+					return ops[0]
 				}
 			}
 			log.Debug("no operator created for selected stores", zap.String("scheduler", l.GetName()), zap.Uint64("source", sourceID))
@@ -175,9 +204,9 @@ func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 			l.counter.WithLabelValues("low-score", targetStoreLabel).Inc()
 
 			for j := 0; j < balanceLeaderRetryLimit; j++ {
-				if ops := l.transferLeaderIn(cluster, target); len(ops) > 0 {
+				if ops := l.transferLeaderIn(cluster, target, opInfluence, usedRegions); len(ops) > 0 {
 					ops[0].Counters = append(ops[0].Counters, l.counter.WithLabelValues("transfer-in", targetStoreLabel))
-					return ops
+					return ops[0]
 				}
 			}
 			log.Debug("no operator created for selected stores", zap.String("scheduler", l.GetName()), zap.Uint64("target", targetID))
@@ -189,7 +218,7 @@ func (l *balanceLeaderScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 // transferLeaderOut transfers leader from the source store.
 // It randomly selects a health region from the source store, then picks
 // the best follower peer and transfers the leader.
-func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *core.StoreInfo, opInfluence operator.OpInfluence) []*operator.Operator {
+func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *core.StoreInfo, opInfluence operator.OpInfluence, usedRegions map[uint64]struct{}) []*operator.Operator {
 	sourceID := source.GetID()
 	region := cluster.RandLeaderRegion(sourceID, l.conf.Ranges, opt.HealthRegion(cluster))
 	if region == nil {
@@ -197,11 +226,17 @@ func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *
 		schedulerCounter.WithLabelValues(l.GetName(), "no-leader-region").Inc()
 		return nil
 	}
+	if _, ok := usedRegions[region.GetID()]; ok {
+		return nil
+	}
 	targets := cluster.GetFollowerStores(region)
 	finalFilters := l.filters
 	if leaderFilter := filter.NewPlacementLeaderSafeguard(l.GetName(), cluster, region, source); leaderFilter != nil {
 		finalFilters = append(l.filters, leaderFilter)
 	}
+	if key := cluster.GetOpts().GetBalanceLabelGroup(); key != "" {
+		finalFilters = append(finalFilters, filter.NewLabelGroupFilter(l.GetName(), key, source))
+	}
 	targets = filter.SelectTargetStores(targets, finalFilters, cluster.GetOpts())
 	leaderSchedulePolicy := l.opController.GetLeaderSchedulePolicy()
 	sort.Slice(targets, func(i, j int) bool {
@@ -211,7 +246,7 @@ func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *
 		return targets[i].LeaderScore(leaderSchedulePolicy, iOp) < targets[j].LeaderScore(leaderSchedulePolicy, jOp)
 	})
 	for _, target := range targets {
-		if op := l.createOperator(cluster, region, source, target); len(op) > 0 {
+		if op := l.createOperator(cluster, region, source, target, opInfluence); len(op) > 0 {
 			return op
 		}
 	}
@@ -223,7 +258,7 @@ func (l *balanceLeaderScheduler) transferLeaderOut(cluster opt.Cluster, source *
 // transferLeaderIn transfers leader to the target store.
 // It randomly selects a health region from the target store, then picks
 // the worst follower peer and transfers the leader.
-func (l *balanceLeaderScheduler) transferLeaderIn(cluster opt.Cluster, target *core.StoreInfo) []*operator.Operator {
+func (l *balanceLeaderScheduler) transferLeaderIn(cluster opt.Cluster, target *core.StoreInfo, opInfluence operator.OpInfluence, usedRegions map[uint64]struct{}) []*operator.Operator {
 	targetID := target.GetID()
 	region := cluster.RandFollowerRegion(targetID, l.conf.Ranges, opt.HealthRegion(cluster))
 	if region == nil {
@@ -231,6 +266,9 @@ func (l *balanceLeaderScheduler) transferLeaderIn(cluster opt.Cluster, target *c
 		schedulerCounter.WithLabelValues(l.GetName(), "no-follower-region").Inc()
 		return nil
 	}
+	if _, ok := usedRegions[region.GetID()]; ok {
+		return nil
+	}
 	leaderStoreID := region.GetLeader().GetStoreId()
 	source := cluster.GetStore(leaderStoreID)
 	if source == nil {
@@ -249,20 +287,23 @@ func (l *balanceLeaderScheduler) transferLeaderIn(cluster opt.Cluster, target *c
 	if leaderFilter := filter.NewPlacementLeaderSafeguard(l.GetName(), cluster, region, source); leaderFilter != nil {
 		finalFilters = append(l.filters, leaderFilter)
 	}
+	if key := cluster.GetOpts().GetBalanceLabelGroup(); key != "" {
+		finalFilters = append(finalFilters, filter.NewLabelGroupFilter(l.GetName(), key, source))
+	}
 	targets = filter.SelectTargetStores(targets, finalFilters, cluster.GetOpts())
 	if len(targets) < 1 {
 		log.Debug("region has no target store", zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()))
 		schedulerCounter.WithLabelValues(l.GetName(), "no-target-store").Inc()
 		return nil
 	}
-	return l.createOperator(cluster, region, source, targets[0])
+	return l.createOperator(cluster, region, source, targets[0], opInfluence)
 }
 
 // createOperator creates the operator according to the source and target store.
 // If the region is hot or the difference between the two stores is tolerable, then
 // no new operator need to be created, otherwise create an operator that transfers
 // the leader from the source store to the target store for the region.
-func (l *balanceLeaderScheduler) createOperator(cluster opt.Cluster, region *core.RegionInfo, source, target *core.StoreInfo) []*operator.Operator {
+func (l *balanceLeaderScheduler) createOperator(cluster opt.Cluster, region *core.RegionInfo, source, target *core.StoreInfo, opInfluence operator.OpInfluence) []*operator.Operator {
 	if cluster.IsRegionHot(region) {
 		log.Debug("region is hot region, ignore it", zap.String("scheduler", l.GetName()), zap.Uint64("region-id", region.GetID()))
 		schedulerCounter.WithLabelValues(l.GetName(), "region-hot").Inc()
@@ -272,7 +313,6 @@ func (l *balanceLeaderScheduler) createOperator(cluster opt.Cluster, region *cor
 	sourceID := source.GetID()
 	targetID := target.GetID()
 
-	opInfluence := l.opController.GetOpInfluence(cluster)
 	kind := core.NewScheduleKind(core.LeaderKind, cluster.GetOpts().GetLeaderSchedulePolicy())
 	shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, l.GetName())
 	if !shouldBalance {
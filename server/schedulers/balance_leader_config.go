@@ -0,0 +1,133 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/unrolled/render"
+)
+
+const (
+	// defaultBalanceLeaderBatch is the default number of operators created by
+	// the balance leader scheduler in one scheduling round.
+	defaultBalanceLeaderBatch = 4
+	// maxBalanceLeaderBatch is the max number of operators created by the
+	// balance leader scheduler in one scheduling round.
+	maxBalanceLeaderBatch = 10
+)
+
+type balanceLeaderSchedulerConfig struct {
+	sync.RWMutex
+	storage *core.Storage
+
+	Name   string          `json:"name"`
+	Ranges []core.KeyRange `json:"ranges"`
+	// Batch is the max number of transfer leader operators created in one
+	// scheduling round. 0 means 1, which is the case of the configs persisted
+	// before the batch is introduced.
+	Batch int `json:"batch"`
+}
+
+func (conf *balanceLeaderSchedulerConfig) EncodeConfig() ([]byte, error) {
+	conf.RLock()
+	defer conf.RUnlock()
+	return schedule.EncodeConfig(conf)
+}
+
+func (conf *balanceLeaderSchedulerConfig) GetBatch() int {
+	conf.RLock()
+	defer conf.RUnlock()
+	if conf.Batch <= 0 {
+		return 1
+	}
+	return conf.Batch
+}
+
+func (conf *balanceLeaderSchedulerConfig) validate() error {
+	if conf.Batch < 1 || conf.Batch > maxBalanceLeaderBatch {
+		return errors.Errorf("batch should be in [1, %d]", maxBalanceLeaderBatch)
+	}
+	return nil
+}
+
+func (conf *balanceLeaderSchedulerConfig) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := mux.NewRouter()
+	router.HandleFunc("/list", conf.handleGetConfig).Methods("GET")
+	router.HandleFunc("/config", conf.handleSetConfig).Methods("POST")
+	router.ServeHTTP(w, r)
+}
+
+func (conf *balanceLeaderSchedulerConfig) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	conf.RLock()
+	defer conf.RUnlock()
+	rd := render.New(render.Options{IndentJSON: true})
+	rd.JSON(w, http.StatusOK, conf)
+}
+
+func (conf *balanceLeaderSchedulerConfig) handleSetConfig(w http.ResponseWriter, r *http.Request) {
+	rd := render.New(render.Options{IndentJSON: true})
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(rd, w, r.Body, &input); err != nil {
+		return
+	}
+	// Only the batch can be changed online, the name and the ranges are
+	// decided when the scheduler is added.
+	for k := range input {
+		if k != "batch" {
+			rd.JSON(w, http.StatusBadRequest, "config item "+k+" not found")
+			return
+		}
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conf.Lock()
+	defer conf.Unlock()
+	oldBatch := conf.Batch
+	if err := json.Unmarshal(data, conf); err != nil {
+		conf.Batch = oldBatch // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.validate(); err != nil {
+		conf.Batch = oldBatch // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.persist(); err != nil {
+		conf.Batch = oldBatch // revert
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rd.Text(w, http.StatusOK, "success")
+}
+
+func (conf *balanceLeaderSchedulerConfig) persist() error {
+	data, err := schedule.EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveScheduleConfig(conf.Name, data)
+}
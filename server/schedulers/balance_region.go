@@ -14,6 +14,7 @@
 package schedulers
 
 import (
+	"net/http"
 	"sort"
 	"strconv"
 
@@ -21,11 +22,14 @@ import (
 	"github.com/pingcap/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/filter"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/schedule/weight"
+	"github.com/tikv/pd/server/statistics"
 	"go.uber.org/zap"
 )
 
@@ -42,11 +46,12 @@ func init() {
 			}
 			conf.Ranges = ranges
 			conf.Name = BalanceRegionName
+			conf.Dimension = balanceRegionBySize
 			return nil
 		}
 	})
 	schedule.RegisterScheduler(BalanceRegionType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
-		conf := &balanceRegionSchedulerConfig{}
+		conf := &balanceRegionSchedulerConfig{storage: storage}
 		if err := decoder(conf); err != nil {
 			return nil, err
 		}
@@ -63,11 +68,6 @@ const (
 	BalanceRegionType = "balance-region"
 )
 
-type balanceRegionSchedulerConfig struct {
-	Name   string          `json:"name"`
-	Ranges []core.KeyRange `json:"ranges"`
-}
-
 type balanceRegionScheduler struct {
 	*BaseScheduler
 	conf         *balanceRegionSchedulerConfig
@@ -113,6 +113,10 @@ func WithBalanceRegionName(name string) BalanceRegionCreateOption {
 	}
 }
 
+func (s *balanceRegionScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.conf.ServeHTTP(w, r)
+}
+
 func (s *balanceRegionScheduler) GetName() string {
 	return s.conf.Name
 }
@@ -122,11 +126,11 @@ func (s *balanceRegionScheduler) GetType() string {
 }
 
 func (s *balanceRegionScheduler) EncodeConfig() ([]byte, error) {
-	return schedule.EncodeConfig(s.conf)
+	return s.conf.EncodeConfig()
 }
 
 func (s *balanceRegionScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
-	return s.opController.OperatorCount(operator.OpRegion)-s.opController.OperatorCount(operator.OpMerge) < cluster.GetOpts().GetRegionScheduleLimit()
+	return s.opController.OperatorCount(operator.OpRegion)-s.opController.OperatorCount(operator.OpMerge) < s.opController.GetBalanceRegionScheduleLimit(cluster.GetOpts())
 }
 
 func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
@@ -135,13 +139,21 @@ func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 	opts := cluster.GetOpts()
 	stores = filter.SelectSourceStores(stores, s.filters, opts)
 	opInfluence := s.opController.GetOpInfluence(cluster)
-	kind := core.NewScheduleKind(core.RegionKind, core.BySize)
-	sort.Slice(stores, func(i, j int) bool {
-		iOp := opInfluence.GetStoreInfluence(stores[i].GetID()).ResourceProperty(kind)
-		jOp := opInfluence.GetStoreInfluence(stores[j].GetID()).ResourceProperty(kind)
-		return stores[i].RegionScore(opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), iOp) >
-			stores[j].RegionScore(opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), jOp)
-	})
+	var scorer *storeLoadScorer
+	if dimension := s.conf.GetDimension(); dimension != balanceRegionBySize {
+		scorer = newStoreLoadScorer(cluster, dimension, opInfluence)
+		sort.Slice(stores, func(i, j int) bool {
+			return scorer.score(stores[i], 0, 0) > scorer.score(stores[j], 0, 0)
+		})
+	} else {
+		kind := core.NewScheduleKind(core.RegionKind, core.BySize)
+		sort.Slice(stores, func(i, j int) bool {
+			iOp := opInfluence.GetStoreInfluence(stores[i].GetID()).ResourceProperty(kind)
+			jOp := opInfluence.GetStoreInfluence(stores[j].GetID()).ResourceProperty(kind)
+			return stores[i].RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetRegionScoreWeights(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), iOp) >
+				stores[j].RegionScore(opts.GetRegionScoreFormulaVersion(), opts.GetRegionScoreWeights(), opts.GetHighSpaceRatio(), opts.GetLowSpaceRatio(), jOp)
+		})
+	}
 	for _, source := range stores {
 		sourceID := source.GetID()
 
@@ -173,6 +185,13 @@ func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 				schedulerCounter.WithLabelValues(s.GetName(), "region-hot").Inc()
 				continue
 			}
+			// Skip the weighted regions unless there is no other choice, to keep
+			// the important regions from being moved only for balance.
+			if i < balanceRegionRetryLimit-1 && cluster.GetRegionWeight(region) > weight.DefaultWeight {
+				log.Debug("region is weighted", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
+				schedulerCounter.WithLabelValues(s.GetName(), "region-weighted").Inc()
+				continue
+			}
 			// Check region whether have leader
 			if region.GetLeader() == nil {
 				log.Warn("region have no leader", zap.String("scheduler", s.GetName()), zap.Uint64("region-id", region.GetID()))
@@ -181,7 +200,7 @@ func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 			}
 
 			oldPeer := region.GetStorePeer(sourceID)
-			if op := s.transferPeer(cluster, region, oldPeer); op != nil {
+			if op := s.transferPeer(cluster, region, oldPeer, scorer); op != nil {
 				op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
 				return []*operator.Operator{op}
 			}
@@ -191,7 +210,8 @@ func (s *balanceRegionScheduler) Schedule(cluster opt.Cluster) []*operator.Opera
 }
 
 // transferPeer selects the best store to create a new peer to replace the old peer.
-func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.RegionInfo, oldPeer *metapb.Peer) *operator.Operator {
+// The stores are measured by the scorer if it is not nil, otherwise by the region score.
+func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.RegionInfo, oldPeer *metapb.Peer, scorer *storeLoadScorer) *operator.Operator {
 	// scoreGuard guarantees that the distinct score will not decrease.
 	sourceStoreID := oldPeer.GetStoreId()
 	source := cluster.GetStore(sourceStoreID)
@@ -205,11 +225,19 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 		filter.NewPlacementSafeguard(s.GetName(), cluster, region, source),
 		filter.NewSpecialUseFilter(s.GetName()),
 		filter.StoreStateFilter{ActionScope: s.GetName(), MoveRegion: true},
+		filter.NewSnapshotCountFilter(s.GetName()),
+	}
+	if key := cluster.GetOpts().GetBalanceLabelGroup(); key != "" {
+		filters = append(filters, filter.NewLabelGroupFilter(s.GetName(), key, source))
 	}
 
+	comparer := filter.RegionScoreComparer(cluster.GetOpts())
+	if scorer != nil {
+		comparer = scorer.comparer()
+	}
 	candidates := filter.NewCandidates(cluster.GetStores()).
 		FilterTarget(cluster.GetOpts(), filters...).
-		Sort(filter.RegionScoreComparer(cluster.GetOpts()))
+		Sort(comparer)
 
 	for _, target := range candidates.Stores {
 		regionID := region.GetID()
@@ -217,10 +245,18 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 		targetID := target.GetID()
 		log.Debug("", zap.Uint64("region-id", regionID), zap.Uint64("source-store", sourceID), zap.Uint64("target-store", targetID))
 
-		opInfluence := s.opController.GetOpInfluence(cluster)
-		kind := core.NewScheduleKind(core.RegionKind, core.BySize)
-		shouldBalance, sourceScore, targetScore := shouldBalance(cluster, source, target, region, kind, opInfluence, s.GetName())
-		if !shouldBalance {
+		var (
+			shouldBalanced           bool
+			sourceScore, targetScore float64
+		)
+		if scorer != nil {
+			shouldBalanced, sourceScore, targetScore = scorer.shouldBalance(cluster, source, target, region, s.GetName())
+		} else {
+			opInfluence := s.opController.GetOpInfluence(cluster)
+			kind := core.NewScheduleKind(core.RegionKind, core.BySize)
+			shouldBalanced, sourceScore, targetScore = shouldBalance(cluster, source, target, region, kind, opInfluence, s.GetName())
+		}
+		if !shouldBalanced {
 			schedulerCounter.WithLabelValues(s.GetName(), "skip").Inc()
 			continue
 		}
@@ -248,3 +284,101 @@ func (s *balanceRegionScheduler) transferPeer(cluster opt.Cluster, region *core.
 	schedulerCounter.WithLabelValues(s.GetName(), "no-replacement").Inc()
 	return nil
 }
+
+// storeLoadScorer measures the load of the stores by the flow, or by both the
+// region score and the flow, for the balance region scheduler.
+type storeLoadScorer struct {
+	opts        *config.PersistOptions
+	dimension   string
+	opInfluence operator.OpInfluence
+	storesStats *statistics.StoresStats
+	// avgRegionScore and avgFlow are used to normalize the two parts of the
+	// score when balancing by both of them.
+	avgRegionScore float64
+	avgFlow        float64
+}
+
+func newStoreLoadScorer(cluster opt.Cluster, dimension string, opInfluence operator.OpInfluence) *storeLoadScorer {
+	scorer := &storeLoadScorer{
+		opts:        cluster.GetOpts(),
+		dimension:   dimension,
+		opInfluence: opInfluence,
+		storesStats: cluster.GetStoresStats(),
+	}
+	var count int
+	for _, store := range cluster.GetStores() {
+		if !store.IsUp() {
+			continue
+		}
+		scorer.avgRegionScore += scorer.regionScore(store, 0)
+		scorer.avgFlow += scorer.flow(store, 0)
+		count++
+	}
+	if count > 0 {
+		scorer.avgRegionScore /= float64(count)
+		scorer.avgFlow /= float64(count)
+	}
+	return scorer
+}
+
+func (l *storeLoadScorer) regionScore(store *core.StoreInfo, sizeDelta int64) float64 {
+	influence := l.opInfluence.GetStoreInfluence(store.GetID()).RegionSize
+	return store.RegionScore(l.opts.GetRegionScoreFormulaVersion(), l.opts.GetRegionScoreWeights(), l.opts.GetHighSpaceRatio(), l.opts.GetLowSpaceRatio(), influence+sizeDelta)
+}
+
+func (l *storeLoadScorer) flow(store *core.StoreInfo, flowDelta float64) float64 {
+	writeRate, readRate := l.storesStats.GetStoreBytesRate(store.GetID())
+	return writeRate + readRate + l.opInfluence.GetStoreInfluence(store.GetID()).ByteRate + flowDelta
+}
+
+// score returns the load of the store after the given size and flow are added.
+func (l *storeLoadScorer) score(store *core.StoreInfo, sizeDelta int64, flowDelta float64) float64 {
+	if l.dimension == balanceRegionByFlow {
+		return l.flow(store, flowDelta)
+	}
+	var score float64
+	if l.avgRegionScore > 0 {
+		score += l.regionScore(store, sizeDelta) / l.avgRegionScore
+	}
+	if l.avgFlow > 0 {
+		score += l.flow(store, flowDelta) / l.avgFlow
+	}
+	return score
+}
+
+func (l *storeLoadScorer) comparer() filter.StoreComparer {
+	return func(a, b *core.StoreInfo) int {
+		sa, sb := l.score(a, 0, 0), l.score(b, 0, 0)
+		switch {
+		case sa > sb:
+			return 1
+		case sa < sb:
+			return -1
+		default:
+			return 0
+		}
+	}
+}
+
+// shouldBalance checks whether the source store is still more loaded than the
+// target store after the region is moved.
+func (l *storeLoadScorer) shouldBalance(cluster opt.Cluster, source, target *core.StoreInfo, region *core.RegionInfo, scheduleName string) (shouldBalance bool, sourceScore float64, targetScore float64) {
+	kind := core.NewScheduleKind(core.RegionKind, core.BySize)
+	tolerantResource := getTolerantResource(cluster, region, kind)
+	regionFlow := region.GetBytesRate()
+	if l.dimension == balanceRegionByFlow && regionFlow == 0 {
+		// Moving a region without any flow makes no difference.
+		return false, l.score(source, 0, 0), l.score(target, 0, 0)
+	}
+	sourceScore = l.score(source, -tolerantResource, -regionFlow)
+	targetScore = l.score(target, tolerantResource, regionFlow)
+	shouldBalance = sourceScore > targetScore
+	if !shouldBalance {
+		log.Debug("skip balance region",
+			zap.String("scheduler", scheduleName), zap.Uint64("region-id", region.GetID()), zap.Uint64("source-store", source.GetID()), zap.Uint64("target-store", target.GetID()),
+			zap.String("dimension", l.dimension),
+			zap.Float64("source-score", sourceScore), zap.Float64("target-score", targetScore),
+			zap.Float64("region-flow", regionFlow), zap.Int64("tolerant-resource", tolerantResource))
+	}
+	return shouldBalance, sourceScore, targetScore
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/unrolled/render"
+)
+
+const (
+	// balanceRegionBySize balances the regions by the region score of the
+	// stores, which mainly depends on the region size.
+	balanceRegionBySize = "size"
+	// balanceRegionByFlow balances the regions by the read and written bytes
+	// of the stores.
+	balanceRegionByFlow = "flow"
+	// balanceRegionBySizeAndFlow balances the regions by both the region
+	// score and the flow of the stores, each of them is normalized by the
+	// average of the cluster.
+	balanceRegionBySizeAndFlow = "size-and-flow"
+)
+
+type balanceRegionSchedulerConfig struct {
+	sync.RWMutex
+	storage *core.Storage
+
+	Name   string          `json:"name"`
+	Ranges []core.KeyRange `json:"ranges"`
+	// Dimension decides how the load of a store is measured. Empty means
+	// balanceRegionBySize, which is the case of the configs persisted before
+	// the dimension is introduced.
+	Dimension string `json:"dimension"`
+}
+
+func (conf *balanceRegionSchedulerConfig) EncodeConfig() ([]byte, error) {
+	conf.RLock()
+	defer conf.RUnlock()
+	return schedule.EncodeConfig(conf)
+}
+
+func (conf *balanceRegionSchedulerConfig) GetDimension() string {
+	conf.RLock()
+	defer conf.RUnlock()
+	if conf.Dimension == "" {
+		return balanceRegionBySize
+	}
+	return conf.Dimension
+}
+
+func (conf *balanceRegionSchedulerConfig) validate() error {
+	switch conf.Dimension {
+	case balanceRegionBySize, balanceRegionByFlow, balanceRegionBySizeAndFlow:
+		return nil
+	default:
+		return errors.Errorf("dimension should be one of %s, %s and %s",
+			balanceRegionBySize, balanceRegionByFlow, balanceRegionBySizeAndFlow)
+	}
+}
+
+func (conf *balanceRegionSchedulerConfig) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := mux.NewRouter()
+	router.HandleFunc("/list", conf.handleGetConfig).Methods("GET")
+	router.HandleFunc("/config", conf.handleSetConfig).Methods("POST")
+	router.ServeHTTP(w, r)
+}
+
+func (conf *balanceRegionSchedulerConfig) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	conf.RLock()
+	defer conf.RUnlock()
+	rd := render.New(render.Options{IndentJSON: true})
+	rd.JSON(w, http.StatusOK, conf)
+}
+
+func (conf *balanceRegionSchedulerConfig) handleSetConfig(w http.ResponseWriter, r *http.Request) {
+	rd := render.New(render.Options{IndentJSON: true})
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(rd, w, r.Body, &input); err != nil {
+		return
+	}
+	// Only the dimension can be changed online, the name and the ranges are
+	// decided when the scheduler is added.
+	for k := range input {
+		if k != "dimension" {
+			rd.JSON(w, http.StatusBadRequest, "config item "+k+" not found")
+			return
+		}
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conf.Lock()
+	defer conf.Unlock()
+	oldDimension := conf.Dimension
+	if err := json.Unmarshal(data, conf); err != nil {
+		conf.Dimension = oldDimension // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.validate(); err != nil {
+		conf.Dimension = oldDimension // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.persist(); err != nil {
+		conf.Dimension = oldDimension // revert
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rd.Text(w, http.StatusOK, "success")
+}
+
+func (conf *balanceRegionSchedulerConfig) persist() error {
+	data, err := schedule.EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveScheduleConfig(conf.Name, data)
+}
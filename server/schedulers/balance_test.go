@@ -29,6 +29,7 @@ import (
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/statistics"
 	"github.com/tikv/pd/server/versioninfo"
 )
 
@@ -343,6 +344,49 @@ func (s *testBalanceLeaderSchedulerSuite) TestTransferLeaderOut(c *C) {
 	}
 }
 
+func (s *testBalanceLeaderSchedulerSuite) TestBatch(c *C) {
+	s.tc.SetTolerantSizeRatio(1)
+	// Stores:     1    2    3    4
+	// Leaders:    20   0    0    0
+	s.tc.AddLeaderStore(1, 20)
+	s.tc.AddLeaderStore(2, 0)
+	s.tc.AddLeaderStore(3, 0)
+	s.tc.AddLeaderStore(4, 0)
+	for i := uint64(1); i <= 10; i++ {
+		s.tc.AddLeaderRegion(i, 1, 2, 3, 4)
+	}
+
+	checkOps := func(ops []*operator.Operator, count int) {
+		c.Assert(ops, HasLen, count)
+		regions := make(map[uint64]struct{})
+		for _, op := range ops {
+			tr := op.Step(0).(operator.TransferLeader)
+			c.Assert(tr.FromStore, Equals, uint64(1))
+			regions[op.RegionID()] = struct{}{}
+		}
+		c.Assert(regions, HasLen, count)
+	}
+	checkOps(s.schedule(), defaultBalanceLeaderBatch)
+
+	// The batch is bounded by the leader schedule limit.
+	s.tc.SetLeaderScheduleLimit(2)
+	checkOps(s.schedule(), 2)
+	s.tc.SetLeaderScheduleLimit(4)
+
+	s.lb.(*balanceLeaderScheduler).conf.Batch = 1
+	checkOps(s.schedule(), 1)
+
+	// The batch stops once the stores become balanced.
+	// Stores:     1    2    3    4
+	// Leaders:    6    3    3    3
+	s.lb.(*balanceLeaderScheduler).conf.Batch = maxBalanceLeaderBatch
+	s.tc.UpdateLeaderCount(1, 6)
+	s.tc.UpdateLeaderCount(2, 3)
+	s.tc.UpdateLeaderCount(3, 3)
+	s.tc.UpdateLeaderCount(4, 3)
+	checkOps(s.schedule(), 1)
+}
+
 func (s *testBalanceLeaderSchedulerSuite) TestBalanceFilter(c *C) {
 	// Stores:     1    2    3    4
 	// Leaders:    1    2    3   16
@@ -376,6 +420,30 @@ func (s *testBalanceLeaderSchedulerSuite) TestBalanceFilter(c *C) {
 	c.Assert(s.schedule(), HasLen, 0)
 }
 
+func (s *testBalanceLeaderSchedulerSuite) TestBalanceLabelGroup(c *C) {
+	s.tc.SetTolerantSizeRatio(2.5)
+	// Stores:     1    2    3
+	// Zone:       z1   z1   z2
+	// Leaders:    16   8    0
+	// Region1:    L    F    F
+	s.tc.AddLabelsStore(1, 16, map[string]string{"zone": "z1"})
+	s.tc.AddLabelsStore(2, 8, map[string]string{"zone": "z1"})
+	s.tc.AddLabelsStore(3, 0, map[string]string{"zone": "z2"})
+	s.tc.UpdateLeaderCount(1, 16)
+	s.tc.UpdateLeaderCount(2, 8)
+	s.tc.UpdateLeaderCount(3, 0)
+	s.tc.AddLeaderRegion(1, 1, 2, 3)
+	testutil.CheckTransferLeader(c, s.schedule()[0], operator.OpKind(0), 1, 3)
+
+	// The leader is only transferred in the same zone.
+	s.tc.SetBalanceLabelGroup("zone")
+	testutil.CheckTransferLeader(c, s.schedule()[0], operator.OpKind(0), 1, 2)
+
+	// Stores in the same zone are balanced.
+	s.tc.UpdateLeaderCount(2, 16)
+	c.Assert(s.schedule(), IsNil)
+}
+
 func (s *testBalanceLeaderSchedulerSuite) TestLeaderWeight(c *C) {
 	// Stores:     1       2       3       4
 	// Leaders:    10      10      10      10
@@ -611,6 +679,43 @@ func (s *testBalanceRegionSchedulerSuite) TestBalance(c *C) {
 	c.Assert(sb.Schedule(tc), NotNil)
 }
 
+func (s *testBalanceRegionSchedulerSuite) TestDimension(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	sb, err := schedule.CreateScheduler(BalanceRegionType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	conf := sb.(*balanceRegionScheduler).conf
+	c.Assert(conf.GetDimension(), Equals, balanceRegionBySize)
+
+	opt.SetMaxReplicas(1)
+
+	// Store 1 hosts many cold regions, store 2 hosts a few regions with
+	// a lot of flow.
+	tc.AddRegionStore(1, 20)
+	tc.AddRegionStore(2, 2)
+	tc.AddRegionStore(3, 2)
+	tc.UpdateStorageWrittenBytes(1, 10*MB*statistics.StoreHeartBeatReportInterval)
+	tc.UpdateStorageWrittenBytes(2, 100*MB*statistics.StoreHeartBeatReportInterval)
+	tc.AddLeaderRegionWithWriteInfo(1, 2, 100*statistics.RegionHeartBeatReportInterval, 0, statistics.RegionHeartBeatReportInterval, nil)
+
+	// The region score of store 2 is the same as store 3.
+	c.Assert(sb.Schedule(tc), IsNil)
+
+	conf.Dimension = balanceRegionByFlow
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 2, 3)
+
+	conf.Dimension = balanceRegionBySizeAndFlow
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 2, 3)
+
+	// A region without any flow is not moved by flow.
+	tc.AddLeaderRegion(1, 2)
+	conf.Dimension = balanceRegionByFlow
+	c.Assert(sb.Schedule(tc), IsNil)
+}
+
 func (s *testBalanceRegionSchedulerSuite) TestReplicas3(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
@@ -811,6 +916,31 @@ func (s *testBalanceRegionSchedulerSuite) TestBalance1(c *C) {
 	testutil.CheckTransferPeer(c, sb.Schedule(tc)[0], operator.OpKind(0), 1, 4)
 }
 
+func (s *testBalanceRegionSchedulerSuite) TestBalanceLabelGroup(c *C) {
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.DisableFeature(versioninfo.JointConsensus)
+	oc := schedule.NewOperatorController(s.ctx, nil, nil)
+
+	sb, err := schedule.CreateScheduler(BalanceRegionType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigSliceDecoder(BalanceRegionType, []string{"", ""}))
+	c.Assert(err, IsNil)
+	opt.SetMaxReplicas(1)
+
+	tc.AddLabelsStore(1, 16, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(2, 8, map[string]string{"zone": "z1"})
+	tc.AddLabelsStore(3, 0, map[string]string{"zone": "z2"})
+	tc.AddLeaderRegion(1, 1)
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 1, 3)
+
+	// The region is only moved in the same zone.
+	tc.SetBalanceLabelGroup("zone")
+	testutil.CheckTransferPeerWithLeaderTransfer(c, sb.Schedule(tc)[0], operator.OpKind(0), 1, 2)
+
+	// Stores in the same zone are balanced.
+	tc.UpdateRegionCount(2, 16)
+	c.Assert(sb.Schedule(tc), IsNil)
+}
+
 func (s *testBalanceRegionSchedulerSuite) TestStoreWeight(c *C) {
 	opt := config.NewTestOptions()
 	tc := mockcluster.NewCluster(opt)
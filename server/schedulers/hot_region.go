@@ -169,11 +169,31 @@ func (h *hotScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
 
 func (h *hotScheduler) allowBalanceLeader(cluster opt.Cluster) bool {
 	return h.OpController.OperatorCount(operator.OpHotRegion) < cluster.GetOpts().GetHotRegionScheduleLimit() &&
-		h.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit()
+		h.OpController.OperatorCount(operator.OpLeader) < cluster.GetOpts().GetLeaderScheduleLimit() &&
+		h.allowHotOperator(transferLeader, h.conf.GetMaxLeaderOperators())
 }
 
 func (h *hotScheduler) allowBalanceRegion(cluster opt.Cluster) bool {
-	return h.OpController.OperatorCount(operator.OpHotRegion) < cluster.GetOpts().GetHotRegionScheduleLimit()
+	return h.OpController.OperatorCount(operator.OpHotRegion) < cluster.GetOpts().GetHotRegionScheduleLimit() &&
+		h.allowHotOperator(movePeer, h.conf.GetMaxPeerOperators())
+}
+
+// allowHotOperator checks if the running hot operators of the type are less
+// than the limit, 0 means no limit.
+func (h *hotScheduler) allowHotOperator(ty opType, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+	count := 0
+	for _, op := range h.OpController.GetOperators() {
+		if op.Kind()&operator.OpHotRegion == 0 {
+			continue
+		}
+		if isLeader := op.Kind()&operator.OpRegion == 0; isLeader == (ty == transferLeader) {
+			count++
+		}
+	}
+	return count < limit
 }
 
 func (h *hotScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
@@ -203,7 +223,10 @@ func (h *hotScheduler) prepareForBalance(cluster opt.Cluster) {
 
 	storesStat := cluster.GetStoresStats()
 
-	minHotDegree := cluster.GetOpts().GetHotRegionCacheHitsThreshold()
+	minHotDegree := h.conf.GetMinHotDegree()
+	if minHotDegree == 0 {
+		minHotDegree = cluster.GetOpts().GetHotRegionCacheHitsThreshold()
+	}
 	{ // update read statistics
 		regionRead := cluster.RegionReadStats()
 		storeByte := storesStat.GetStoresBytesReadStat()
@@ -801,6 +824,7 @@ func (bs *balanceSolver) filterDstStores() map[uint64]*storeLoadDetail {
 			filter.NewExcludedFilter(bs.sche.GetName(), bs.cur.region.GetStoreIds(), bs.cur.region.GetStoreIds()),
 			filter.NewSpecialUseFilter(bs.sche.GetName(), filter.SpecialUseHotRegion),
 			filter.NewPlacementSafeguard(bs.sche.GetName(), bs.cluster, bs.cur.region, srcStore),
+			filter.NewSnapshotCountFilter(bs.sche.GetName()),
 		}
 
 		candidates = bs.cluster.GetStores()
@@ -894,6 +918,17 @@ func (bs *balanceSolver) betterThan(old *solution) bool {
 		return false
 	}
 
+	// The regions with a larger weight are de-hotspotted first.
+	if bs.cur.region != old.region {
+		curWeight, oldWeight := bs.cluster.GetRegionWeight(bs.cur.region), bs.cluster.GetRegionWeight(old.region)
+		switch {
+		case curWeight > oldWeight:
+			return true
+		case curWeight < oldWeight:
+			return false
+		}
+	}
+
 	if r := bs.compareSrcStore(bs.cur.srcStoreID, old.srcStoreID); r < 0 {
 		return true
 	} else if r > 0 {
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/server/statistics"
@@ -55,6 +56,14 @@ type hotRegionSchedulerConfig struct {
 	MinHotKeyRate   float64 `json:"min-hot-key-rate"`
 	MaxZombieRounds int     `json:"max-zombie-rounds"`
 	MaxPeerNum      int     `json:"max-peer-number"`
+	// MinHotDegree is the min hot degree of the peers to be scheduled, 0
+	// means to use hot-region-cache-hits-threshold of the cluster.
+	MinHotDegree int `json:"min-hot-degree"`
+	// MaxPeerOperators and MaxLeaderOperators limit the running operators
+	// which move hot peers and transfer hot leaders, 0 means they are only
+	// limited by hot-region-schedule-limit.
+	MaxPeerOperators   int `json:"max-peer-operators"`
+	MaxLeaderOperators int `json:"max-leader-operators"`
 
 	// rank step ratio decide the step when calculate rank
 	// step = max current * rank step ratio
@@ -151,6 +160,40 @@ func (conf *hotRegionSchedulerConfig) GetMinHotByteRate() float64 {
 	return conf.MinHotByteRate
 }
 
+func (conf *hotRegionSchedulerConfig) GetMinHotDegree() int {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.MinHotDegree
+}
+
+func (conf *hotRegionSchedulerConfig) GetMaxPeerOperators() int {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.MaxPeerOperators
+}
+
+func (conf *hotRegionSchedulerConfig) GetMaxLeaderOperators() int {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.MaxLeaderOperators
+}
+
+func (conf *hotRegionSchedulerConfig) validate() error {
+	if conf.MinHotByteRate < 0 || conf.MinHotKeyRate < 0 {
+		return errors.New("min-hot-byte-rate and min-hot-key-rate should not be negative")
+	}
+	if conf.MinHotDegree < 0 || conf.MaxPeerOperators < 0 || conf.MaxLeaderOperators < 0 {
+		return errors.New("min-hot-degree, max-peer-operators and max-leader-operators should not be negative")
+	}
+	if conf.MaxPeerNum <= 0 {
+		return errors.New("max-peer-number should be positive")
+	}
+	if conf.SrcToleranceRatio <= 0 || conf.DstToleranceRatio <= 0 {
+		return errors.New("src-tolerance-ratio and dst-tolerance-ratio should be positive")
+	}
+	return nil
+}
+
 func (conf *hotRegionSchedulerConfig) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	router := mux.NewRouter()
 	router.HandleFunc("/list", conf.handleGetConfig).Methods("GET")
@@ -169,7 +212,11 @@ func (conf *hotRegionSchedulerConfig) handleSetConfig(w http.ResponseWriter, r *
 	conf.Lock()
 	defer conf.Unlock()
 	rd := render.New(render.Options{IndentJSON: true})
-	oldc, _ := json.Marshal(conf)
+	oldc, err := json.Marshal(conf)
+	if err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 	data, err := ioutil.ReadAll(r.Body)
 	r.Body.Close()
 	if err != nil {
@@ -177,13 +224,35 @@ func (conf *hotRegionSchedulerConfig) handleSetConfig(w http.ResponseWriter, r *
 		return
 	}
 
-	if err := json.Unmarshal(data, conf); err != nil {
+	// The input is decoded and validated on a copy of the config, so that
+	// the config in use is untouched if the input is invalid.
+	candidate := &hotRegionSchedulerConfig{}
+	if err := json.Unmarshal(oldc, candidate); err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := json.Unmarshal(data, candidate); err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := candidate.validate(); err != nil {
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	newc, err := json.Marshal(candidate)
+	if err != nil {
 		rd.JSON(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	newc, _ := json.Marshal(conf)
 	if !bytes.Equal(oldc, newc) {
-		conf.persist()
+		if err := json.Unmarshal(newc, conf); err != nil {
+			rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := conf.persist(); err != nil {
+			rd.JSON(w, http.StatusInternalServerError, err.Error())
+			return
+		}
 		rd.Text(w, http.StatusOK, "success")
 	}
 
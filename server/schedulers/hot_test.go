@@ -16,6 +16,9 @@ package schedulers
 import (
 	"context"
 	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -26,6 +29,7 @@ import (
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/kv"
 	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/placement"
 	"github.com/tikv/pd/server/statistics"
@@ -119,6 +123,76 @@ func (s *testHotSchedulerSuite) TestGCPendingOpInfos(c *C) {
 	}
 }
 
+func (s *testHotSchedulerSuite) TestHotOperatorLimit(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	for id := uint64(1); id <= 4; id++ {
+		tc.PutStoreWithLabels(id)
+	}
+	stream := hbstream.NewTestHeartbeatStreams(ctx, tc.ID, tc, false /* no need to run */)
+	oc := schedule.NewOperatorController(ctx, tc, stream)
+	sche, err := schedule.CreateScheduler(HotRegionType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigJSONDecoder([]byte("null")))
+	c.Assert(err, IsNil)
+	hb := sche.(*hotScheduler)
+	c.Assert(hb.allowBalanceLeader(tc), IsTrue)
+	c.Assert(hb.allowBalanceRegion(tc), IsTrue)
+
+	tc.AddLeaderRegion(1, 1, 2, 3)
+	tc.AddLeaderRegion(2, 1, 2, 3)
+	op1, err := operator.CreateTransferLeaderOperator("transfer-hot-read-leader", tc, tc.GetRegion(1), 1, 2, operator.OpHotRegion)
+	c.Assert(err, IsNil)
+	op2, err := operator.CreateMovePeerOperator("move-hot-write-peer", tc, tc.GetRegion(2), operator.OpHotRegion, 3, &metapb.Peer{StoreId: 4})
+	c.Assert(err, IsNil)
+	c.Assert(oc.AddOperator(op1, op2), IsTrue)
+
+	// The operators are only limited by the schedule limits by default.
+	c.Assert(hb.allowBalanceLeader(tc), IsTrue)
+	c.Assert(hb.allowBalanceRegion(tc), IsTrue)
+	hb.conf.MaxLeaderOperators = 1
+	c.Assert(hb.allowBalanceLeader(tc), IsFalse)
+	c.Assert(hb.allowBalanceRegion(tc), IsTrue)
+	hb.conf.MaxPeerOperators = 2
+	c.Assert(hb.allowBalanceRegion(tc), IsTrue)
+	hb.conf.MaxPeerOperators = 1
+	c.Assert(hb.allowBalanceRegion(tc), IsFalse)
+}
+
+func (s *testHotSchedulerSuite) TestHotConfigValidate(c *C) {
+	conf := initHotRegionScheduleConfig()
+	c.Assert(conf.validate(), IsNil)
+	conf.MinHotDegree = -1
+	c.Assert(conf.validate(), NotNil)
+	conf.MinHotDegree = 5
+	conf.SrcToleranceRatio = 0
+	c.Assert(conf.validate(), NotNil)
+	conf.SrcToleranceRatio = 1.1
+	conf.MaxPeerOperators = -1
+	c.Assert(conf.validate(), NotNil)
+	conf.MaxPeerOperators = 0
+	c.Assert(conf.validate(), IsNil)
+}
+
+func (s *testHotSchedulerSuite) TestHotConfigSetInvalid(c *C) {
+	conf := initHotRegionScheduleConfig()
+	conf.storage = core.NewStorage(kv.NewMemoryKV())
+	set := func(input string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/config", strings.NewReader(input))
+		conf.handleSetConfig(w, r)
+		return w.Code
+	}
+	// The config is untouched by the invalid input.
+	c.Assert(set(`{"min-hot-degree": 5, "max-peer-number": 0}`), Equals, http.StatusBadRequest)
+	c.Assert(set(`{"min-hot-degree": 5, "max-peer-number": "foo"}`), Equals, http.StatusInternalServerError)
+	c.Assert(conf.MinHotDegree, Equals, 0)
+	c.Assert(conf.MaxPeerNum, Equals, 1000)
+
+	c.Assert(set(`{"min-hot-degree": 5}`), Equals, http.StatusOK)
+	c.Assert(conf.MinHotDegree, Equals, 5)
+}
+
 func newTestRegion(id uint64) *core.RegionInfo {
 	peers := []*metapb.Peer{{Id: id*100 + 1, StoreId: 1}, {Id: id*100 + 2, StoreId: 2}, {Id: id*100 + 3, StoreId: 3}}
 	return core.NewRegionInfo(&metapb.Region{Id: id, Peers: peers}, peers[0])
@@ -0,0 +1,137 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/pingcap/kvproto/pkg/pdpb"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/operator"
+	"github.com/tikv/pd/server/schedule/opt"
+	"github.com/tikv/pd/server/statistics"
+)
+
+const (
+	// LoadSplitName is load split scheduler name.
+	LoadSplitName = "load-split-scheduler"
+	// LoadSplitType is load split scheduler type.
+	LoadSplitType = "load-split"
+)
+
+func init() {
+	schedule.RegisterSliceDecoderBuilder(LoadSplitType, func(args []string) schedule.ConfigDecoder {
+		return func(v interface{}) error {
+			if _, ok := v.(*loadSplitSchedulerConfig); !ok {
+				return errs.ErrScheduleConfigNotExist.FastGenByArgs()
+			}
+			return nil
+		}
+	})
+	schedule.RegisterScheduler(LoadSplitType, func(opController *schedule.OperatorController, storage *core.Storage, decoder schedule.ConfigDecoder) (schedule.Scheduler, error) {
+		conf := initLoadSplitSchedulerConfig()
+		if err := decoder(conf); err != nil {
+			return nil, err
+		}
+		conf.storage = storage
+		return newLoadSplitScheduler(opController, conf), nil
+	})
+}
+
+// loadSplitScheduler asks the leader of a region to split it when its flow
+// keeps exceeding the configured thresholds, so that the load of a hot region
+// can be spread by the other schedulers afterwards. The region is split by
+// size, as the pinned kvproto does not carry the bucket statistics that would
+// let the split keys follow the flow inside the region.
+type loadSplitScheduler struct {
+	*BaseScheduler
+	conf *loadSplitSchedulerConfig
+}
+
+// newLoadSplitScheduler creates a scheduler that splits hot regions by load.
+func newLoadSplitScheduler(opController *schedule.OperatorController, conf *loadSplitSchedulerConfig) schedule.Scheduler {
+	return &loadSplitScheduler{
+		BaseScheduler: NewBaseScheduler(opController),
+		conf:          conf,
+	}
+}
+
+func (s *loadSplitScheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.conf.ServeHTTP(w, r)
+}
+
+func (s *loadSplitScheduler) GetName() string {
+	return LoadSplitName
+}
+
+func (s *loadSplitScheduler) GetType() string {
+	return LoadSplitType
+}
+
+func (s *loadSplitScheduler) EncodeConfig() ([]byte, error) {
+	return s.conf.EncodeConfig()
+}
+
+func (s *loadSplitScheduler) IsScheduleAllowed(cluster opt.Cluster) bool {
+	return s.OpController.OperatorCount(operator.OpSplit) < s.conf.GetSplitLimit()
+}
+
+func (s *loadSplitScheduler) Schedule(cluster opt.Cluster) []*operator.Operator {
+	schedulerCounter.WithLabelValues(s.GetName(), "schedule").Inc()
+	for _, stat := range s.candidates(cluster) {
+		region := cluster.GetRegion(stat.RegionID)
+		if region == nil || region.GetMeta().GetRegionEpoch().GetVersion() != stat.Version {
+			schedulerCounter.WithLabelValues(s.GetName(), "stale-region").Inc()
+			continue
+		}
+		if s.OpController.GetOperator(region.GetID()) != nil {
+			schedulerCounter.WithLabelValues(s.GetName(), "region-busy").Inc()
+			continue
+		}
+		if !opt.IsRegionHealthy(cluster, region) {
+			schedulerCounter.WithLabelValues(s.GetName(), "unhealthy-region").Inc()
+			continue
+		}
+		op := operator.CreateSplitRegionOperator(LoadSplitType, region, 0, pdpb.CheckPolicy_APPROXIMATE, nil)
+		op.Counters = append(op.Counters, schedulerCounter.WithLabelValues(s.GetName(), "new-operator"))
+		return []*operator.Operator{op}
+	}
+	schedulerCounter.WithLabelValues(s.GetName(), "no-region").Inc()
+	return nil
+}
+
+// candidates returns the leader peers whose read or write flow exceeds the
+// thresholds, ordered by byte rate from high to low.
+func (s *loadSplitScheduler) candidates(cluster opt.Cluster) []*statistics.HotPeerStat {
+	minByteRate, minKeyRate := s.conf.GetMinByteRate(), s.conf.GetMinKeyRate()
+	hotDegree := int(cluster.GetOpts().GetHotRegionCacheHitsThreshold())
+	var ret []*statistics.HotPeerStat
+	for _, stats := range []map[uint64][]*statistics.HotPeerStat{cluster.RegionReadStats(), cluster.RegionWriteStats()} {
+		for _, peers := range stats {
+			for _, peer := range peers {
+				if !peer.IsLeader() || peer.HotDegree < hotDegree {
+					continue
+				}
+				if peer.GetByteRate() >= minByteRate || peer.GetKeyRate() >= minKeyRate {
+					ret = append(ret, peer)
+				}
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].GetByteRate() > ret[j].GetByteRate() })
+	return ret
+}
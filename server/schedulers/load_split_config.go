@@ -0,0 +1,133 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedulers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
+	"github.com/tikv/pd/pkg/apiutil"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/schedule"
+	"github.com/unrolled/render"
+)
+
+func initLoadSplitSchedulerConfig() *loadSplitSchedulerConfig {
+	return &loadSplitSchedulerConfig{
+		MinByteRate: 30 * 1024 * 1024,
+		MinKeyRate:  3000,
+		SplitLimit:  4,
+	}
+}
+
+type loadSplitSchedulerConfig struct {
+	sync.RWMutex
+	storage *core.Storage
+
+	// A hot region is split once its byte rate or key rate reaches one of
+	// the thresholds.
+	MinByteRate float64 `json:"min-byte-rate"`
+	MinKeyRate  float64 `json:"min-key-rate"`
+	// SplitLimit is the max coexist split operators created by the scheduler.
+	SplitLimit uint64 `json:"split-limit"`
+}
+
+func (conf *loadSplitSchedulerConfig) EncodeConfig() ([]byte, error) {
+	conf.RLock()
+	defer conf.RUnlock()
+	return schedule.EncodeConfig(conf)
+}
+
+func (conf *loadSplitSchedulerConfig) GetMinByteRate() float64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.MinByteRate
+}
+
+func (conf *loadSplitSchedulerConfig) GetMinKeyRate() float64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.MinKeyRate
+}
+
+func (conf *loadSplitSchedulerConfig) GetSplitLimit() uint64 {
+	conf.RLock()
+	defer conf.RUnlock()
+	return conf.SplitLimit
+}
+
+func (conf *loadSplitSchedulerConfig) validate() error {
+	if conf.MinByteRate <= 0 || conf.MinKeyRate <= 0 {
+		return errors.New("min-byte-rate and min-key-rate should be positive")
+	}
+	return nil
+}
+
+func (conf *loadSplitSchedulerConfig) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	router := mux.NewRouter()
+	router.HandleFunc("/list", conf.handleGetConfig).Methods("GET")
+	router.HandleFunc("/config", conf.handleSetConfig).Methods("POST")
+	router.ServeHTTP(w, r)
+}
+
+func (conf *loadSplitSchedulerConfig) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	conf.RLock()
+	defer conf.RUnlock()
+	rd := render.New(render.Options{IndentJSON: true})
+	rd.JSON(w, http.StatusOK, conf)
+}
+
+func (conf *loadSplitSchedulerConfig) handleSetConfig(w http.ResponseWriter, r *http.Request) {
+	rd := render.New(render.Options{IndentJSON: true})
+	var input map[string]interface{}
+	if err := apiutil.ReadJSONRespondError(rd, w, r.Body, &input); err != nil {
+		return
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conf.Lock()
+	defer conf.Unlock()
+	oldByteRate, oldKeyRate, oldLimit := conf.MinByteRate, conf.MinKeyRate, conf.SplitLimit
+	if err := json.Unmarshal(data, conf); err != nil {
+		conf.MinByteRate, conf.MinKeyRate, conf.SplitLimit = oldByteRate, oldKeyRate, oldLimit // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.validate(); err != nil {
+		conf.MinByteRate, conf.MinKeyRate, conf.SplitLimit = oldByteRate, oldKeyRate, oldLimit // revert
+		rd.JSON(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := conf.persist(); err != nil {
+		conf.MinByteRate, conf.MinKeyRate, conf.SplitLimit = oldByteRate, oldKeyRate, oldLimit // revert
+		rd.JSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rd.Text(w, http.StatusOK, "success")
+}
+
+func (conf *loadSplitSchedulerConfig) persist() error {
+	data, err := schedule.EncodeConfig(conf)
+	if err != nil {
+		return err
+	}
+	return conf.storage.SaveScheduleConfig(LoadSplitName, data)
+}
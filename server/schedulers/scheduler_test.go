@@ -19,12 +19,14 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/kvproto/pkg/pdpb"
 	"github.com/tikv/pd/pkg/mock/mockcluster"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/kv"
 	"github.com/tikv/pd/server/schedule"
+	"github.com/tikv/pd/server/schedule/hbstream"
 	"github.com/tikv/pd/server/schedule/operator"
 	"github.com/tikv/pd/server/schedule/opt"
 	"github.com/tikv/pd/server/schedule/placement"
@@ -195,6 +197,50 @@ func (s *testShuffleHotRegionSchedulerSuite) checkBalance(c *C, tc *mockcluster.
 	c.Assert(op[0].Step(1).(operator.PromoteLearner).ToStore, Not(Equals), 6)
 }
 
+var _ = Suite(&testLoadSplitSchedulerSuite{})
+
+type testLoadSplitSchedulerSuite struct{}
+
+func (s *testLoadSplitSchedulerSuite) TestSplit(c *C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opt := config.NewTestOptions()
+	tc := mockcluster.NewCluster(opt)
+	tc.SetHotRegionCacheHitsThreshold(0)
+	stream := hbstream.NewTestHeartbeatStreams(ctx, tc.ID, tc, false /* no need to run */)
+	oc := schedule.NewOperatorController(ctx, tc, stream)
+	ls, err := schedule.CreateScheduler(LoadSplitType, oc, core.NewStorage(kv.NewMemoryKV()), schedule.ConfigJSONDecoder([]byte(`{"min-byte-rate":262144,"min-key-rate":1000,"split-limit":1}`)))
+	c.Assert(err, IsNil)
+
+	tc.AddRegionStore(1, 2)
+	tc.AddRegionStore(2, 2)
+	tc.AddRegionStore(3, 2)
+
+	// No region reaches the thresholds.
+	tc.AddLeaderRegionWithReadInfo(1, 1, 128*KB*statistics.RegionHeartBeatReportInterval, 0, statistics.RegionHeartBeatReportInterval, []uint64{2, 3})
+	c.Assert(ls.Schedule(tc), IsNil)
+
+	// Region 2 is read hot by bytes.
+	tc.AddLeaderRegionWithReadInfo(2, 2, 512*KB*statistics.RegionHeartBeatReportInterval, 0, statistics.RegionHeartBeatReportInterval, []uint64{1, 3})
+	ops := ls.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].RegionID(), Equals, uint64(2))
+	c.Assert(ops[0].Kind()&operator.OpSplit, Equals, operator.OpSplit)
+	c.Assert(ops[0].Step(0).(operator.SplitRegion).Policy, Equals, pdpb.CheckPolicy_APPROXIMATE)
+
+	// Only one split operator is allowed at the same time.
+	c.Assert(oc.AddOperator(ops[0]), IsTrue)
+	c.Assert(ls.IsScheduleAllowed(tc), IsFalse)
+	oc.RemoveOperator(ops[0])
+	c.Assert(ls.IsScheduleAllowed(tc), IsTrue)
+
+	// Region 3 is write hot by keys and is preferred because of the higher byte rate.
+	tc.AddLeaderRegionWithWriteInfo(3, 3, 1024*KB*statistics.RegionHeartBeatReportInterval, 2000*statistics.RegionHeartBeatReportInterval, statistics.RegionHeartBeatReportInterval, []uint64{1, 2})
+	ops = ls.Schedule(tc)
+	c.Assert(ops, HasLen, 1)
+	c.Assert(ops[0].RegionID(), Equals, uint64(3))
+}
+
 var _ = Suite(&testHotRegionSchedulerSuite{})
 
 type testHotRegionSchedulerSuite struct{}
@@ -49,6 +49,7 @@ import (
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/encryptionkm"
+	"github.com/tikv/pd/server/gc"
 	"github.com/tikv/pd/server/id"
 	"github.com/tikv/pd/server/kv"
 	"github.com/tikv/pd/server/member"
@@ -63,12 +64,15 @@ import (
 	"go.etcd.io/etcd/pkg/types"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
 const (
 	etcdTimeout           = time.Second * 3
 	serverMetricsInterval = time.Minute
-	leaderTickInterval    = 50 * time.Millisecond
+	// tlsReloadInterval is the interval to check if the TLS files are changed.
+	tlsReloadInterval  = time.Minute
+	leaderTickInterval = 50 * time.Millisecond
 	// pdRootPath for all pd servers.
 	pdRootPath      = "/pd"
 	pdAPIPrefix     = "/pd/"
@@ -109,8 +113,10 @@ type Server struct {
 	client *clientv3.Client
 	// http client
 	httpClient *http.Client
-	clusterID  uint64 // pd cluster id.
-	rootPath   string
+	// reloads the TLS files, nil if TLS is not enabled.
+	tlsReloader *grpcutil.TLSReloader
+	clusterID   uint64 // pd cluster id.
+	rootPath    string
 
 	// Server services.
 	// for id allocator, we can use one allocator for
@@ -121,6 +127,8 @@ type Server struct {
 	encryptionKeyManager *encryptionkm.KeyManager
 	// for storage operation.
 	storage *core.Storage
+	// for the history of hot regions.
+	hotRegionStorage *core.HotRegionStorage
 	// for basicCluster operation.
 	basicCluster *core.BasicCluster
 	// for tso.
@@ -129,6 +137,8 @@ type Server struct {
 	cluster *cluster.RaftCluster
 	// For async region heartbeat.
 	hbStreams *hbstream.HeartbeatStreams
+	// reports the status of the server to the gRPC health clients.
+	healthServer *health.Server
 	// Zap logger
 	lg       *zap.Logger
 	logProps *log.ZapProperties
@@ -137,8 +147,8 @@ type Server struct {
 	startCallbacks []func()
 	closeCallbacks []func()
 
-	// serviceSafePointLock is a lock for UpdateServiceGCSafePoint
-	serviceSafePointLock sync.Mutex
+	// gcSafePointManager manages the GC safe points of the cluster and the services.
+	gcSafePointManager *gc.SafePointManager
 }
 
 // HandlerBuilder builds a server HTTP handler.
@@ -225,6 +235,7 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 		ctx:               ctx,
 		startTimestamp:    time.Now().Unix(),
 		DiagnosticsServer: sysutil.NewDiagnosticsServer(cfg.Log.File.Filename),
+		healthServer:      newHealthServer(),
 	}
 
 	s.handler = newHandler(s)
@@ -244,6 +255,7 @@ func CreateServer(ctx context.Context, cfg *config.Config, serviceBuilders ...Ha
 	etcdCfg.ServiceRegister = func(gs *grpc.Server) {
 		pdpb.RegisterPDServer(gs, s)
 		diagnosticspb.RegisterDiagnosticsServer(gs, s)
+		s.registerIntrospectionServices(gs)
 	}
 	s.etcdCfg = etcdCfg
 	if EnableZap {
@@ -318,12 +330,17 @@ func (s *Server) startEtcd(ctx context.Context) error {
 		}
 	}
 	s.client = client
-	s.httpClient = &http.Client{
-		Transport: &http.Transport{
-			DisableKeepAlives: true,
-			TLSClientConfig:   tlsConfig,
-		},
+	transport := &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig:   tlsConfig,
 	}
+	if tlsConfig != nil {
+		if s.tlsReloader, err = grpcutil.NewTLSReloader(s.cfg.Security.TLSConfig); err != nil {
+			return err
+		}
+		transport.DialTLS = s.tlsReloader.DialTLS
+	}
+	s.httpClient = &http.Client{Transport: transport}
 
 	failpoint.Inject("memberNil", func() {
 		time.Sleep(1500 * time.Millisecond)
@@ -361,7 +378,7 @@ func (s *Server) startServer(ctx context.Context) error {
 		return err
 	}
 	kvBase := kv.NewEtcdKVBase(s.client, s.rootPath)
-	encryptionKeyManager, err := encryptionkm.NewKeyManager(kvBase, &s.cfg.Security.Encryption)
+	encryptionKeyManager, err := encryptionkm.NewKeyManager(s.client, s.rootPath, &s.cfg.Security.Encryption)
 	if err != nil {
 		return err
 	}
@@ -377,9 +394,14 @@ func (s *Server) startServer(ctx context.Context) error {
 		core.WithRegionStorage(regionStorage),
 		core.WithEncryptionKeyManager(encryptionKeyManager),
 	)
+	s.gcSafePointManager = gc.NewSafePointManager(s.storage)
+	s.hotRegionStorage, err = core.NewHotRegionStorage(filepath.Join(s.cfg.DataDir, "hot-region"))
+	if err != nil {
+		return err
+	}
 	s.basicCluster = core.NewBasicCluster()
 	s.cluster = cluster.NewRaftCluster(ctx, s.GetClusterRootPath(), s.clusterID, syncer.NewRegionSyncer(s), s.client, s.httpClient)
-	s.hbStreams = hbstream.NewHeartbeatStreams(ctx, s.clusterID, s.cluster)
+	s.hbStreams = hbstream.NewHeartbeatStreams(ctx, s.clusterID, s.cluster, s.cfg.HeartbeatStreamBindInterval.Duration)
 
 	// Run callbacks
 	for _, cb := range s.startCallbacks {
@@ -388,6 +410,7 @@ func (s *Server) startServer(ctx context.Context) error {
 
 	// Server has started.
 	atomic.StoreInt64(&s.isServing, 1)
+	s.setServingStatus("", true)
 	return nil
 }
 
@@ -421,6 +444,8 @@ func (s *Server) Close() {
 
 	log.Info("closing server")
 
+	s.healthServer.Shutdown()
+
 	s.stopServerLoop()
 
 	if s.client != nil {
@@ -443,6 +468,11 @@ func (s *Server) Close() {
 	if err := s.storage.Close(); err != nil {
 		log.Error("close storage meet error", errs.ZapError(err))
 	}
+	if s.hotRegionStorage != nil {
+		if err := s.hotRegionStorage.Close(); err != nil {
+			log.Error("close hot region storage meet error", errs.ZapError(err))
+		}
+	}
 
 	// Run callbacks
 	for _, cb := range s.closeCallbacks {
@@ -488,11 +518,12 @@ func (s *Server) LoopContext() context.Context {
 
 func (s *Server) startServerLoop(ctx context.Context) {
 	s.serverLoopCtx, s.serverLoopCancel = context.WithCancel(ctx)
-	s.serverLoopWg.Add(4)
+	s.serverLoopWg.Add(5)
 	go s.leaderLoop()
 	go s.etcdLeaderLoop()
 	go s.serverMetricsLoop()
 	go s.tsoAllocatorLoop()
+	go s.tlsReloadLoop()
 }
 
 func (s *Server) stopServerLoop() {
@@ -684,6 +715,12 @@ func (s *Server) GetStorage() *core.Storage {
 // When we use it, we should prevent calling GetStorage, otherwise, it may cause a data race problem.
 func (s *Server) SetStorage(storage *core.Storage) {
 	s.storage = storage
+	s.gcSafePointManager = gc.NewSafePointManager(storage)
+}
+
+// GetHotRegionStorage returns the storage of hot region history.
+func (s *Server) GetHotRegionStorage() *core.HotRegionStorage {
+	return s.hotRegionStorage
 }
 
 // GetBasicCluster returns the basic cluster of server.
@@ -942,12 +979,29 @@ func (s *Server) GetLabelProperty() config.LabelPropertyConfig {
 	return s.persistOptions.GetLabelPropertyConfig().Clone()
 }
 
-// SetClusterVersion sets the version of cluster.
+// SetClusterVersion sets the version of cluster. The version can not be
+// higher than the minimum version of the stores, otherwise PD may send
+// commands that some stores can not execute.
 func (s *Server) SetClusterVersion(v string) error {
+	return s.setClusterVersion(v, false)
+}
+
+// ForceSetClusterVersion sets the version of cluster without checking the
+// versions of the stores.
+func (s *Server) ForceSetClusterVersion(v string) error {
+	return s.setClusterVersion(v, true)
+}
+
+func (s *Server) setClusterVersion(v string, force bool) error {
 	version, err := versioninfo.ParseVersion(v)
 	if err != nil {
 		return err
 	}
+	if rc := s.GetRaftCluster(); rc != nil && !force {
+		if minVersion := rc.GetMinStoreVersion(); minVersion != nil && minVersion.LessThan(*version) {
+			return errs.ErrClusterVersionTooHigh.FastGenByArgs(version, minVersion)
+		}
+	}
 	old := s.persistOptions.GetClusterVersion()
 	s.persistOptions.SetClusterVersion(version)
 	err = s.persistOptions.Persist(s.storage)
@@ -973,6 +1027,50 @@ func (s *Server) GetTLSConfig() *grpcutil.TLSConfig {
 	return &s.cfg.Security.TLSConfig
 }
 
+// ReloadTLS reloads the TLS files if they are changed, and returns the
+// expiration time of the certificate. The listeners and the etcd and gRPC
+// clients load the certificate and the key on every handshake, while a changed
+// CA only takes effect for the HTTP requests to other members after the
+// reload. Changing the CA of the listeners and the etcd and gRPC clients still
+// requires restarting.
+func (s *Server) ReloadTLS() (time.Time, error) {
+	if s.tlsReloader == nil {
+		return time.Time{}, errs.ErrSecurityConfig.FastGenByArgs("TLS is not enabled")
+	}
+	changed, err := s.tlsReloader.Reload()
+	if err != nil {
+		return time.Time{}, err
+	}
+	notAfter := s.tlsReloader.NotAfter()
+	if changed {
+		log.Info("TLS files are reloaded", zap.Time("cert-not-after", notAfter))
+	}
+	tlsCertExpireGauge.Set(float64(notAfter.Unix()))
+	return notAfter, nil
+}
+
+func (s *Server) tlsReloadLoop() {
+	defer logutil.LogPanic()
+	defer s.serverLoopWg.Done()
+
+	if s.tlsReloader == nil {
+		return
+	}
+	ticker := time.NewTicker(tlsReloadInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.ReloadTLS(); err != nil {
+			log.Error("failed to reload TLS files", errs.ZapError(err))
+		}
+		select {
+		case <-ticker.C:
+		case <-s.serverLoopCtx.Done():
+			log.Info("server is closed, exit TLS reload loop")
+			return
+		}
+	}
+}
+
 // GetServerRootPath returns the server root path.
 func (s *Server) GetServerRootPath() string {
 	return s.rootPath
@@ -1180,6 +1278,8 @@ func (s *Server) campaignLeader() {
 	defer s.stopRaftCluster()
 
 	s.member.EnableLeader()
+	s.setServingStatus(leaderHealthService, true)
+	defer s.setServingStatus(leaderHealthService, false)
 
 	CheckPDVersion(s.persistOptions)
 	log.Info("PD cluster leader is ready to serve", zap.String("pd-leader-name", s.Name()))
@@ -22,11 +22,13 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/tikv/pd/pkg/etcdutil"
+	"github.com/tikv/pd/pkg/grpcutil"
 	"github.com/tikv/pd/pkg/testutil"
 	"github.com/tikv/pd/server/config"
 	"go.etcd.io/etcd/embed"
 	"go.etcd.io/etcd/pkg/types"
 	"go.uber.org/goleak"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestServer(t *testing.T) {
@@ -95,6 +97,35 @@ func (s *testLeaderServerSuite) TearDownSuite(c *C) {
 	}
 }
 
+func (s *testLeaderServerSuite) TestHealthStatus(c *C) {
+	svrs := make([]*Server, 0, len(s.svrs))
+	for _, svr := range s.svrs {
+		svrs = append(svrs, svr)
+	}
+	leader := mustWaitLeader(c, svrs)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, svr := range svrs {
+		conn, err := grpcutil.GetClientConn(ctx, svr.GetAddr(), nil)
+		c.Assert(err, IsNil)
+		client := healthpb.NewHealthClient(conn)
+		check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+			resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			c.Assert(err, IsNil)
+			return resp.GetStatus()
+		}
+		c.Assert(check(""), Equals, healthpb.HealthCheckResponse_SERVING)
+		if svr == leader {
+			testutil.WaitUntil(c, func(c *C) bool {
+				return check(leaderHealthService) == healthpb.HealthCheckResponse_SERVING
+			})
+		} else {
+			c.Assert(check(leaderHealthService), Equals, healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+		conn.Close()
+	}
+}
+
 var _ = Suite(&testServerSuite{})
 
 type testServerSuite struct{}
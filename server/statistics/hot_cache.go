@@ -16,6 +16,7 @@ package statistics
 import (
 	"math/rand"
 
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 )
 
@@ -30,10 +31,10 @@ type HotCache struct {
 }
 
 // NewHotCache creates a new hot spot cache.
-func NewHotCache() *HotCache {
+func NewHotCache(opt *config.PersistOptions) *HotCache {
 	return &HotCache{
-		writeFlow: NewHotStoresStats(WriteFlow),
-		readFlow:  NewHotStoresStats(ReadFlow),
+		writeFlow: NewHotStoresStats(WriteFlow, opt),
+		readFlow:  NewHotStoresStats(ReadFlow, opt),
 	}
 }
 
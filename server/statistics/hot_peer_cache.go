@@ -18,19 +18,15 @@ import (
 	"time"
 
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 )
 
 const (
-	topNN             = 60
-	topNTTL           = 3 * RegionHeartBeatReportInterval * time.Second
-	hotThresholdRatio = 0.8
-
-	rollingWindowsSize = 5
+	topNN   = 60
+	topNTTL = 3 * RegionHeartBeatReportInterval * time.Second
 
 	hotRegionReportMinInterval = 3
-
-	hotRegionAntiCount = 2
 )
 
 var (
@@ -49,14 +45,16 @@ var (
 // hotPeerCache saves the hot peer's statistics.
 type hotPeerCache struct {
 	kind           FlowKind
+	opt            *config.PersistOptions
 	peersOfStore   map[uint64]*TopN               // storeID -> hot peers
 	storesOfRegion map[uint64]map[uint64]struct{} // regionID -> storeIDs
 }
 
 // NewHotStoresStats creates a HotStoresStats
-func NewHotStoresStats(kind FlowKind) *hotPeerCache {
+func NewHotStoresStats(kind FlowKind, opt *config.PersistOptions) *hotPeerCache {
 	return &hotPeerCache{
 		kind:           kind,
+		opt:            opt,
 		peersOfStore:   make(map[uint64]*TopN),
 		storesOfRegion: make(map[uint64]map[uint64]struct{}),
 	}
@@ -254,7 +252,7 @@ func (f *hotPeerCache) calcHotThresholds(storeID uint64) [dimLen]float64 {
 		keyDim:  tn.GetTopNMin(keyDim).(*HotPeerStat).KeyRate,
 	}
 	for k := 0; k < dimLen; k++ {
-		ret[k] = math.Max(ret[k]*hotThresholdRatio, minThresholds[k])
+		ret[k] = math.Max(ret[k]*f.opt.GetHotRegionThresholdRatio(), minThresholds[k])
 	}
 	return ret
 }
@@ -310,20 +308,47 @@ func (f *hotPeerCache) isRegionHotWithPeer(region *core.RegionInfo, peer *metapb
 }
 
 func (f *hotPeerCache) updateHotPeerStat(newItem, oldItem *HotPeerStat, storesStats *StoresStats) *HotPeerStat {
-	thresholds := f.calcHotThresholds(newItem.StoreID)
-	isHot := newItem.ByteRate >= thresholds[byteDim] ||
-		newItem.KeyRate >= thresholds[keyDim]
-
 	if newItem.needDelete {
 		return newItem
 	}
 
-	if oldItem != nil {
+	// The flow is smoothed by the moving average before it is compared with
+	// the thresholds, so that the transient spikes are not regarded as hot.
+	alpha := f.opt.GetHotRegionFlowEWMAAlpha()
+	switch {
+	case oldItem == nil:
+		newItem.rollingByteRate = NewEMA(alpha)
+		newItem.rollingKeyRate = NewEMA(alpha)
+	case oldItem.StoreID != newItem.StoreID:
+		// The peer is moved from another store, inherit the flow of the old
+		// peer without sharing the moving average with it.
+		newItem.rollingByteRate = NewEMA(alpha)
+		newItem.rollingByteRate.Set(oldItem.GetByteRate())
+		newItem.rollingKeyRate = NewEMA(alpha)
+		newItem.rollingKeyRate.Set(oldItem.GetKeyRate())
+	default:
 		newItem.rollingByteRate = oldItem.rollingByteRate
 		newItem.rollingKeyRate = oldItem.rollingKeyRate
+	}
+	newItem.rollingByteRate.Add(newItem.ByteRate)
+	newItem.rollingKeyRate.Add(newItem.KeyRate)
+
+	thresholds := f.calcHotThresholds(newItem.StoreID)
+	isHot := newItem.GetByteRate() >= thresholds[byteDim] ||
+		newItem.GetKeyRate() >= thresholds[keyDim]
+	// The hot degree only grows when the latest flow is also hot, so the
+	// decaying average of a transient spike can not make a peer hot.
+	isLatestHot := newItem.ByteRate >= thresholds[byteDim] ||
+		newItem.KeyRate >= thresholds[keyDim]
+
+	antiCount := f.opt.GetHotRegionAntiCount()
+	if oldItem != nil {
 		if isHot {
-			newItem.HotDegree = oldItem.HotDegree + 1
-			newItem.AntiCount = hotRegionAntiCount
+			newItem.HotDegree = oldItem.HotDegree
+			if isLatestHot {
+				newItem.HotDegree++
+			}
+			newItem.AntiCount = antiCount
 		} else {
 			newItem.HotDegree = oldItem.HotDegree - 1
 			newItem.AntiCount = oldItem.AntiCount - 1
@@ -335,14 +360,9 @@ func (f *hotPeerCache) updateHotPeerStat(newItem, oldItem *HotPeerStat, storesSt
 		if !isHot {
 			return nil
 		}
-		newItem.rollingByteRate = NewMedianFilter(rollingWindowsSize)
-		newItem.rollingKeyRate = NewMedianFilter(rollingWindowsSize)
-		newItem.AntiCount = hotRegionAntiCount
+		newItem.AntiCount = antiCount
 		newItem.isNew = true
 	}
 
-	newItem.rollingByteRate.Add(newItem.ByteRate)
-	newItem.rollingKeyRate.Add(newItem.KeyRate)
-
 	return newItem
 }
@@ -18,6 +18,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 )
 
@@ -26,7 +27,7 @@ var _ = Suite(&testHotPeerCache{})
 type testHotPeerCache struct{}
 
 func (t *testHotPeerCache) TestStoreTimeUnsync(c *C) {
-	cache := NewHotStoresStats(WriteFlow)
+	cache := NewHotStoresStats(WriteFlow, config.NewTestOptions())
 	stats := NewStoresStats()
 	peers := newPeers(3,
 		func(i int) uint64 { return uint64(10000 + i) },
@@ -56,6 +57,53 @@ func (t *testHotPeerCache) TestStoreTimeUnsync(c *C) {
 	}
 }
 
+func (t *testHotPeerCache) TestSmoothFlow(c *C) {
+	cache := NewHotStoresStats(WriteFlow, config.NewTestOptions())
+	stats := NewStoresStats()
+	peers := newPeers(3,
+		func(i int) uint64 { return uint64(10000 + i) },
+		func(i int) uint64 { return uint64(i) })
+	meta := &metapb.Region{
+		Id:          1000,
+		Peers:       peers,
+		RegionEpoch: &metapb.RegionEpoch{ConfVer: 6, Version: 6},
+	}
+	const interval = uint64(60)
+	report := func(byteRate uint64) []*HotPeerStat {
+		region := core.NewRegionInfo(meta, peers[0],
+			core.SetReportInterval(interval),
+			core.SetWrittenBytes(interval*byteRate))
+		res := cache.CheckRegionFlow(region, stats)
+		for _, p := range res {
+			cache.Update(p)
+		}
+		return res
+	}
+
+	// A transient spike can not make the region hot, and the region is
+	// removed after the average flow is cold.
+	res := report(100 * 1024)
+	c.Assert(res, HasLen, 3)
+	deleted := false
+	for i := 0; i < 20 && !deleted; i++ {
+		for _, item := range report(512) {
+			c.Assert(item.HotDegree, LessEqual, 0)
+			deleted = deleted || item.IsNeedDelete()
+		}
+	}
+	c.Assert(deleted, IsTrue)
+
+	// A transient drop does not make the hot region cold.
+	for i := 0; i < 3; i++ {
+		report(100 * 1024)
+	}
+	for _, item := range report(512) {
+		c.Assert(item.HotDegree, Equals, 2)
+		c.Assert(item.IsNeedDelete(), IsFalse)
+		c.Assert(item.GetByteRate(), Greater, float64(512))
+	}
+}
+
 type operator int
 
 const (
@@ -89,7 +137,7 @@ func testCache(c *C, t *testCacheCase) {
 		ReadFlow:  1, // only leader
 		WriteFlow: 3, // all peers
 	}
-	cache := NewHotStoresStats(t.kind)
+	cache := NewHotStoresStats(t.kind, config.NewTestOptions())
 	stats := NewStoresStats()
 	region := buildRegion(nil, nil, t.kind)
 	checkAndUpdate(c, cache, region, stats, defaultSize[t.kind])
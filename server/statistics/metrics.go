@@ -40,6 +40,22 @@ var (
 			Help:      "Status of the regions.",
 		}, []string{"type"})
 
+	namespaceRegionStatusGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "regions",
+			Name:      "namespace_status",
+			Help:      "Status of the regions in each namespace.",
+		}, []string{"namespace", "type"})
+
+	ruleGroupRegionStatusGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "pd",
+			Subsystem: "regions",
+			Name:      "rule_group_status",
+			Help:      "Status of the regions in each placement rule group.",
+		}, []string{"group", "type"})
+
 	clusterStatusGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "pd",
@@ -125,6 +141,8 @@ func init() {
 	prometheus.MustRegister(hotCacheStatusGauge)
 	prometheus.MustRegister(storeStatusGauge)
 	prometheus.MustRegister(regionStatusGauge)
+	prometheus.MustRegister(namespaceRegionStatusGauge)
+	prometheus.MustRegister(ruleGroupRegionStatusGauge)
 	prometheus.MustRegister(clusterStatusGauge)
 	prometheus.MustRegister(placementStatusGauge)
 	prometheus.MustRegister(configStatusGauge)
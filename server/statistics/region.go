@@ -34,6 +34,10 @@ type RegionStats struct {
 	StoreLeaderKeys  map[uint64]int64 `json:"store_leader_keys"`
 	StorePeerSize    map[uint64]int64 `json:"store_peer_size"`
 	StorePeerKeys    map[uint64]int64 `json:"store_peer_keys"`
+	WrittenBytes     uint64           `json:"written_bytes"`
+	ReadBytes        uint64           `json:"read_bytes"`
+	WrittenKeys      uint64           `json:"written_keys"`
+	ReadKeys         uint64           `json:"read_keys"`
 }
 
 // GetRegionStats sums regions' statistics.
@@ -66,6 +70,10 @@ func (s *RegionStats) Observe(r *core.RegionInfo) {
 	}
 	s.StorageSize += approximateSize
 	s.StorageKeys += approximateKeys
+	s.WrittenBytes += r.GetBytesWritten()
+	s.ReadBytes += r.GetBytesRead()
+	s.WrittenKeys += r.GetKeysWritten()
+	s.ReadKeys += r.GetKeysRead()
 	leader := r.GetLeader()
 	if leader != nil {
 		storeID := leader.GetStoreId()
@@ -15,6 +15,8 @@ package statistics
 
 import (
 	"github.com/pingcap/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tikv/pd/pkg/slice"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/core"
 	"github.com/tikv/pd/server/schedule/placement"
@@ -34,22 +36,61 @@ const (
 	EmptyRegion
 )
 
+var regionStatisticTypeNames = []struct {
+	typ  RegionStatisticType
+	name string
+}{
+	{MissPeer, "miss-peer-region-count"},
+	{ExtraPeer, "extra-peer-region-count"},
+	{DownPeer, "down-peer-region-count"},
+	{PendingPeer, "pending-peer-region-count"},
+	{OfflinePeer, "offline-peer-region-count"},
+	{LearnerPeer, "learner-peer-region-count"},
+	{EmptyRegion, "empty-region-count"},
+}
+
 const nonIsolation = "none"
 
+// regionGroup records the namespace and the placement rule groups which a
+// region belongs to.
+type regionGroup struct {
+	namespace       string
+	leaderNamespace string
+	ruleGroups      []string
+	leaderRuleGroup string
+	size            int64
+}
+
 // RegionStatistics is used to record the status of regions.
 type RegionStatistics struct {
 	opt         *config.PersistOptions
 	stats       map[RegionStatisticType]map[uint64]*core.RegionInfo
 	index       map[uint64]RegionStatisticType
+	groups      map[uint64]*regionGroup
 	ruleManager *placement.RuleManager
+	// namespaces and ruleGroups are the status of the groups, which are
+	// updated as the regions are observed.
+	namespaces map[string]*GroupStatus
+	ruleGroups map[string]*GroupStatus
+	// collectedNamespaces and collectedRuleGroups are the groups whose status
+	// is collected last time, so the gauges of the removed ones are deleted.
+	collectedNamespaces map[string]struct{}
+	collectedRuleGroups map[string]struct{}
+	// namespaceLabelKey is the store label key that assigns a store to a
+	// namespace.
+	namespaceLabelKey string
 }
 
 // NewRegionStatistics creates a new RegionStatistics.
-func NewRegionStatistics(opt *config.PersistOptions, ruleManager *placement.RuleManager) *RegionStatistics {
+func NewRegionStatistics(opt *config.PersistOptions, ruleManager *placement.RuleManager, namespaceLabelKey string) *RegionStatistics {
 	r := &RegionStatistics{
-		opt:   opt,
-		stats: make(map[RegionStatisticType]map[uint64]*core.RegionInfo),
-		index: make(map[uint64]RegionStatisticType),
+		opt:               opt,
+		stats:             make(map[RegionStatisticType]map[uint64]*core.RegionInfo),
+		index:             make(map[uint64]RegionStatisticType),
+		groups:            make(map[uint64]*regionGroup),
+		namespaces:        make(map[string]*GroupStatus),
+		ruleGroups:        make(map[string]*GroupStatus),
+		namespaceLabelKey: namespaceLabelKey,
 	}
 	r.stats[MissPeer] = make(map[uint64]*core.RegionInfo)
 	r.stats[ExtraPeer] = make(map[uint64]*core.RegionInfo)
@@ -87,6 +128,13 @@ func (r *RegionStatistics) Observe(region *core.RegionInfo, stores []*core.Store
 		peerTypeIndex RegionStatisticType
 		deleteIndex   RegionStatisticType
 	)
+	group := &regionGroup{size: region.GetApproximateSize()}
+	var leaderStore *core.StoreInfo
+	for _, store := range stores {
+		if store.GetID() == region.GetLeader().GetStoreId() {
+			leaderStore = store
+		}
+	}
 	desiredReplicas := r.opt.GetMaxReplicas()
 	if r.opt.IsPlacementRulesEnabled() {
 		if !r.ruleManager.IsInitialized() {
@@ -97,6 +145,16 @@ func (r *RegionStatistics) Observe(region *core.RegionInfo, stores []*core.Store
 		rules := r.ruleManager.GetRulesForApplyRegion(region)
 		for _, rule := range rules {
 			desiredReplicas += rule.Count
+			if !slice.AnyOf(group.ruleGroups, func(i int) bool { return group.ruleGroups[i] == rule.GroupID }) {
+				group.ruleGroups = append(group.ruleGroups, rule.GroupID)
+			}
+		}
+		group.leaderRuleGroup = leaderRuleGroup(rules, leaderStore)
+	}
+	if r.namespaceLabelKey != "" {
+		group.namespace = core.RegionLabelValue(region, stores, r.namespaceLabelKey)
+		if leaderStore != nil {
+			group.leaderNamespace = leaderStore.GetLabelValue(r.namespaceLabelKey)
 		}
 	}
 
@@ -142,30 +200,177 @@ func (r *RegionStatistics) Observe(region *core.RegionInfo, stores []*core.Store
 		deleteIndex = oldIndex &^ peerTypeIndex
 	}
 	r.deleteEntry(deleteIndex, regionID)
+	if oldGroup, ok := r.groups[regionID]; ok {
+		r.updateGroupStatus(oldGroup, r.index[regionID], -1)
+	}
+	r.updateGroupStatus(group, peerTypeIndex, 1)
+	r.groups[regionID] = group
 	r.index[regionID] = peerTypeIndex
 }
 
 // ClearDefunctRegion is used to handle the overlap region.
 func (r *RegionStatistics) ClearDefunctRegion(regionID uint64) {
-	if oldIndex, ok := r.index[regionID]; ok {
+	oldIndex, ok := r.index[regionID]
+	if ok {
 		r.deleteEntry(oldIndex, regionID)
 	}
+	if oldGroup, ok := r.groups[regionID]; ok {
+		r.updateGroupStatus(oldGroup, oldIndex, -1)
+		delete(r.groups, regionID)
+	}
+}
+
+// updateGroupStatus adds a region of the group with the status index to the
+// status of the groups if delta is 1, or removes it if delta is -1. The
+// groups without any region or leader are removed.
+func (r *RegionStatistics) updateGroupStatus(group *regionGroup, index RegionStatisticType, delta int) {
+	if group.namespace != "" {
+		updateGroupRegion(r.namespaces, group.namespace, group.size, index, delta)
+	}
+	for _, ruleGroup := range group.ruleGroups {
+		updateGroupRegion(r.ruleGroups, ruleGroup, group.size, index, delta)
+	}
+	if group.leaderNamespace != "" {
+		updateGroupLeader(r.namespaces, group.leaderNamespace, delta)
+	}
+	if group.leaderRuleGroup != "" {
+		updateGroupLeader(r.ruleGroups, group.leaderRuleGroup, delta)
+	}
+}
+
+func updateGroupRegion(groups map[string]*GroupStatus, key string, size int64, index RegionStatisticType, delta int) {
+	status := getGroupStatus(groups, key)
+	status.RegionCount += delta
+	status.RegionSize += int64(delta) * size
+	for _, t := range regionStatisticTypeNames {
+		if index&t.typ == 0 {
+			continue
+		}
+		status.Status[t.name] += delta
+		if status.Status[t.name] == 0 {
+			delete(status.Status, t.name)
+		}
+	}
+	removeEmptyGroup(groups, key)
+}
+
+func updateGroupLeader(groups map[string]*GroupStatus, key string, delta int) {
+	getGroupStatus(groups, key).LeaderCount += delta
+	removeEmptyGroup(groups, key)
+}
+
+func getGroupStatus(groups map[string]*GroupStatus, key string) *GroupStatus {
+	if groups[key] == nil {
+		groups[key] = newGroupStatus()
+	}
+	return groups[key]
+}
+
+func removeEmptyGroup(groups map[string]*GroupStatus, key string) {
+	if status := groups[key]; status.RegionCount == 0 && status.LeaderCount == 0 {
+		delete(groups, key)
+	}
+}
+
+// GroupStatus is the status of the regions in a namespace or a placement
+// rule group. The key of Status is the type of the region statistics, like
+// "miss-peer-region-count". The leaders of a namespace are the ones on its
+// stores, and the leaders of a rule group are the ones placed by its leader or
+// voter rules.
+type GroupStatus struct {
+	RegionCount int
+	LeaderCount int
+	RegionSize  int64
+	Status      map[string]int
+}
+
+func newGroupStatus() *GroupStatus {
+	return &GroupStatus{Status: make(map[string]int)}
+}
+
+// GetGroupStatus returns the status of the regions broken down by namespace
+// and by placement rule group.
+func (r *RegionStatistics) GetGroupStatus() (namespaces map[string]*GroupStatus, ruleGroups map[string]*GroupStatus) {
+	return cloneGroupStatus(r.namespaces), cloneGroupStatus(r.ruleGroups)
+}
+
+func cloneGroupStatus(groups map[string]*GroupStatus) map[string]*GroupStatus {
+	res := make(map[string]*GroupStatus, len(groups))
+	for key, status := range groups {
+		clone := *status
+		clone.Status = make(map[string]int, len(status.Status))
+		for name, count := range status.Status {
+			clone.Status[name] = count
+		}
+		res[key] = &clone
+	}
+	return res
 }
 
 // Collect collects the metrics of the regions' status.
 func (r *RegionStatistics) Collect() {
-	regionStatusGauge.WithLabelValues("miss-peer-region-count").Set(float64(len(r.stats[MissPeer])))
-	regionStatusGauge.WithLabelValues("extra-peer-region-count").Set(float64(len(r.stats[ExtraPeer])))
-	regionStatusGauge.WithLabelValues("down-peer-region-count").Set(float64(len(r.stats[DownPeer])))
-	regionStatusGauge.WithLabelValues("pending-peer-region-count").Set(float64(len(r.stats[PendingPeer])))
-	regionStatusGauge.WithLabelValues("offline-peer-region-count").Set(float64(len(r.stats[OfflinePeer])))
-	regionStatusGauge.WithLabelValues("learner-peer-region-count").Set(float64(len(r.stats[LearnerPeer])))
-	regionStatusGauge.WithLabelValues("empty-region-count").Set(float64(len(r.stats[EmptyRegion])))
+	for _, t := range regionStatisticTypeNames {
+		regionStatusGauge.WithLabelValues(t.name).Set(float64(len(r.stats[t.typ])))
+	}
+	r.collectedNamespaces = collectGroupStatus(namespaceRegionStatusGauge, r.namespaces, r.collectedNamespaces)
+	r.collectedRuleGroups = collectGroupStatus(ruleGroupRegionStatusGauge, r.ruleGroups, r.collectedRuleGroups)
+}
+
+// collectGroupStatus sets the gauges of the groups, and deletes the gauges of
+// the collected groups which no longer exist. It returns the groups collected
+// this time.
+func collectGroupStatus(gauge *prometheus.GaugeVec, groups map[string]*GroupStatus, collected map[string]struct{}) map[string]struct{} {
+	for group := range collected {
+		if _, ok := groups[group]; ok {
+			continue
+		}
+		for _, typ := range []string{"region-count", "leader-count", "region-size"} {
+			gauge.DeleteLabelValues(group, typ)
+		}
+		for _, t := range regionStatisticTypeNames {
+			gauge.DeleteLabelValues(group, t.name)
+		}
+	}
+	collected = make(map[string]struct{}, len(groups))
+	for group, status := range groups {
+		gauge.WithLabelValues(group, "region-count").Set(float64(status.RegionCount))
+		gauge.WithLabelValues(group, "leader-count").Set(float64(status.LeaderCount))
+		gauge.WithLabelValues(group, "region-size").Set(float64(status.RegionSize))
+		for _, t := range regionStatisticTypeNames {
+			gauge.WithLabelValues(group, t.name).Set(float64(status.Status[t.name]))
+		}
+		collected[group] = struct{}{}
+	}
+	return collected
+}
+
+// leaderRuleGroup returns the group of the rule which places the leader. The
+// leader rules are preferred to the voter rules.
+func leaderRuleGroup(rules []*placement.Rule, leader *core.StoreInfo) string {
+	var group string
+	for _, rule := range rules {
+		if rule.Role != placement.Leader && rule.Role != placement.Voter {
+			continue
+		}
+		if !placement.MatchLabelConstraints(leader, rule.LabelConstraints) {
+			continue
+		}
+		if rule.Role == placement.Leader {
+			return rule.GroupID
+		}
+		if group == "" {
+			group = rule.GroupID
+		}
+	}
+	return group
 }
 
 // Reset resets the metrics of the regions' status.
 func (r *RegionStatistics) Reset() {
 	regionStatusGauge.Reset()
+	namespaceRegionStatusGauge.Reset()
+	ruleGroupRegionStatusGauge.Reset()
+	r.collectedNamespaces, r.collectedRuleGroups = nil, nil
 }
 
 // LabelStatistics is the statistics of the level of labels.
@@ -72,7 +72,7 @@ func (t *testRegionStatisticsSuite) TestRegionStatistics(c *C) {
 	r2 := &metapb.Region{Id: 2, Peers: peers[0:2], StartKey: []byte("cc"), EndKey: []byte("dd")}
 	region1 := core.NewRegionInfo(r1, peers[0])
 	region2 := core.NewRegionInfo(r2, peers[0])
-	regionStats := NewRegionStatistics(opt, t.manager)
+	regionStats := NewRegionStatistics(opt, t.manager, "")
 	regionStats.Observe(region1, stores)
 	c.Assert(len(regionStats.stats[ExtraPeer]), Equals, 1)
 	c.Assert(len(regionStats.stats[LearnerPeer]), Equals, 1)
@@ -142,7 +142,7 @@ func (t *testRegionStatisticsSuite) TestRegionStatisticsWithPlacementRule(c *C)
 	region2 := core.NewRegionInfo(r2, peers[0])
 	region3 := core.NewRegionInfo(r3, peers[0])
 	region4 := core.NewRegionInfo(r4, peers[0])
-	regionStats := NewRegionStatistics(opt, t.manager)
+	regionStats := NewRegionStatistics(opt, t.manager, "")
 	// r2 didn't match the rules
 	regionStats.Observe(region2, stores)
 	c.Assert(len(regionStats.stats[MissPeer]), Equals, 1)
@@ -155,6 +155,79 @@ func (t *testRegionStatisticsSuite) TestRegionStatisticsWithPlacementRule(c *C)
 	c.Assert(len(regionStats.stats[ExtraPeer]), Equals, 1)
 }
 
+func (t *testRegionStatisticsSuite) TestRegionGroupStatus(c *C) {
+	opt := config.NewTestOptions()
+	opt.SetPlacementRuleEnabled(true)
+	peers := []*metapb.Peer{
+		{Id: 4, StoreId: 1},
+		{Id: 5, StoreId: 2},
+		{Id: 6, StoreId: 3},
+	}
+	metaStores := []*metapb.Store{
+		{Id: 1, Address: "mock://tikv-1", Labels: []*metapb.StoreLabel{{Key: "namespace", Value: "ns1"}}},
+		{Id: 2, Address: "mock://tikv-2", Labels: []*metapb.StoreLabel{{Key: "namespace", Value: "ns1"}}},
+		{Id: 3, Address: "mock://tikv-3", Labels: []*metapb.StoreLabel{{Key: "namespace", Value: "ns2"}}},
+	}
+	stores := make([]*core.StoreInfo, 0, len(metaStores))
+	for _, m := range metaStores {
+		stores = append(stores, core.NewStoreInfo(m))
+	}
+	r1 := &metapb.Region{Id: 1, Peers: peers, StartKey: []byte("aa"), EndKey: []byte("bb")}
+	r2 := &metapb.Region{Id: 2, Peers: peers[2:], StartKey: []byte("cc"), EndKey: []byte("dd")}
+	// The leader of region 1 is in ns2 while most of its peers are in ns1.
+	region1 := core.NewRegionInfo(r1, peers[2], core.SetApproximateSize(100))
+	region2 := core.NewRegionInfo(r2, peers[2], core.SetApproximateSize(30))
+	regionStats := NewRegionStatistics(opt, t.manager, "namespace")
+	regionStats.Observe(region1, stores)
+	regionStats.Observe(region2, stores[2:])
+
+	namespaces, ruleGroups := regionStats.GetGroupStatus()
+	c.Assert(namespaces, HasLen, 2)
+	c.Assert(namespaces["ns1"].RegionCount, Equals, 1)
+	c.Assert(namespaces["ns1"].LeaderCount, Equals, 0)
+	c.Assert(namespaces["ns1"].RegionSize, Equals, int64(100))
+	c.Assert(namespaces["ns1"].Status["miss-peer-region-count"], Equals, 0)
+	c.Assert(namespaces["ns2"].RegionCount, Equals, 1)
+	c.Assert(namespaces["ns2"].LeaderCount, Equals, 2)
+	c.Assert(namespaces["ns2"].RegionSize, Equals, int64(30))
+	c.Assert(namespaces["ns2"].Status["miss-peer-region-count"], Equals, 1)
+	c.Assert(ruleGroups, HasLen, 1)
+	c.Assert(ruleGroups["pd"].RegionCount, Equals, 2)
+	// Both leaders are placed by the voter rule of the group.
+	c.Assert(ruleGroups["pd"].LeaderCount, Equals, 2)
+	c.Assert(ruleGroups["pd"].RegionSize, Equals, int64(130))
+	c.Assert(ruleGroups["pd"].Status["miss-peer-region-count"], Equals, 1)
+
+	// Observing a region again replaces its old status.
+	regionStats.Observe(region2.Clone(core.SetApproximateSize(40), core.WithAddPeer(peers[0]), core.WithAddPeer(peers[1])), stores)
+	namespaces, ruleGroups = regionStats.GetGroupStatus()
+	c.Assert(namespaces["ns1"].RegionCount, Equals, 2)
+	c.Assert(namespaces["ns1"].RegionSize, Equals, int64(140))
+	c.Assert(namespaces["ns2"].RegionCount, Equals, 0)
+	c.Assert(namespaces["ns2"].LeaderCount, Equals, 2)
+	c.Assert(ruleGroups["pd"].RegionCount, Equals, 2)
+	c.Assert(ruleGroups["pd"].RegionSize, Equals, int64(140))
+	c.Assert(ruleGroups["pd"].Status, DeepEquals, map[string]int{})
+	regionStats.Observe(region2, stores[2:])
+
+	regionStats.ClearDefunctRegion(2)
+	namespaces, ruleGroups = regionStats.GetGroupStatus()
+	c.Assert(namespaces["ns2"].RegionCount, Equals, 0)
+	c.Assert(namespaces["ns2"].LeaderCount, Equals, 1)
+	c.Assert(ruleGroups["pd"].RegionCount, Equals, 1)
+	c.Assert(ruleGroups["pd"].LeaderCount, Equals, 1)
+
+	// Only the gauges of the groups which no longer exist are deleted.
+	regionStats.Collect()
+	regionStats.ClearDefunctRegion(1)
+	regionStats.Observe(region2, stores[2:])
+	regionStats.Collect()
+	c.Assert(namespaceRegionStatusGauge.DeleteLabelValues("ns1", "region-count"), IsFalse)
+	c.Assert(namespaceRegionStatusGauge.DeleteLabelValues("ns2", "region-count"), IsTrue)
+	c.Assert(ruleGroupRegionStatusGauge.DeleteLabelValues("pd", "region-count"), IsTrue)
+	regionStats.Reset()
+}
+
 func (t *testRegionStatisticsSuite) TestRegionLabelIsolationLevel(c *C) {
 	locationLabels := []string{"zone", "rack", "host"}
 	labelLevelStats := NewLabelStatistics()
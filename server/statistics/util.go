@@ -82,3 +82,46 @@ func (r *MedianFilter) Set(n float64) {
 	r.records[0] = n
 	r.count = 1
 }
+
+// EMA works as an exponential moving average filter, the weight of a data
+// point decreases exponentially as new data points are added. It follows the
+// trend more smoothly than the raw data, so transient spikes are damped.
+// References: https://en.wikipedia.org/wiki/Moving_average#Exponential_moving_average.
+type EMA struct {
+	// alpha is the weight of the newest data point, which is in (0, 1].
+	alpha float64
+	value float64
+	count uint64
+}
+
+// NewEMA returns an EMA with the weight of the newest data point.
+func NewEMA(alpha float64) *EMA {
+	return &EMA{alpha: alpha}
+}
+
+// Add adds a data point.
+func (e *EMA) Add(n float64) {
+	if e.count == 0 {
+		e.value = n
+	} else {
+		e.value = e.alpha*n + (1-e.alpha)*e.value
+	}
+	e.count++
+}
+
+// Get returns the exponential moving average of the data set.
+func (e *EMA) Get() float64 {
+	return e.value
+}
+
+// Reset cleans the data set.
+func (e *EMA) Reset() {
+	e.value = 0
+	e.count = 0
+}
+
+// Set = Reset + Add.
+func (e *EMA) Set(n float64) {
+	e.value = n
+	e.count = 1
+}
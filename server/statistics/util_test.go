@@ -81,3 +81,20 @@ func (t *testMovingAvg) TestMedianFilter(c *C) {
 	t.checkAdd(c, mf, data, expected)
 	t.checkSet(c, mf, data, expected)
 }
+
+func (t *testMovingAvg) TestEMA(c *C) {
+	var empty float64 = 0
+	data := []float64{2, 4, 2, 800, 600, 6, 3}
+	expected := []float64{2, 3, 2.5, 401.25, 500.625, 253.3125, 128.15625}
+
+	ema := NewEMA(0.5)
+	c.Assert(ema.Get(), Equals, empty)
+
+	t.checkReset(c, ema, empty)
+	t.checkAdd(c, ema, data, expected)
+	t.checkSet(c, ema, data, expected)
+
+	// The raw data is followed if the weight of the newest data point is 1.
+	ema = NewEMA(1)
+	t.checkAdd(c, ema, data, data)
+}
@@ -0,0 +1,229 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storeconfig
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/log"
+	"github.com/tikv/pd/pkg/errs"
+	"github.com/tikv/pd/server/core"
+	"go.uber.org/zap"
+)
+
+// globalKey is the storage key of the config snippet shared by all stores.
+const globalKey = "global"
+
+// Snippet is a versioned set of config items of the stores, such as
+// `raftstore.region-split-check-diff = "16MB"`. The version is increased
+// every time the snippet changes. The stores poll for the snippets instead
+// of being pushed, so a change takes effect on a store only at its next poll,
+// which may be a whole poll interval later. A shorter interval applies the
+// changes sooner at the cost of more requests to PD. PD doesn't notify the
+// stores of the changes through the store heartbeat responses, as the
+// StoreHeartbeatResponse of kvproto has no field to carry them.
+type Snippet struct {
+	Version uint64            `json:"version"`
+	Items   map[string]string `json:"items"`
+}
+
+func newSnippet() *Snippet {
+	return &Snippet{Items: make(map[string]string)}
+}
+
+func (s *Snippet) clone() *Snippet {
+	items := make(map[string]string, len(s.Items))
+	for k, v := range s.Items {
+		items[k] = v
+	}
+	return &Snippet{Version: s.Version, Items: items}
+}
+
+// update applies the items to the snippet, an item with an empty value is
+// removed. It returns false if nothing changes.
+func (s *Snippet) update(items map[string]string) bool {
+	changed := false
+	for k, v := range items {
+		old, ok := s.Items[k]
+		switch {
+		case v == "" && ok:
+			delete(s.Items, k)
+			changed = true
+		case v != "" && (!ok || old != v):
+			s.Items[k] = v
+			changed = true
+		}
+	}
+	if changed {
+		s.Version++
+	}
+	return changed
+}
+
+// StoreConfig is the effective config of a store, which is the global
+// snippet overridden by the snippet of the store.
+type StoreConfig struct {
+	StoreID       uint64            `json:"store_id"`
+	GlobalVersion uint64            `json:"global_version"`
+	LocalVersion  uint64            `json:"local_version"`
+	Items         map[string]string `json:"items"`
+}
+
+// IsNewerThan checks if the config is updated after the given versions,
+// which the stores use to know whether they need to reload the config.
+func (c *StoreConfig) IsNewerThan(globalVersion, localVersion uint64) bool {
+	return c.GlobalVersion > globalVersion || c.LocalVersion > localVersion
+}
+
+// Manager holds the config snippets of the stores.
+type Manager struct {
+	sync.RWMutex
+	storage *core.Storage
+	global  *Snippet
+	stores  map[uint64]*Snippet
+}
+
+// NewManager creates a Manager instance.
+func NewManager(storage *core.Storage) *Manager {
+	return &Manager{
+		storage: storage,
+		global:  newSnippet(),
+		stores:  make(map[uint64]*Snippet),
+	}
+}
+
+// Initialize loads the config snippets from storage.
+func (m *Manager) Initialize() error {
+	m.Lock()
+	defer m.Unlock()
+	return m.storage.LoadStoreConfigs(func(k, v string) {
+		s := newSnippet()
+		if err := json.Unmarshal([]byte(v), s); err != nil {
+			log.Error("failed to unmarshal store config", zap.String("config-key", k), zap.String("config-value", v), errs.ZapError(errs.ErrLoadStoreConfig))
+			return
+		}
+		if s.Items == nil {
+			s.Items = make(map[string]string)
+		}
+		if k == globalKey {
+			m.global = s
+			return
+		}
+		storeID, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			log.Error("invalid store config key", zap.String("config-key", k), errs.ZapError(errs.ErrLoadStoreConfig))
+			return
+		}
+		m.stores[storeID] = s
+	})
+}
+
+// GetGlobal returns the config snippet shared by all stores.
+func (m *Manager) GetGlobal() *Snippet {
+	m.RLock()
+	defer m.RUnlock()
+	return m.global.clone()
+}
+
+// UpdateGlobal updates the config items shared by all stores.
+func (m *Manager) UpdateGlobal(items map[string]string) error {
+	m.Lock()
+	defer m.Unlock()
+	s := m.global.clone()
+	if !s.update(items) {
+		return nil
+	}
+	if err := m.storage.SaveStoreConfig(globalKey, s); err != nil {
+		return err
+	}
+	m.global = s
+	log.Info("global store config updated", zap.Uint64("version", s.Version), zap.Any("items", items))
+	return nil
+}
+
+// GetStoreOverride returns the config snippet overriding the global one for
+// the store, nil if there is none.
+func (m *Manager) GetStoreOverride(storeID uint64) *Snippet {
+	m.RLock()
+	defer m.RUnlock()
+	if s, ok := m.stores[storeID]; ok && len(s.Items) > 0 {
+		return s.clone()
+	}
+	return nil
+}
+
+// UpdateStoreOverride updates the config items of the store which override
+// the global ones.
+func (m *Manager) UpdateStoreOverride(storeID uint64, items map[string]string) error {
+	m.Lock()
+	defer m.Unlock()
+	s, ok := m.stores[storeID]
+	if ok {
+		s = s.clone()
+	} else {
+		s = newSnippet()
+	}
+	if !s.update(items) {
+		return nil
+	}
+	if err := m.storage.SaveStoreConfig(strconv.FormatUint(storeID, 10), s); err != nil {
+		return err
+	}
+	m.stores[storeID] = s
+	log.Info("store config updated", zap.Uint64("store-id", storeID), zap.Uint64("version", s.Version), zap.Any("items", items))
+	return nil
+}
+
+// DeleteStoreOverride removes the config items of the store, so that the
+// store uses the global ones. The emptied snippet is kept to keep the version
+// of the store increasing, otherwise the store can't tell the removal, or a
+// later override, from the config it has loaded.
+func (m *Manager) DeleteStoreOverride(storeID uint64) error {
+	m.Lock()
+	defer m.Unlock()
+	s, ok := m.stores[storeID]
+	if !ok || len(s.Items) == 0 {
+		return nil
+	}
+	s = &Snippet{Version: s.Version + 1, Items: make(map[string]string)}
+	if err := m.storage.SaveStoreConfig(strconv.FormatUint(storeID, 10), s); err != nil {
+		return err
+	}
+	m.stores[storeID] = s
+	log.Info("store config is removed", zap.Uint64("store-id", storeID), zap.Uint64("version", s.Version))
+	return nil
+}
+
+// GetStoreConfig returns the effective config of the store.
+func (m *Manager) GetStoreConfig(storeID uint64) *StoreConfig {
+	m.RLock()
+	defer m.RUnlock()
+	cfg := &StoreConfig{
+		StoreID:       storeID,
+		GlobalVersion: m.global.Version,
+		Items:         make(map[string]string, len(m.global.Items)),
+	}
+	for k, v := range m.global.Items {
+		cfg.Items[k] = v
+	}
+	if s, ok := m.stores[storeID]; ok {
+		cfg.LocalVersion = s.Version
+		for k, v := range s.Items {
+			cfg.Items[k] = v
+		}
+	}
+	return cfg
+}
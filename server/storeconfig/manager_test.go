@@ -0,0 +1,81 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storeconfig
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/pd/server/core"
+	"github.com/tikv/pd/server/kv"
+)
+
+func TestStoreConfig(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testManagerSuite{})
+
+type testManagerSuite struct{}
+
+func (s *testManagerSuite) TestManager(c *C) {
+	storage := core.NewStorage(kv.NewMemoryKV())
+	m := NewManager(storage)
+	c.Assert(m.Initialize(), IsNil)
+	cfg := m.GetStoreConfig(1)
+	c.Assert(cfg.GlobalVersion, Equals, uint64(0))
+	c.Assert(cfg.Items, HasLen, 0)
+
+	c.Assert(m.UpdateGlobal(map[string]string{"a": "1", "b": "2"}), IsNil)
+	c.Assert(m.UpdateStoreOverride(1, map[string]string{"b": "3"}), IsNil)
+	// Nothing changes.
+	c.Assert(m.UpdateGlobal(map[string]string{"a": "1", "c": ""}), IsNil)
+	c.Assert(m.GetGlobal().Version, Equals, uint64(1))
+
+	cfg = m.GetStoreConfig(1)
+	c.Assert(cfg.GlobalVersion, Equals, uint64(1))
+	c.Assert(cfg.LocalVersion, Equals, uint64(1))
+	c.Assert(cfg.Items, DeepEquals, map[string]string{"a": "1", "b": "3"})
+	c.Assert(cfg.IsNewerThan(1, 1), IsFalse)
+	c.Assert(cfg.IsNewerThan(1, 0), IsTrue)
+	cfg = m.GetStoreConfig(2)
+	c.Assert(cfg.Items, DeepEquals, map[string]string{"a": "1", "b": "2"})
+
+	// Remove an item.
+	c.Assert(m.UpdateGlobal(map[string]string{"a": ""}), IsNil)
+	c.Assert(m.GetGlobal().Version, Equals, uint64(2))
+	c.Assert(m.GetStoreConfig(2).Items, DeepEquals, map[string]string{"b": "2"})
+
+	// Reload from storage.
+	m2 := NewManager(storage)
+	c.Assert(m2.Initialize(), IsNil)
+	c.Assert(m2.GetGlobal(), DeepEquals, m.GetGlobal())
+	c.Assert(m2.GetStoreConfig(1), DeepEquals, m.GetStoreConfig(1))
+
+	c.Assert(m.DeleteStoreOverride(1), IsNil)
+	c.Assert(m.GetStoreOverride(1), IsNil)
+	cfg = m.GetStoreConfig(1)
+	c.Assert(cfg.Items, DeepEquals, map[string]string{"b": "2"})
+	// The removal and the later overrides are still newer for the store.
+	c.Assert(cfg.IsNewerThan(2, 1), IsTrue)
+	localVersion := cfg.LocalVersion
+	m2 = NewManager(storage)
+	c.Assert(m2.Initialize(), IsNil)
+	c.Assert(m2.GetStoreOverride(1), IsNil)
+	c.Assert(m2.GetStoreConfig(1), DeepEquals, cfg)
+	c.Assert(m.DeleteStoreOverride(1), IsNil)
+	c.Assert(m.GetStoreConfig(1).LocalVersion, Equals, localVersion)
+	c.Assert(m.UpdateStoreOverride(1, map[string]string{"b": "3"}), IsNil)
+	c.Assert(m.GetStoreConfig(1).IsNewerThan(2, localVersion), IsTrue)
+}
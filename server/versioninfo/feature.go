@@ -42,6 +42,10 @@ const (
 	Version5_0
 	// JointConsensus can support safe conf change across data center.
 	JointConsensus
+	// Learner supports adding a peer as a learner first and promoting it
+	// after it catches up, which avoids reducing the availability of the
+	// region while the new peer is applying the snapshot.
+	Learner
 )
 
 var featuresDict = map[Feature]string{
@@ -53,6 +57,31 @@ var featuresDict = map[Feature]string{
 	Version4_0:     "4.0.0",
 	Version5_0:     "5.0.0",
 	JointConsensus: "5.0.0",
+	Learner:        "2.0.0",
+}
+
+// featureNames is the name of the features which can be queried by users.
+var featureNames = map[Feature]string{
+	RegionMerge:    "region-merge",
+	BatchSplit:     "batch-split",
+	JointConsensus: "joint-consensus",
+	Learner:        "learner",
+}
+
+// String implements fmt.Stringer.
+func (f Feature) String() string {
+	if name, ok := featureNames[f]; ok {
+		return name
+	}
+	if v, ok := featuresDict[f]; ok {
+		return "version-" + v
+	}
+	return "unknown"
+}
+
+// NamedFeatures returns the features which have a name.
+func NamedFeatures() []Feature {
+	return []Feature{RegionMerge, BatchSplit, Learner, JointConsensus}
 }
 
 // MinSupportedVersion returns the minimum support version for the specified feature.
@@ -385,6 +385,48 @@ func (s *testClientSuite) TestTSORace(c *C) {
 	wg.Wait()
 }
 
+func (s *testClientSuite) TestTSOBatchWait(c *C) {
+	cli, err := pd.NewClientWithContext(s.ctx, s.srv.GetEndpoints(), pd.SecurityOption{},
+		pd.WithMaxTSOBatchWaitInterval(time.Millisecond))
+	c.Assert(err, IsNil)
+	defer cli.Close()
+
+	var wg sync.WaitGroup
+	count := 10
+	results := make([][]int64, count)
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				p, l, err := cli.GetTS(context.Background())
+				c.Assert(err, IsNil)
+				results[i] = append(results[i], p<<18+l)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// The timestamps are unique and increasing for each caller.
+	seen := make(map[int64]struct{})
+	for _, tss := range results {
+		var last int64
+		for _, ts := range tss {
+			c.Assert(ts, Greater, last)
+			last = ts
+			_, ok := seen[ts]
+			c.Assert(ok, IsFalse)
+			seen[ts] = struct{}{}
+		}
+	}
+
+	// The canceled request does not block.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = cli.GetTS(ctx)
+	c.Assert(err, NotNil)
+}
+
 func (s *testClientSuite) TestGetRegion(c *C) {
 	regionID := regionIDAllocator.alloc()
 	region := &metapb.Region{
@@ -574,7 +616,7 @@ func (s *testClientSuite) TestGetStore(c *C) {
 	c.Assert(stores, DeepEquals, stores)
 
 	// Mark the store as offline.
-	err = cluster.RemoveStore(store.GetId())
+	err = cluster.RemoveStore(store.GetId(), false)
 	c.Assert(err, IsNil)
 	offlineStore := proto.Clone(store).(*metapb.Store)
 	offlineStore.State = metapb.StoreState_Offline
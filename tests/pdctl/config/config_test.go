@@ -125,9 +125,14 @@ func (s *configTestSuite) TestConfig(c *C) {
 	c.Assert(clusterVersion, DeepEquals, svr.GetClusterVersion())
 
 	// config set cluster-version <value>
+	// The version higher than the version of the stores is rejected.
 	args2 := []string{"-u", pdAddr, "config", "set", "cluster-version", "2.1.0-rc.5"}
 	_, _, err = pdctl.ExecuteCommandC(cmd, args2...)
 	c.Assert(err, IsNil)
+	c.Assert(clusterVersion, DeepEquals, svr.GetClusterVersion())
+	args2 = append(args2, "--force")
+	_, _, err = pdctl.ExecuteCommandC(cmd, args2...)
+	c.Assert(err, IsNil)
 	c.Assert(clusterVersion, Not(DeepEquals), svr.GetClusterVersion())
 	_, output, err = pdctl.ExecuteCommandC(cmd, args1...)
 	c.Assert(err, IsNil)
@@ -202,11 +207,11 @@ func (s *configTestSuite) TestConfig(c *C) {
 	// test error or deprecated config name
 	args1 = []string{"-u", pdAddr, "config", "set", "foo-bar", "1"}
 	_, output, err = pdctl.ExecuteCommandC(cmd, args1...)
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "not found"), IsTrue)
 	args1 = []string{"-u", pdAddr, "config", "set", "disable-remove-down-replica", "true"}
 	_, output, err = pdctl.ExecuteCommandC(cmd, args1...)
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "already been deprecated"), IsTrue)
 
 	// set enable-placement-rules twice, make sure it does not return error.
@@ -364,7 +369,7 @@ func (s *configTestSuite) TestPlacementRuleGroups(c *C) {
 
 	// show again
 	_, output, err = pdctl.ExecuteCommandC(cmd, "-u", pdAddr, "config", "placement-rules", "rule-group", "show", "group2")
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "404"), IsTrue)
 }
 
@@ -394,11 +399,11 @@ func (s *configTestSuite) TestPlacementRuleBundle(c *C) {
 	c.Assert(strings.Contains(string(output), "Success!"), IsTrue)
 
 	_, output, err = pdctl.ExecuteCommandC(cmd, "-u", pdAddr, "config", "set", "max-replicas", "1")
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "please update rule instead"), IsTrue)
 
 	_, output, err = pdctl.ExecuteCommandC(cmd, "-u", pdAddr, "config", "set", "location-labels", "dc,rack")
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "please update rule instead"), IsTrue)
 
 	// test get
@@ -41,6 +41,7 @@ func InitCommand() *cobra.Command {
 	commandFlags := pdctl.CommandFlags{}
 	rootCmd := &cobra.Command{}
 	rootCmd.PersistentFlags().StringVarP(&commandFlags.URL, "pd", "u", "", "")
+	rootCmd.PersistentFlags().StringVarP(&commandFlags.Output, "output", "o", command.OutputJSON, "")
 	rootCmd.Flags().StringVar(&commandFlags.CAPath, "cacert", "", "")
 	rootCmd.Flags().StringVar(&commandFlags.CertPath, "cert", "", "")
 	rootCmd.Flags().StringVar(&commandFlags.KeyPath, "key", "", "")
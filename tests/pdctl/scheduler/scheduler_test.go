@@ -23,6 +23,7 @@ import (
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/tikv/pd/server"
+	"github.com/tikv/pd/server/schedule"
 	"github.com/tikv/pd/tests"
 	"github.com/tikv/pd/tests/pdctl"
 )
@@ -256,6 +257,9 @@ func (s *schedulerTestSuite) TestScheduler(c *C) {
 		"min-hot-key-rate":          float64(10),
 		"max-zombie-rounds":         float64(3),
 		"max-peer-number":           float64(1000),
+		"min-hot-degree":            float64(0),
+		"max-peer-operators":        float64(0),
+		"max-leader-operators":      float64(0),
 		"byte-rate-rank-step-ratio": 0.05,
 		"key-rate-rank-step-ratio":  0.05,
 		"count-rank-step-ratio":     0.01,
@@ -270,4 +274,17 @@ func (s *schedulerTestSuite) TestScheduler(c *C) {
 	var conf1 map[string]interface{}
 	mustExec([]string{"-u", pdAddr, "scheduler", "config", "balance-hot-region-scheduler"}, &conf1)
 	c.Assert(conf1, DeepEquals, expected1)
+	mustExec([]string{"-u", pdAddr, "scheduler", "config", "balance-hot-region-scheduler", "set", "max-leader-operators", "2"}, nil)
+	expected1["max-leader-operators"] = float64(2)
+	var conf2 map[string]interface{}
+	mustExec([]string{"-u", pdAddr, "scheduler", "config", "balance-hot-region-scheduler"}, &conf2)
+	c.Assert(conf2, DeepEquals, expected1)
+
+	// test store list
+	mustExec([]string{"-u", pdAddr, "scheduler", "stores", "set", "balance-hot-region-scheduler", "--allow=1,2,3", "--deny=3"}, nil)
+	var list schedule.StoreList
+	mustExec([]string{"-u", pdAddr, "scheduler", "stores", "show", "balance-hot-region-scheduler"}, &list)
+	c.Assert(list, DeepEquals, schedule.StoreList{Allow: []uint64{1, 2, 3}, Deny: []uint64{3}})
+	echo = pdctl.GetEcho([]string{"-u", pdAddr, "scheduler", "stores", "show", "not-exist-scheduler"})
+	c.Assert(strings.Contains(echo, "404"), IsTrue)
 }
@@ -214,7 +214,7 @@ func (s *storeTestSuite) TestStore(c *C) {
 	// store limit all 0 is invalid
 	args = []string{"-u", pdAddr, "store", "limit", "all", "0"}
 	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
-	c.Assert(err, IsNil)
+	c.Assert(err, NotNil)
 	c.Assert(strings.Contains(string(output), "rate should be a number that > 0"), IsTrue)
 
 	// store limit <type>
@@ -307,4 +307,22 @@ func (s *storeTestSuite) TestStore(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(scene.Idle, Equals, 100)
 
+	// store <store_id> --output=yaml command
+	args = []string{"-u", pdAddr, "store", "1", "--output", "yaml"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(output), "address: "+stores[0].Address), IsTrue)
+
+	// store --output=table command
+	args = []string{"-u", pdAddr, "store", "-o", "table"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(string(output), "STATUS."), IsTrue)
+	c.Assert(strings.Contains(string(output), "STORE.ID"), IsTrue)
+
+	// store --output=<unknown> command
+	args = []string{"-u", pdAddr, "store", "-o", "xml"}
+	_, output, err = pdctl.ExecuteCommandC(cmd, args...)
+	c.Assert(err, NotNil)
+	c.Assert(strings.Contains(string(output), "unknown output format"), IsTrue)
 }
@@ -269,7 +269,7 @@ func testRemoveStore(c *C, clusterID uint64, rc *cluster.RaftCluster, grpcPDClie
 		beforeState := metapb.StoreState_Up // When store is up
 		// Case 1: RemoveStore should be OK;
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
-			return cluster.RemoveStore(store.GetId())
+			return cluster.RemoveStore(store.GetId(), false)
 		}, metapb.StoreState_Offline)
 		// Case 2: BuryStore w/ force should be OK;
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
@@ -284,7 +284,7 @@ func testRemoveStore(c *C, clusterID uint64, rc *cluster.RaftCluster, grpcPDClie
 		beforeState := metapb.StoreState_Offline // When store is offline
 		// Case 1: RemoveStore should be OK;
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
-			return cluster.RemoveStore(store.GetId())
+			return cluster.RemoveStore(store.GetId(), false)
 		}, metapb.StoreState_Offline)
 		// Case 2: BuryStore w/ or w/o force should be OK.
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
@@ -295,7 +295,7 @@ func testRemoveStore(c *C, clusterID uint64, rc *cluster.RaftCluster, grpcPDClie
 		beforeState := metapb.StoreState_Tombstone // When store is tombstone
 		// Case 1: RemoveStore should should fail;
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
-			return cluster.RemoveStore(store.GetId())
+			return cluster.RemoveStore(store.GetId(), false)
 		})
 		// Case 2: BuryStore w/ or w/o force should be OK.
 		testStateAndLimit(c, clusterID, rc, grpcPDClient, store, beforeState, func(cluster *cluster.RaftCluster) error {
@@ -423,7 +423,7 @@ func (s *clusterTestSuite) TestStoreVersionChange(c *C) {
 	clusterID := leaderServer.GetClusterID()
 	bootstrapCluster(c, clusterID, grpcPDClient, "127.0.0.1:0")
 	svr := leaderServer.GetServer()
-	svr.SetClusterVersion("2.0.0")
+	svr.ForceSetClusterVersion("2.0.0")
 	storeID, err := leaderServer.GetAllocator().Alloc()
 	c.Assert(err, IsNil)
 	store := newMetaStore(storeID, "127.0.0.1:4", "2.1.0", metapb.StoreState_Up, fmt.Sprintf("test/store%d", storeID))
@@ -752,7 +752,8 @@ func (s *clusterTestSuite) TestTiFlashWithPlacementRules(c *C) {
 	_, err = putStore(c, grpcPDClient, clusterID, tiflashStore)
 	c.Assert(err, IsNil)
 	// test TiFlash store limit
-	expect := map[uint64]config.StoreLimitConfig{11: {AddPeer: 30, RemovePeer: 30}}
+	transferLeaderLimit := storelimit.Unlimited
+	expect := map[uint64]config.StoreLimitConfig{11: {AddPeer: 30, RemovePeer: 30, TransferLeader: &transferLeaderLimit}}
 	c.Assert(svr.GetScheduleConfig().StoreLimit, DeepEquals, expect)
 
 	// cannot disable placement rules with TiFlash nodes
@@ -958,7 +959,7 @@ func (s *clusterTestSuite) TestOfflineStoreLimit(c *C) {
 
 	// offline store 1
 	rc.SetStoreLimit(1, storelimit.RemovePeer, storelimit.Unlimited)
-	rc.RemoveStore(1)
+	rc.RemoveStore(1, false)
 
 	// can add unlimited remove peer operators on store 1
 	for i := uint64(1); i <= 30; i++ {
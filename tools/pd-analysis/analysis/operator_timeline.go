@@ -0,0 +1,364 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The events in the lifecycle of an operator.
+const (
+	OperatorCreate  = "create"
+	OperatorFinish  = "finish"
+	OperatorTimeout = "timeout"
+	OperatorCancel  = "cancel"
+	OperatorReplace = "replace"
+	OperatorExpire  = "expire"
+)
+
+// operatorMessages maps the log messages of the operator controller to the
+// operator events.
+var operatorMessages = map[string]string{
+	"add operator":         OperatorCreate,
+	"operator finish":      OperatorFinish,
+	"operator timeout":     OperatorTimeout,
+	"operator canceled":    OperatorCancel,
+	"replace old operator": OperatorReplace,
+	"operator expired":     OperatorExpire,
+}
+
+// cancelReasons maps the log messages logged right before an operator is
+// canceled to the reasons of the cancel. They are not terminal events by
+// themselves, but annotate the cancel event of the operator that follows.
+var cancelReasons = map[string]string{
+	"operator removed":                           "removed",
+	"remove operator because region disappeared": "region disappeared",
+}
+
+var (
+	operatorMessageRegex = regexp.MustCompile(`\["([a-z ]+)"\]`)
+	regionIDRegex        = regexp.MustCompile(`\[region-id=([0-9]+)\]`)
+	durationRegex        = regexp.MustCompile(`\[(?:takes|lives)=([0-9a-zµ.]+)\]`)
+	operatorDescRegex    = regexp.MustCompile(`\[operator=[\\"]*([a-zA-Z0-9-]+) \{`)
+	storeRegex           = regexp.MustCompile(`store \[?([0-9]+)\]?`)
+	targetStoreRegex     = regexp.MustCompile(`to \[([0-9]+)\]`)
+)
+
+// OperatorEvent is an event of an operator parsed from the PD log.
+type OperatorEvent struct {
+	Time     time.Time     `json:"time"`
+	RegionID uint64        `json:"region_id"`
+	Event    string        `json:"event"`
+	Desc     string        `json:"desc"`
+	Stores   []uint64      `json:"stores"`
+	Duration time.Duration `json:"duration,omitempty"`
+	// Reason is why the operator is canceled, it is only set for the cancel
+	// events.
+	Reason string `json:"reason,omitempty"`
+}
+
+// OperatorTimeline is the timeline of the operators reconstructed from the
+// PD log, which is used for the offline incident analysis.
+type OperatorTimeline struct {
+	Events []*OperatorEvent
+	// RegionID and StoreID filter the events if they are not zero.
+	RegionID uint64
+	StoreID  uint64
+}
+
+// NewOperatorTimeline creates an OperatorTimeline.
+func NewOperatorTimeline(regionID, storeID uint64) *OperatorTimeline {
+	return &OperatorTimeline{RegionID: regionID, StoreID: storeID}
+}
+
+// ParseLog parses the operator events between start and end from the log.
+func (t *OperatorTimeline) ParseLog(filename, start, end, layout string) error {
+	afterStart := isExpectTime(start, layout, false)
+	beforeEnd := isExpectTime(end, layout, true)
+	getCurrent := currentTime(layout)
+	// reasons holds the cancel reasons of the regions until the cancel
+	// events are parsed.
+	reasons := make(map[uint64]string)
+	err := forEachLine(filename, func(content string) error {
+		current, err := getCurrent(content)
+		if err != nil || current.IsZero() {
+			return err
+		}
+		if !afterStart(current) || !beforeEnd(current) {
+			return nil
+		}
+		e := parseOperatorEvent(content, current)
+		if e == nil {
+			return nil
+		}
+		switch e.Event {
+		case "":
+			reasons[e.RegionID] = e.Reason
+			return nil
+		case OperatorCancel:
+			e.Reason = reasons[e.RegionID]
+			delete(reasons, e.RegionID)
+		}
+		if t.match(e) {
+			t.Events = append(t.Events, e)
+		}
+		return nil
+	})
+	sort.SliceStable(t.Events, func(i, j int) bool { return t.Events[i].Time.Before(t.Events[j].Time) })
+	return err
+}
+
+func (t *OperatorTimeline) match(e *OperatorEvent) bool {
+	if t.RegionID != 0 && e.RegionID != t.RegionID {
+		return false
+	}
+	if t.StoreID == 0 {
+		return true
+	}
+	for _, id := range e.Stores {
+		if id == t.StoreID {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOperatorEvent parses the operator event from the log line. For the lines
+// giving the cancel reasons, the event is empty and only the reason is set.
+func parseOperatorEvent(content string, ts time.Time) *OperatorEvent {
+	msg := operatorMessageRegex.FindStringSubmatch(content)
+	if len(msg) != 2 {
+		return nil
+	}
+	event, ok := operatorMessages[msg[1]]
+	reason, isReason := cancelReasons[msg[1]]
+	if !ok && !isReason {
+		return nil
+	}
+	region := regionIDRegex.FindStringSubmatch(content)
+	if len(region) != 2 {
+		return nil
+	}
+	regionID, err := strconv.ParseUint(region[1], 10, 64)
+	if err != nil {
+		return nil
+	}
+	e := &OperatorEvent{Time: ts, RegionID: regionID, Event: event, Reason: reason}
+	if d := durationRegex.FindStringSubmatch(content); len(d) == 2 {
+		e.Duration, _ = time.ParseDuration(d[1])
+	}
+	if loc := operatorDescRegex.FindStringSubmatchIndex(content); loc != nil {
+		e.Desc = content[loc[2]:loc[3]]
+		e.Stores = parseStores(content[loc[1]:])
+	}
+	return e
+}
+
+// parseStores collects the stores involved by the operator from its brief
+// and steps, e.g. "mv peer: store [1] to [2]" and "add learner peer 5 on store 2".
+func parseStores(s string) []uint64 {
+	var stores []uint64
+	seen := make(map[uint64]struct{})
+	add := func(matches [][]string) {
+		for _, m := range matches {
+			id, err := strconv.ParseUint(m[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				stores = append(stores, id)
+			}
+		}
+	}
+	add(storeRegex.FindAllStringSubmatch(s, -1))
+	add(targetStoreRegex.FindAllStringSubmatch(s, -1))
+	return stores
+}
+
+// OperatorStats counts the operator events.
+type OperatorStats struct {
+	Create       int     `json:"create"`
+	Finish       int     `json:"finish"`
+	Timeout      int     `json:"timeout"`
+	Cancel       int     `json:"cancel"`
+	Replace      int     `json:"replace"`
+	Expire       int     `json:"expire"`
+	TimeoutRatio float64 `json:"timeout_ratio"`
+}
+
+func (s *OperatorStats) add(event string) {
+	switch event {
+	case OperatorCreate:
+		s.Create++
+	case OperatorFinish:
+		s.Finish++
+	case OperatorTimeout:
+		s.Timeout++
+	case OperatorCancel:
+		s.Cancel++
+	case OperatorReplace:
+		s.Replace++
+	case OperatorExpire:
+		s.Expire++
+	}
+}
+
+func (s *OperatorStats) calcTimeoutRatio() {
+	if ended := s.Finish + s.Timeout + s.Cancel + s.Replace + s.Expire; ended > 0 {
+		s.TimeoutRatio = float64(s.Timeout) / float64(ended)
+	}
+}
+
+// OperatorChurn is the number of the operators created on a region or store.
+type OperatorChurn struct {
+	ID        uint64 `json:"id"`
+	Operators int    `json:"operators"`
+}
+
+// OperatorSummary is the summary statistics of the operator timeline.
+type OperatorSummary struct {
+	Total      OperatorStats             `json:"total"`
+	PerHour    map[string]*OperatorStats `json:"per_hour"`
+	PerDesc    map[string]*OperatorStats `json:"per_desc"`
+	TopRegions []OperatorChurn           `json:"top_regions"`
+	TopStores  []OperatorChurn           `json:"top_stores"`
+}
+
+// hourLayout is the layout of the hours in the summary.
+const hourLayout = "2006/01/02 15:00"
+
+// Summary returns the statistics of the operators, with the top n regions
+// and stores which have the most operators.
+func (t *OperatorTimeline) Summary(top int) *OperatorSummary {
+	s := &OperatorSummary{
+		PerHour: make(map[string]*OperatorStats),
+		PerDesc: make(map[string]*OperatorStats),
+	}
+	regions := make(map[uint64]int)
+	stores := make(map[uint64]int)
+	for _, e := range t.Events {
+		hour := e.Time.Format(hourLayout)
+		if s.PerHour[hour] == nil {
+			s.PerHour[hour] = &OperatorStats{}
+		}
+		if s.PerDesc[e.Desc] == nil {
+			s.PerDesc[e.Desc] = &OperatorStats{}
+		}
+		s.Total.add(e.Event)
+		s.PerHour[hour].add(e.Event)
+		s.PerDesc[e.Desc].add(e.Event)
+		if e.Event == OperatorCreate {
+			regions[e.RegionID]++
+			for _, id := range e.Stores {
+				stores[id]++
+			}
+		}
+	}
+	s.Total.calcTimeoutRatio()
+	for _, stats := range s.PerHour {
+		stats.calcTimeoutRatio()
+	}
+	for _, stats := range s.PerDesc {
+		stats.calcTimeoutRatio()
+	}
+	s.TopRegions = topChurns(regions, top)
+	s.TopStores = topChurns(stores, top)
+	return s
+}
+
+func topChurns(counts map[uint64]int, top int) []OperatorChurn {
+	churns := make([]OperatorChurn, 0, len(counts))
+	for id, n := range counts {
+		churns = append(churns, OperatorChurn{ID: id, Operators: n})
+	}
+	sort.Slice(churns, func(i, j int) bool {
+		if churns[i].Operators != churns[j].Operators {
+			return churns[i].Operators > churns[j].Operators
+		}
+		return churns[i].ID < churns[j].ID
+	})
+	if top > 0 && len(churns) > top {
+		churns = churns[:top]
+	}
+	return churns
+}
+
+// WriteJSON writes the events and the summary in JSON.
+func (t *OperatorTimeline) WriteJSON(w io.Writer, top int) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Events  []*OperatorEvent `json:"events"`
+		Summary *OperatorSummary `json:"summary"`
+	}{t.Events, t.Summary(top)})
+}
+
+// WriteCSV writes the events in CSV.
+func (t *OperatorTimeline) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "region_id", "event", "desc", "stores", "duration", "reason"}); err != nil {
+		return err
+	}
+	for _, e := range t.Events {
+		stores := make([]string, 0, len(e.Stores))
+		for _, id := range e.Stores {
+			stores = append(stores, strconv.FormatUint(id, 10))
+		}
+		var duration string
+		if e.Duration > 0 {
+			duration = e.Duration.String()
+		}
+		record := []string{e.Time.Format(DefaultLayout), strconv.FormatUint(e.RegionID, 10), e.Event, e.Desc, strings.Join(stores, " "), duration, e.Reason}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummaryCSV writes the statistics of each kind of operators in CSV.
+func (t *OperatorTimeline) WriteSummaryCSV(w io.Writer) error {
+	s := t.Summary(0)
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"desc", "create", "finish", "timeout", "cancel", "replace", "expire", "timeout_ratio"}); err != nil {
+		return err
+	}
+	descs := make([]string, 0, len(s.PerDesc))
+	for desc := range s.PerDesc {
+		descs = append(descs, desc)
+	}
+	sort.Strings(descs)
+	for _, desc := range descs {
+		stats := s.PerDesc[desc]
+		record := []string{desc}
+		for _, n := range []int{stats.Create, stats.Finish, stats.Timeout, stats.Cancel, stats.Replace, stats.Expire} {
+			record = append(record, strconv.Itoa(n))
+		}
+		record = append(record, strconv.FormatFloat(stats.TimeoutRatio, 'f', 4, 64))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,123 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testOperatorTimeline{})
+
+type testOperatorTimeline struct{}
+
+var operatorLogs = []string{
+	`[2019/09/03 17:42:06.602 +08:00] [INFO] [operator_controller.go:520] ["add operator"] [region-id=24622] [operator=""balance-region {mv peer: store [6] to [1]} (kind:region,balance, region:24622(1,1), createAt:2019-09-03 17:42:06.602589701 +0800 CST m=+737.457773921, startAt:0001-01-01 00:00:00 +0000 UTC, currentStep:0, steps:[add learner peer 64064 on store 1, promote learner peer 64064 on store 1 to voter, remove peer on store 6])""] ["additional info"=]`,
+	`[2019/09/03 17:42:07.898 +08:00] [INFO] [operator_controller.go:633] ["operator finish"] [region-id=24622] [takes=1.295s] [operator=""balance-region {mv peer: store [6] to [1]} (kind:region,balance, region:24622(1,1), createAt:2019-09-03 17:42:06.602589701 +0800 CST m=+737.457773921, startAt:2019-09-03 17:42:06.602849306 +0800 CST m=+737.458033475, currentStep:3, steps:[add learner peer 64064 on store 1, promote learner peer 64064 on store 1 to voter, remove peer on store 6]) finished""] ["additional info"=]`,
+	`[2019/09/03 18:05:52.400 +08:00] [INFO] [operator_controller.go:520] ["add operator"] [region-id=54252] [operator=""balance-leader {transfer leader: store 4 to 6} (kind:leader,balance, region:54252(8243,398), createAt:2019-09-03 18:05:52.400290023 +0800 CST m=+91268.739649520, startAt:0001-01-01 00:00:00 +0000 UTC, currentStep:0, steps:[transfer leader from store 4 to store 6])""] ["additional info"=]`,
+	`[2019/09/03 18:15:52.404 +08:00] [INFO] [operator_controller.go:657] ["operator timeout"] [region-id=54252] [takes=10m0.004s] [operator=""balance-leader {transfer leader: store 4 to 6} (kind:leader,balance, region:54252(8243,398), createAt:2019-09-03 18:05:52.400290023 +0800 CST m=+91268.739649520, startAt:2019-09-03 18:05:52.400489629 +0800 CST m=+91268.739849120, currentStep:0, steps:[transfer leader from store 4 to store 6]) timeout""]`,
+	`[2019/09/03 18:20:00.000 +08:00] [INFO] [cluster.go:700] ["leader changed"] [region-id=54252] [from=4] [to=6]`,
+	`[2019/09/03 18:30:00.100 +08:00] [INFO] [operator_controller.go:520] ["add operator"] [region-id=24622] [operator=""balance-region {mv peer: store [1] to [5]} (kind:region,balance, region:24622(1,1), createAt:2019-09-03 18:30:00.100289701 +0800 CST m=+3611.955473921, startAt:0001-01-01 00:00:00 +0000 UTC, currentStep:0, steps:[add learner peer 64065 on store 5, promote learner peer 64065 on store 5 to voter, remove peer on store 1])""] ["additional info"=]`,
+	`[2019/09/03 18:30:01.200 +08:00] [INFO] [operator_controller.go:592] ["operator removed"] [region-id=24622] [takes=1.1s] [operator=""balance-region {mv peer: store [1] to [5]} (kind:region,balance, region:24622(1,1), createAt:2019-09-03 18:30:00.100289701 +0800 CST m=+3611.955473921, startAt:2019-09-03 18:30:00.100549306 +0800 CST m=+3611.955733475, currentStep:1, steps:[add learner peer 64065 on store 5, promote learner peer 64065 on store 5 to voter, remove peer on store 1]) canceled""]`,
+	`[2019/09/03 18:30:01.200 +08:00] [INFO] [operator_controller.go:675] ["operator canceled"] [region-id=24622] [takes=1.1s] [operator=""balance-region {mv peer: store [1] to [5]} (kind:region,balance, region:24622(1,1), createAt:2019-09-03 18:30:00.100289701 +0800 CST m=+3611.955473921, startAt:2019-09-03 18:30:00.100549306 +0800 CST m=+3611.955733475, currentStep:1, steps:[add learner peer 64065 on store 5, promote learner peer 64065 on store 5 to voter, remove peer on store 1]) canceled""]`,
+}
+
+func (t *testOperatorTimeline) writeLog(c *C) string {
+	f, err := ioutil.TempFile("", "pd-analysis")
+	c.Assert(err, IsNil)
+	defer f.Close()
+	_, err = f.WriteString(strings.Join(operatorLogs, "\n"))
+	c.Assert(err, IsNil)
+	return f.Name()
+}
+
+func (t *testOperatorTimeline) TestParseOperatorEvent(c *C) {
+	e := parseOperatorEvent(operatorLogs[1], time.Time{})
+	c.Assert(e, NotNil)
+	c.Assert(e.RegionID, Equals, uint64(24622))
+	c.Assert(e.Event, Equals, OperatorFinish)
+	c.Assert(e.Desc, Equals, "balance-region")
+	c.Assert(e.Stores, DeepEquals, []uint64{6, 1})
+	c.Assert(e.Duration, Equals, 1295*time.Millisecond)
+
+	e = parseOperatorEvent(operatorLogs[3], time.Time{})
+	c.Assert(e.Event, Equals, OperatorTimeout)
+	c.Assert(e.Desc, Equals, "balance-leader")
+	c.Assert(e.Stores, DeepEquals, []uint64{4, 6})
+	c.Assert(e.Duration, Equals, 10*time.Minute+4*time.Millisecond)
+
+	c.Assert(parseOperatorEvent(operatorLogs[4], time.Time{}), IsNil)
+
+	// The removal only gives the reason of the cancel that follows.
+	e = parseOperatorEvent(operatorLogs[6], time.Time{})
+	c.Assert(e.Event, Equals, "")
+	c.Assert(e.Reason, Equals, "removed")
+	e = parseOperatorEvent(operatorLogs[7], time.Time{})
+	c.Assert(e.Event, Equals, OperatorCancel)
+	c.Assert(e.Reason, Equals, "")
+}
+
+func (t *testOperatorTimeline) TestOperatorTimeline(c *C) {
+	name := t.writeLog(c)
+	defer os.Remove(name)
+
+	tl := NewOperatorTimeline(0, 0)
+	c.Assert(tl.ParseLog(name, "", "", DefaultLayout), IsNil)
+	c.Assert(tl.Events, HasLen, 6)
+	// The removed operator is counted once as canceled.
+	c.Assert(tl.Events[5].Event, Equals, OperatorCancel)
+	c.Assert(tl.Events[5].Reason, Equals, "removed")
+	s := tl.Summary(1)
+	c.Assert(s.Total.Create, Equals, 3)
+	c.Assert(s.Total.Cancel, Equals, 1)
+	c.Assert(s.Total.TimeoutRatio, Equals, 1.0/3)
+	c.Assert(s.PerHour["2019/09/03 17:00"].Finish, Equals, 1)
+	c.Assert(s.PerHour["2019/09/03 18:00"].Timeout, Equals, 1)
+	c.Assert(s.PerDesc["balance-leader"].TimeoutRatio, Equals, 1.0)
+	c.Assert(s.PerDesc["balance-region"].TimeoutRatio, Equals, 0.0)
+	c.Assert(s.TopRegions, DeepEquals, []OperatorChurn{{ID: 24622, Operators: 2}})
+	c.Assert(s.TopStores, HasLen, 1)
+
+	// Filter by the store and the time.
+	tl = NewOperatorTimeline(0, 4)
+	c.Assert(tl.ParseLog(name, "2019/09/03 18:10:00", "", DefaultLayout), IsNil)
+	c.Assert(tl.Events, HasLen, 1)
+	c.Assert(tl.Events[0].Event, Equals, OperatorTimeout)
+
+	var buf bytes.Buffer
+	c.Assert(tl.WriteCSV(&buf), IsNil)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	c.Assert(lines, HasLen, 2)
+	c.Assert(lines[1], Equals, "2019/09/03 18:15:52,54252,timeout,balance-leader,4 6,10m0.004s,")
+
+	buf.Reset()
+	c.Assert(tl.WriteSummaryCSV(&buf), IsNil)
+	c.Assert(strings.Contains(buf.String(), "balance-leader,0,0,1,0,0,0,1.0000"), IsTrue)
+
+	buf.Reset()
+	c.Assert(tl.WriteJSON(&buf, 10), IsNil)
+	var output struct {
+		Events  []*OperatorEvent `json:"events"`
+		Summary *OperatorSummary `json:"summary"`
+	}
+	c.Assert(json.Unmarshal(buf.Bytes(), &output), IsNil)
+	c.Assert(output.Events, HasLen, 1)
+	c.Assert(output.Summary.Total.Timeout, Equals, 1)
+}
@@ -26,10 +26,15 @@ var (
 	input    = flag.String("input", "", "input pd log file, required")
 	output   = flag.String("output", "", "output file, default output to stdout")
 	logLevel = flag.String("logLevel", "info", "log level, default info")
-	style    = flag.String("style", "", "analysis style, e.g. transfer-counter")
+	style    = flag.String("style", "", "analysis style, e.g. transfer-counter, operator-timeline")
 	operator = flag.String("operator", "", "operator style, e.g. balance-region, balance-leader, transfer-hot-read-leader, move-hot-read-region, transfer-hot-write-leader, move-hot-write-region")
 	start    = flag.String("start", "", "start time, e.g. 2019/09/10 12:20:07, default: total file")
 	end      = flag.String("end", "", "end time, e.g. 2019/09/10 14:20:07, default: total file")
+	format   = flag.String("format", "json", "output format of operator-timeline, e.g. json, csv")
+	summary  = flag.Bool("summary", false, "output the statistics of each kind of operators instead of the events of operator-timeline in csv")
+	regionID = flag.Uint64("region", 0, "only analyze the operators of the region, default: all regions")
+	storeID  = flag.Uint64("store", 0, "only analyze the operators involving the store, default: all stores")
+	top      = flag.Int("top", 10, "the number of the regions and stores with the most operators in the summary")
 )
 
 // Logger is the global logger used for simulator.
@@ -75,6 +80,25 @@ func main() {
 			analysis.GetTransferCounter().PrintResult()
 			break
 		}
+	case "operator-timeline":
+		t := analysis.NewOperatorTimeline(*regionID, *storeID)
+		if err := t.ParseLog(*input, *start, *end, analysis.DefaultLayout); err != nil {
+			Logger.Fatal(err.Error())
+		}
+		var err error
+		switch {
+		case *format == "json":
+			err = t.WriteJSON(os.Stdout, *top)
+		case *format == "csv" && *summary:
+			err = t.WriteSummaryCSV(os.Stdout)
+		case *format == "csv":
+			err = t.WriteCSV(os.Stdout)
+		default:
+			Logger.Fatal("Format is not supported.")
+		}
+		if err != nil {
+			Logger.Fatal(err.Error())
+		}
 	default:
 		Logger.Fatal("Style is not exist.")
 	}
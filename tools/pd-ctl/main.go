@@ -50,7 +50,7 @@ func main() {
 
 	var input []string
 	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
+	if (stat.Mode()&os.ModeCharDevice) == 0 && !pdctl.IsBatchMode(os.Args[1:]) {
 		b, err := ioutil.ReadAll(os.Stdin)
 		if err != nil {
 			fmt.Println(err)
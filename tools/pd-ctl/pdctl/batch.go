@@ -0,0 +1,130 @@
+// Copyright 2021 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pdctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/pingcap/errors"
+)
+
+// batchStdin is the batch file name which means reading the commands from stdin.
+const batchStdin = "-"
+
+// batchSleepCommand is the built-in command of the batch mode which waits
+// for a while before running the next command, e.g. `sleep 30s`.
+const batchSleepCommand = "sleep"
+
+// BatchResult is the result of a command run in the batch mode.
+type BatchResult struct {
+	Line    int    `json:"line"`
+	Command string `json:"command"`
+	Success bool   `json:"success"`
+	Output  string `json:"output"`
+}
+
+// IsBatchMode returns if the arguments ask pd-ctl to run in the batch mode
+// and read the commands from stdin.
+func IsBatchMode(args []string) bool {
+	for i, arg := range args {
+		if arg == "--batch="+batchStdin || ((arg == "-b" || arg == "--batch") && i+1 < len(args) && args[i+1] == batchStdin) {
+			return true
+		}
+	}
+	return false
+}
+
+func runBatchFile(name string, w io.Writer) error {
+	if name == batchStdin {
+		return runBatch(os.Stdin, w)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer f.Close()
+	return runBatch(f, w)
+}
+
+// runBatch runs the commands read from r line by line and writes the result
+// of each command to w as a line of JSON. Empty lines and the lines starting
+// with `#` are skipped. It stops at the first failed command.
+func runBatch(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	encoder := json.NewEncoder(w)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		result := runBatchCommand(line)
+		result.Line = lineNum
+		if err := encoder.Encode(result); err != nil {
+			return errors.WithStack(err)
+		}
+		if !result.Success {
+			return errors.Errorf("command %q at line %d failed", line, lineNum)
+		}
+	}
+	return errors.WithStack(scanner.Err())
+}
+
+func runBatchCommand(line string) *BatchResult {
+	result := &BatchResult{Command: line}
+	args, err := shellwords.Parse(line)
+	if err != nil {
+		result.Output = fmt.Sprintf("parse command err: %v", err)
+		return result
+	}
+	if len(args) == 0 {
+		result.Success = true
+		return result
+	}
+	if args[0] == batchSleepCommand {
+		if len(args) != 2 {
+			result.Output = "Usage: sleep <duration>"
+			return result
+		}
+		d, err := time.ParseDuration(args[1])
+		if err != nil {
+			result.Output = err.Error()
+			return result
+		}
+		time.Sleep(d)
+		result.Success = true
+		return result
+	}
+
+	var out bytes.Buffer
+	rootCmd := getInteractCmd(args)
+	rootCmd.SetOutput(&out)
+	err = rootCmd.Execute()
+	result.Output = strings.TrimSpace(out.String())
+	if err != nil {
+		result.Output = strings.TrimSpace(result.Output + "\n" + err.Error())
+		return result
+	}
+	result.Success = true
+	return result
+}
@@ -16,6 +16,7 @@ package command
 import (
 	"net/http"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -27,7 +28,7 @@ func NewClusterCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "show the cluster information",
-		Run:   showClusterCommandFunc,
+		RunE:  showClusterCommandFunc,
 	}
 	cmd.AddCommand(NewClusterStatusCommand())
 	return cmd
@@ -38,25 +39,23 @@ func NewClusterStatusCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "status",
 		Short: "show the cluster status",
-		Run:   showClusterStatusCommandFunc,
+		RunE:  showClusterStatusCommandFunc,
 	}
 	return r
 }
 
-func showClusterCommandFunc(cmd *cobra.Command, args []string) {
+func showClusterCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, clusterPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get the cluster information: %s\n", err)
-		return
+		return errors.Errorf("failed to get the cluster information: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showClusterStatusCommandFunc(cmd *cobra.Command, args []string) {
+func showClusterStatusCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, clusterStatusPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get the cluster status: %s\n", err)
-		return
+		return errors.Errorf("failed to get the cluster status: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
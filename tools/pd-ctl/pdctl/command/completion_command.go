@@ -81,7 +81,7 @@ func NewCompletionCommand() *cobra.Command {
 		Short:                 "Output shell completion code for the specified shell (bash)",
 		Long:                  completionLongDesc,
 		Example:               completionExample,
-		Run:                   RunCompletion,
+		RunE:                  RunCompletion,
 		ValidArgs:             shells,
 	}
 
@@ -89,25 +89,23 @@ func NewCompletionCommand() *cobra.Command {
 }
 
 // RunCompletion wrapped the bash and zsh completion scripts
-func RunCompletion(cmd *cobra.Command, args []string) {
+func RunCompletion(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		cmd.Println("Shell not specified.")
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	if len(args) > 1 {
 		cmd.Println("Too many arguments. Expected only the shell type.")
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	run, found := completionShells[args[0]]
 	if !found {
 		cmd.Printf("Unsupported shell type %q.\n", args[0])
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 
 	run(os.Stdout, cmd.Root())
+	return nil
 }
 
 func runCompletionBash(out io.Writer, cmd *cobra.Command) error {
@@ -24,6 +24,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 	"github.com/tikv/pd/server/config"
 	"github.com/tikv/pd/server/schedule/placement"
@@ -62,7 +63,7 @@ func NewShowConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "show [replication|label-property|all]",
 		Short: "show replication and schedule config of PD",
-		Run:   showConfigCommandFunc,
+		RunE:  showConfigCommandFunc,
 	}
 	sc.AddCommand(NewShowAllConfigCommand())
 	sc.AddCommand(NewShowScheduleConfigCommand())
@@ -78,7 +79,7 @@ func NewShowAllConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "all",
 		Short: "show all config of PD",
-		Run:   showAllConfigCommandFunc,
+		RunE:  showAllConfigCommandFunc,
 	}
 	return sc
 }
@@ -88,7 +89,7 @@ func NewShowScheduleConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "schedule",
 		Short: "show schedule config of PD",
-		Run:   showScheduleConfigCommandFunc,
+		RunE:  showScheduleConfigCommandFunc,
 	}
 	return sc
 }
@@ -98,7 +99,7 @@ func NewShowReplicationConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "replication",
 		Short: "show replication config of PD",
-		Run:   showReplicationConfigCommandFunc,
+		RunE:  showReplicationConfigCommandFunc,
 	}
 	return sc
 }
@@ -108,7 +109,7 @@ func NewShowLabelPropertyCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "label-property",
 		Short: "show label property config",
-		Run:   showLabelPropertyConfigCommandFunc,
+		RunE:  showLabelPropertyConfigCommandFunc,
 	}
 	return sc
 }
@@ -118,7 +119,7 @@ func NewShowClusterVersionCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "cluster-version",
 		Short: "show the cluster version",
-		Run:   showClusterVersionCommandFunc,
+		RunE:  showClusterVersionCommandFunc,
 	}
 	return sc
 }
@@ -127,7 +128,7 @@ func newShowReplicationModeCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "replication-mode",
 		Short: "show replication mode config",
-		Run:   showReplicationModeCommandFunc,
+		RunE:  showReplicationModeCommandFunc,
 	}
 }
 
@@ -136,7 +137,7 @@ func NewSetConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "set <option> <value>, set label-property <type> <key> <value>, set cluster-version <version>",
 		Short: "set the option with value",
-		Run:   setConfigCommandFunc,
+		RunE:  setConfigCommandFunc,
 	}
 	sc.AddCommand(NewSetLabelPropertyCommand())
 	sc.AddCommand(NewSetClusterVersionCommand())
@@ -149,7 +150,7 @@ func NewSetLabelPropertyCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "label-property <type> <key> <value>",
 		Short: "set a label property config item",
-		Run:   setLabelPropertyConfigCommandFunc,
+		RunE:  setLabelPropertyConfigCommandFunc,
 	}
 	return sc
 }
@@ -157,10 +158,11 @@ func NewSetLabelPropertyCommand() *cobra.Command {
 // NewSetClusterVersionCommand creates a set subcommand of set subcommand
 func NewSetClusterVersionCommand() *cobra.Command {
 	sc := &cobra.Command{
-		Use:   "cluster-version <version>",
+		Use:   "cluster-version <version> [--force]",
 		Short: "set cluster version",
-		Run:   setClusterVersionCommandFunc,
+		RunE:  setClusterVersionCommandFunc,
 	}
+	sc.Flags().Bool("force", false, "set the version even if it is higher than the version of some stores")
 	return sc
 }
 
@@ -168,7 +170,7 @@ func newSetReplicationModeCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "replication-mode <mode> [<key>, <value>]",
 		Short: "set replication mode config",
-		Run:   setReplicationModeCommandFunc,
+		RunE:  setReplicationModeCommandFunc,
 	}
 }
 
@@ -187,22 +189,20 @@ func NewDeleteLabelPropertyConfigCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:   "label-property <type> <key> <value>",
 		Short: "delete a label property config item",
-		Run:   deleteLabelPropertyConfigCommandFunc,
+		RunE:  deleteLabelPropertyConfigCommandFunc,
 	}
 	return sc
 }
 
-func showConfigCommandFunc(cmd *cobra.Command, args []string) {
+func showConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	allR, err := doRequest(cmd, configPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get config: %s\n", err)
-		return
+		return errors.Errorf("failed to get config: %s", err)
 	}
 	allData := make(map[string]interface{})
 	err = json.Unmarshal([]byte(allR), &allData)
 	if err != nil {
-		cmd.Printf("Failed to unmarshal config: %s\n", err)
-		return
+		return errors.Errorf("failed to unmarshal config: %s", err)
 	}
 
 	data := make(map[string]interface{})
@@ -210,13 +210,11 @@ func showConfigCommandFunc(cmd *cobra.Command, args []string) {
 	scheduleConfig := make(map[string]interface{})
 	scheduleConfigData, err := json.Marshal(allData["schedule"])
 	if err != nil {
-		cmd.Printf("Failed to marshal schedule config: %s\n", err)
-		return
+		return errors.Errorf("failed to marshal schedule config: %s", err)
 	}
 	err = json.Unmarshal(scheduleConfigData, &scheduleConfig)
 	if err != nil {
-		cmd.Printf("Failed to unmarshal schedule config: %s\n", err)
-		return
+		return errors.Errorf("failed to unmarshal schedule config: %s", err)
 	}
 
 	delete(scheduleConfig, "schedulers-v2")
@@ -225,64 +223,58 @@ func showConfigCommandFunc(cmd *cobra.Command, args []string) {
 	data["schedule"] = scheduleConfig
 	r, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		cmd.Printf("Failed to marshal config: %s\n", err)
-		return
+		return errors.Errorf("failed to marshal config: %s", err)
 	}
 	cmd.Println(string(r))
+	return nil
 }
 
-func showScheduleConfigCommandFunc(cmd *cobra.Command, args []string) {
+func showScheduleConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, schedulePrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get config: %s\n", err)
-		return
+		return errors.Errorf("failed to get config: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showReplicationConfigCommandFunc(cmd *cobra.Command, args []string) {
+func showReplicationConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, replicatePrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get config: %s\n", err)
-		return
+		return errors.Errorf("failed to get config: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) {
+func showLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, labelPropertyPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get config: %s\n", err)
-		return
+		return errors.Errorf("failed to get config: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showAllConfigCommandFunc(cmd *cobra.Command, args []string) {
+func showAllConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, configPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get config: %s\n", err)
-		return
+		return errors.Errorf("failed to get config: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showClusterVersionCommandFunc(cmd *cobra.Command, args []string) {
+func showClusterVersionCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, clusterVersionPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get cluster version: %s\n", err)
-		return
+		return errors.Errorf("failed to get cluster version: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showReplicationModeCommandFunc(cmd *cobra.Command, args []string) {
+func showReplicationModeCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, replicationModePrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get replication mode config: %s\n", err)
-		return
+		return errors.Errorf("failed to get replication mode config: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 func postConfigDataWithPath(cmd *cobra.Command, key, value, path string) error {
@@ -305,32 +297,30 @@ func postConfigDataWithPath(cmd *cobra.Command, key, value, path string) error {
 	return nil
 }
 
-func setConfigCommandFunc(cmd *cobra.Command, args []string) {
+func setConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	opt, val := args[0], args[1]
 	err := postConfigDataWithPath(cmd, opt, val, configPrefix)
 	if err != nil {
-		cmd.Printf("Failed to set config: %s\n", err)
-		return
+		return errors.Errorf("failed to set config: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func setLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) {
-	postLabelProperty(cmd, "set", args)
+func setLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) error {
+	return postLabelProperty(cmd, "set", args)
 }
 
-func deleteLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) {
-	postLabelProperty(cmd, "delete", args)
+func deleteLabelPropertyConfigCommandFunc(cmd *cobra.Command, args []string) error {
+	return postLabelProperty(cmd, "delete", args)
 }
 
-func postLabelProperty(cmd *cobra.Command, action string, args []string) {
+func postLabelProperty(cmd *cobra.Command, action string, args []string) error {
 	if len(args) != 3 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	input := map[string]interface{}{
 		"type":        args[0],
@@ -339,39 +329,39 @@ func postLabelProperty(cmd *cobra.Command, action string, args []string) {
 		"label-value": args[2],
 	}
 	prefix := path.Join(labelPropertyPrefix)
-	postJSON(cmd, prefix, input)
+	return postJSON(cmd, prefix, input)
 }
 
-func setClusterVersionCommandFunc(cmd *cobra.Command, args []string) {
+func setClusterVersionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	input := map[string]interface{}{
 		"cluster-version": args[0],
 	}
-	postJSON(cmd, clusterVersionPrefix, input)
+	prefix := clusterVersionPrefix
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		prefix += "?force=true"
+	}
+	return postJSON(cmd, prefix, input)
 }
 
-func setReplicationModeCommandFunc(cmd *cobra.Command, args []string) {
+func setReplicationModeCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) == 1 {
-		postJSON(cmd, replicationModePrefix, map[string]interface{}{"replication-mode": args[0]})
+		return postJSON(cmd, replicationModePrefix, map[string]interface{}{"replication-mode": args[0]})
 	} else if len(args) == 3 {
 		t := findFieldByJSONTag(reflect.TypeOf(config.ReplicationModeConfig{}), []string{args[0], args[1]})
 		if t != nil && t.Kind() != reflect.String {
 			// convert to number for numberic fields.
 			arg2, err := strconv.ParseInt(args[2], 10, 64)
 			if err != nil {
-				cmd.Printf("value %v cannot covert to number: %v", args[2], err)
-				return
+				return errors.Errorf("value %v cannot covert to number: %v", args[2], err)
 			}
-			postJSON(cmd, replicationModePrefix, map[string]interface{}{args[0]: map[string]interface{}{args[1]: arg2}})
-			return
+			return postJSON(cmd, replicationModePrefix, map[string]interface{}{args[0]: map[string]interface{}{args[1]: arg2}})
 		}
-		postJSON(cmd, replicationModePrefix, map[string]interface{}{args[0]: map[string]string{args[1]: args[2]}})
-	} else {
-		cmd.Println(cmd.UsageString())
+		return postJSON(cmd, replicationModePrefix, map[string]interface{}{args[0]: map[string]string{args[1]: args[2]}})
 	}
+	return usageError(cmd)
 }
 
 func findFieldByJSONTag(t reflect.Type, tags []string) reflect.Type {
@@ -402,17 +392,17 @@ func NewPlacementRulesCommand() *cobra.Command {
 	enable := &cobra.Command{
 		Use:   "enable",
 		Short: "enable placement rules",
-		Run:   enablePlacementRulesFunc,
+		RunE:  enablePlacementRulesFunc,
 	}
 	disable := &cobra.Command{
 		Use:   "disable",
 		Short: "disable placement rules",
-		Run:   disablePlacementRulesFunc,
+		RunE:  disablePlacementRulesFunc,
 	}
 	show := &cobra.Command{
 		Use:   "show",
 		Short: "show placement rules",
-		Run:   getPlacementRulesFunc,
+		RunE:  getPlacementRulesFunc,
 	}
 	show.Flags().String("group", "", "group id")
 	show.Flags().String("id", "", "rule id")
@@ -420,7 +410,7 @@ func NewPlacementRulesCommand() *cobra.Command {
 	load := &cobra.Command{
 		Use:   "load",
 		Short: "load placement rules to a file",
-		Run:   getPlacementRulesFunc,
+		RunE:  getPlacementRulesFunc,
 	}
 	load.Flags().String("group", "", "group id")
 	load.Flags().String("id", "", "rule id")
@@ -429,7 +419,7 @@ func NewPlacementRulesCommand() *cobra.Command {
 	save := &cobra.Command{
 		Use:   "save",
 		Short: "save rules from file",
-		Run:   putPlacementRulesFunc,
+		RunE:  putPlacementRulesFunc,
 	}
 	save.Flags().String("in", "rules.json", "the filename contains rules")
 	ruleGroup := &cobra.Command{
@@ -439,17 +429,17 @@ func NewPlacementRulesCommand() *cobra.Command {
 	ruleGroupShow := &cobra.Command{
 		Use:   "show [id]",
 		Short: "show rule group configuration(s)",
-		Run:   showRuleGroupFunc,
+		RunE:  showRuleGroupFunc,
 	}
 	ruleGroupSet := &cobra.Command{
 		Use:   "set <id> <index> <override>",
 		Short: "update rule group configuration",
-		Run:   updateRuleGroupFunc,
+		RunE:  updateRuleGroupFunc,
 	}
 	ruleGroupDelete := &cobra.Command{
 		Use:   "delete <id>",
 		Short: "delete rule group configuration",
-		Run:   deleteRuleGroupFunc,
+		RunE:  deleteRuleGroupFunc,
 	}
 	ruleGroup.AddCommand(ruleGroupShow, ruleGroupSet, ruleGroupDelete)
 	ruleBundle := &cobra.Command{
@@ -459,31 +449,31 @@ func NewPlacementRulesCommand() *cobra.Command {
 	ruleBundleGet := &cobra.Command{
 		Use:   "get <id>",
 		Short: "get rule group config and its rules by group id",
-		Run:   getRuleBundle,
+		RunE:  getRuleBundle,
 	}
 	ruleBundleGet.Flags().String("out", "", "the output file")
 	ruleBundleSet := &cobra.Command{
 		Use:   "set",
 		Short: "set rule group config and its rules from file",
-		Run:   setRuleBundle,
+		RunE:  setRuleBundle,
 	}
 	ruleBundleSet.Flags().String("in", "group.json", "the file contains one group config and its rules")
 	ruleBundleDelete := &cobra.Command{
 		Use:   "delete <id>",
 		Short: "delete rule group config and its rules by group id",
-		Run:   delRuleBundle,
+		RunE:  delRuleBundle,
 	}
 	ruleBundleDelete.Flags().Bool("regexp", false, "match group id by regular expression")
 	ruleBundleLoad := &cobra.Command{
 		Use:   "load",
 		Short: "load all group configs and rules to file",
-		Run:   loadRuleBundle,
+		RunE:  loadRuleBundle,
 	}
 	ruleBundleLoad.Flags().String("out", "rules.json", "the output file")
 	ruleBundleSave := &cobra.Command{
 		Use:   "save",
 		Short: "save all group configs and rules from file",
-		Run:   saveRuleBundle,
+		RunE:  saveRuleBundle,
 	}
 	ruleBundleSave.Flags().String("in", "rules.json", "the file contains all group configs and all rules")
 	ruleBundleSave.Flags().Bool("partial", false, "do not drop all old configurations, partial update")
@@ -492,25 +482,25 @@ func NewPlacementRulesCommand() *cobra.Command {
 	return c
 }
 
-func enablePlacementRulesFunc(cmd *cobra.Command, args []string) {
+func enablePlacementRulesFunc(cmd *cobra.Command, args []string) error {
 	err := postConfigDataWithPath(cmd, "enable-placement-rules", "true", configPrefix)
 	if err != nil {
-		cmd.Printf("Failed to set config: %s\n", err)
-		return
+		return errors.Errorf("failed to set config: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func disablePlacementRulesFunc(cmd *cobra.Command, args []string) {
+func disablePlacementRulesFunc(cmd *cobra.Command, args []string) error {
 	err := postConfigDataWithPath(cmd, "enable-placement-rules", "false", configPrefix)
 	if err != nil {
-		cmd.Printf("Failed to set config: %s\n", err)
-		return
+		return errors.Errorf("failed to set config: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func getPlacementRulesFunc(cmd *cobra.Command, args []string) {
+func getPlacementRulesFunc(cmd *cobra.Command, args []string) error {
 	getFlag := func(key string) string {
 		if f := cmd.Flag(key); f != nil {
 			return f.Value.String()
@@ -525,8 +515,7 @@ func getPlacementRulesFunc(cmd *cobra.Command, args []string) {
 	case region == "" && group == "" && id == "": // all rules
 		reqPath = rulesPrefix
 	case region == "" && group == "" && id != "":
-		cmd.Println(`"id" should be specified along with "group"`)
-		return
+		return errors.New(`"id" should be specified along with "group"`)
 	case region == "" && group != "" && id == "": // all rules in a group
 		reqPath = path.Join(rulesPrefix, "group", group)
 	case region == "" && group != "" && id != "": // single rule
@@ -534,44 +523,40 @@ func getPlacementRulesFunc(cmd *cobra.Command, args []string) {
 	case region != "" && group == "" && id == "": // rules matches a region
 		reqPath = path.Join(rulesPrefix, "region", region)
 	default:
-		cmd.Println(`"region" should not be specified with "group" or "id" at the same time`)
-		return
+		return errors.New(`"region" should not be specified with "group" or "id" at the same time`)
 	}
 	res, err := doRequest(cmd, reqPath, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	if file == "" {
 		cmd.Println(res)
-		return
+		return nil
 	}
 	if !respIsList {
 		res = "[\n" + res + "]\n"
 	}
 	err = ioutil.WriteFile(file, []byte(res), 0644)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Println("rules saved to file " + file)
+	return nil
 }
 
-func putPlacementRulesFunc(cmd *cobra.Command, args []string) {
+func putPlacementRulesFunc(cmd *cobra.Command, args []string) error {
 	var file string
 	if f := cmd.Flag("in"); f != nil {
 		file = f.Value.String()
 	}
 	content, err := ioutil.ReadFile(file)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	var opts []*placement.RuleOp
 	if err = json.Unmarshal(content, &opts); err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	validOpts := opts[:0]
@@ -588,17 +573,16 @@ func putPlacementRulesFunc(cmd *cobra.Command, args []string) {
 	b, _ := json.Marshal(validOpts)
 	_, err = doRequest(cmd, rulesBatchPrefix, http.MethodPost, WithBody("application/json", bytes.NewBuffer(b)))
 	if err != nil {
-		cmd.Printf("failed to save rules %s: %s\n", b, err)
-		return
+		return errors.Errorf("failed to save rules %s: %s", b, err)
 	}
 
 	cmd.Println("Success!")
+	return nil
 }
 
-func showRuleGroupFunc(cmd *cobra.Command, args []string) {
+func showRuleGroupFunc(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	reqPath := ruleGroupsPrefix
@@ -608,21 +592,19 @@ func showRuleGroupFunc(cmd *cobra.Command, args []string) {
 
 	res, err := doRequest(cmd, reqPath, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Println(res)
+	return nil
 }
 
-func updateRuleGroupFunc(cmd *cobra.Command, args []string) {
+func updateRuleGroupFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 3 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	index, err := strconv.ParseInt(args[1], 10, 64)
 	if err != nil {
-		cmd.Printf("index %s should be a number\n", args[1])
-		return
+		return errors.Errorf("index %s should be a number", args[1])
 	}
 	var override bool
 	switch strings.ToLower(args[2]) {
@@ -630,41 +612,37 @@ func updateRuleGroupFunc(cmd *cobra.Command, args []string) {
 	case "true":
 		override = true
 	default:
-		cmd.Printf("override %s should be a boolean\n", args[2])
-		return
+		return errors.Errorf("override %s should be a boolean", args[2])
 	}
-	postJSON(cmd, ruleGroupPrefix, map[string]interface{}{
+	return postJSON(cmd, ruleGroupPrefix, map[string]interface{}{
 		"id":       args[0],
 		"index":    index,
 		"override": override,
 	})
 }
 
-func deleteRuleGroupFunc(cmd *cobra.Command, args []string) {
+func deleteRuleGroupFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	_, err := doRequest(cmd, path.Join(ruleGroupPrefix, args[0]), http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to remove rule group config: %s \n", err)
-		return
+		return errors.Errorf("failed to remove rule group config: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func getRuleBundle(cmd *cobra.Command, args []string) {
+func getRuleBundle(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	reqPath := path.Join(ruleBundlePrefix, args[0])
 
 	res, err := doRequest(cmd, reqPath, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	file := ""
@@ -673,51 +651,48 @@ func getRuleBundle(cmd *cobra.Command, args []string) {
 	}
 	if file == "" {
 		cmd.Println(res)
-		return
+		return nil
 	}
 
 	err = ioutil.WriteFile(file, []byte(res), 0644)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Printf("rule group saved to file %s\n", file)
+	return nil
 }
 
-func setRuleBundle(cmd *cobra.Command, args []string) {
+func setRuleBundle(cmd *cobra.Command, args []string) error {
 	var file string
 	if f := cmd.Flag("in"); f != nil {
 		file = f.Value.String()
 	}
 	content, err := ioutil.ReadFile(file)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	id := struct {
 		GroupID string `json:"group_id"`
 	}{}
 	if err = json.Unmarshal(content, &id); err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	reqPath := path.Join(ruleBundlePrefix, id.GroupID)
 
 	res, err := doRequest(cmd, reqPath, http.MethodPost, WithBody("application/json", bytes.NewReader(content)))
 	if err != nil {
-		cmd.Printf("failed to save rule bundle %s: %s\n", content, err)
-		return
+		return errors.Errorf("failed to save rule bundle %s: %s", content, err)
 	}
 
 	cmd.Println(res)
+	return nil
 }
 
-func delRuleBundle(cmd *cobra.Command, args []string) {
+func delRuleBundle(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	reqPath := path.Join(ruleBundlePrefix, url.PathEscape(args[0]))
@@ -728,18 +703,17 @@ func delRuleBundle(cmd *cobra.Command, args []string) {
 
 	res, err := doRequest(cmd, reqPath, http.MethodDelete)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	cmd.Println(res)
+	return nil
 }
 
-func loadRuleBundle(cmd *cobra.Command, args []string) {
+func loadRuleBundle(cmd *cobra.Command, args []string) error {
 	res, err := doRequest(cmd, ruleBundlePrefix, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	file := ""
@@ -748,26 +722,25 @@ func loadRuleBundle(cmd *cobra.Command, args []string) {
 	}
 	if file == "" {
 		cmd.Println(res)
-		return
+		return nil
 	}
 
 	err = ioutil.WriteFile(file, []byte(res), 0644)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Printf("rule group saved to file %s\n", file)
+	return nil
 }
 
-func saveRuleBundle(cmd *cobra.Command, args []string) {
+func saveRuleBundle(cmd *cobra.Command, args []string) error {
 	var file string
 	if f := cmd.Flag("in"); f != nil {
 		file = f.Value.String()
 	}
 	content, err := ioutil.ReadFile(file)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	path := ruleBundlePrefix
@@ -777,9 +750,9 @@ func saveRuleBundle(cmd *cobra.Command, args []string) {
 
 	res, err := doRequest(cmd, path, http.MethodPost, WithBody("application/json", bytes.NewReader(content)))
 	if err != nil {
-		cmd.Printf("failed to save rule bundles %s: %s\n", content, err)
-		return
+		return errors.Errorf("failed to save rule bundles %s: %s", content, err)
 	}
 
 	cmd.Println(res)
+	return nil
 }
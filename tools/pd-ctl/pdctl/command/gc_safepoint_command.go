@@ -16,6 +16,7 @@ package command
 import (
 	"net/http"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +29,7 @@ func NewServiceGCSafepointCommand() *cobra.Command {
 	l := &cobra.Command{
 		Use:   "service-gc-safepoint",
 		Short: "show all service gc safepoint",
-		Run:   showSSPs,
+		RunE:  showSSPs,
 	}
 	l.AddCommand(NewDeleteServiceGCSafepointCommand())
 	return l
@@ -39,32 +40,29 @@ func NewDeleteServiceGCSafepointCommand() *cobra.Command {
 	l := &cobra.Command{
 		Use:    "delete <service ID>",
 		Short:  "delete a service gc safepoint",
-		Run:    deleteSSP,
+		RunE:   deleteSSP,
 		Hidden: true,
 	}
 	return l
 }
 
-func showSSPs(cmd *cobra.Command, args []string) {
+func showSSPs(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, serviceGCSafepointPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get service GC safepoint: %s\n", err)
-		return
+		return errors.Errorf("failed to get service GC safepoint: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func deleteSSP(cmd *cobra.Command, args []string) {
+func deleteSSP(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	serviceID := args[0]
 	deleteURL := serviceGCSafepointPrefix + "/" + serviceID
 	r, err := doRequest(cmd, deleteURL, http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to delete service GC safepoint: %s\n", err)
-		return
+		return errors.Errorf("failed to delete service GC safepoint: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
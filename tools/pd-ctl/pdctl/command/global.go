@@ -175,11 +175,17 @@ func getEndpoints(cmd *cobra.Command) []string {
 	return eps
 }
 
-func postJSON(cmd *cobra.Command, prefix string, input map[string]interface{}) {
+// usageError prints the usage of the command and returns an error, so that
+// the command called with the wrong arguments is known to have failed.
+func usageError(cmd *cobra.Command) error {
+	cmd.Usage()
+	return errors.Errorf("wrong arguments of %s", cmd.CommandPath())
+}
+
+func postJSON(cmd *cobra.Command, prefix string, input map[string]interface{}) error {
 	data, err := json.Marshal(input)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	endpoints := getEndpoints(cmd)
@@ -202,8 +208,8 @@ func postJSON(cmd *cobra.Command, prefix string, input map[string]interface{}) {
 		return nil
 	})
 	if err != nil {
-		cmd.Printf("Failed! %s", err)
-		return
+		return errors.Errorf("failed! %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
@@ -28,16 +28,15 @@ func NewHealthCommand() *cobra.Command {
 	m := &cobra.Command{
 		Use:   "health",
 		Short: "show all node's health information of the pd cluster",
-		Run:   showHealthCommandFunc,
+		RunE:  showHealthCommandFunc,
 	}
 	return m
 }
 
-func showHealthCommandFunc(cmd *cobra.Command, args []string) {
+func showHealthCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, healthPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
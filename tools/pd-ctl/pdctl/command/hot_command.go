@@ -16,6 +16,7 @@ package command
 import (
 	"net/http"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -42,18 +43,17 @@ func NewHotWriteRegionCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "write",
 		Short: "show the hot write regions",
-		Run:   showHotWriteRegionsCommandFunc,
+		RunE:  showHotWriteRegionsCommandFunc,
 	}
 	return cmd
 }
 
-func showHotWriteRegionsCommandFunc(cmd *cobra.Command, args []string) {
+func showHotWriteRegionsCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, hotWriteRegionsPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get hotspot: %s\n", err)
-		return
+		return errors.Errorf("failed to get hotspot: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewHotReadRegionCommand return a hot read regions subcommand of hotSpotCmd
@@ -61,18 +61,17 @@ func NewHotReadRegionCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "read",
 		Short: "show the hot read regions",
-		Run:   showHotReadRegionsCommandFunc,
+		RunE:  showHotReadRegionsCommandFunc,
 	}
 	return cmd
 }
 
-func showHotReadRegionsCommandFunc(cmd *cobra.Command, args []string) {
+func showHotReadRegionsCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, hotReadRegionsPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get hotspot: %s\n", err)
-		return
+		return errors.Errorf("failed to get hotspot: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewHotStoreCommand return a hot stores subcommand of hotSpotCmd
@@ -80,16 +79,15 @@ func NewHotStoreCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "store",
 		Short: "show the hot stores",
-		Run:   showHotStoresCommandFunc,
+		RunE:  showHotStoresCommandFunc,
 	}
 	return cmd
 }
 
-func showHotStoresCommandFunc(cmd *cobra.Command, args []string) {
+func showHotStoresCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, hotStoresPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get hotspot: %s\n", err)
-		return
+		return errors.Errorf("failed to get hotspot: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -30,28 +31,26 @@ func NewLogCommand() *cobra.Command {
 	conf := &cobra.Command{
 		Use:   "log [fatal|error|warn|info|debug]",
 		Short: "set log level",
-		Run:   logCommandFunc,
+		RunE:  logCommandFunc,
 	}
 	return conf
 }
 
-func logCommandFunc(cmd *cobra.Command, args []string) {
+func logCommandFunc(cmd *cobra.Command, args []string) error {
 	var err error
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	data, err := json.Marshal(args[0])
 	if err != nil {
-		cmd.Printf("Failed to set log level: %s\n", err)
-		return
+		return errors.Errorf("failed to set log level: %s", err)
 	}
 	_, err = doRequest(cmd, logPrefix, http.MethodPost,
 		WithBody("application/json", bytes.NewBuffer(data)))
 	if err != nil {
-		cmd.Printf("Failed to set log level: %s\n", err)
-		return
+		return errors.Errorf("failed to set log level: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -32,7 +33,7 @@ func NewMemberCommand() *cobra.Command {
 	m := &cobra.Command{
 		Use:   "member [leader|delete|leader_priority]",
 		Short: "show the pd member status",
-		Run:   showMemberCommandFunc,
+		RunE:  showMemberCommandFunc,
 	}
 	m.AddCommand(NewLeaderMemberCommand())
 	m.AddCommand(NewDeleteMemberCommand())
@@ -40,7 +41,7 @@ func NewMemberCommand() *cobra.Command {
 	m.AddCommand(&cobra.Command{
 		Use:   "leader_priority <member_name> <priority>",
 		Short: "set the member's priority to be elected as etcd leader",
-		Run:   setLeaderPriorityFunc,
+		RunE:  setLeaderPriorityFunc,
 	})
 	return m
 }
@@ -54,12 +55,12 @@ func NewDeleteMemberCommand() *cobra.Command {
 	d.AddCommand(&cobra.Command{
 		Use:   "name <member_name>",
 		Short: "delete a member by name",
-		Run:   deleteMemberByNameCommandFunc,
+		RunE:  deleteMemberByNameCommandFunc,
 	})
 	d.AddCommand(&cobra.Command{
 		Use:   "id <member_id>",
 		Short: "delete a member by id",
-		Run:   deleteMemberByIDCommandFunc,
+		RunE:  deleteMemberByIDCommandFunc,
 	})
 	return d
 }
@@ -73,108 +74,101 @@ func NewLeaderMemberCommand() *cobra.Command {
 	d.AddCommand(&cobra.Command{
 		Use:   "show",
 		Short: "show the leader member status",
-		Run:   getLeaderMemberCommandFunc,
+		RunE:  getLeaderMemberCommandFunc,
 	})
 	d.AddCommand(&cobra.Command{
 		Use:   "resign",
 		Short: "resign current leader pd's leadership",
-		Run:   resignLeaderCommandFunc,
+		RunE:  resignLeaderCommandFunc,
 	})
 	d.AddCommand(&cobra.Command{
 		Use:   "transfer <member_name>",
 		Short: "transfer leadership to another pd",
-		Run:   transferPDLeaderCommandFunc,
+		RunE:  transferPDLeaderCommandFunc,
 	})
 	return d
 }
 
-func showMemberCommandFunc(cmd *cobra.Command, args []string) {
+func showMemberCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, membersPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get pd members: %s\n", err)
-		return
+		return errors.Errorf("failed to get pd members: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func deleteMemberByNameCommandFunc(cmd *cobra.Command, args []string) {
+func deleteMemberByNameCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println("Usage: member delete <member_name>")
-		return
+		return errors.New("usage: member delete <member_name>")
 	}
 	prefix := membersPrefix + "/name/" + args[0]
 	_, err := doRequest(cmd, prefix, http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to delete member %s: %s\n", args[0], err)
-		return
+		return errors.Errorf("failed to delete member %s: %s", args[0], err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func deleteMemberByIDCommandFunc(cmd *cobra.Command, args []string) {
+func deleteMemberByIDCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println("Usage: member delete id <member_id>")
-		return
+		return errors.New("usage: member delete id <member_id>")
 	}
 	prefix := membersPrefix + "/id/" + args[0]
 	_, err := doRequest(cmd, prefix, http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to delete member %s: %s\n", args[0], err)
-		return
+		return errors.Errorf("failed to delete member %s: %s", args[0], err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func getLeaderMemberCommandFunc(cmd *cobra.Command, args []string) {
+func getLeaderMemberCommandFunc(cmd *cobra.Command, args []string) error {
 	r, err := doRequest(cmd, leaderMemberPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get the leader of pd members: %s\n", err)
-		return
+		return errors.Errorf("failed to get the leader of pd members: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func resignLeaderCommandFunc(cmd *cobra.Command, args []string) {
+func resignLeaderCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := leaderMemberPrefix + "/resign"
 	_, err := doRequest(cmd, prefix, http.MethodPost)
 	if err != nil {
-		cmd.Printf("Failed to resign: %s\n", err)
-		return
+		return errors.Errorf("failed to resign: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func transferPDLeaderCommandFunc(cmd *cobra.Command, args []string) {
+func transferPDLeaderCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println("Usage: leader transfer <member_name>")
-		return
+		return errors.New("usage: leader transfer <member_name>")
 	}
 	prefix := leaderMemberPrefix + "/transfer/" + args[0]
 	_, err := doRequest(cmd, prefix, http.MethodPost)
 	if err != nil {
-		cmd.Printf("Failed to transfer leadership: %s\n", err)
-		return
+		return errors.Errorf("failed to transfer leadership: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func setLeaderPriorityFunc(cmd *cobra.Command, args []string) {
+func setLeaderPriorityFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println("Usage: leader_priority <member_name> <priority>")
-		return
+		return errors.New("usage: leader_priority <member_name> <priority>")
 	}
 	prefix := membersPrefix + "/name/" + args[0]
 	priority, err := strconv.ParseFloat(args[1], 64)
 	if err != nil {
-		cmd.Printf("failed to parse priority: %v\n", err)
-		return
+		return errors.Errorf("failed to parse priority: %v", err)
 	}
 	data := map[string]interface{}{"leader-priority": priority}
 	reqData, _ := json.Marshal(data)
 	_, err = doRequest(cmd, prefix, http.MethodPost, WithBody("application/json", bytes.NewBuffer(reqData)))
 	if err != nil {
-		cmd.Printf("failed to set leader priority: %v\n", err)
-		return
+		return errors.Errorf("failed to set leader priority: %v", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
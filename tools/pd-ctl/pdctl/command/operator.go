@@ -44,7 +44,7 @@ func NewCheckOperatorCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "check [region_id]",
 		Short: "checks the status of operator",
-		Run:   checkOperatorCommandFunc,
+		RunE:  checkOperatorCommandFunc,
 	}
 	return c
 }
@@ -53,48 +53,44 @@ func NewCheckOperatorCommand() *cobra.Command {
 func NewShowOperatorCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "show [kind]",
-		Short: "show operators",
-		Run:   showOperatorCommandFunc,
+		Short: "show operators, the kinds can be joined by comma, e.g. admin,region",
+		RunE:  showOperatorCommandFunc,
 	}
 	return c
 }
 
-func showOperatorCommandFunc(cmd *cobra.Command, args []string) {
+func showOperatorCommandFunc(cmd *cobra.Command, args []string) error {
 	var path string
 	if len(args) == 0 {
 		path = operatorsPrefix
 	} else if len(args) == 1 {
 		path = fmt.Sprintf("%s?kind=%s", operatorsPrefix, args[0])
 	} else {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	r, err := doRequest(cmd, path, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func checkOperatorCommandFunc(cmd *cobra.Command, args []string) {
+func checkOperatorCommandFunc(cmd *cobra.Command, args []string) error {
 	var path string
 	if len(args) == 0 {
 		path = operatorsPrefix
 	} else if len(args) == 1 {
 		path = fmt.Sprintf("%s/%s", operatorsPrefix, args[0])
 	} else {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	r, err := doRequest(cmd, path, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewAddOperatorCommand returns a command to add operators.
@@ -120,28 +116,26 @@ func NewTransferLeaderCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "transfer-leader <region_id> <to_store_id>",
 		Short: "transfer a region's leader to the specified store",
-		Run:   transferLeaderCommandFunc,
+		RunE:  transferLeaderCommandFunc,
 	}
 	return c
 }
 
-func transferLeaderCommandFunc(cmd *cobra.Command, args []string) {
+func transferLeaderCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["to_store_id"] = ids[1]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewTransferRegionCommand returns a command to transfer region.
@@ -149,28 +143,26 @@ func NewTransferRegionCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "transfer-region <region_id> <to_store_id>...",
 		Short: "transfer a region's peers to the specified stores",
-		Run:   transferRegionCommandFunc,
+		RunE:  transferRegionCommandFunc,
 	}
 	return c
 }
 
-func transferRegionCommandFunc(cmd *cobra.Command, args []string) {
+func transferRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) <= 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["to_store_ids"] = ids[1:]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewTransferPeerCommand returns a command to transfer region.
@@ -178,21 +170,19 @@ func NewTransferPeerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "transfer-peer <region_id> <from_store_id> <to_store_id>",
 		Short: "transfer a region's peer from the specified store to another store",
-		Run:   transferPeerCommandFunc,
+		RunE:  transferPeerCommandFunc,
 	}
 	return c
 }
 
-func transferPeerCommandFunc(cmd *cobra.Command, args []string) {
+func transferPeerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 3 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
@@ -200,7 +190,7 @@ func transferPeerCommandFunc(cmd *cobra.Command, args []string) {
 	input["region_id"] = ids[0]
 	input["from_store_id"] = ids[1]
 	input["to_store_id"] = ids[2]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewAddPeerCommand returns a command to add region peer.
@@ -208,28 +198,26 @@ func NewAddPeerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "add-peer <region_id> <to_store_id>",
 		Short: "add a region peer on specified store",
-		Run:   addPeerCommandFunc,
+		RunE:  addPeerCommandFunc,
 	}
 	return c
 }
 
-func addPeerCommandFunc(cmd *cobra.Command, args []string) {
+func addPeerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["store_id"] = ids[1]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewAddLearnerCommand returns a command to add region learner.
@@ -237,28 +225,26 @@ func NewAddLearnerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "add-learner <region_id> <to_store_id>",
 		Short: "add a region learner on specified store",
-		Run:   addLearnerCommandFunc,
+		RunE:  addLearnerCommandFunc,
 	}
 	return c
 }
 
-func addLearnerCommandFunc(cmd *cobra.Command, args []string) {
+func addLearnerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		fmt.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["store_id"] = ids[1]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewMergeRegionCommand returns a command to merge two regions.
@@ -266,28 +252,26 @@ func NewMergeRegionCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "merge-region <source_region_id> <target_region_id>",
 		Short: "merge source region into target region",
-		Run:   mergeRegionCommandFunc,
+		RunE:  mergeRegionCommandFunc,
 	}
 	return c
 }
 
-func mergeRegionCommandFunc(cmd *cobra.Command, args []string) {
+func mergeRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["source_region_id"] = ids[0]
 	input["target_region_id"] = ids[1]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewRemovePeerCommand returns a command to add region peer.
@@ -295,28 +279,26 @@ func NewRemovePeerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "remove-peer <region_id> <from_store_id>",
 		Short: "remove a region peer on specified store",
-		Run:   removePeerCommandFunc,
+		RunE:  removePeerCommandFunc,
 	}
 	return c
 }
 
-func removePeerCommandFunc(cmd *cobra.Command, args []string) {
+func removePeerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["store_id"] = ids[1]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewSplitRegionCommand returns a command to split a region.
@@ -324,22 +306,20 @@ func NewSplitRegionCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "split-region <region_id> [--policy=scan|approximate]",
 		Short: "split a region",
-		Run:   splitRegionCommandFunc,
+		RunE:  splitRegionCommandFunc,
 	}
 	c.Flags().String("policy", "scan", "the policy to get region split key")
 	return c
 }
 
-func splitRegionCommandFunc(cmd *cobra.Command, args []string) {
+func splitRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	policy := cmd.Flags().Lookup("policy").Value.String()
@@ -347,15 +327,14 @@ func splitRegionCommandFunc(cmd *cobra.Command, args []string) {
 	case "scan", "approximate":
 		break
 	default:
-		cmd.Println("Error: unknown policy")
-		return
+		return errors.New("unknown policy")
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
 	input["policy"] = policy
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewScatterRegionCommand returns a command to scatter a region.
@@ -364,27 +343,25 @@ func NewScatterRegionCommand() *cobra.Command {
 		Use:   "scatter-region <region_id>",
 		Short: "usually used for a batch of adjacent regions",
 		Long:  "usually used for a batch of adjacent regions, for example, scatter the regions for 1 to 100, need to use the following commands in order: \"scatter-region 1; scatter-region 2; ...; scatter-region 100;\"",
-		Run:   scatterRegionCommandFunc,
+		RunE:  scatterRegionCommandFunc,
 	}
 	return c
 }
 
-func scatterRegionCommandFunc(cmd *cobra.Command, args []string) {
+func scatterRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	ids, err := parseUint64s(args)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["region_id"] = ids[0]
-	postJSON(cmd, operatorsPrefix, input)
+	return postJSON(cmd, operatorsPrefix, input)
 }
 
 // NewRemoveOperatorCommand returns a command to remove operators.
@@ -392,24 +369,23 @@ func NewRemoveOperatorCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "remove <region_id>",
 		Short: "remove the region operator",
-		Run:   removeOperatorCommandFunc,
+		RunE:  removeOperatorCommandFunc,
 	}
 	return c
 }
 
-func removeOperatorCommandFunc(cmd *cobra.Command, args []string) {
+func removeOperatorCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	path := operatorsPrefix + "/" + args[0]
 	_, err := doRequest(cmd, path, http.MethodDelete)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
 func parseUint64s(args []string) ([]uint64, error) {
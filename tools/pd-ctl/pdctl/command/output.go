@@ -0,0 +1,203 @@
+// Copyright 2020 TiKV Project Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ghodss/yaml"
+	"github.com/pingcap/errors"
+	"github.com/spf13/cobra"
+)
+
+// The output formats supported by the `--output` flag.
+const (
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+)
+
+// printResponse prints the JSON response of PD in the format specified by
+// the `--output` flag. Responses which are not JSON are printed as they are.
+func printResponse(cmd *cobra.Command, r string) error {
+	format := OutputJSON
+	if flag := cmd.Flag("output"); flag != nil {
+		format = flag.Value.String()
+	}
+	out, err := formatResponse(format, r)
+	if err != nil {
+		return err
+	}
+	cmd.Println(out)
+	return nil
+}
+
+func formatResponse(format, r string) (string, error) {
+	switch format {
+	case "", OutputJSON:
+		return r, nil
+	case OutputYAML, OutputTable:
+	default:
+		return "", errors.Errorf("unknown output format %q, it should be one of %s, %s and %s", format, OutputJSON, OutputYAML, OutputTable)
+	}
+
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(r))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return r, nil
+	}
+	if format == OutputYAML {
+		out, err := yaml.JSONToYAML([]byte(r))
+		if err != nil {
+			return "", errors.WithStack(err)
+		}
+		return strings.TrimSuffix(string(out), "\n"), nil
+	}
+	return formatTable(v), nil
+}
+
+// formatTable renders the decoded JSON value as a table. A list of objects
+// is rendered one row per object, and the columns are the sorted union of
+// their flattened keys, e.g. `store.id`. An object which holds exactly one
+// list of objects, such as `{"count": 2, "stores": [...]}`, is rendered as
+// that list. Any other object is rendered as key-value rows.
+func formatTable(v interface{}) string {
+	if obj, ok := v.(map[string]interface{}); ok {
+		var list []interface{}
+		for _, field := range obj {
+			if l, ok := field.([]interface{}); ok && isObjectList(l) {
+				if list != nil {
+					list = nil
+					break
+				}
+				list = l
+			}
+		}
+		if list != nil {
+			v = list
+		}
+	}
+
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	switch val := v.(type) {
+	case []interface{}:
+		if !isObjectList(val) {
+			fmt.Fprintln(w, "VALUE")
+			for _, item := range val {
+				fmt.Fprintln(w, formatCell(item))
+			}
+			break
+		}
+		rows := make([]map[string]string, 0, len(val))
+		columnSet := make(map[string]struct{})
+		for _, item := range val {
+			row := make(map[string]string)
+			flatten("", item, row)
+			for k := range row {
+				columnSet[k] = struct{}{}
+			}
+			rows = append(rows, row)
+		}
+		columns := make([]string, 0, len(columnSet))
+		for k := range columnSet {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+		headers := make([]string, 0, len(columns))
+		for _, c := range columns {
+			headers = append(headers, strings.ToUpper(c))
+		}
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			cells := make([]string, 0, len(columns))
+			for _, c := range columns {
+				cells = append(cells, row[c])
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+	case map[string]interface{}:
+		row := make(map[string]string)
+		flatten("", val, row)
+		keys := make([]string, 0, len(row))
+		for k := range row {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Fprintln(w, "KEY\tVALUE")
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s\t%s\n", k, row[k])
+		}
+	default:
+		fmt.Fprintln(w, formatCell(val))
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func isObjectList(l []interface{}) bool {
+	if len(l) == 0 {
+		return false
+	}
+	for _, item := range l {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten flattens the nested objects into dot-separated keys. Lists are
+// kept as compact JSON in a single cell.
+func flatten(prefix string, v interface{}, row map[string]string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		if prefix == "" {
+			prefix = "value"
+		}
+		row[prefix] = formatCell(v)
+		return
+	}
+	for k, field := range obj {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		flatten(k, field, row)
+	}
+}
+
+func formatCell(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		return fmt.Sprint(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 	"github.com/tikv/pd/server/cluster"
 )
@@ -42,7 +43,7 @@ func NewLoadPluginCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "load <plugin_path>",
 		Short: "load a plugin, path must begin with ./pd/plugin/",
-		Run:   loadPluginCommandFunc,
+		RunE:  loadPluginCommandFunc,
 	}
 	return r
 }
@@ -52,31 +53,29 @@ func NewUnloadPluginCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "unload <plugin_path>",
 		Short: "unload a plugin, path must begin with ./pd/plugin/",
-		Run:   unloadPluginCommandFunc,
+		RunE:  unloadPluginCommandFunc,
 	}
 	return r
 }
 
-func loadPluginCommandFunc(cmd *cobra.Command, args []string) {
-	sendPluginCommand(cmd, cluster.PluginLoad, args)
+func loadPluginCommandFunc(cmd *cobra.Command, args []string) error {
+	return sendPluginCommand(cmd, cluster.PluginLoad, args)
 }
 
-func unloadPluginCommandFunc(cmd *cobra.Command, args []string) {
-	sendPluginCommand(cmd, cluster.PluginUnload, args)
+func unloadPluginCommandFunc(cmd *cobra.Command, args []string) error {
+	return sendPluginCommand(cmd, cluster.PluginUnload, args)
 }
 
-func sendPluginCommand(cmd *cobra.Command, action string, args []string) {
+func sendPluginCommand(cmd *cobra.Command, action string, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.Usage())
-		return
+		return usageError(cmd)
 	}
 	data := map[string]interface{}{
 		"plugin-path": args[0],
 	}
 	reqData, err := json.Marshal(data)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	switch action {
 	case cluster.PluginLoad:
@@ -84,12 +83,11 @@ func sendPluginCommand(cmd *cobra.Command, action string, args []string) {
 	case cluster.PluginUnload:
 		_, err = doRequest(cmd, pluginPrefix, http.MethodDelete, WithBody("application/json", bytes.NewBuffer(reqData)))
 	default:
-		cmd.Printf("Unknown action %s\n", action)
-		return
+		return errors.Errorf("unknown action %s", action)
 	}
 	if err != nil {
-		cmd.Printf("Failed to %s plugin %s: %s\n", action, args[0], err)
-		return
+		return errors.Errorf("failed to %s plugin %s: %s", action, args[0], err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
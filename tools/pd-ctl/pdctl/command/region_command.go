@@ -50,7 +50,7 @@ func NewRegionCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   `region <region_id> [-jq="<query string>"]`,
 		Short: "show the region status",
-		Run:   showRegionCommandFunc,
+		RunE:  showRegionCommandFunc,
 	}
 	r.AddCommand(NewRegionWithKeyCommand())
 	r.AddCommand(NewRegionWithCheckCommand())
@@ -61,7 +61,7 @@ func NewRegionCommand() *cobra.Command {
 	topRead := &cobra.Command{
 		Use:   `topread <limit> [--jq="<query string>"]`,
 		Short: "show regions with top read flow",
-		Run:   showRegionTopReadCommandFunc,
+		RunE:  showRegionTopReadCommandFunc,
 	}
 	topRead.Flags().String("jq", "", "jq query")
 	r.AddCommand(topRead)
@@ -69,7 +69,7 @@ func NewRegionCommand() *cobra.Command {
 	topWrite := &cobra.Command{
 		Use:   `topwrite <limit> [--jq="<query string>"]`,
 		Short: "show regions with top write flow",
-		Run:   showRegionTopWriteCommandFunc,
+		RunE:  showRegionTopWriteCommandFunc,
 	}
 	topWrite.Flags().String("jq", "", "jq query")
 	r.AddCommand(topWrite)
@@ -77,7 +77,7 @@ func NewRegionCommand() *cobra.Command {
 	topConfVer := &cobra.Command{
 		Use:   `topconfver <limit> [--jq="<query string>"]`,
 		Short: "show regions with top conf version",
-		Run:   showRegionTopConfVerCommandFunc,
+		RunE:  showRegionTopConfVerCommandFunc,
 	}
 	topConfVer.Flags().String("jq", "", "jq query")
 	r.AddCommand(topConfVer)
@@ -85,7 +85,7 @@ func NewRegionCommand() *cobra.Command {
 	topVersion := &cobra.Command{
 		Use:   `topversion <limit> [--jq="<query string>"]`,
 		Short: "show regions with top version",
-		Run:   showRegionTopVersionCommandFunc,
+		RunE:  showRegionTopVersionCommandFunc,
 	}
 	topVersion.Flags().String("jq", "", "jq query")
 	r.AddCommand(topVersion)
@@ -93,7 +93,7 @@ func NewRegionCommand() *cobra.Command {
 	topSize := &cobra.Command{
 		Use:   `topsize <limit> [--jq="<query string>"]`,
 		Short: "show regions with top size",
-		Run:   showRegionTopSizeCommandFunc,
+		RunE:  showRegionTopSizeCommandFunc,
 	}
 	topSize.Flags().String("jq", "", "jq query")
 	r.AddCommand(topSize)
@@ -101,7 +101,7 @@ func NewRegionCommand() *cobra.Command {
 	scanRegion := &cobra.Command{
 		Use:   `scan [--jq="<query string>"]`,
 		Short: "scan all regions",
-		Run:   scanRegionCommandFunc,
+		RunE:  scanRegionCommandFunc,
 	}
 	scanRegion.Flags().String("jq", "", "jq query")
 	r.AddCommand(scanRegion)
@@ -111,43 +111,41 @@ func NewRegionCommand() *cobra.Command {
 	return r
 }
 
-func showRegionCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsPrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("region_id should be a number")
-			return
+			return errors.New("region_id should be a number")
 		}
 		prefix = regionIDPrefix + "/" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get region: %s\n", err)
-		return
+		return errors.Errorf("failed to get region: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
 
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func scanRegionCommandFunc(cmd *cobra.Command, args []string) {
+func scanRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	const limit = 1024
 	var key []byte
 	for {
 		uri := fmt.Sprintf("%s?key=%s&limit=%d", regionsKeyPrefix, url.QueryEscape(string(key)), limit)
 		r, err := doRequest(cmd, uri, http.MethodGet)
 		if err != nil {
-			cmd.Printf("Failed to scan regions: %s\n", err)
-			return
+			return errors.Errorf("failed to scan regions: %s", err)
 		}
 
 		if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-			printWithJQFilter(r, flag.Value.String())
-		} else {
-			cmd.Println(r)
+			if err := printWithJQFilter(r, flag.Value.String()); err != nil {
+				return err
+			}
+		} else if err := printResponse(cmd, r); err != nil {
+			return err
 		}
 
 		// Extract last region's endkey for next batch.
@@ -159,129 +157,112 @@ func scanRegionCommandFunc(cmd *cobra.Command, args []string) {
 
 		var regions regionsInfo
 		if err = json.Unmarshal([]byte(r), &regions); err != nil {
-			cmd.Printf("Failed to unmarshal regions: %s\n", err)
-			return
+			return errors.Errorf("failed to unmarshal regions: %s", err)
 		}
 		if len(regions.Regions) == 0 {
-			return
+			return nil
 		}
 
 		lastEndKey := regions.Regions[len(regions.Regions)-1].EndKey
 		if lastEndKey == "" {
-			return
+			return nil
 		}
 
 		key, err = hex.DecodeString(lastEndKey)
 		if err != nil {
-			cmd.Println("Bad format region key: ", key)
-			return
+			return errors.Errorf("bad format region key: %s", lastEndKey)
 		}
 	}
 }
 
-func showRegionTopWriteCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionTopWriteCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsWriteFlowPrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "?limit=" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showRegionTopReadCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionTopReadCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsReadFlowPrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "?limit=" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showRegionTopConfVerCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionTopConfVerCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsConfVerPrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "?limit=" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showRegionTopVersionCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionTopVersionCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsVersionPrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "?limit=" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showRegionTopSizeCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionTopSizeCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := regionsSizePrefix
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "?limit=" + args[0]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewRegionWithKeyCommand return a region with key subcommand of regionCmd
@@ -289,30 +270,27 @@ func NewRegionWithKeyCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "key [--format=raw|encode|hex] <key>",
 		Short: "show the region with key",
-		Run:   showRegionWithTableCommandFunc,
+		RunE:  showRegionWithTableCommandFunc,
 	}
 	r.Flags().String("format", "hex", "the key format")
 	return r
 }
 
-func showRegionWithTableCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionWithTableCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	key, err := parseKey(cmd.Flags(), args[0])
 	if err != nil {
-		cmd.Println("Error: ", err)
-		return
+		return err
 	}
 	key = url.QueryEscape(key)
 	prefix := regionKeyPrefix + "/" + key
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get region: %s\n", err)
-		return
+		return errors.Errorf("failed to get region: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 func parseKey(flags *pflag.FlagSet, key string) (string, error) {
@@ -377,38 +355,34 @@ func NewRegionsWithStartKeyCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "startkey [--format=raw|encode|hex] <key> <limit>",
 		Short: "show regions from start key",
-		Run:   showRegionsFromStartKeyCommandFunc,
+		RunE:  showRegionsFromStartKeyCommandFunc,
 	}
 
 	r.Flags().String("format", "hex", "the key format")
 	return r
 }
 
-func showRegionsFromStartKeyCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionsFromStartKeyCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 || len(args) > 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	key, err := parseKey(cmd.Flags(), args[0])
 	if err != nil {
-		cmd.Println("Error: ", err)
-		return
+		return err
 	}
 	key = url.QueryEscape(key)
 	prefix := regionsKeyPrefix + "?key=" + key
 	if len(args) == 2 {
 		if _, err = strconv.Atoi(args[1]); err != nil {
-			cmd.Println("limit should be a number")
-			return
+			return errors.New("limit should be a number")
 		}
 		prefix += "&limit=" + args[1]
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get region: %s\n", err)
-		return
+		return errors.Errorf("failed to get region: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewRegionWithCheckCommand returns a region with check subcommand of regionCmd
@@ -416,23 +390,21 @@ func NewRegionWithCheckCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "check [miss-peer|extra-peer|down-peer|learner-peer|pending-peer|offline-peer|empty-region|hist-size|hist-keys]",
 		Short: "show the region with check specific status",
-		Run:   showRegionWithCheckCommandFunc,
+		RunE:  showRegionWithCheckCommandFunc,
 	}
 	return r
 }
 
-func showRegionWithCheckCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionWithCheckCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) < 1 || len(args) > 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	state := args[0]
 	prefix := regionsCheckPrefix + "/" + state
 	if strings.EqualFold(state, "hist-size") {
 		if len(args) == 2 {
 			if _, err := strconv.Atoi(args[1]); err != nil {
-				cmd.Println("region size histogram bound should be a number")
-				return
+				return errors.New("region size histogram bound should be a number")
 			}
 			prefix += "?bound=" + args[1]
 		} else {
@@ -441,8 +413,7 @@ func showRegionWithCheckCommandFunc(cmd *cobra.Command, args []string) {
 	} else if strings.EqualFold(state, "hist-keys") {
 		if len(args) == 2 {
 			if _, err := strconv.Atoi(args[1]); err != nil {
-				cmd.Println("region keys histogram bound should be a number")
-				return
+				return errors.New("region keys histogram bound should be a number")
 			}
 			prefix += "?bound=" + args[1]
 		} else {
@@ -451,10 +422,9 @@ func showRegionWithCheckCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get region: %s\n", err)
-		return
+		return errors.Errorf("failed to get region: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewRegionWithSiblingCommand returns a region with sibling subcommand of regionCmd
@@ -462,24 +432,22 @@ func NewRegionWithSiblingCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "sibling <region_id>",
 		Short: "show the sibling regions of specific region",
-		Run:   showRegionWithSiblingCommandFunc,
+		RunE:  showRegionWithSiblingCommandFunc,
 	}
 	return r
 }
 
-func showRegionWithSiblingCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionWithSiblingCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	regionID := args[0]
 	prefix := regionsSiblingPrefix + "/" + regionID
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get region sibling: %s\n", err)
-		return
+		return errors.Errorf("failed to get region sibling: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewRegionWithStoreCommand returns regions with store subcommand of regionCmd
@@ -487,32 +455,29 @@ func NewRegionWithStoreCommand() *cobra.Command {
 	r := &cobra.Command{
 		Use:   "store <store_id>",
 		Short: "show the regions of a specific store",
-		Run:   showRegionWithStoreCommandFunc,
+		RunE:  showRegionWithStoreCommandFunc,
 	}
 	return r
 }
 
-func showRegionWithStoreCommandFunc(cmd *cobra.Command, args []string) {
+func showRegionWithStoreCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	storeID := args[0]
 	prefix := regionsStorePrefix + "/" + storeID
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get regions with the given storeID: %s\n", err)
-		return
+		return errors.Errorf("failed to get regions with the given storeID: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func printWithJQFilter(data, filter string) {
+func printWithJQFilter(data, filter string) error {
 	cmd := exec.Command("jq", "-c", filter)
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
 
 	go func() {
@@ -525,9 +490,9 @@ func printWithJQFilter(data, filter string) {
 
 	out, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Println(string(out), err)
-		return
+		return errors.Errorf("%s %s", out, err)
 	}
 
 	fmt.Printf("%s\n", out)
+	return nil
 }
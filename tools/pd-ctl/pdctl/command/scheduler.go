@@ -22,6 +22,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 )
 
@@ -44,23 +45,102 @@ func NewSchedulerCommand() *cobra.Command {
 	c.AddCommand(NewPauseSchedulerCommand())
 	c.AddCommand(NewResumeSchedulerCommand())
 	c.AddCommand(NewConfigSchedulerCommand())
+	c.AddCommand(NewNamespaceSchedulerCommand())
+	c.AddCommand(NewStoreListSchedulerCommand())
 	return c
 }
 
+// NewNamespaceSchedulerCommand returns a command to bind a scheduler to a namespace.
+func NewNamespaceSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "namespace <scheduler> [<namespace>]",
+		Short: "bind a scheduler to a namespace, unbind it if the namespace is omitted",
+		RunE:  namespaceSchedulerCommandFunc,
+	}
+	return c
+}
+
+func namespaceSchedulerCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 && len(args) != 1 {
+		return usageError(cmd)
+	}
+	path := schedulersPrefix + "/" + args[0] + "/namespace"
+	input := map[string]interface{}{"namespace": ""}
+	if len(args) == 2 {
+		input["namespace"] = args[1]
+	}
+	return postJSON(cmd, path, input)
+}
+
+// NewStoreListSchedulerCommand returns a command to limit the stores used by a scheduler.
+func NewStoreListSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "stores",
+		Short: "show or set the stores allowed and denied for a scheduler",
+	}
+	c.AddCommand(&cobra.Command{
+		Use:   "show <scheduler>",
+		Short: "show the stores allowed and denied for a scheduler",
+		RunE:  showSchedulerStoreListCommandFunc,
+	})
+	sc := &cobra.Command{
+		Use:   "set <scheduler> [--allow=<store-ids>] [--deny=<store-ids>]",
+		Short: "set the stores allowed and denied for a scheduler, remove the limit if both are omitted",
+		RunE:  setSchedulerStoreListCommandFunc,
+	}
+	sc.Flags().StringSlice("allow", nil, "the only stores the scheduler can use")
+	sc.Flags().StringSlice("deny", nil, "the stores the scheduler never uses")
+	c.AddCommand(sc)
+	return c
+}
+
+func showSchedulerStoreListCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	r, err := doRequest(cmd, schedulersPrefix+"/"+args[0]+"/stores", http.MethodGet)
+	if err != nil {
+		return err
+	}
+	return printResponse(cmd, r)
+}
+
+func setSchedulerStoreListCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	input := make(map[string]interface{})
+	for _, name := range []string{"allow", "deny"} {
+		values, err := cmd.Flags().GetStringSlice(name)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint64, 0, len(values))
+		for _, v := range values {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return errors.Errorf("invalid store id %s", v)
+			}
+			ids = append(ids, id)
+		}
+		input[name] = ids
+	}
+	return postJSON(cmd, schedulersPrefix+"/"+args[0]+"/stores", input)
+}
+
 // NewPauseSchedulerCommand returns a command to pause a scheduler.
 func NewPauseSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "pause <scheduler> <delay>",
 		Short: "pause a scheduler",
-		Run:   pauseOrResumeSchedulerCommandFunc,
+		RunE:  pauseOrResumeSchedulerCommandFunc,
 	}
 	return c
 }
 
-func pauseOrResumeSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+func pauseOrResumeSchedulerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 2 && len(args) != 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	path := schedulersPrefix + "/" + args[0]
 	input := make(map[string]interface{})
@@ -68,12 +148,11 @@ func pauseOrResumeSchedulerCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) == 2 {
 		delay, err := strconv.Atoi(args[1])
 		if err != nil {
-			cmd.Usage()
-			return
+			return usageError(cmd)
 		}
 		input["delay"] = delay
 	}
-	postJSON(cmd, path, input)
+	return postJSON(cmd, path, input)
 }
 
 // NewShowSchedulerCommand returns a command to show schedulers.
@@ -81,7 +160,7 @@ func NewShowSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "show",
 		Short: "show schedulers",
-		Run:   showSchedulerCommandFunc,
+		RunE:  showSchedulerCommandFunc,
 	}
 	return c
 }
@@ -91,23 +170,21 @@ func NewResumeSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "resume <scheduler>",
 		Short: "resume a scheduler",
-		Run:   pauseOrResumeSchedulerCommandFunc,
+		RunE:  pauseOrResumeSchedulerCommandFunc,
 	}
 	return c
 }
 
-func showSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+func showSchedulerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	r, err := doRequest(cmd, schedulersPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
 // NewAddSchedulerCommand returns a command to add scheduler.
@@ -127,6 +204,7 @@ func NewAddSchedulerCommand() *cobra.Command {
 	c.AddCommand(NewBalanceHotRegionSchedulerCommand())
 	c.AddCommand(NewRandomMergeSchedulerCommand())
 	c.AddCommand(NewLabelSchedulerCommand())
+	c.AddCommand(NewLoadSplitSchedulerCommand())
 	return c
 }
 
@@ -135,7 +213,7 @@ func NewGrantLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "grant-leader-scheduler <store_id>",
 		Short: "add a scheduler to grant leader to a store",
-		Run:   addSchedulerForStoreCommandFunc,
+		RunE:  addSchedulerForStoreCommandFunc,
 	}
 	return c
 }
@@ -145,7 +223,7 @@ func NewEvictLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "evict-leader-scheduler <store_id>",
 		Short: "add a scheduler to evict leader from a store",
-		Run:   addSchedulerForStoreCommandFunc,
+		RunE:  addSchedulerForStoreCommandFunc,
 	}
 	return c
 }
@@ -153,7 +231,6 @@ func NewEvictLeaderSchedulerCommand() *cobra.Command {
 func checkSchedulerExist(cmd *cobra.Command, schedulerName string) (bool, error) {
 	r, err := doRequest(cmd, schedulersPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
 		return false, err
 	}
 	var schedulerList []string
@@ -166,10 +243,9 @@ func checkSchedulerExist(cmd *cobra.Command, schedulerName string) (bool, error)
 	return false, nil
 }
 
-func addSchedulerForStoreCommandFunc(cmd *cobra.Command, args []string) {
+func addSchedulerForStoreCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	// we should ensure whether it is the first time to create evict-leader-scheduler
 	// or just update the evict-leader. But is add one ttl time.
@@ -177,26 +253,23 @@ func addSchedulerForStoreCommandFunc(cmd *cobra.Command, args []string) {
 	case evictLeaderSchedulerName, grantLeaderSchedulerName:
 		exist, err := checkSchedulerExist(cmd, cmd.Name())
 		if err != nil {
-			return
+			return err
 		}
 		if exist {
-			addStoreToSchedulerConfig(cmd, cmd.Name(), args)
-			return
+			return addStoreToSchedulerConfig(cmd, cmd.Name(), args)
 		}
 		fallthrough
 	default:
 		storeID, err := strconv.ParseUint(args[0], 10, 64)
 		if err != nil {
-			cmd.Println(err)
-			return
+			return err
 		}
 
 		input := make(map[string]interface{})
 		input["name"] = cmd.Name()
 		input["store_id"] = storeID
-		postJSON(cmd, schedulersPrefix, input)
+		return postJSON(cmd, schedulersPrefix, input)
 	}
-
 }
 
 // NewShuffleLeaderSchedulerCommand returns a command to add a shuffle-leader-scheduler.
@@ -204,7 +277,7 @@ func NewShuffleLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "shuffle-leader-scheduler",
 		Short: "add a scheduler to shuffle leaders between stores",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -214,7 +287,7 @@ func NewShuffleRegionSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "shuffle-region-scheduler",
 		Short: "add a scheduler to shuffle regions between stores",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -224,29 +297,27 @@ func NewShuffleHotRegionSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "shuffle-hot-region-scheduler [limit]",
 		Short: "add a scheduler to shuffle hot regions",
-		Run:   addSchedulerForShuffleHotRegionCommandFunc,
+		RunE:  addSchedulerForShuffleHotRegionCommandFunc,
 	}
 	return c
 }
 
-func addSchedulerForShuffleHotRegionCommandFunc(cmd *cobra.Command, args []string) {
+func addSchedulerForShuffleHotRegionCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	limit := uint64(1)
 	if len(args) == 1 {
 		l, err := strconv.ParseUint(args[0], 10, 64)
 		if err != nil {
-			cmd.Println("Error: ", err)
-			return
+			return err
 		}
 		limit = l
 	}
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
 	input["limit"] = limit
-	postJSON(cmd, schedulersPrefix, input)
+	return postJSON(cmd, schedulersPrefix, input)
 }
 
 // NewBalanceLeaderSchedulerCommand returns a command to add a balance-leader-scheduler.
@@ -254,7 +325,7 @@ func NewBalanceLeaderSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "balance-leader-scheduler",
 		Short: "add a scheduler to balance leaders between stores",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -264,7 +335,7 @@ func NewBalanceRegionSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "balance-region-scheduler",
 		Short: "add a scheduler to balance regions between stores",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -274,7 +345,7 @@ func NewBalanceHotRegionSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "balance-hot-region-scheduler",
 		Short: "add a scheduler to balance hot regions between stores",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -284,7 +355,7 @@ func NewRandomMergeSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "random-merge-scheduler",
 		Short: "add a scheduler to merge regions randomly",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
@@ -294,20 +365,29 @@ func NewLabelSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "label-scheduler",
 		Short: "add a scheduler to schedule regions according to the label",
-		Run:   addSchedulerCommandFunc,
+		RunE:  addSchedulerCommandFunc,
 	}
 	return c
 }
 
-func addSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+// NewLoadSplitSchedulerCommand returns a command to add a load-split-scheduler.
+func NewLoadSplitSchedulerCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "load-split-scheduler",
+		Short: "add a scheduler to split regions according to the load",
+		RunE:  addSchedulerCommandFunc,
+	}
+	return c
+}
+
+func addSchedulerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 
 	input := make(map[string]interface{})
 	input["name"] = cmd.Name()
-	postJSON(cmd, schedulersPrefix, input)
+	return postJSON(cmd, schedulersPrefix, input)
 }
 
 // NewScatterRangeSchedulerCommand returns a command to add a scatter-range-scheduler.
@@ -315,26 +395,23 @@ func NewScatterRangeSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "scatter-range [--format=raw|encode|hex] <start_key> <end_key> <range_name>",
 		Short: "add a scheduler to scatter range",
-		Run:   addSchedulerForScatterRangeCommandFunc,
+		RunE:  addSchedulerForScatterRangeCommandFunc,
 	}
 	c.Flags().String("format", "hex", "the key format")
 	return c
 }
 
-func addSchedulerForScatterRangeCommandFunc(cmd *cobra.Command, args []string) {
+func addSchedulerForScatterRangeCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 3 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	startKey, err := parseKey(cmd.Flags(), args[0])
 	if err != nil {
-		cmd.Println("Error: ", err)
-		return
+		return err
 	}
 	endKey, err := parseKey(cmd.Flags(), args[1])
 	if err != nil {
-		cmd.Println("Error: ", err)
-		return
+		return err
 	}
 
 	input := make(map[string]interface{})
@@ -342,7 +419,7 @@ func addSchedulerForScatterRangeCommandFunc(cmd *cobra.Command, args []string) {
 	input["start_key"] = url.QueryEscape(startKey)
 	input["end_key"] = url.QueryEscape(endKey)
 	input["range_name"] = args[2]
-	postJSON(cmd, schedulersPrefix, input)
+	return postJSON(cmd, schedulersPrefix, input)
 }
 
 // NewRemoveSchedulerCommand returns a command to remove scheduler.
@@ -350,38 +427,36 @@ func NewRemoveSchedulerCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "remove <scheduler>",
 		Short: "remove a scheduler",
-		Run:   removeSchedulerCommandFunc,
+		RunE:  removeSchedulerCommandFunc,
 	}
 	return c
 }
 
-func redirectRemoveSchedulerToDeleteConfig(cmd *cobra.Command, schedulerName string, args []string) {
+func redirectRemoveSchedulerToDeleteConfig(cmd *cobra.Command, schedulerName string, args []string) error {
 	args = strings.Split(args[0], "-")
 	args = args[len(args)-1:]
-	deleteStoreFromSchedulerConfig(cmd, schedulerName, args)
+	return deleteStoreFromSchedulerConfig(cmd, schedulerName, args)
 }
 
-func removeSchedulerCommandFunc(cmd *cobra.Command, args []string) {
+func removeSchedulerCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.Usage())
-		return
+		return usageError(cmd)
 	}
 	// FIXME: maybe there is a more graceful method to handler it
 	switch {
 	case strings.HasPrefix(args[0], evictLeaderSchedulerName) && args[0] != evictLeaderSchedulerName:
-		redirectRemoveSchedulerToDeleteConfig(cmd, evictLeaderSchedulerName, args)
+		return redirectRemoveSchedulerToDeleteConfig(cmd, evictLeaderSchedulerName, args)
 	case strings.HasPrefix(args[0], grantLeaderSchedulerName) && args[0] != grantLeaderSchedulerName:
-		redirectRemoveSchedulerToDeleteConfig(cmd, grantLeaderSchedulerName, args)
+		return redirectRemoveSchedulerToDeleteConfig(cmd, grantLeaderSchedulerName, args)
 	default:
 		path := schedulersPrefix + "/" + args[0]
 		_, err := doRequest(cmd, path, http.MethodDelete)
 		if err != nil {
-			cmd.Println(err)
-			return
+			return err
 		}
 		cmd.Println("Success!")
+		return nil
 	}
-
 }
 
 // NewConfigSchedulerCommand returns commands to config scheduler.
@@ -394,7 +469,10 @@ func NewConfigSchedulerCommand() *cobra.Command {
 		newConfigEvictLeaderCommand(),
 		newConfigGrantLeaderCommand(),
 		newConfigHotRegionCommand(),
+		newConfigBalanceLeaderCommand(),
+		newConfigBalanceRegionCommand(),
 		newConfigShuffleRegionCommand(),
+		newConfigLoadSplitCommand(),
 	)
 	return c
 }
@@ -403,16 +481,75 @@ func newConfigHotRegionCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "balance-hot-region-scheduler",
 		Short: "evict-leader-scheduler config",
-		Run:   listSchedulerConfigCommandFunc,
+		RunE:  listSchedulerConfigCommandFunc,
+	}
+	c.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "list the config item",
+		RunE:  listSchedulerConfigCommandFunc})
+	c.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "set the config item",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postSchedulerConfigCommandFunc(cmd, c.Name(), args)
+		}})
+	return c
+}
+
+func newConfigBalanceLeaderCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "balance-leader-scheduler",
+		Short: "balance-leader-scheduler config",
+		RunE:  listSchedulerConfigCommandFunc,
+	}
+	c.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "list the config item",
+		RunE:  listSchedulerConfigCommandFunc})
+	c.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "set the config item",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postSchedulerConfigCommandFunc(cmd, c.Name(), args)
+		}})
+	return c
+}
+
+func newConfigBalanceRegionCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "balance-region-scheduler",
+		Short: "balance-region-scheduler config",
+		RunE:  listSchedulerConfigCommandFunc,
+	}
+	c.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "list the config item",
+		RunE:  listSchedulerConfigCommandFunc})
+	c.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "set the config item",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postSchedulerConfigCommandFunc(cmd, c.Name(), args)
+		}})
+	return c
+}
+
+func newConfigLoadSplitCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "load-split-scheduler",
+		Short: "load-split-scheduler config",
+		RunE:  listSchedulerConfigCommandFunc,
 	}
 	c.AddCommand(&cobra.Command{
 		Use:   "list",
 		Short: "list the config item",
-		Run:   listSchedulerConfigCommandFunc})
+		RunE:  listSchedulerConfigCommandFunc})
 	c.AddCommand(&cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "set the config item",
-		Run:   func(cmd *cobra.Command, args []string) { postSchedulerConfigCommandFunc(cmd, c.Name(), args) }})
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return postSchedulerConfigCommandFunc(cmd, c.Name(), args)
+		}})
 	return c
 }
 
@@ -420,16 +557,18 @@ func newConfigEvictLeaderCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "evict-leader-scheduler",
 		Short: "evict-leader-scheduler config",
-		Run:   listSchedulerConfigCommandFunc,
+		RunE:  listSchedulerConfigCommandFunc,
 	}
 	c.AddCommand(&cobra.Command{
 		Use:   "add-store <store-id>",
 		Short: "add a store to evict leader list",
-		Run:   func(cmd *cobra.Command, args []string) { addStoreToSchedulerConfig(cmd, c.Name(), args) },
+		RunE:  func(cmd *cobra.Command, args []string) error { return addStoreToSchedulerConfig(cmd, c.Name(), args) },
 	}, &cobra.Command{
 		Use:   "delete-store <store-id>",
 		Short: "delete a store from evict leader list",
-		Run:   func(cmd *cobra.Command, args []string) { deleteStoreFromSchedulerConfig(cmd, c.Name(), args) },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteStoreFromSchedulerConfig(cmd, c.Name(), args)
+		},
 	})
 	return c
 }
@@ -438,16 +577,18 @@ func newConfigGrantLeaderCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "grant-leader-scheduler",
 		Short: "grant-leader-scheduler config",
-		Run:   listSchedulerConfigCommandFunc,
+		RunE:  listSchedulerConfigCommandFunc,
 	}
 	c.AddCommand(&cobra.Command{
 		Use:   "add-store <store-id>",
 		Short: "add a store to grant leader list",
-		Run:   func(cmd *cobra.Command, args []string) { addStoreToSchedulerConfig(cmd, c.Name(), args) },
+		RunE:  func(cmd *cobra.Command, args []string) error { return addStoreToSchedulerConfig(cmd, c.Name(), args) },
 	}, &cobra.Command{
 		Use:   "delete-store <store-id>",
 		Short: "delete a store from grant leader list",
-		Run:   func(cmd *cobra.Command, args []string) { deleteStoreFromSchedulerConfig(cmd, c.Name(), args) },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return deleteStoreFromSchedulerConfig(cmd, c.Name(), args)
+		},
 	})
 	return c
 }
@@ -456,41 +597,38 @@ func newConfigShuffleRegionCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "shuffle-region-scheduler",
 		Short: "shuffle-region-scheduler config",
-		Run:   showShuffleRegionSchedulerRolesCommandFunc,
+		RunE:  showShuffleRegionSchedulerRolesCommandFunc,
 	}
 	c.AddCommand(&cobra.Command{
 		Use:   "show-roles",
 		Short: "show affected roles (leader, follower, learner)",
-		Run:   showShuffleRegionSchedulerRolesCommandFunc,
+		RunE:  showShuffleRegionSchedulerRolesCommandFunc,
 	}, &cobra.Command{
 		Use:   "set-roles [leader,][follower,][learner]",
 		Short: "set affected roles",
-		Run:   setShuffleRegionSchedulerRolesCommandFunc,
+		RunE:  setShuffleRegionSchedulerRolesCommandFunc,
 	})
 	return c
 }
 
-func addStoreToSchedulerConfig(cmd *cobra.Command, schedulerName string, args []string) {
+func addStoreToSchedulerConfig(cmd *cobra.Command, schedulerName string, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	storeID, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	input := make(map[string]interface{})
 	input["name"] = schedulerName
 	input["store_id"] = storeID
 
-	postJSON(cmd, path.Join(schedulerConfigPrefix, schedulerName, "config"), input)
+	return postJSON(cmd, path.Join(schedulerConfigPrefix, schedulerName, "config"), input)
 }
 
-func listSchedulerConfigCommandFunc(cmd *cobra.Command, args []string) {
+func listSchedulerConfigCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	p := cmd.Name()
 	if p == "list" {
@@ -499,16 +637,14 @@ func listSchedulerConfigCommandFunc(cmd *cobra.Command, args []string) {
 	path := path.Join(schedulerConfigPrefix, p, "list")
 	r, err := doRequest(cmd, path, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func postSchedulerConfigCommandFunc(cmd *cobra.Command, schedulerName string, args []string) {
+func postSchedulerConfigCommandFunc(cmd *cobra.Command, schedulerName string, args []string) error {
 	if len(args) != 2 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	var val interface{}
 	input := make(map[string]interface{})
@@ -518,27 +654,25 @@ func postSchedulerConfigCommandFunc(cmd *cobra.Command, schedulerName string, ar
 		val = value
 	}
 	input[key] = val
-	postJSON(cmd, path.Join(schedulerConfigPrefix, schedulerName, "config"), input)
+	return postJSON(cmd, path.Join(schedulerConfigPrefix, schedulerName, "config"), input)
 }
 
-func deleteStoreFromSchedulerConfig(cmd *cobra.Command, schedulerName string, args []string) {
+func deleteStoreFromSchedulerConfig(cmd *cobra.Command, schedulerName string, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.Usage())
-		return
+		return usageError(cmd)
 	}
 	path := path.Join(schedulerConfigPrefix, "/", schedulerName, "delete", args[0])
 	_, err := doRequest(cmd, path, http.MethodDelete)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func showShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []string) {
+func showShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	p := cmd.Name()
 	if p == "show-roles" {
@@ -547,16 +681,14 @@ func showShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []strin
 	path := path.Join(schedulerConfigPrefix, p, "roles")
 	r, err := doRequest(cmd, path, http.MethodGet)
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func setShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []string) {
+func setShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println(cmd.UsageString())
-		return
+		return usageError(cmd)
 	}
 	var roles []string
 	fields := strings.Split(strings.ToLower(args[0]), ",")
@@ -570,8 +702,8 @@ func setShuffleRegionSchedulerRolesCommandFunc(cmd *cobra.Command, args []string
 	_, err := doRequest(cmd, path, http.MethodPost,
 		WithBody("application/json", bytes.NewBuffer(b)))
 	if err != nil {
-		cmd.Println(err)
-		return
+		return err
 	}
 	cmd.Println("Success!")
+	return nil
 }
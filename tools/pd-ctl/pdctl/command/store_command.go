@@ -20,7 +20,9 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/spf13/cobra"
 )
@@ -36,7 +38,7 @@ func NewStoreCommand() *cobra.Command {
 	s := &cobra.Command{
 		Use:   `store [command] [flags]`,
 		Short: "manipulate or query stores",
-		Run:   showStoreCommandFunc,
+		RunE:  showStoreCommandFunc,
 	}
 	s.AddCommand(NewDeleteStoreCommand())
 	s.AddCommand(NewLabelStoreCommand())
@@ -44,6 +46,12 @@ func NewStoreCommand() *cobra.Command {
 	s.AddCommand(NewStoreLimitCommand())
 	s.AddCommand(NewRemoveTombStoneCommand())
 	s.AddCommand(NewStoreLimitSceneCommand())
+	s.AddCommand(NewStoreRestartCommand())
+	s.AddCommand(NewStorePauseCommand())
+	s.AddCommand(NewStoreCordonCommand())
+	s.AddCommand(NewStoreUncordonCommand())
+	s.AddCommand(NewStoreDrainCommand())
+	s.AddCommand(NewStoreDestroyedCommand())
 	s.Flags().String("jq", "", "jq query")
 	s.Flags().StringSlice("state", nil, "state filter")
 	return s
@@ -54,7 +62,7 @@ func NewDeleteStoreByAddrCommand() *cobra.Command {
 	d := &cobra.Command{
 		Use:   "addr <address>",
 		Short: "delete store by its address",
-		Run:   deleteStoreCommandByAddrFunc,
+		RunE:  deleteStoreCommandByAddrFunc,
 	}
 	return d
 }
@@ -64,8 +72,9 @@ func NewDeleteStoreCommand() *cobra.Command {
 	d := &cobra.Command{
 		Use:   "delete <store_id>",
 		Short: "delete the store",
-		Run:   deleteStoreCommandFunc,
+		RunE:  deleteStoreCommandFunc,
 	}
+	d.PersistentFlags().Bool("physically-destroyed", false, "the store will never come back, its peers are replaced without waiting and its address can not be registered again")
 	d.AddCommand(NewDeleteStoreByAddrCommand())
 	return d
 }
@@ -75,7 +84,7 @@ func NewLabelStoreCommand() *cobra.Command {
 	l := &cobra.Command{
 		Use:   "label <store_id> <key> <value> [<key> <value>]...",
 		Short: "set a store's label value",
-		Run:   labelStoreCommandFunc,
+		RunE:  labelStoreCommandFunc,
 	}
 	l.Flags().BoolP("force", "f", false, "overwrite the label forcibly")
 	return l
@@ -86,7 +95,89 @@ func NewSetStoreWeightCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "weight <store_id> <leader_weight> <region_weight>",
 		Short: "set a store's leader and region balance weight",
-		Run:   setStoreWeightCommandFunc,
+		RunE:  setStoreWeightCommandFunc,
+	}
+}
+
+// NewStoreRestartCommand returns a restart subcommand of storeCmd.
+func NewStoreRestartCommand() *cobra.Command {
+	r := &cobra.Command{
+		Use:   "restart <store_id> [<grace_period>]",
+		Short: "evict the leaders of the store and pause replacing its down peers before restarting it",
+		RunE:  storeRestartCommandFunc,
+	}
+	r.AddCommand(&cobra.Command{
+		Use:   "cancel <store_id>",
+		Short: "cancel the restarting state of the store",
+		RunE:  cancelStoreRestartCommandFunc,
+	})
+	return r
+}
+
+// NewStoreDrainCommand returns a drain subcommand of storeCmd.
+func NewStoreDrainCommand() *cobra.Command {
+	d := &cobra.Command{
+		Use:   "drain <store_id>",
+		Short: "evict the leaders of the store, then move out its regions until it becomes tombstone",
+		RunE:  storeDrainCommandFunc,
+	}
+	d.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "show the progress of draining the stores",
+		RunE:  showStoreDrainCommandFunc,
+	}, &cobra.Command{
+		Use:   "cancel <store_id>",
+		Short: "cancel draining the store",
+		RunE:  cancelStoreDrainCommandFunc,
+	})
+	return d
+}
+
+// NewStoreDestroyedCommand returns a destroyed subcommand of storeCmd.
+func NewStoreDestroyedCommand() *cobra.Command {
+	d := &cobra.Command{
+		Use:   "destroyed",
+		Short: "show the physically destroyed stores, which can not be registered again",
+		RunE:  showDestroyedStoresCommandFunc,
+	}
+	d.AddCommand(&cobra.Command{
+		Use:   "remove <store_id>",
+		Short: "allow the physically destroyed store to be registered again after it is tombstone or removed",
+		RunE:  removeDestroyedStoreCommandFunc,
+	})
+	return d
+}
+
+// NewStorePauseCommand returns a pause subcommand of storeCmd.
+func NewStorePauseCommand() *cobra.Command {
+	p := &cobra.Command{
+		Use:   "pause <store_id> [<ttl>]",
+		Short: "stop using the store as source or target of new operators until the ttl expires",
+		RunE:  storePauseCommandFunc,
+	}
+	p.AddCommand(&cobra.Command{
+		Use:   "resume <store_id>",
+		Short: "resume the scheduling of the paused store",
+		RunE:  storeResumeCommandFunc,
+	})
+	return p
+}
+
+// NewStoreCordonCommand returns a cordon subcommand of storeCmd.
+func NewStoreCordonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cordon <store_id> [<ttl>]",
+		Short: "stop using the store as target of new operators, the cordon never expires if the ttl is not set",
+		RunE:  storeCordonCommandFunc,
+	}
+}
+
+// NewStoreUncordonCommand returns an uncordon subcommand of storeCmd.
+func NewStoreUncordonCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uncordon <store_id>",
+		Short: "use the cordoned store as target of new operators again",
+		RunE:  storeUncordonCommandFunc,
 	}
 }
 
@@ -95,8 +186,8 @@ func NewStoreLimitCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:   "limit [<type>]|[<store_id>|<all> [<key> <value>]... <limit> <type>]",
 		Short: "show or set a store's rate limit",
-		Long:  "show or set a store's rate limit, <type> can be 'add-peer'(default) or 'remove-peer'",
-		Run:   storeLimitCommandFunc,
+		Long:  "show or set a store's rate limit, <type> can be 'add-peer'(default), 'remove-peer' or 'transfer-leader'",
+		RunE:  storeLimitCommandFunc,
 	}
 	return c
 }
@@ -121,7 +212,7 @@ func NewRemoveTombStoneCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "remove-tombstone",
 		Short: "remove tombstone record if only safe",
-		Run:   removeTombStoneCommandFunc,
+		RunE:  removeTombStoneCommandFunc,
 	}
 }
 
@@ -131,7 +222,7 @@ func NewRemoveTombStoneCommandDeprecated() *cobra.Command {
 		Use:        "remove-tombstone",
 		Short:      "remove tombstone record if only safe",
 		Deprecated: "use store remove-tombstone instead",
-		Run:        removeTombStoneCommandFunc,
+		RunE:       removeTombStoneCommandFunc,
 	}
 }
 
@@ -140,7 +231,7 @@ func NewShowStoresCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:        "show [limit]",
 		Short:      "show the stores",
-		Run:        showStoresCommandFunc,
+		RunE:       showStoresCommandFunc,
 		Deprecated: "use store [limit] instead",
 	}
 	sc.AddCommand(NewShowAllStoresLimitCommand())
@@ -151,9 +242,9 @@ func NewShowStoresCommand() *cobra.Command {
 func NewShowAllStoresLimitCommand() *cobra.Command {
 	sc := &cobra.Command{
 		Use:        "limit <type>",
-		Short:      "show all stores' limit, <type> can be 'add-peer'(default) or 'remove-peer'",
+		Short:      "show all stores' limit, <type> can be 'add-peer'(default), 'remove-peer' or 'transfer-leader'",
 		Deprecated: "use store limit instead",
-		Run:        showAllStoresLimitCommandFunc,
+		RunE:       showAllStoresLimitCommandFunc,
 	}
 	return sc
 }
@@ -174,9 +265,9 @@ func NewSetAllLimitCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:        "limit <rate> <type>",
 		Short:      "set all store's rate limit",
-		Long:       "set all store's rate limit, <type> can be 'add-peer'(default) or 'remove-peer'",
+		Long:       "set all store's rate limit, <type> can be 'add-peer'(default), 'remove-peer' or 'transfer-leader'",
 		Deprecated: "use store limit all <rate> instead",
-		Run:        setAllLimitCommandFunc,
+		RunE:       setAllLimitCommandFunc,
 	}
 }
 
@@ -186,11 +277,11 @@ func NewStoreLimitSceneCommand() *cobra.Command {
 		Use:   "limit-scene [<type>]|[<scene> <rate> <type>]",
 		Short: "show or set the limit value for a scene",
 		Long:  "show or set the limit value for a scene, <type> can be 'add-peer'(default) or 'remove-peer'",
-		Run:   storeLimitSceneCommandFunc,
+		RunE:  storeLimitSceneCommandFunc,
 	}
 }
 
-func storeLimitSceneCommandFunc(cmd *cobra.Command, args []string) {
+func storeLimitSceneCommandFunc(cmd *cobra.Command, args []string) error {
 	var resp string
 	var err error
 	prefix := fmt.Sprintf("%s/limit/scene", storesPrefix)
@@ -203,8 +294,7 @@ func storeLimitSceneCommandFunc(cmd *cobra.Command, args []string) {
 		}
 		resp, err = doRequest(cmd, prefix, http.MethodGet)
 		if err != nil {
-			cmd.Println(err)
-			return
+			return err
 		}
 		cmd.Println(resp)
 	case 2, 3:
@@ -214,32 +304,29 @@ func storeLimitSceneCommandFunc(cmd *cobra.Command, args []string) {
 			scene != "low" &&
 			scene != "normal" &&
 			scene != "high" {
-			cmd.Println("invalid scene")
-			return
+			return errors.New("invalid scene")
 		}
 
 		rate, err := strconv.Atoi(args[1])
 		if err != nil {
-			cmd.Println(err)
-			return
+			return err
 		}
 		if len(args) == 3 {
 			prefix = path.Join(prefix, fmt.Sprintf("?type=%s", args[2]))
 		}
-		postJSON(cmd, prefix, map[string]interface{}{scene: rate})
+		return postJSON(cmd, prefix, map[string]interface{}{scene: rate})
 	}
+	return nil
 }
 
-func showStoreCommandFunc(cmd *cobra.Command, args []string) {
+func showStoreCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := storesPrefix
 	if len(args) > 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	if len(args) == 1 {
 		if _, err := strconv.Atoi(args[0]); err != nil {
-			cmd.Println("store_id should be a number")
-			return
+			return errors.New("store_id should be a number")
 		}
 		prefix = fmt.Sprintf(storePrefix, args[0])
 	} else {
@@ -252,8 +339,7 @@ func showStoreCommandFunc(cmd *cobra.Command, args []string) {
 		for _, state := range states {
 			stateValue, ok := metapb.StoreState_value[state]
 			if !ok {
-				cmd.Println("Unknown state: " + state)
-				return
+				return errors.New("unknown state: " + state)
 			}
 			stateValues = append(stateValues, fmt.Sprintf("state=%v", stateValue))
 		}
@@ -263,46 +349,47 @@ func showStoreCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get store: %s\n", err)
-		return
+		return errors.Errorf("failed to get store: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func deleteStoreCommandFunc(cmd *cobra.Command, args []string) {
+func deleteStoreCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	if _, err := strconv.Atoi(args[0]); err != nil {
-		cmd.Println("store_id should be a number")
-		return
+		return errors.New("store_id should be a number")
 	}
-	prefix := fmt.Sprintf(storePrefix, args[0])
-	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	_, err := doRequest(cmd, deleteStorePath(cmd, args[0]), http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to delete store %s: %s\n", args[0], err)
-		return
+		return errors.Errorf("failed to delete store %s: %s", args[0], err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func deleteStoreCommandByAddrFunc(cmd *cobra.Command, args []string) {
+func deleteStorePath(cmd *cobra.Command, storeID string) string {
+	prefix := fmt.Sprintf(storePrefix, storeID)
+	if destroyed, _ := cmd.Flags().GetBool("physically-destroyed"); destroyed {
+		prefix += "?physically_destroyed"
+	}
+	return prefix
+}
+
+func deleteStoreCommandByAddrFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	addr := args[0]
 
 	// fetch all the stores
 	r, err := doRequest(cmd, storesPrefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get store: %s\n", err)
-		return
+		return errors.Errorf("failed to get store: %s", err)
 	}
 
 	storeInfo := struct {
@@ -314,8 +401,7 @@ func deleteStoreCommandByAddrFunc(cmd *cobra.Command, args []string) {
 		} `json:"stores"`
 	}{}
 	if err = json.Unmarshal([]byte(r), &storeInfo); err != nil {
-		cmd.Printf("Failed to parse store info: %s\n", err)
-		return
+		return errors.Errorf("failed to parse store info: %s", err)
 	}
 
 	// filter by the addr
@@ -328,31 +414,27 @@ func deleteStoreCommandByAddrFunc(cmd *cobra.Command, args []string) {
 	}
 
 	if id == -1 {
-		cmd.Printf("address not found: %s\n", addr)
-		return
+		return errors.Errorf("address not found: %s", addr)
 	}
 
 	// delete store by its ID
-	prefix := fmt.Sprintf(storePrefix, id)
-	_, err = doRequest(cmd, prefix, http.MethodDelete)
+	_, err = doRequest(cmd, deleteStorePath(cmd, strconv.Itoa(id)), http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to delete store %s: %s\n", args[0], err)
-		return
+		return errors.Errorf("failed to delete store %s: %s", args[0], err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func labelStoreCommandFunc(cmd *cobra.Command, args []string) {
+func labelStoreCommandFunc(cmd *cobra.Command, args []string) error {
 	// The least args' numbers is 1, which means users can set empty key and value
 	// In this way, if force flag is set then it means clear all labels,
 	// if force flag isn't set then it means do nothing
 	if len(args) < 1 || len(args)%2 != 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	if _, err := strconv.Atoi(args[0]); err != nil {
-		cmd.Println("store_id should be a number")
-		return
+		return errors.New("store_id should be a number")
 	}
 	prefix := fmt.Sprintf(path.Join(storePrefix, "label"), args[0])
 	labels := make(map[string]interface{})
@@ -362,32 +444,197 @@ func labelStoreCommandFunc(cmd *cobra.Command, args []string) {
 	if force, _ := cmd.Flags().GetBool("force"); force {
 		prefix += "?force=true"
 	}
-	postJSON(cmd, prefix, labels)
+	return postJSON(cmd, prefix, labels)
 }
 
-func setStoreWeightCommandFunc(cmd *cobra.Command, args []string) {
+func setStoreWeightCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 3 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	leader, err := strconv.ParseFloat(args[1], 64)
 	if err != nil || leader < 0 {
-		cmd.Println("leader_weight should be a number that >= 0.")
-		return
+		return errors.New("leader_weight should be a number that >= 0")
 	}
 	region, err := strconv.ParseFloat(args[2], 64)
 	if err != nil || region < 0 {
-		cmd.Println("region_weight should be a number that >= 0")
-		return
+		return errors.New("region_weight should be a number that >= 0")
 	}
 	prefix := fmt.Sprintf(path.Join(storePrefix, "weight"), args[0])
-	postJSON(cmd, prefix, map[string]interface{}{
+	return postJSON(cmd, prefix, map[string]interface{}{
 		"leader": leader,
 		"region": region,
 	})
 }
 
-func storeLimitCommandFunc(cmd *cobra.Command, args []string) {
+func storeRestartCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	input := make(map[string]interface{})
+	if len(args) == 2 {
+		if _, err := time.ParseDuration(args[1]); err != nil {
+			return errors.New("grace_period should be a duration such as 10m")
+		}
+		input["grace-period"] = args[1]
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "restart"), args[0])
+	return postJSON(cmd, prefix, input)
+}
+
+func cancelStoreRestartCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "restart"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		return errors.Errorf("failed to cancel restarting store %s: %s", args[0], err)
+	}
+	cmd.Println("Success!")
+	return nil
+}
+
+func storeDrainCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "drain"), args[0])
+	return postJSON(cmd, prefix, nil)
+}
+
+func showStoreDrainCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return usageError(cmd)
+	}
+	r, err := doRequest(cmd, path.Join(storesPrefix, "drain"), http.MethodGet)
+	if err != nil {
+		return errors.Errorf("failed to get the progress of draining stores: %s", err)
+	}
+	cmd.Println(r)
+	return nil
+}
+
+func cancelStoreDrainCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "drain"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		return errors.Errorf("failed to cancel draining store %s: %s", args[0], err)
+	}
+	cmd.Println("Success!")
+	return nil
+}
+
+func showDestroyedStoresCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return usageError(cmd)
+	}
+	r, err := doRequest(cmd, path.Join(storesPrefix, "destroyed"), http.MethodGet)
+	if err != nil {
+		return errors.Errorf("failed to get the physically destroyed stores: %s", err)
+	}
+	cmd.Println(r)
+	return nil
+}
+
+func removeDestroyedStoreCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	_, err := doRequest(cmd, path.Join(storesPrefix, "destroyed", args[0]), http.MethodDelete)
+	if err != nil {
+		return errors.Errorf("failed to remove the physically destroyed store %s: %s", args[0], err)
+	}
+	cmd.Println("Success!")
+	return nil
+}
+
+func storePauseCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	input := make(map[string]interface{})
+	if len(args) == 2 {
+		if _, err := time.ParseDuration(args[1]); err != nil {
+			return errors.New("ttl should be a duration such as 10m")
+		}
+		input["ttl"] = args[1]
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "pause"), args[0])
+	return postJSON(cmd, prefix, input)
+}
+
+func storeResumeCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "pause"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		return errors.Errorf("failed to resume store %s: %s", args[0], err)
+	}
+	cmd.Println("Success!")
+	return nil
+}
+
+func storeCordonCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	input := make(map[string]interface{})
+	if len(args) == 2 {
+		if _, err := time.ParseDuration(args[1]); err != nil {
+			return errors.New("ttl should be a duration such as 10m")
+		}
+		input["ttl"] = args[1]
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "cordon"), args[0])
+	return postJSON(cmd, prefix, input)
+}
+
+func storeUncordonCommandFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return usageError(cmd)
+	}
+	if _, err := strconv.Atoi(args[0]); err != nil {
+		return errors.New("store_id should be a number")
+	}
+	prefix := fmt.Sprintf(path.Join(storePrefix, "cordon"), args[0])
+	_, err := doRequest(cmd, prefix, http.MethodDelete)
+	if err != nil {
+		return errors.Errorf("failed to uncordon store %s: %s", args[0], err)
+	}
+	cmd.Println("Success!")
+	return nil
+}
+
+func storeLimitCommandFunc(cmd *cobra.Command, args []string) error {
 	argsCount := len(args)
 	if argsCount <= 1 {
 		prefix := storesLimitPrefix
@@ -396,15 +643,14 @@ func storeLimitCommandFunc(cmd *cobra.Command, args []string) {
 		}
 		r, err := doRequest(cmd, prefix, http.MethodGet)
 		if err != nil {
-			cmd.Printf("Failed to get store limit: %s\n", err)
-			return
+			return errors.Errorf("failed to get store limit: %s", err)
 		}
-		cmd.Println(r)
-	} else if argsCount <= 3 {
+		return printResponse(cmd, r)
+	}
+	if argsCount <= 3 {
 		rate, err := strconv.ParseFloat(args[1], 64)
 		if err != nil || rate <= 0 {
-			cmd.Println("rate should be a number that > 0.")
-			return
+			return errors.New("rate should be a number that > 0")
 		}
 		// if the store id is "all", set limits for all stores
 		var prefix string
@@ -419,52 +665,46 @@ func storeLimitCommandFunc(cmd *cobra.Command, args []string) {
 		if argsCount == 3 {
 			postInput["type"] = args[2]
 		}
-		postJSON(cmd, prefix, postInput)
-	} else {
-		if args[0] != "all" {
-			cmd.Println("Labels are an option of set all stores limit.")
-		} else {
-			postInput := map[string]interface{}{}
-			prefix := storesLimitPrefix
-			ratePos := argsCount - 1
-			if argsCount%2 == 1 {
-				postInput["type"] = args[argsCount-1]
-				ratePos = argsCount - 2
-			}
-			rate, err := strconv.ParseFloat(args[ratePos], 64)
-			if err != nil || rate <= 0 {
-				cmd.Println("rate should be a number that > 0.")
-				return
-			}
-			postInput["rate"] = rate
-			labels := make(map[string]interface{})
-			for i := 1; i < ratePos; i += 2 {
-				labels[args[i]] = args[i+1]
-			}
-			postInput["labels"] = labels
-			postJSON(cmd, prefix, postInput)
-		}
+		return postJSON(cmd, prefix, postInput)
 	}
+	if args[0] != "all" {
+		return errors.New("labels are an option of set all stores limit")
+	}
+	postInput := map[string]interface{}{}
+	prefix := storesLimitPrefix
+	ratePos := argsCount - 1
+	if argsCount%2 == 1 {
+		postInput["type"] = args[argsCount-1]
+		ratePos = argsCount - 2
+	}
+	rate, err := strconv.ParseFloat(args[ratePos], 64)
+	if err != nil || rate <= 0 {
+		return errors.New("rate should be a number that > 0")
+	}
+	postInput["rate"] = rate
+	labels := make(map[string]interface{})
+	for i := 1; i < ratePos; i += 2 {
+		labels[args[i]] = args[i+1]
+	}
+	postInput["labels"] = labels
+	return postJSON(cmd, prefix, postInput)
 }
 
-func showStoresCommandFunc(cmd *cobra.Command, args []string) {
+func showStoresCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := storesPrefix
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get store: %s\n", err)
-		return
+		return errors.Errorf("failed to get store: %s", err)
 	}
 	if flag := cmd.Flag("jq"); flag != nil && flag.Value.String() != "" {
-		printWithJQFilter(r, flag.Value.String())
-		return
+		return printWithJQFilter(r, flag.Value.String())
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func showAllStoresLimitCommandFunc(cmd *cobra.Command, args []string) {
+func showAllStoresLimitCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) > 1 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	prefix := storesLimitPrefix
 	if len(args) == 1 {
@@ -472,32 +712,29 @@ func showAllStoresLimitCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	r, err := doRequest(cmd, prefix, http.MethodGet)
 	if err != nil {
-		cmd.Printf("Failed to get all stores' limit: %s\n", err)
-		return
+		return errors.Errorf("failed to get all stores' limit: %s", err)
 	}
-	cmd.Println(r)
+	return printResponse(cmd, r)
 }
 
-func removeTombStoneCommandFunc(cmd *cobra.Command, args []string) {
+func removeTombStoneCommandFunc(cmd *cobra.Command, args []string) error {
 	prefix := path.Join(storesPrefix, "remove-tombstone")
 	_, err := doRequest(cmd, prefix, http.MethodDelete)
 	if err != nil {
-		cmd.Printf("Failed to remove tombstone store %s \n", err)
-		return
+		return errors.Errorf("failed to remove tombstone store: %s", err)
 	}
 	cmd.Println("Success!")
+	return nil
 }
 
-func setAllLimitCommandFunc(cmd *cobra.Command, args []string) {
+func setAllLimitCommandFunc(cmd *cobra.Command, args []string) error {
 	argsCount := len(args)
 	if argsCount != 1 && argsCount != 2 {
-		cmd.Usage()
-		return
+		return usageError(cmd)
 	}
 	rate, err := strconv.ParseFloat(args[0], 64)
 	if err != nil || rate <= 0 {
-		cmd.Println("rate should be a number that > 0.")
-		return
+		return errors.New("rate should be a number that > 0")
 	}
 	prefix := storesLimitPrefix
 	input := map[string]interface{}{
@@ -506,5 +743,5 @@ func setAllLimitCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) == 2 {
 		input["type"] = args[1]
 	}
-	postJSON(cmd, prefix, input)
+	return postJSON(cmd, prefix, input)
 }
@@ -16,6 +16,7 @@ package command
 import (
 	"strconv"
 
+	"github.com/pingcap/errors"
 	"github.com/spf13/cobra"
 	"github.com/tikv/pd/pkg/tsoutil"
 )
@@ -25,23 +26,22 @@ func NewTSOCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "tso <timestamp>",
 		Short: "parse TSO to the system and logic time",
-		Run:   showTSOCommandFunc,
+		RunE:  showTSOCommandFunc,
 	}
 	return cmd
 }
 
-func showTSOCommandFunc(cmd *cobra.Command, args []string) {
+func showTSOCommandFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
-		cmd.Println("Usage: tso <timestamp>")
-		return
+		return errors.New("usage: tso <timestamp>")
 	}
 	ts, err := strconv.ParseUint(args[0], 10, 64)
 	if err != nil {
-		cmd.Printf("Failed to parse TSO: %s\n", err)
-		return
+		return errors.Errorf("failed to parse TSO: %s", err)
 	}
 
 	physicalTime, logical := tsoutil.ParseTS(ts)
 	cmd.Println("system: ", physicalTime)
 	cmd.Println("logic: ", logical)
+	return nil
 }
@@ -32,16 +32,19 @@ type CommandFlags struct {
 	CAPath   string
 	CertPath string
 	KeyPath  string
+	Output   string
 	Help     bool
 }
 
 var (
 	commandFlags = CommandFlags{
-		URL: "http://127.0.0.1:2379",
+		URL:    "http://127.0.0.1:2379",
+		Output: command.OutputJSON,
 	}
 
 	detach            bool
 	interact          bool
+	batch             string
 	version           bool
 	readlineCompleter *readline.PrefixCompleter
 )
@@ -57,6 +60,13 @@ func pdctlRun(cmd *cobra.Command, args []string) {
 	}
 	if interact {
 		loop()
+		return
+	}
+	if batch != "" {
+		if err := runBatchFile(batch, os.Stdout); err != nil {
+			cmd.Println(err)
+			os.Exit(1)
+		}
 	}
 }
 
@@ -70,6 +80,7 @@ func getBasicCmd() *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(&commandFlags.CAPath, "cacert", commandFlags.CAPath, "path of file that contains list of trusted SSL CAs")
 	rootCmd.PersistentFlags().StringVar(&commandFlags.CertPath, "cert", commandFlags.CertPath, "path of file that contains X509 certificate in PEM format")
 	rootCmd.PersistentFlags().StringVar(&commandFlags.KeyPath, "key", commandFlags.KeyPath, "path of file that contains X509 key in PEM format")
+	rootCmd.PersistentFlags().StringVarP(&commandFlags.Output, "output", "o", commandFlags.Output, "output format, one of json, yaml and table")
 	rootCmd.PersistentFlags().BoolVarP(&commandFlags.Help, "help", "h", false, "help message")
 
 	rootCmd.AddCommand(
@@ -95,6 +106,9 @@ func getBasicCmd() *cobra.Command {
 
 	rootCmd.Flags().ParseErrorsWhitelist.UnknownFlags = true
 	rootCmd.SilenceErrors = true
+	// The commands print their own usage when they are called with the wrong
+	// arguments.
+	rootCmd.SilenceUsage = true
 
 	return rootCmd
 }
@@ -116,6 +130,7 @@ func getMainCmd(args []string) *cobra.Command {
 	rootCmd.Flags().BoolVarP(&detach, "detach", "d", true, "Run pdctl without readline.")
 	rootCmd.Flags().BoolVarP(&interact, "interact", "i", false, "Run pdctl with readline.")
 	rootCmd.Flags().BoolVarP(&version, "version", "V", false, "Print version information and exit.")
+	rootCmd.Flags().StringVarP(&batch, "batch", "b", "", "Run the commands in the file line by line and stop at the first failure, '-' means reading from stdin.")
 	rootCmd.Run = pdctlRun
 
 	rootCmd.SetArgs(args)
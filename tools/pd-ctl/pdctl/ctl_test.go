@@ -14,6 +14,9 @@
 package pdctl
 
 import (
+	"bytes"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -68,3 +71,38 @@ func TestGenCompleter(t *testing.T) {
 	}
 
 }
+
+func TestRunBatch(t *testing.T) {
+	input := "# wait for the leaders to be evicted\n\nsleep 1ms\nsleep abc\nsleep 1ms\n"
+	var out bytes.Buffer
+	if err := runBatch(strings.NewReader(input), &out); err == nil {
+		t.Error("the batch should fail at line 4")
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expect 2 results, got %d", len(lines))
+	}
+	var results []BatchResult
+	for _, line := range lines {
+		var result BatchResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, result)
+	}
+	if !results[0].Success || results[0].Line != 3 {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+	if results[1].Success || results[1].Line != 4 {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+
+	result := runBatchCommand("store abc")
+	if result.Success || !strings.Contains(result.Output, "store_id should be a number") {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if !IsBatchMode([]string{"-u", "127.0.0.1:2379", "-b", "-"}) || IsBatchMode([]string{"-b", "runbook.txt"}) {
+		t.Error("unexpected batch mode")
+	}
+}